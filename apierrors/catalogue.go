@@ -0,0 +1,196 @@
+package apierrors
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Errors of any route
+var (
+	Internal         = newError(http.StatusInternalServerError, "internal.unknown", "Unknown error")
+	RequestFailed    = newError(http.StatusBadRequest, "request.failed", "Bad Request")
+	RouteNotFound    = newError(http.StatusNotFound, "request.route_not_found", "Not Found")
+	MethodNotAllowed = newError(http.StatusMethodNotAllowed, "request.method_not_allowed", "Method Not Allowed")
+	BodyTooLarge     = newError(http.StatusRequestEntityTooLarge, "request.body_too_large", "Request Entity Too Large")
+	InvalidUUID      = newError(http.StatusBadRequest, "request.invalid_uuid", "Invalid uuid parameter")
+	InvalidBody      = newError(http.StatusInternalServerError, "request.invalid_body", "Error while parsing JSON body, check your request")
+	// ValidationFailed has the message of each invalid field of the body in the details
+	ValidationFailed  = newError(http.StatusBadRequest, "request.validation_failed", "Validation failed")
+	invalidQueryParam = newError(http.StatusBadRequest, "request.invalid_query_param", "Invalid query param")
+)
+
+// Requirement is what a query param needs to be, which completes the message of InvalidQueryParam
+type Requirement string
+
+const (
+	ValidDate   Requirement = "date"
+	ValidNumber Requirement = "number"
+	ValidBool   Requirement = "bool"
+	ValidUUID   Requirement = "uuid"
+	// OneOf takes the accepted values
+	OneOf Requirement = "one_of"
+	// WholeNumberBetween takes the min and the max
+	WholeNumberBetween Requirement = "whole_number_between"
+	// DaysBetween takes the min and the max
+	DaysBetween Requirement = "days_between"
+)
+
+// InvalidQueryParam is sent for query params that can't be read, with the param in the details.
+// The args fill the requirement, like the accepted values of OneOf.
+func InvalidQueryParam(param string, requirement Requirement, args ...string) *Error {
+	return invalidQueryParam.withParams(invalidQueryParam.Code+"."+string(requirement), append([]string{param}, args...)...).WithDetails(map[string]string{
+		"param": param,
+	})
+}
+
+// Errors of the pagination params
+var (
+	InvalidOffset      = newError(http.StatusBadRequest, "pagination.invalid_offset", "Offset needs to be a valid integer")
+	InvalidLimit       = newError(http.StatusBadRequest, "pagination.invalid_limit", "Limit needs to be a valid integer")
+	NegativePagination = newError(http.StatusBadRequest, "pagination.negative", "Offset and limit can't be negative")
+	InvalidCursor      = newError(http.StatusBadRequest, "pagination.invalid_cursor", "Invalid pagination cursor")
+	CursorSortMismatch = newError(http.StatusBadRequest, "pagination.cursor_sort_mismatch", "Pagination cursor was created for a different sort, check your request")
+)
+
+// Errors of authentication and authorization
+var (
+	MissingAuthHeader     = newError(http.StatusUnauthorized, "auth.missing_header", "Missing Authorization header")
+	InvalidAuthHeader     = newError(http.StatusUnauthorized, "auth.invalid_header", "Invalid Authorization header format")
+	InvalidToken          = newError(http.StatusUnauthorized, "auth.invalid_token", "Invalid or non-existing token")
+	TokenExpired          = newError(http.StatusUnauthorized, "auth.token_expired", "Token has expired, login again")
+	SessionRevoked        = newError(http.StatusUnauthorized, "auth.session_revoked", "Session has been revoked, login again")
+	InvalidRefreshToken   = newError(http.StatusUnauthorized, "auth.invalid_refresh_token", "Invalid or expired refresh token, login again")
+	InvalidCredentials    = newError(http.StatusBadRequest, "auth.invalid_credentials", "Invalid email/password")
+	InvalidResetToken     = newError(http.StatusBadRequest, "auth.invalid_reset_token", "Invalid or expired reset token")
+	PasswordResetRequired = newError(http.StatusForbidden, "auth.password_reset_required", "Password reset required, set a new password with POST /password-reset")
+	PermissionDenied      = newError(http.StatusUnauthorized, "auth.permission_denied", "User doesn't have permission to access this route")
+	NotSelf               = newError(http.StatusUnauthorized, "auth.not_self", "This route is only accessible by the user with the same id as the parameter or by users with permission")
+	notOwner              = newError(http.StatusUnauthorized, "auth.not_owner", "This route is only accessible to administrators or by the owner of the {0}")
+	UserBanned            = newError(http.StatusForbidden, "auth.user_banned", "User is banned")
+)
+
+// NotOwner is sent to users that aren't the owner of the resource and don't have permission to access it, with the
+// resource in the details
+func NotOwner(resource string) *Error {
+	return notOwner.withParams(notOwner.Code, resource).WithDetails(map[string]string{
+		"resource": resource,
+	})
+}
+
+// UserBannedUntil is sent to users banned until a time, which is in the details. Permanently banned users get UserBanned.
+func UserBannedUntil(until time.Time) *Error {
+	bannedUntil := until.UTC().Format(time.RFC3339)
+	return UserBanned.withParams(UserBanned.Code+".until", bannedUntil).WithDetails(map[string]string{
+		"bannedUntil": bannedUntil,
+	})
+}
+
+// Errors of the users, their roles and the admin routes
+var (
+	UserNotFound        = newError(http.StatusNotFound, "user.not_found", "User id not found in database")
+	UserEmailTaken      = newError(http.StatusBadRequest, "user.email_taken", "User with this email already exists")
+	UserDeleted         = newError(http.StatusBadRequest, "user.deleted", "Trying to act as a deleted user, check your request")
+	InvalidRole         = newError(http.StatusBadRequest, "role.invalid", "Role needs to be one of: admin, moderator, curator")
+	RoleAlreadyGranted  = newError(http.StatusBadRequest, "role.already_granted", "User already has the role")
+	RoleNotGranted      = newError(http.StatusNotFound, "role.not_granted", "User doesn't have the role")
+	SelfDemotion        = newError(http.StatusBadRequest, "admin.self_demotion", "Admins can't remove their own admin role")
+	SelfBan             = newError(http.StatusBadRequest, "admin.self_ban", "Admins can't ban themselves")
+	BanExpiryInPast     = newError(http.StatusBadRequest, "admin.ban_expiry_in_past", "Ban expiry needs to be in the future")
+	UserNotBanned       = newError(http.StatusBadRequest, "admin.user_not_banned", "User is not banned")
+	SelfFollow          = newError(http.StatusBadRequest, "follow.self", "Users can't follow themselves")
+	UserAlreadyFollowed = newError(http.StatusBadRequest, "follow.already_followed", "User is already followed")
+	UserNotFollowed     = newError(http.StatusNotFound, "follow.not_followed", "User is not followed")
+)
+
+// Errors of the movies and their cast, crew and genres
+var (
+	MovieNotFound         = newError(http.StatusNotFound, "movie.not_found", "Movie id not found in database")
+	MovieTitleTaken       = newError(http.StatusBadRequest, "movie.title_taken", "Movie with this title already exists")
+	InvalidMovieUUID      = newError(http.StatusBadRequest, "movie.invalid_id", "Invalid movie uuid")
+	ActorAlreadyInMovie   = newError(http.StatusBadRequest, "movie.actor_already_cast", "There is an actor already in the movie on the request")
+	ActorNotInMovie       = newError(http.StatusBadRequest, "movie.actor_not_cast", "Trying to delete an actor that is already not on the movie")
+	CastingNotFound       = newError(http.StatusNotFound, "movie.casting_not_found", "Actor is not in the cast of the movie")
+	EmptyCastingUpdate    = newError(http.StatusBadRequest, "movie.empty_casting_update", "Send a characterName or a billingOrder to update the casting")
+	CastOnDeletedMovie    = newError(http.StatusBadRequest, "movie.deleted_cast", "Trying to create an actor relationship on a deleted movie, check your request")
+	GenreAlreadyInMovie   = newError(http.StatusBadRequest, "movie.genre_already_added", "There is a genre already in the movie on the request")
+	GenreNotInMovie       = newError(http.StatusBadRequest, "movie.genre_not_added", "Trying to delete a genre that is already not on the movie")
+	GenresOnDeletedMovie  = newError(http.StatusBadRequest, "movie.deleted_genres", "Trying to create a genre relationship on a deleted movie, check your request")
+	CrewAlreadyInMovie    = newError(http.StatusBadRequest, "movie.crew_already_credited", "There is a person already in the crew of the movie with the same role on the request")
+	CrewNotInMovie        = newError(http.StatusBadRequest, "movie.crew_not_credited", "Trying to delete a person that is already not in the crew of the movie with this role")
+	CrewOnDeletedMovie    = newError(http.StatusBadRequest, "movie.deleted_crew", "Trying to create a crew relationship on a deleted movie, check your request")
+	ActorNotFound         = newError(http.StatusNotFound, "actor.not_found", "Actor id not found in database")
+	GenreNotFound         = newError(http.StatusNotFound, "genre.not_found", "Genre id not found in database")
+	GenreNameTaken        = newError(http.StatusBadRequest, "genre.name_taken", "Genre with this name already exists")
+	PersonNotFound        = newError(http.StatusNotFound, "person.not_found", "Person id not found in database")
+	InvalidSearchQuery    = newError(http.StatusBadRequest, "search.empty_query", "Search query needs at least one word")
+	InvalidSearchType     = newError(http.StatusBadRequest, "search.invalid_type", "Type needs to be either movie or actor")
+	MovieAlreadyWatchlist = newError(http.StatusBadRequest, "watchlist.already_added", "Movie is already in the watchlist")
+	MovieNotInWatchlist   = newError(http.StatusNotFound, "watchlist.not_added", "Movie is not in the watchlist")
+	DeletedMovieWatchlist = newError(http.StatusBadRequest, "watchlist.deleted_movie", "Trying to add a deleted movie, check your request")
+	DiaryEntryNotFound    = newError(http.StatusNotFound, "diary.not_found", "Diary entry id not found in database")
+	InvalidDiaryReview    = newError(http.StatusBadRequest, "diary.invalid_review", "Review needs to be a comment of the user on the movie of the diary entry")
+	ListNotFound          = newError(http.StatusNotFound, "list.not_found", "List id not found in database")
+	MovieAlreadyInList    = newError(http.StatusBadRequest, "list.movie_already_added", "Movie is already in the list")
+	MovieNotInList        = newError(http.StatusNotFound, "list.movie_not_added", "Movie is not in the list")
+	InvalidListOrder      = newError(http.StatusBadRequest, "list.invalid_order", "The new order needs to have every movie of the list exactly once")
+)
+
+// Errors of the comments and their moderation
+var (
+	CommentNotFound        = newError(http.StatusNotFound, "comment.not_found", "Comment id not found in database")
+	ParentCommentNotFound  = newError(http.StatusNotFound, "comment.parent_not_found", "Parent comment id not found in database")
+	CommentAsDeletedUser   = newError(http.StatusBadRequest, "comment.deleted_user", "Trying to comment as a deleted user, check your request")
+	ReviewOfDeletedMovie   = newError(http.StatusBadRequest, "comment.deleted_movie", "Trying to review a deleted movie, check your request")
+	ReplyToDeletedComment  = newError(http.StatusBadRequest, "comment.deleted_parent", "Trying to reply to a deleted comment, check your request")
+	MovieAlreadyReviewed   = newError(http.StatusConflict, "comment.already_reviewed", "User already reviewed this movie, use PUT /movies/:uuid/review to update the review")
+	ReplyOnOtherMovie      = newError(http.StatusBadRequest, "comment.reply_movie_mismatch", "Reply needs to be on the same movie as the parent comment")
+	ReplyWithGrade         = newError(http.StatusBadRequest, "comment.reply_with_grade", "Replies can't have a grade")
+	replyTooDeep           = newError(http.StatusBadRequest, "comment.reply_too_deep", "Replies can't be nested deeper than {0} levels")
+	CommentAlreadyLiked    = newError(http.StatusBadRequest, "comment.already_liked", "Comment is already liked")
+	CommentNotLiked        = newError(http.StatusNotFound, "comment.not_liked", "Comment is not liked")
+	CommentAlreadyReported = newError(http.StatusBadRequest, "comment.already_reported", "Comment was already reported by the user")
+	SelfReport             = newError(http.StatusBadRequest, "comment.self_report", "Users can't report their own comments")
+	BannedFromCommenting   = newError(http.StatusForbidden, "comment.user_banned", "Banned users can't comment")
+	BanPermissionDenied    = newError(http.StatusUnauthorized, "moderation.ban_permission_denied", "User doesn't have permission to ban users")
+)
+
+// ReplyTooDeep is sent for replies deeper than maxDepth, with the max depth in the details
+func ReplyTooDeep(maxDepth int) *Error {
+	depth := strconv.Itoa(maxDepth)
+	return replyTooDeep.withParams(replyTooDeep.Code, depth).WithDetails(map[string]string{
+		"maxDepth": depth,
+	})
+}
+
+// Messages of the errors built by functions that don't have a code of their own, keyed like the codes
+var functionMessages = map[string]string{
+	"auth.user_banned.until":                           "User is banned until {0}",
+	"request.invalid_query_param.date":                 "Query param {0} needs to be a valid date in the YYYY-MM-DD format",
+	"request.invalid_query_param.number":               "Query param {0} needs to be a valid number",
+	"request.invalid_query_param.bool":                 "Query param {0} needs to be either true or false",
+	"request.invalid_query_param.uuid":                 "Query param {0} needs to be a valid uuid",
+	"request.invalid_query_param.one_of":               "Query param {0} needs to be one of: {1}",
+	"request.invalid_query_param.whole_number_between": "Query param {0} needs to be a whole number between {1} and {2}",
+	"request.invalid_query_param.days_between":         "Query param {0} needs to be a whole number of days between {1} and {2}",
+}
+
+// fromStatus is the error of the catalogue for the fiber errors of a status, which are only sent by fiber itself
+func fromStatus(status int) *Error {
+	switch status {
+	case http.StatusNotFound:
+		return RouteNotFound
+	case http.StatusMethodNotAllowed:
+		return MethodNotAllowed
+	case http.StatusRequestEntityTooLarge:
+		return BodyTooLarge
+	}
+
+	if status >= http.StatusInternalServerError {
+		return Internal
+	}
+
+	failed := *RequestFailed
+	failed.Status = status
+	return &failed
+}
@@ -0,0 +1,172 @@
+// Package apierrors is the catalogue of the errors the API sends. Every error has a stable code, like "movie.not_found",
+// which clients can rely on, while the message is only meant for people, may be reworded and is sent in the language
+// of the request.
+package apierrors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/VinOfSteel/cinemagrader/i18n"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ContentType is the media type of the error responses, defined by RFC 7807
+const ContentType = "application/problem+json"
+
+// Error is an error of the catalogue. Handlers return them as they are, or with the details of the request that caused
+// them, and the error handler of the server turns them into a Problem.
+type Error struct {
+	Status  int
+	Code    string
+	Message string
+	// Details are extra information about the error, like the invalid fields of a body, keyed by what they refer to
+	Details map[string]string
+
+	// key and params are the message in the i18n catalogues, which is the code for the errors of the catalogue.
+	// Messages that don't come from the catalogues, like the ones of fiber, have no key and aren't translated.
+	key    string
+	params []string
+	// localizedDetails builds the details in other languages, for details that are messages themselves
+	localizedDetails LocalizedDetails
+}
+
+// LocalizedDetails builds the details of an error in the language of the translator
+type LocalizedDetails func(trans ut.Translator) map[string]string
+
+var catalogue = map[string]*Error{}
+
+// newError adds an error to the catalogue. Codes are what clients match on, so they can't be reused.
+func newError(status int, code string, message string) *Error {
+	if _, exists := catalogue[code]; exists {
+		panic(fmt.Sprintf("apierrors: duplicate error code %s", code))
+	}
+
+	e := &Error{Status: status, Code: code, Message: message, key: code}
+	catalogue[code] = e
+	return e
+}
+
+// All returns every error of the catalogue, sorted by code
+func All() []*Error {
+	all := make([]*Error, 0, len(catalogue))
+	for _, e := range catalogue {
+		all = append(all, e)
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Code < all[j].Code
+	})
+	return all
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// WithDetails returns a copy of the error with the details, leaving the one of the catalogue untouched
+func (e *Error) WithDetails(details map[string]string) *Error {
+	copied := *e
+	copied.Details = details
+	copied.localizedDetails = nil
+	return &copied
+}
+
+// WithLocalizedDetails returns a copy of the error with the details built by the function, in English until the error is localized
+func (e *Error) WithLocalizedDetails(details LocalizedDetails) *Error {
+	copied := e.WithDetails(details(i18n.Translator(i18n.English)))
+	copied.localizedDetails = details
+	return copied
+}
+
+// withParams returns a copy of the error with the message of the key, for the errors built by functions
+func (e *Error) withParams(key string, params ...string) *Error {
+	copied := *e
+	copied.key = key
+	copied.params = params
+	copied.Message = i18n.T(i18n.Translator(i18n.English), key, params...)
+	return &copied
+}
+
+// withMessage returns a copy of the error with a message that isn't in the catalogues, which is sent as it is
+func (e *Error) withMessage(message string) *Error {
+	copied := *e
+	copied.key = ""
+	copied.params = nil
+	copied.Message = message
+	return &copied
+}
+
+// Localize returns a copy of the error with the message and details in the language of the translator
+func (e *Error) Localize(trans ut.Translator) *Error {
+	localized := *e
+	if e.key != "" {
+		if message := i18n.T(trans, e.key, e.params...); message != "" {
+			localized.Message = message
+		}
+	}
+
+	if e.localizedDetails != nil {
+		localized.Details = e.localizedDetails(trans)
+	}
+
+	return &localized
+}
+
+// Is matches errors by code, so errors.Is(err, apierrors.MovieNotFound) holds for copies with details too
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// As lets errors.As read the error as a *fiber.Error, which is what fiber's default error handler looks for
+func (e *Error) As(target any) bool {
+	if fiberError, ok := target.(**fiber.Error); ok {
+		*fiberError = &fiber.Error{Code: e.Status, Message: e.Message}
+		return true
+	}
+
+	return false
+}
+
+// Problem is the body of every error response, a RFC 7807 problem with the code, message, details and request id as extensions
+type Problem struct {
+	Type      string            `json:"type"`
+	Title     string            `json:"title"`
+	Status    int               `json:"status"`
+	Instance  string            `json:"instance"`
+	Code      string            `json:"code"`
+	Message   string            `json:"message"`
+	Details   map[string]string `json:"details,omitempty"`
+	RequestID string            `json:"requestId,omitempty"`
+}
+
+// NewProblem describes the error as a Problem in the language of the translator. Errors that aren't from the catalogue
+// are sent as they were before it: fiber errors, like the 404 of unknown routes and the 405 of wrong methods, keep their
+// status and message, and any other error is an Internal.
+func NewProblem(err error, instance string, requestID string, trans ut.Translator) Problem {
+	var e *Error
+	if !errors.As(err, &e) {
+		e = Internal
+
+		var fiberError *fiber.Error
+		if errors.As(err, &fiberError) {
+			e = fromStatus(fiberError.Code).withMessage(fiberError.Message)
+		}
+	}
+	e = e.Localize(trans)
+
+	return Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(e.Status),
+		Status:    e.Status,
+		Instance:  instance,
+		Code:      e.Code,
+		Message:   e.Message,
+		Details:   e.Details,
+		RequestID: requestID,
+	}
+}
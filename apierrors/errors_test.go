@@ -0,0 +1,212 @@
+package apierrors
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/VinOfSteel/cinemagrader/i18n"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+var codeRegex = regexp.MustCompile(`^[a-z]+\.[a-z_]+$`)
+
+func Test_Catalogue(t *testing.T) {
+	all := All()
+	assert.NotEmpty(t, all, "Catalogue should have errors")
+
+	for _, e := range all {
+		assert.Regexp(t, codeRegex, e.Code, "Codes should be a domain and a reason in snake case")
+		assert.GreaterOrEqual(t, e.Status, http.StatusBadRequest, "Error %s should have an error status", e.Code)
+		assert.NotEmpty(t, e.Message, "Error %s should have a message", e.Code)
+		assert.Empty(t, e.Details, "Error %s of the catalogue shouldn't have details", e.Code)
+	}
+
+	assert.Panics(t, func() { newError(http.StatusNotFound, MovieNotFound.Code, "Duplicate") }, "Codes can't be reused")
+}
+
+func Test_ErrorMatching(t *testing.T) {
+	withDetails := ValidationFailed.WithDetails(map[string]string{"email": "The email field is required."})
+
+	assert.Empty(t, ValidationFailed.Details, "WithDetails shouldn't change the catalogue error")
+	assert.ErrorIs(t, withDetails, ValidationFailed, "Copies should match the catalogue error")
+	assert.NotErrorIs(t, withDetails, InvalidBody, "Errors shouldn't match other codes")
+
+	var fiberError *fiber.Error
+	if assert.ErrorAs(t, MovieNotFound, &fiberError, "Errors should be read as fiber errors") {
+		assert.Equal(t, http.StatusNotFound, fiberError.Code, "Status mismatch")
+		assert.Equal(t, MovieNotFound.Message, fiberError.Message, "Message mismatch")
+	}
+}
+
+func Test_ErrorFunctions(t *testing.T) {
+	until := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	testCases := []struct {
+		description     string
+		err             *Error
+		expectedCode    string
+		expectedMessage string
+		expectedDetails map[string]string
+	}{
+		{
+			description:     "Invalid query param",
+			err:             InvalidQueryParam("year", WholeNumberBetween, "1", "9999"),
+			expectedCode:    "request.invalid_query_param",
+			expectedMessage: "Query param year needs to be a whole number between 1 and 9999",
+			expectedDetails: map[string]string{"param": "year"},
+		},
+		{
+			description:     "Not owner",
+			err:             NotOwner("list"),
+			expectedCode:    "auth.not_owner",
+			expectedMessage: "This route is only accessible to administrators or by the owner of the list",
+			expectedDetails: map[string]string{"resource": "list"},
+		},
+		{
+			description:     "Temporary ban",
+			err:             UserBannedUntil(until),
+			expectedCode:    "auth.user_banned",
+			expectedMessage: "User is banned until 2030-01-02T03:04:05Z",
+			expectedDetails: map[string]string{"bannedUntil": "2030-01-02T03:04:05Z"},
+		},
+		{
+			description:     "Reply too deep",
+			err:             ReplyTooDeep(3),
+			expectedCode:    "comment.reply_too_deep",
+			expectedMessage: "Replies can't be nested deeper than 3 levels",
+			expectedDetails: map[string]string{"maxDepth": "3"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			assert.Equal(t, testCase.expectedCode, testCase.err.Code, "Code mismatch")
+			assert.Equal(t, testCase.expectedMessage, testCase.err.Message, "Message mismatch")
+			assert.Equal(t, testCase.expectedDetails, testCase.err.Details, "Details mismatch")
+		})
+	}
+}
+
+func Test_NewProblem(t *testing.T) {
+	testCases := []struct {
+		description     string
+		err             error
+		expectedStatus  int
+		expectedCode    string
+		expectedMessage string
+	}{
+		{
+			description:     "Catalogue error",
+			err:             MovieNotFound,
+			expectedStatus:  http.StatusNotFound,
+			expectedCode:    "movie.not_found",
+			expectedMessage: "Movie id not found in database",
+		},
+		{
+			description:     "Unknown route",
+			err:             fiber.NewError(http.StatusNotFound, "Cannot GET /unknown"),
+			expectedStatus:  http.StatusNotFound,
+			expectedCode:    "request.route_not_found",
+			expectedMessage: "Cannot GET /unknown",
+		},
+		{
+			description:     "Fiber error without its own code",
+			err:             fiber.ErrRequestTimeout,
+			expectedStatus:  http.StatusRequestTimeout,
+			expectedCode:    "request.failed",
+			expectedMessage: "Request Timeout",
+		},
+		{
+			description:     "Error that isn't from the catalogue",
+			err:             errors.New("pq: connection refused"),
+			expectedStatus:  http.StatusInternalServerError,
+			expectedCode:    "internal.unknown",
+			expectedMessage: "Unknown error",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			problem := NewProblem(testCase.err, "/movies", "request-id", i18n.Translator(i18n.English))
+
+			assert.Equal(t, "about:blank", problem.Type, "Type mismatch")
+			assert.Equal(t, http.StatusText(testCase.expectedStatus), problem.Title, "Title mismatch")
+			assert.Equal(t, testCase.expectedStatus, problem.Status, "Status mismatch")
+			assert.Equal(t, testCase.expectedCode, problem.Code, "Code mismatch")
+			assert.Equal(t, testCase.expectedMessage, problem.Message, "Message mismatch")
+			assert.Equal(t, "/movies", problem.Instance, "Instance mismatch")
+			assert.Equal(t, "request-id", problem.RequestID, "Request id mismatch")
+		})
+	}
+}
+
+func Test_Localize(t *testing.T) {
+	portuguese := i18n.Translator(i18n.BrazilianPortuguese)
+	fieldMessages := func(trans ut.Translator) map[string]string {
+		return map[string]string{"email": i18n.T(trans, "auth.invalid_credentials")}
+	}
+
+	testCases := []struct {
+		description     string
+		err             *Error
+		expectedMessage string
+		expectedDetails map[string]string
+	}{
+		{
+			description:     "Catalogue error",
+			err:             MovieNotFound,
+			expectedMessage: "Id do filme não encontrado no banco de dados",
+		},
+		{
+			description:     "Error built by a function",
+			err:             InvalidQueryParam("status", OneOf, "visible, pending, hidden"),
+			expectedMessage: "O parâmetro de query status precisa ser um de: visible, pending, hidden",
+			expectedDetails: map[string]string{"param": "status"},
+		},
+		{
+			description:     "Localized details",
+			err:             ValidationFailed.WithLocalizedDetails(fieldMessages),
+			expectedMessage: "Falha na validação",
+			expectedDetails: map[string]string{"email": "Email/senha inválidos"},
+		},
+		{
+			description:     "Message that isn't in the catalogues",
+			err:             RouteNotFound.withMessage("Cannot GET /unknown"),
+			expectedMessage: "Cannot GET /unknown",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			localized := testCase.err.Localize(portuguese)
+
+			assert.Equal(t, testCase.expectedMessage, localized.Message, "Message mismatch")
+			assert.Equal(t, testCase.expectedDetails, localized.Details, "Details mismatch")
+			assert.Equal(t, testCase.err.Code, localized.Code, "Localizing shouldn't change the code")
+		})
+	}
+
+	assert.Equal(t, map[string]string{"email": "Invalid email/password"}, ValidationFailed.WithLocalizedDetails(fieldMessages).Details, "Details should be in English before localizing")
+}
+
+var placeholderRegex = regexp.MustCompile(`\{\d+\}`)
+
+func Test_Messages(t *testing.T) {
+	english := englishMessages()
+
+	for key, message := range english {
+		translated, found := brazilianPortugueseMessages[key]
+		if assert.True(t, found, "Message %s should be translated", key) {
+			assert.ElementsMatch(t, placeholderRegex.FindAllString(message, -1), placeholderRegex.FindAllString(translated, -1), "Message %s should have the same placeholders", key)
+		}
+	}
+
+	for key := range brazilianPortugueseMessages {
+		assert.Contains(t, english, key, "Translated message %s should exist in English", key)
+	}
+}
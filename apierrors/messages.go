@@ -0,0 +1,127 @@
+package apierrors
+
+import "github.com/VinOfSteel/cinemagrader/i18n"
+
+// Brazilian Portuguese messages of the catalogue and of the errors built by functions, keyed like the English ones
+var brazilianPortugueseMessages = map[string]string{
+	"internal.unknown":                                 "Erro desconhecido",
+	"request.failed":                                   "Requisição inválida",
+	"request.route_not_found":                          "Rota não encontrada",
+	"request.method_not_allowed":                       "Método não permitido",
+	"request.body_too_large":                           "Corpo da requisição grande demais",
+	"request.invalid_uuid":                             "Parâmetro uuid inválido",
+	"request.invalid_body":                             "Erro ao ler o corpo JSON, verifique sua requisição",
+	"request.validation_failed":                        "Falha na validação",
+	"request.invalid_query_param":                      "Parâmetro de query inválido",
+	"request.invalid_query_param.date":                 "O parâmetro de query {0} precisa ser uma data válida no formato AAAA-MM-DD",
+	"request.invalid_query_param.number":               "O parâmetro de query {0} precisa ser um número válido",
+	"request.invalid_query_param.bool":                 "O parâmetro de query {0} precisa ser true ou false",
+	"request.invalid_query_param.uuid":                 "O parâmetro de query {0} precisa ser um uuid válido",
+	"request.invalid_query_param.one_of":               "O parâmetro de query {0} precisa ser um de: {1}",
+	"request.invalid_query_param.whole_number_between": "O parâmetro de query {0} precisa ser um número inteiro entre {1} e {2}",
+	"request.invalid_query_param.days_between":         "O parâmetro de query {0} precisa ser um número inteiro de dias entre {1} e {2}",
+
+	"pagination.invalid_offset":       "O offset precisa ser um número inteiro válido",
+	"pagination.invalid_limit":        "O limit precisa ser um número inteiro válido",
+	"pagination.negative":             "Offset e limit não podem ser negativos",
+	"pagination.invalid_cursor":       "Cursor de paginação inválido",
+	"pagination.cursor_sort_mismatch": "O cursor de paginação foi criado para outra ordenação, verifique sua requisição",
+
+	"auth.missing_header":          "Header Authorization ausente",
+	"auth.invalid_header":          "Formato do header Authorization inválido",
+	"auth.invalid_token":           "Token inválido ou inexistente",
+	"auth.token_expired":           "O token expirou, faça login novamente",
+	"auth.session_revoked":         "A sessão foi encerrada, faça login novamente",
+	"auth.invalid_refresh_token":   "Refresh token inválido ou expirado, faça login novamente",
+	"auth.invalid_credentials":     "Email/senha inválidos",
+	"auth.invalid_reset_token":     "Token de redefinição de senha inválido ou expirado",
+	"auth.password_reset_required": "É preciso redefinir a senha, defina uma nova com POST /password-reset",
+	"auth.permission_denied":       "O usuário não tem permissão para acessar esta rota",
+	"auth.not_self":                "Esta rota só pode ser acessada pelo usuário com o mesmo id do parâmetro ou por usuários com permissão",
+	"auth.not_owner":               "Esta rota só pode ser acessada por administradores ou pelo dono do recurso ({0})",
+	"auth.user_banned":             "O usuário está banido",
+	"auth.user_banned.until":       "O usuário está banido até {0}",
+	"user.not_found":               "Id do usuário não encontrado no banco de dados",
+	"user.email_taken":             "Já existe um usuário com este email",
+	"user.deleted":                 "Tentando agir como um usuário removido, verifique sua requisição",
+	"role.invalid":                 "O papel precisa ser um de: admin, moderator, curator",
+	"role.already_granted":         "O usuário já tem o papel",
+	"role.not_granted":             "O usuário não tem o papel",
+	"admin.self_demotion":          "Administradores não podem retirar o próprio papel de admin",
+	"admin.self_ban":               "Administradores não podem banir a si mesmos",
+	"admin.ban_expiry_in_past":     "O fim do banimento precisa ser no futuro",
+	"admin.user_not_banned":        "O usuário não está banido",
+	"follow.self":                  "Usuários não podem seguir a si mesmos",
+	"follow.already_followed":      "O usuário já é seguido",
+	"follow.not_followed":          "O usuário não é seguido",
+
+	"movie.not_found":             "Id do filme não encontrado no banco de dados",
+	"movie.title_taken":           "Já existe um filme com este título",
+	"movie.invalid_id":            "Uuid do filme inválido",
+	"movie.actor_already_cast":    "Um dos atores da requisição já está no filme",
+	"movie.actor_not_cast":        "Tentando remover um ator que já não está no filme",
+	"movie.casting_not_found":     "O ator não está no elenco do filme",
+	"movie.empty_casting_update":  "Envie um characterName ou um billingOrder para atualizar a escalação",
+	"movie.deleted_cast":          "Tentando adicionar atores a um filme removido, verifique sua requisição",
+	"movie.genre_already_added":   "Um dos gêneros da requisição já está no filme",
+	"movie.genre_not_added":       "Tentando remover um gênero que já não está no filme",
+	"movie.deleted_genres":        "Tentando adicionar gêneros a um filme removido, verifique sua requisição",
+	"movie.crew_already_credited": "Uma das pessoas da requisição já está na equipe do filme com o mesmo papel",
+	"movie.crew_not_credited":     "Tentando remover uma pessoa que já não está na equipe do filme com este papel",
+	"movie.deleted_crew":          "Tentando adicionar pessoas à equipe de um filme removido, verifique sua requisição",
+	"actor.not_found":             "Id do ator não encontrado no banco de dados",
+	"genre.not_found":             "Id do gênero não encontrado no banco de dados",
+	"genre.name_taken":            "Já existe um gênero com este nome",
+	"person.not_found":            "Id da pessoa não encontrado no banco de dados",
+	"search.empty_query":          "A busca precisa ter pelo menos uma palavra",
+	"search.invalid_type":         "O tipo precisa ser movie ou actor",
+	"watchlist.already_added":     "O filme já está na watchlist",
+	"watchlist.not_added":         "O filme não está na watchlist",
+	"watchlist.deleted_movie":     "Tentando adicionar um filme removido, verifique sua requisição",
+	"diary.not_found":             "Id da entrada do diário não encontrado no banco de dados",
+	"diary.invalid_review":        "A avaliação precisa ser um comentário do usuário no filme da entrada do diário",
+	"list.not_found":              "Id da lista não encontrado no banco de dados",
+	"list.movie_already_added":    "O filme já está na lista",
+	"list.movie_not_added":        "O filme não está na lista",
+	"list.invalid_order":          "A nova ordem precisa ter cada filme da lista exatamente uma vez",
+
+	"comment.not_found":                "Id do comentário não encontrado no banco de dados",
+	"comment.parent_not_found":         "Id do comentário respondido não encontrado no banco de dados",
+	"comment.deleted_user":             "Tentando comentar como um usuário removido, verifique sua requisição",
+	"comment.deleted_movie":            "Tentando avaliar um filme removido, verifique sua requisição",
+	"comment.deleted_parent":           "Tentando responder um comentário removido, verifique sua requisição",
+	"comment.already_reviewed":         "O usuário já avaliou este filme, use PUT /movies/:uuid/review para atualizar a avaliação",
+	"comment.reply_movie_mismatch":     "A resposta precisa ser no mesmo filme do comentário respondido",
+	"comment.reply_with_grade":         "Respostas não podem ter nota",
+	"comment.reply_too_deep":           "Respostas não podem passar de {0} níveis de profundidade",
+	"comment.already_liked":            "O comentário já foi curtido",
+	"comment.not_liked":                "O comentário não foi curtido",
+	"comment.already_reported":         "O comentário já foi denunciado pelo usuário",
+	"comment.self_report":              "Usuários não podem denunciar os próprios comentários",
+	"comment.user_banned":              "Usuários banidos não podem comentar",
+	"moderation.ban_permission_denied": "O usuário não tem permissão para banir usuários",
+}
+
+// englishMessages are the messages of the catalogue and of the errors built by functions
+func englishMessages() map[string]string {
+	messages := map[string]string{}
+	for code, e := range catalogue {
+		messages[code] = e.Message
+	}
+
+	for key, message := range functionMessages {
+		messages[key] = message
+	}
+
+	return messages
+}
+
+func init() {
+	if err := i18n.Add(i18n.Translator(i18n.English), englishMessages()); err != nil {
+		panic(err)
+	}
+
+	if err := i18n.Add(i18n.Translator(i18n.BrazilianPortuguese), brazilianPortugueseMessages); err != nil {
+		panic(err)
+	}
+}
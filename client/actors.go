@@ -0,0 +1,37 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/google/uuid"
+)
+
+type ActorsService struct {
+	client *Client
+}
+
+func (s *ActorsService) Create(ctx context.Context, actor models.ActorBody) (models.ActorResponse, error) {
+	return call[models.ActorResponse](ctx, s.client, http.MethodPost, "/actors", nil, actor)
+}
+
+func (s *ActorsService) List(ctx context.Context, options *ActorListOptions) (models.Page[models.ActorResponse], error) {
+	return call[models.Page[models.ActorResponse]](ctx, s.client, http.MethodGet, "/actors", options.values(), nil)
+}
+
+func (s *ActorsService) Get(ctx context.Context, id uuid.UUID) (models.ActorResponse, error) {
+	return call[models.ActorResponse](ctx, s.client, http.MethodGet, "/actors/"+id.String(), nil, nil)
+}
+
+func (s *ActorsService) Movies(ctx context.Context, id uuid.UUID) (models.ActorResponseWithMovies, error) {
+	return call[models.ActorResponseWithMovies](ctx, s.client, http.MethodGet, "/actors/"+id.String()+"/movies", nil, nil)
+}
+
+func (s *ActorsService) Update(ctx context.Context, id uuid.UUID, actor models.ActorEditBody) (models.ActorResponse, error) {
+	return call[models.ActorResponse](ctx, s.client, http.MethodPatch, "/actors/"+id.String(), nil, actor)
+}
+
+func (s *ActorsService) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.client.do(ctx, http.MethodDelete, "/actors/"+id.String(), nil, nil, nil)
+}
@@ -0,0 +1,42 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/VinOfSteel/cinemagrader/controllers"
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/google/uuid"
+)
+
+type AdminService struct {
+	client *Client
+}
+
+// Promote grants one of the models.Role* roles to the user
+func (s *AdminService) Promote(ctx context.Context, userId uuid.UUID, role string) (models.UserRoles, error) {
+	return call[models.UserRoles](ctx, s.client, http.MethodPost, "/admin/users/"+userId.String()+"/roles", nil, models.RoleBody{Role: role})
+}
+
+// Demote revokes one of the models.Role* roles from the user
+func (s *AdminService) Demote(ctx context.Context, userId uuid.UUID, role string) (models.UserRoles, error) {
+	return call[models.UserRoles](ctx, s.client, http.MethodDelete, "/admin/users/"+userId.String()+"/roles/"+role, nil, nil)
+}
+
+// Ban bans the user, until ban.Until or for good when it is nil
+func (s *AdminService) Ban(ctx context.Context, userId uuid.UUID, ban models.BanBody) (models.UserResponse, error) {
+	return call[models.UserResponse](ctx, s.client, http.MethodPost, "/admin/users/"+userId.String()+"/ban", nil, ban)
+}
+
+func (s *AdminService) Unban(ctx context.Context, userId uuid.UUID) (models.UserResponse, error) {
+	return call[models.UserResponse](ctx, s.client, http.MethodDelete, "/admin/users/"+userId.String()+"/ban", nil, nil)
+}
+
+// ForcePasswordReset logs the user out everywhere and returns the token they need to set a new password with Sessions.ResetPassword
+func (s *AdminService) ForcePasswordReset(ctx context.Context, userId uuid.UUID) (controllers.PasswordResetResponse, error) {
+	return call[controllers.PasswordResetResponse](ctx, s.client, http.MethodPost, "/admin/users/"+userId.String()+"/password-reset", nil, nil)
+}
+
+func (s *AdminService) AuditLog(ctx context.Context, options *AuditLogOptions) (models.Page[models.AuditLogModel], error) {
+	return call[models.Page[models.AuditLogModel]](ctx, s.client, http.MethodGet, "/admin/audit-log", options.values(), nil)
+}
@@ -0,0 +1,265 @@
+// Package client is a typed client of the Cinema Grader API. It sends and receives the same models the server uses,
+// keeps the session of the logged user, refreshing its token when it expires, and turns error responses into *APIError
+// and *ValidationError.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/VinOfSteel/cinemagrader/controllers"
+)
+
+// Client calls the API at its base URL. The routes are grouped in services, like the controllers of the server.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	language   string
+
+	// mu guards the session, refreshMu makes concurrent requests that got a 401 refresh it only once
+	mu             sync.Mutex
+	refreshMu      sync.Mutex
+	token          string
+	tokenExpiresAt time.Time
+	refreshToken   string
+
+	Sessions   *SessionsService
+	Users      *UsersService
+	Watchlist  *WatchlistService
+	Diary      *DiaryService
+	Lists      *ListsService
+	Follows    *FollowsService
+	Actors     *ActorsService
+	Movies     *MoviesService
+	Comments   *CommentsService
+	Moderation *ModerationService
+	Admin      *AdminService
+	Search     *SearchService
+	Genres     *GenresService
+	People     *PeopleService
+}
+
+type Option func(*Client)
+
+// WithHTTPClient sends the requests with the http client, instead of http.DefaultClient
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTokens resumes a session saved with Tokens, without logging in again
+func WithTokens(token string, refreshToken string) Option {
+	return func(c *Client) {
+		c.token = token
+		c.refreshToken = refreshToken
+	}
+}
+
+// WithLanguage asks for the error messages in the language, sent as the Accept-Language header (like "pt-BR")
+func WithLanguage(language string) Option {
+	return func(c *Client) {
+		c.language = language
+	}
+}
+
+func New(baseURL string, options ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: http.DefaultClient,
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	c.Sessions = &SessionsService{client: c}
+	c.Users = &UsersService{client: c}
+	c.Watchlist = &WatchlistService{client: c}
+	c.Diary = &DiaryService{client: c}
+	c.Lists = &ListsService{client: c}
+	c.Follows = &FollowsService{client: c}
+	c.Actors = &ActorsService{client: c}
+	c.Movies = &MoviesService{client: c}
+	c.Comments = &CommentsService{client: c}
+	c.Moderation = &ModerationService{client: c}
+	c.Admin = &AdminService{client: c}
+	c.Search = &SearchService{client: c}
+	c.Genres = &GenresService{client: c}
+	c.People = &PeopleService{client: c}
+
+	return c
+}
+
+// Tokens returns the access and refresh tokens of the current session, which are empty when logged out
+func (c *Client) Tokens() (string, string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.token, c.refreshToken
+}
+
+func (c *Client) setSession(session controllers.LoginResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.token = session.Token
+	c.tokenExpiresAt = session.TokenExpiresAt
+	c.refreshToken = session.RefreshToken
+}
+
+func (c *Client) clearSession() {
+	c.setSession(controllers.LoginResponse{})
+}
+
+// tokenExpired reports if the token is known to be expired, which is only the case for tokens that came from a login or refresh
+func (c *Client) tokenExpired() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.refreshToken != "" && !c.tokenExpiresAt.IsZero() && time.Now().After(c.tokenExpiresAt)
+}
+
+// do sends the request and decodes the response into out, unless out is nil or the response has no content.
+// Requests rejected with 401 are sent again once, after refreshing the session, when there is a refresh token.
+func (c *Client) do(ctx context.Context, method string, path string, query url.Values, body any, out any) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	if c.tokenExpired() {
+		token, _ := c.Tokens()
+		if err := c.refreshSession(ctx, token); err != nil {
+			return err
+		}
+	}
+
+	response, token, err := c.send(ctx, method, path, query, payload)
+	if err != nil {
+		return err
+	}
+
+	if _, refreshToken := c.Tokens(); response.StatusCode == http.StatusUnauthorized && refreshToken != "" {
+		response.Body.Close()
+
+		if err := c.refreshSession(ctx, token); err != nil {
+			return err
+		}
+
+		if response, _, err = c.send(ctx, method, path, query, payload); err != nil {
+			return err
+		}
+	}
+	defer response.Body.Close()
+
+	return decodeResponse(response, out)
+}
+
+// send sends the request with the current token, which is returned so a failed refresh can tell if it is still the current one
+func (c *Client) send(ctx context.Context, method string, path string, query url.Values, payload []byte) (*http.Response, string, error) {
+	endpoint := c.baseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return nil, "", err
+	}
+
+	request.Header.Set("Accept", "application/json")
+	if payload != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	if c.language != "" {
+		request.Header.Set("Accept-Language", c.language)
+	}
+
+	token, _ := c.Tokens()
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return response, token, nil
+}
+
+// refreshSession trades the refresh token for a new session, unless another request already did it since staleToken was sent
+func (c *Client) refreshSession(ctx context.Context, staleToken string) error {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	if token, _ := c.Tokens(); token != staleToken && !c.tokenExpired() {
+		return nil
+	}
+
+	_, err := c.refresh(ctx)
+	return err
+}
+
+// refresh sends the refresh token and starts the session it is traded for. Callers hold refreshMu.
+func (c *Client) refresh(ctx context.Context) (controllers.LoginResponse, error) {
+	_, refreshToken := c.Tokens()
+	payload, err := json.Marshal(controllers.RefreshBody{RefreshToken: refreshToken})
+	if err != nil {
+		return controllers.LoginResponse{}, err
+	}
+
+	response, _, err := c.send(ctx, http.MethodPost, "/refresh", nil, payload)
+	if err != nil {
+		return controllers.LoginResponse{}, err
+	}
+	defer response.Body.Close()
+
+	var session controllers.LoginResponse
+	if err := decodeResponse(response, &session); err != nil {
+		// The refresh token was rejected, so the session is over and only a new login brings it back
+		if StatusCode(err) == http.StatusUnauthorized {
+			c.clearSession()
+		}
+
+		return controllers.LoginResponse{}, err
+	}
+
+	c.setSession(session)
+	return session, nil
+}
+
+func decodeResponse(response *http.Response, out any) error {
+	if response.StatusCode >= http.StatusBadRequest {
+		return decodeError(response)
+	}
+
+	if out == nil || response.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	return json.NewDecoder(response.Body).Decode(out)
+}
+
+// call sends the request and decodes the response into a T, for the routes that answer with a body
+func call[T any](ctx context.Context, c *Client, method string, path string, query url.Values, body any) (T, error) {
+	var out T
+	err := c.do(ctx, method, path, query, body, &out)
+	return out, err
+}
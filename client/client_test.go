@@ -0,0 +1,334 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/VinOfSteel/cinemagrader/apierrors"
+	"github.com/VinOfSteel/cinemagrader/controllers"
+	"github.com/VinOfSteel/cinemagrader/i18n"
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/VinOfSteel/cinemagrader/server"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// Every route of the server needs a method in the client. The routes are served by a stub with the same paths,
+// which records the ones the client called.
+func Test_ClientCoversRoutes(t *testing.T) {
+	routesApp := fiber.New()
+	server.RegisterRoutes(routesApp, nil, nil, nil)
+
+	var mu sync.Mutex
+	called := map[string]bool{}
+	routes := map[string]bool{}
+
+	stub := fiber.New()
+	for _, route := range routesApp.GetRoutes(true) {
+		if route.Method == fiber.MethodHead {
+			continue
+		}
+
+		key := route.Method + " " + route.Path
+		routes[key] = true
+		stub.Add(route.Method, route.Path, func(c *fiber.Ctx) error {
+			mu.Lock()
+			called[key] = true
+			mu.Unlock()
+
+			return c.SendStatus(fiber.StatusNoContent)
+		})
+	}
+
+	testServer := httptest.NewServer(adaptor.FiberApp(stub))
+	defer testServer.Close()
+
+	ctx := context.Background()
+	c := New(testServer.URL)
+	id := uuid.New()
+	calls := []error{
+		ignore(c.Sessions.Login(ctx, "user@teste.com", "password")),
+		ignore(c.Sessions.Refresh(ctx)),
+		c.Sessions.Logout(ctx),
+		c.Sessions.LogoutAll(ctx),
+		c.Sessions.ResetPassword(ctx, "token", "password"),
+
+		ignore(c.Users.Create(ctx, models.UserBody{})),
+		ignore(c.Users.List(ctx, nil)),
+		ignore(c.Users.Get(ctx, id)),
+		ignore(c.Users.Comments(ctx, id, nil)),
+		c.Users.Delete(ctx, id),
+		ignore(c.Users.Update(ctx, id, models.UserEditBody{})),
+
+		ignore(c.Watchlist.Add(ctx, id, models.WatchlistBody{})),
+		ignore(c.Watchlist.Get(ctx, id, nil)),
+		c.Watchlist.Remove(ctx, id, id),
+		ignore(c.Diary.Create(ctx, id, models.DiaryBody{})),
+		ignore(c.Diary.Get(ctx, id, nil)),
+		ignore(c.Diary.Stats(ctx, id, 0)),
+		ignore(c.Diary.Update(ctx, id, id, models.DiaryEditBody{})),
+		c.Diary.Delete(ctx, id, id),
+
+		ignore(c.Lists.Create(ctx, id, models.ListBody{})),
+		ignore(c.Lists.UserLists(ctx, id, nil)),
+		ignore(c.Lists.List(ctx, nil)),
+		ignore(c.Lists.Get(ctx, id)),
+		ignore(c.Lists.Update(ctx, id, models.ListEditBody{})),
+		c.Lists.Delete(ctx, id),
+		ignore(c.Lists.AddMovie(ctx, id, models.ListItemBody{})),
+		c.Lists.RemoveMovie(ctx, id, id),
+		ignore(c.Lists.Reorder(ctx, id, []string{id.String()})),
+		ignore(c.Lists.Fork(ctx, id)),
+
+		c.Follows.Follow(ctx, id),
+		c.Follows.Unfollow(ctx, id),
+		ignore(c.Follows.Followers(ctx, id, nil)),
+		ignore(c.Follows.Following(ctx, id, nil)),
+		ignore(c.Follows.Feed(ctx, nil)),
+
+		ignore(c.Actors.Create(ctx, models.ActorBody{})),
+		ignore(c.Actors.List(ctx, nil)),
+		ignore(c.Actors.Get(ctx, id)),
+		ignore(c.Actors.Movies(ctx, id)),
+		c.Actors.Delete(ctx, id),
+		ignore(c.Actors.Update(ctx, id, models.ActorEditBody{})),
+
+		ignore(c.Movies.Create(ctx, models.MovieBody{})),
+		ignore(c.Movies.RecomputeStats(ctx)),
+		c.Movies.AddActors(ctx, id, models.MovieActorsBody{}),
+		ignore(c.Movies.List(ctx, nil)),
+		ignore(c.Movies.ListWithActors(ctx, nil)),
+		ignore(c.Movies.Top(ctx, nil)),
+		ignore(c.Movies.Trending(ctx, nil)),
+		ignore(c.Movies.Get(ctx, id)),
+		ignore(c.Movies.Comments(ctx, id, nil)),
+		c.Movies.Delete(ctx, id),
+		c.Movies.RemoveActors(ctx, id, models.MovieActorsBody{}),
+		ignore(c.Movies.UpdateCasting(ctx, id, id, models.CastingEditBody{})),
+		c.Movies.AddGenres(ctx, id, models.MovieGenresBody{}),
+		c.Movies.RemoveGenres(ctx, id, models.MovieGenresBody{}),
+		c.Movies.AddCrew(ctx, id, models.MovieCrewBody{}),
+		c.Movies.RemoveCrew(ctx, id, models.MovieCrewBody{}),
+		ignore(c.Movies.Update(ctx, id, models.MovieEditBody{})),
+
+		ignore(c.Comments.Create(ctx, id, models.CommentBody{})),
+		ignore(c.Comments.List(ctx, nil)),
+		ignore(c.Comments.Get(ctx, id)),
+		c.Comments.Delete(ctx, id),
+		ignore(c.Comments.Update(ctx, id, models.CommentEditBody{})),
+		c.Comments.Like(ctx, id),
+		c.Comments.Unlike(ctx, id),
+		ignore(c.Comments.Report(ctx, id, "Spam")),
+		ignore(c.Comments.FlagSpoilers(ctx, id)),
+		ignore(c.Comments.UnflagSpoilers(ctx, id)),
+		ignore(c.Comments.UpsertReview(ctx, id, models.ReviewBody{})),
+
+		ignore(c.Moderation.Queue(ctx, nil)),
+		ignore(c.Moderation.Moderate(ctx, id, "approve")),
+
+		ignore(c.Admin.Promote(ctx, id, models.RoleModerator)),
+		ignore(c.Admin.Demote(ctx, id, models.RoleModerator)),
+		ignore(c.Admin.Ban(ctx, id, models.BanBody{})),
+		ignore(c.Admin.Unban(ctx, id)),
+		ignore(c.Admin.ForcePasswordReset(ctx, id)),
+		ignore(c.Admin.AuditLog(ctx, nil)),
+
+		ignore(c.Search.Query(ctx, "matrix", nil)),
+
+		ignore(c.Genres.Create(ctx, models.GenreBody{})),
+		ignore(c.Genres.List(ctx, nil)),
+		ignore(c.Genres.Get(ctx, id)),
+		ignore(c.Genres.Movies(ctx, id)),
+		c.Genres.Delete(ctx, id),
+		ignore(c.Genres.Update(ctx, id, models.GenreEditBody{})),
+
+		ignore(c.People.Create(ctx, models.PersonBody{})),
+		ignore(c.People.List(ctx, nil)),
+		ignore(c.People.Get(ctx, id)),
+		ignore(c.People.Filmography(ctx, id)),
+		c.People.Delete(ctx, id),
+		ignore(c.People.Update(ctx, id, models.PersonEditBody{})),
+	}
+
+	for i, err := range calls {
+		assert.NoError(t, err, "Call %d of the client failed", i)
+	}
+
+	for route := range routes {
+		assert.True(t, called[route], "Route %v isn't called by any method of the client", route)
+	}
+}
+
+// ignore drops the response of a client method, for the tests that only need its error
+func ignore[T any](_ T, err error) error {
+	return err
+}
+
+func Test_ListOptionsQuery(t *testing.T) {
+	minGrade := 3.5
+	genre := uuid.New()
+
+	testCases := []struct {
+		Have url.Values
+		Want string
+	}{
+		{(*ListOptions)(nil).values(), ""},
+		{(&ListOptions{Limit: 5, Sort: "name,asc", Deleted: true}).values(), "deleted=true&limit=5&sort=name%2Casc"},
+		{(&MovieListOptions{MinGrade: &minGrade, Genre: genre}).values(), "genre=" + genre.String() + "&min_grade=3.5"},
+		{(&TrendingOptions{Window: 30}).values(), "window=30"},
+		{(&MovieCommentsOptions{Threaded: true, RevealSpoilers: true}).values(), "reveal_spoilers=true&threaded=true"},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.Want, testCase.Have.Encode(), "Query mismatch")
+	}
+}
+
+func Test_ErrorResponses(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users":
+			writeProblem(w, r, apierrors.ValidationFailed.WithDetails(map[string]string{"email": "The email field needs to be a valid email."}))
+		case "/movies/top":
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte("Bad gateway\n"))
+		default:
+			writeProblem(w, r, apierrors.UserNotFound)
+		}
+	}))
+	defer testServer.Close()
+
+	ctx := context.Background()
+	c := New(testServer.URL)
+
+	_, err := c.Users.Get(ctx, uuid.New())
+	var apiError *APIError
+	if assert.ErrorAs(t, err, &apiError, "Error responses should be APIErrors") {
+		assert.Equal(t, http.StatusNotFound, apiError.StatusCode, "Status code mismatch")
+		assert.Equal(t, "User id not found in database", apiError.Message, "Message mismatch")
+		assert.Equal(t, "user.not_found", apiError.Code, "Code mismatch")
+		assert.Equal(t, "test-request", apiError.RequestID, "Request id mismatch")
+	}
+	assert.True(t, IsNotFound(err), "404 should be reported as not found")
+	assert.ErrorIs(t, err, apierrors.UserNotFound, "Errors should match the catalogue error with their code")
+	assert.NotErrorIs(t, err, apierrors.MovieNotFound, "Errors shouldn't match other catalogue errors")
+
+	_, err = New(testServer.URL, WithLanguage("pt-BR")).Users.Get(ctx, uuid.New())
+	if assert.ErrorAs(t, err, &apiError, "Error responses should be APIErrors") {
+		assert.Equal(t, "Id do usuário não encontrado no banco de dados", apiError.Message, "Message should be in the asked language")
+		assert.Equal(t, "user.not_found", apiError.Code, "Code shouldn't depend on the language")
+	}
+
+	_, err = c.Users.Create(ctx, models.UserBody{})
+	var validationError *ValidationError
+	if assert.ErrorAs(t, err, &validationError, "Responses with invalid fields should be ValidationErrors") {
+		assert.Equal(t, "Validation failed", validationError.Message, "Message mismatch")
+		assert.Equal(t, map[string]string{"email": "The email field needs to be a valid email."}, validationError.Errors, "Invalid fields mismatch")
+	}
+	assert.Equal(t, http.StatusBadRequest, StatusCode(err), "ValidationErrors should unwrap to their APIError")
+
+	_, err = c.Movies.Top(ctx, nil)
+	if assert.ErrorAs(t, err, &apiError, "Responses that aren't JSON should be APIErrors") {
+		assert.Equal(t, "Bad gateway", apiError.Message, "Plain text body should be the message")
+		assert.Empty(t, apiError.Code, "Plain text body should have no code")
+	}
+
+	assert.Equal(t, 0, StatusCode(errors.New("connection refused")), "Errors without response should have no status")
+}
+
+// writeProblem answers like the error handler of the server
+func writeProblem(w http.ResponseWriter, r *http.Request, err error) {
+	trans := i18n.Translator(i18n.English)
+	if r.Header.Get("Accept-Language") == "pt-BR" {
+		trans = i18n.Translator(i18n.BrazilianPortuguese)
+	}
+
+	problem := apierrors.NewProblem(err, r.URL.Path, "test-request", trans)
+	w.Header().Set("Content-Type", apierrors.ContentType)
+	w.WriteHeader(problem.Status)
+	json.NewEncoder(w).Encode(problem)
+}
+
+// refreshServer accepts the "fresh" token, and trades the "valid" refresh token for it
+type refreshServer struct {
+	mu        sync.Mutex
+	refreshes int
+}
+
+func (s *refreshServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	switch r.URL.Path {
+	case "/login":
+		json.NewEncoder(w).Encode(controllers.LoginResponse{Token: "stale", TokenExpiresAt: time.Now().Add(time.Hour), RefreshToken: "valid"})
+	case "/refresh":
+		var body controllers.RefreshBody
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.RefreshToken != "valid" {
+			writeProblem(w, r, apierrors.InvalidRefreshToken)
+			return
+		}
+
+		s.mu.Lock()
+		s.refreshes++
+		s.mu.Unlock()
+
+		json.NewEncoder(w).Encode(controllers.LoginResponse{Token: "fresh", TokenExpiresAt: time.Now().Add(time.Hour), RefreshToken: "rotated"})
+	default:
+		if r.Header.Get("Authorization") != "Bearer fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message":"Invalid or non-existing token"}`))
+			return
+		}
+
+		json.NewEncoder(w).Encode(models.UserResponse{Name: "Refreshed"})
+	}
+}
+
+func Test_TokenRefresh(t *testing.T) {
+	handler := &refreshServer{}
+	testServer := httptest.NewServer(handler)
+	defer testServer.Close()
+
+	ctx := context.Background()
+	c := New(testServer.URL)
+
+	if _, err := c.Sessions.Login(ctx, "user@teste.com", "password"); err != nil {
+		t.Fatalf("Error logging in: %v", err)
+	}
+
+	// Requests rejected with 401 are sent again after refreshing, once even when they run at the same time
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			user, err := c.Users.Get(ctx, uuid.New())
+			assert.NoError(t, err, "Request should succeed after the refresh")
+			assert.Equal(t, "Refreshed", user.Name, "Response after the refresh mismatch")
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, handler.refreshes, "Session should be refreshed once")
+	token, refreshToken := c.Tokens()
+	assert.Equal(t, "fresh", token, "Token should be the refreshed one")
+	assert.Equal(t, "rotated", refreshToken, "Refresh token should be the rotated one")
+
+	// A rejected refresh token ends the session
+	expired := New(testServer.URL, WithTokens("stale", "revoked"))
+	_, err := expired.Users.Get(ctx, uuid.New())
+	assert.Equal(t, http.StatusUnauthorized, StatusCode(err), "status code after rejected refresh")
+	token, refreshToken = expired.Tokens()
+	assert.Empty(t, token+refreshToken, "Session should be cleared after rejected refresh")
+}
@@ -0,0 +1,59 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/google/uuid"
+)
+
+type CommentsService struct {
+	client *Client
+}
+
+// Create comments on the movie of the body as the user
+func (s *CommentsService) Create(ctx context.Context, userId uuid.UUID, comment models.CommentBody) (models.CommentResponse, error) {
+	return call[models.CommentResponse](ctx, s.client, http.MethodPost, "/comments/"+userId.String(), nil, comment)
+}
+
+func (s *CommentsService) List(ctx context.Context, options *CommentListOptions) (models.Page[models.CommentResponse], error) {
+	return call[models.Page[models.CommentResponse]](ctx, s.client, http.MethodGet, "/comments", options.values(), nil)
+}
+
+func (s *CommentsService) Get(ctx context.Context, id uuid.UUID) (models.CommentResponse, error) {
+	return call[models.CommentResponse](ctx, s.client, http.MethodGet, "/comments/"+id.String(), nil, nil)
+}
+
+func (s *CommentsService) Update(ctx context.Context, id uuid.UUID, comment models.CommentEditBody) (models.CommentResponse, error) {
+	return call[models.CommentResponse](ctx, s.client, http.MethodPatch, "/comments/"+id.String(), nil, comment)
+}
+
+func (s *CommentsService) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.client.do(ctx, http.MethodDelete, "/comments/"+id.String(), nil, nil, nil)
+}
+
+func (s *CommentsService) Like(ctx context.Context, id uuid.UUID) error {
+	return s.client.do(ctx, http.MethodPost, "/comments/"+id.String()+"/like", nil, nil, nil)
+}
+
+func (s *CommentsService) Unlike(ctx context.Context, id uuid.UUID) error {
+	return s.client.do(ctx, http.MethodDelete, "/comments/"+id.String()+"/like", nil, nil, nil)
+}
+
+func (s *CommentsService) Report(ctx context.Context, id uuid.UUID, reason string) (models.ReportResponse, error) {
+	return call[models.ReportResponse](ctx, s.client, http.MethodPost, "/comments/"+id.String()+"/report", nil, models.ReportBody{Reason: reason})
+}
+
+func (s *CommentsService) FlagSpoilers(ctx context.Context, id uuid.UUID) (models.CommentResponse, error) {
+	return call[models.CommentResponse](ctx, s.client, http.MethodPost, "/comments/"+id.String()+"/spoiler", nil, nil)
+}
+
+func (s *CommentsService) UnflagSpoilers(ctx context.Context, id uuid.UUID) (models.CommentResponse, error) {
+	return call[models.CommentResponse](ctx, s.client, http.MethodDelete, "/comments/"+id.String()+"/spoiler", nil, nil)
+}
+
+// UpsertReview creates the review of the logged user on the movie, or updates it when there is one already
+func (s *CommentsService) UpsertReview(ctx context.Context, movieId uuid.UUID, review models.ReviewBody) (models.CommentResponse, error) {
+	return call[models.CommentResponse](ctx, s.client, http.MethodPut, "/movies/"+movieId.String()+"/review", nil, review)
+}
@@ -0,0 +1,38 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/google/uuid"
+)
+
+type DiaryService struct {
+	client *Client
+}
+
+func (s *DiaryService) Create(ctx context.Context, userId uuid.UUID, entry models.DiaryBody) (models.DiaryEntryResponse, error) {
+	return call[models.DiaryEntryResponse](ctx, s.client, http.MethodPost, "/users/"+userId.String()+"/diary", nil, entry)
+}
+
+func (s *DiaryService) Get(ctx context.Context, userId uuid.UUID, options *DiaryOptions) (models.Page[models.DiaryEntryResponse], error) {
+	return call[models.Page[models.DiaryEntryResponse]](ctx, s.client, http.MethodGet, "/users/"+userId.String()+"/diary", options.values(), nil)
+}
+
+// Stats gets the stats of the diary in the year, or in the current one when year is 0
+func (s *DiaryService) Stats(ctx context.Context, userId uuid.UUID, year int) (models.DiaryStatsResponse, error) {
+	query := url.Values{}
+	setInt(query, "year", year)
+
+	return call[models.DiaryStatsResponse](ctx, s.client, http.MethodGet, "/users/"+userId.String()+"/diary/stats", query, nil)
+}
+
+func (s *DiaryService) Update(ctx context.Context, userId uuid.UUID, entryId uuid.UUID, entry models.DiaryEditBody) (models.DiaryEntryResponse, error) {
+	return call[models.DiaryEntryResponse](ctx, s.client, http.MethodPatch, "/users/"+userId.String()+"/diary/"+entryId.String(), nil, entry)
+}
+
+func (s *DiaryService) Delete(ctx context.Context, userId uuid.UUID, entryId uuid.UUID) error {
+	return s.client.do(ctx, http.MethodDelete, "/users/"+userId.String()+"/diary/"+entryId.String(), nil, nil, nil)
+}
@@ -0,0 +1,102 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/VinOfSteel/cinemagrader/apierrors"
+)
+
+// APIError is an error response of the server, read from the apierrors.Problem sent by its error handler
+type APIError struct {
+	StatusCode int
+	// Code is the stable code of the error in the apierrors catalogue, empty when the response wasn't a problem
+	Code      string
+	Message   string
+	Details   map[string]string
+	RequestID string
+}
+
+func (e *APIError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("cinemagrader: %d %s", e.StatusCode, e.Message)
+	}
+
+	return fmt.Sprintf("cinemagrader: %d %s (%s)", e.StatusCode, e.Message, e.Code)
+}
+
+// Is matches the errors of the catalogue by code, so errors.Is(err, apierrors.MovieNotFound) tells which error the server sent
+func (e *APIError) Is(target error) bool {
+	var catalogueError *apierrors.Error
+	return errors.As(target, &catalogueError) && catalogueError.Code == e.Code
+}
+
+// ValidationError is sent when the body of a request fails validation, with the message of each invalid field.
+// It unwraps to its APIError, so errors.As finds either of them.
+type ValidationError struct {
+	APIError
+	Errors map[string]string
+}
+
+func (e *ValidationError) Error() string {
+	fields := make([]string, 0, len(e.Errors))
+	for field, message := range e.Errors {
+		fields = append(fields, field+": "+message)
+	}
+
+	return e.APIError.Error() + " (" + strings.Join(fields, "; ") + ")"
+}
+
+func (e *ValidationError) Unwrap() error {
+	return &e.APIError
+}
+
+// StatusCode returns the status of the response that caused the error, or 0 when the error didn't come from a response
+func StatusCode(err error) int {
+	var apiError *APIError
+	if errors.As(err, &apiError) {
+		return apiError.StatusCode
+	}
+
+	return 0
+}
+
+// IsNotFound reports if the error is a 404, like the ones sent for ids that don't exist
+func IsNotFound(err error) bool {
+	return StatusCode(err) == http.StatusNotFound
+}
+
+// decodeError reads the error body, which is an apierrors.Problem, with the invalid fields in the details on validation errors.
+// Bodies that aren't JSON, like the ones of proxies in front of the API, become the message as they are.
+func decodeError(response *http.Response) error {
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	var problem apierrors.Problem
+
+	apiError := APIError{StatusCode: response.StatusCode}
+	if err := json.Unmarshal(body, &problem); err == nil && problem.Message != "" {
+		apiError.Code = problem.Code
+		apiError.Message = problem.Message
+		apiError.Details = problem.Details
+		apiError.RequestID = problem.RequestID
+	} else {
+		apiError.Message = strings.TrimSpace(string(body))
+	}
+
+	if apiError.Message == "" {
+		apiError.Message = http.StatusText(response.StatusCode)
+	}
+
+	if apiError.Code == apierrors.ValidationFailed.Code {
+		return &ValidationError{APIError: apiError, Errors: apiError.Details}
+	}
+
+	return &apiError
+}
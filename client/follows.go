@@ -0,0 +1,35 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/google/uuid"
+)
+
+type FollowsService struct {
+	client *Client
+}
+
+// Follow makes the logged user follow the user
+func (s *FollowsService) Follow(ctx context.Context, userId uuid.UUID) error {
+	return s.client.do(ctx, http.MethodPost, "/users/"+userId.String()+"/follow", nil, nil, nil)
+}
+
+func (s *FollowsService) Unfollow(ctx context.Context, userId uuid.UUID) error {
+	return s.client.do(ctx, http.MethodDelete, "/users/"+userId.String()+"/follow", nil, nil, nil)
+}
+
+func (s *FollowsService) Followers(ctx context.Context, userId uuid.UUID, options *ListOptions) (models.Page[models.FollowResponse], error) {
+	return call[models.Page[models.FollowResponse]](ctx, s.client, http.MethodGet, "/users/"+userId.String()+"/followers", options.values(), nil)
+}
+
+func (s *FollowsService) Following(ctx context.Context, userId uuid.UUID, options *ListOptions) (models.Page[models.FollowResponse], error) {
+	return call[models.Page[models.FollowResponse]](ctx, s.client, http.MethodGet, "/users/"+userId.String()+"/following", options.values(), nil)
+}
+
+// Feed lists the activity of the users the logged user follows, reading the pagination options
+func (s *FollowsService) Feed(ctx context.Context, options *ListOptions) (models.Page[models.ActivityResponse], error) {
+	return call[models.Page[models.ActivityResponse]](ctx, s.client, http.MethodGet, "/feed", options.values(), nil)
+}
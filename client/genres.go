@@ -0,0 +1,37 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/google/uuid"
+)
+
+type GenresService struct {
+	client *Client
+}
+
+func (s *GenresService) Create(ctx context.Context, genre models.GenreBody) (models.GenreResponse, error) {
+	return call[models.GenreResponse](ctx, s.client, http.MethodPost, "/genres", nil, genre)
+}
+
+func (s *GenresService) List(ctx context.Context, options *GenreListOptions) (models.Page[models.GenreResponse], error) {
+	return call[models.Page[models.GenreResponse]](ctx, s.client, http.MethodGet, "/genres", options.values(), nil)
+}
+
+func (s *GenresService) Get(ctx context.Context, id uuid.UUID) (models.GenreResponse, error) {
+	return call[models.GenreResponse](ctx, s.client, http.MethodGet, "/genres/"+id.String(), nil, nil)
+}
+
+func (s *GenresService) Movies(ctx context.Context, id uuid.UUID) (models.GenreResponseWithMovies, error) {
+	return call[models.GenreResponseWithMovies](ctx, s.client, http.MethodGet, "/genres/"+id.String()+"/movies", nil, nil)
+}
+
+func (s *GenresService) Update(ctx context.Context, id uuid.UUID, genre models.GenreEditBody) (models.GenreResponse, error) {
+	return call[models.GenreResponse](ctx, s.client, http.MethodPatch, "/genres/"+id.String(), nil, genre)
+}
+
+func (s *GenresService) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.client.do(ctx, http.MethodDelete, "/genres/"+id.String(), nil, nil, nil)
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/google/uuid"
+)
+
+type ListsService struct {
+	client *Client
+}
+
+func (s *ListsService) Create(ctx context.Context, userId uuid.UUID, list models.ListBody) (models.ListResponse, error) {
+	return call[models.ListResponse](ctx, s.client, http.MethodPost, "/users/"+userId.String()+"/lists", nil, list)
+}
+
+// UserLists gets the user with their lists, private ones included, reading the sort and deleted options
+func (s *ListsService) UserLists(ctx context.Context, userId uuid.UUID, options *ListOptions) (models.UserResponseWithLists, error) {
+	return call[models.UserResponseWithLists](ctx, s.client, http.MethodGet, "/users/"+userId.String()+"/lists", options.values(), nil)
+}
+
+// List lists the public lists of every user
+func (s *ListsService) List(ctx context.Context, options *PublicListOptions) (models.Page[models.ListResponse], error) {
+	return call[models.Page[models.ListResponse]](ctx, s.client, http.MethodGet, "/lists", options.values(), nil)
+}
+
+func (s *ListsService) Get(ctx context.Context, id uuid.UUID) (models.ListResponseWithItems, error) {
+	return call[models.ListResponseWithItems](ctx, s.client, http.MethodGet, "/lists/"+id.String(), nil, nil)
+}
+
+func (s *ListsService) Update(ctx context.Context, id uuid.UUID, list models.ListEditBody) (models.ListResponse, error) {
+	return call[models.ListResponse](ctx, s.client, http.MethodPatch, "/lists/"+id.String(), nil, list)
+}
+
+func (s *ListsService) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.client.do(ctx, http.MethodDelete, "/lists/"+id.String(), nil, nil, nil)
+}
+
+func (s *ListsService) AddMovie(ctx context.Context, id uuid.UUID, item models.ListItemBody) (models.ListResponseWithItems, error) {
+	return call[models.ListResponseWithItems](ctx, s.client, http.MethodPost, "/lists/"+id.String()+"/movies", nil, item)
+}
+
+func (s *ListsService) RemoveMovie(ctx context.Context, id uuid.UUID, movieId uuid.UUID) error {
+	return s.client.do(ctx, http.MethodDelete, "/lists/"+id.String()+"/movies/"+movieId.String(), nil, nil, nil)
+}
+
+// Reorder sets the order of the movies of the list, which needs every one of them
+func (s *ListsService) Reorder(ctx context.Context, id uuid.UUID, movieIds []string) (models.ListResponseWithItems, error) {
+	return call[models.ListResponseWithItems](ctx, s.client, http.MethodPut, "/lists/"+id.String()+"/order", nil, models.ListOrderBody{MovieIds: movieIds})
+}
+
+// Fork copies the list to the logged user
+func (s *ListsService) Fork(ctx context.Context, id uuid.UUID) (models.ListResponseWithItems, error) {
+	return call[models.ListResponseWithItems](ctx, s.client, http.MethodPost, "/lists/"+id.String()+"/fork", nil, nil)
+}
@@ -0,0 +1,23 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/google/uuid"
+)
+
+type ModerationService struct {
+	client *Client
+}
+
+// Queue lists the comments waiting for moderation, reading the pagination and sort options
+func (s *ModerationService) Queue(ctx context.Context, options *ListOptions) (models.Page[models.ModerationQueueItem], error) {
+	return call[models.Page[models.ModerationQueueItem]](ctx, s.client, http.MethodGet, "/moderation/queue", options.values(), nil)
+}
+
+// Moderate applies the action to the comment: "approve", "hide", or "ban", which also hides the comment
+func (s *ModerationService) Moderate(ctx context.Context, commentId uuid.UUID, action string) (models.CommentResponse, error) {
+	return call[models.CommentResponse](ctx, s.client, http.MethodPost, "/moderation/comments/"+commentId.String(), nil, models.ModerationActionBody{Action: action})
+}
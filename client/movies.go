@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/VinOfSteel/cinemagrader/controllers"
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/google/uuid"
+)
+
+type MoviesService struct {
+	client *Client
+}
+
+func (s *MoviesService) Create(ctx context.Context, movie models.MovieBody) (models.MovieResponseWithActors, error) {
+	return call[models.MovieResponseWithActors](ctx, s.client, http.MethodPost, "/movies", nil, movie)
+}
+
+// RecomputeStats recomputes the rating stats of every movie from their reviews
+func (s *MoviesService) RecomputeStats(ctx context.Context) (controllers.RecomputeStatsResponse, error) {
+	return call[controllers.RecomputeStatsResponse](ctx, s.client, http.MethodPost, "/movies/stats/recompute", nil, nil)
+}
+
+func (s *MoviesService) AddActors(ctx context.Context, id uuid.UUID, actors models.MovieActorsBody) error {
+	return s.client.do(ctx, http.MethodPost, "/movies/"+id.String()+"/actors", nil, actors, nil)
+}
+
+func (s *MoviesService) RemoveActors(ctx context.Context, id uuid.UUID, actors models.MovieActorsBody) error {
+	return s.client.do(ctx, http.MethodDelete, "/movies/"+id.String()+"/actors", nil, actors, nil)
+}
+
+func (s *MoviesService) UpdateCasting(ctx context.Context, id uuid.UUID, actorId uuid.UUID, casting models.CastingEditBody) (models.CastingResponse, error) {
+	return call[models.CastingResponse](ctx, s.client, http.MethodPatch, "/movies/"+id.String()+"/actors/"+actorId.String(), nil, casting)
+}
+
+func (s *MoviesService) AddGenres(ctx context.Context, id uuid.UUID, genres models.MovieGenresBody) error {
+	return s.client.do(ctx, http.MethodPost, "/movies/"+id.String()+"/genres", nil, genres, nil)
+}
+
+func (s *MoviesService) RemoveGenres(ctx context.Context, id uuid.UUID, genres models.MovieGenresBody) error {
+	return s.client.do(ctx, http.MethodDelete, "/movies/"+id.String()+"/genres", nil, genres, nil)
+}
+
+func (s *MoviesService) AddCrew(ctx context.Context, id uuid.UUID, crew models.MovieCrewBody) error {
+	return s.client.do(ctx, http.MethodPost, "/movies/"+id.String()+"/crew", nil, crew, nil)
+}
+
+func (s *MoviesService) RemoveCrew(ctx context.Context, id uuid.UUID, crew models.MovieCrewBody) error {
+	return s.client.do(ctx, http.MethodDelete, "/movies/"+id.String()+"/crew", nil, crew, nil)
+}
+
+func (s *MoviesService) List(ctx context.Context, options *MovieListOptions) (models.Page[models.MovieResponse], error) {
+	return call[models.Page[models.MovieResponse]](ctx, s.client, http.MethodGet, "/movies", options.values(), nil)
+}
+
+// ListWithActors lists the movies like List, with the actors of each of them
+func (s *MoviesService) ListWithActors(ctx context.Context, options *MovieListOptions) (models.Page[models.MovieResponseWithActors], error) {
+	query := options.values()
+	query.Set("with_actors", "true")
+
+	return call[models.Page[models.MovieResponseWithActors]](ctx, s.client, http.MethodGet, "/movies", query, nil)
+}
+
+// Top lists the reviewed movies, best weighted rating first unless another sort is sent
+func (s *MoviesService) Top(ctx context.Context, options *MovieListOptions) (models.Page[models.MovieResponse], error) {
+	return call[models.Page[models.MovieResponse]](ctx, s.client, http.MethodGet, "/movies/top", options.values(), nil)
+}
+
+// Trending lists the movies with the most reviews per day in the window, unless another sort is sent
+func (s *MoviesService) Trending(ctx context.Context, options *TrendingOptions) (models.Page[models.MovieResponse], error) {
+	return call[models.Page[models.MovieResponse]](ctx, s.client, http.MethodGet, "/movies/trending", options.values(), nil)
+}
+
+func (s *MoviesService) Get(ctx context.Context, id uuid.UUID) (models.MovieResponseWithActors, error) {
+	return call[models.MovieResponseWithActors](ctx, s.client, http.MethodGet, "/movies/"+id.String(), nil, nil)
+}
+
+// Comments gets the movie with its comments. Spoilers are redacted unless the logged user reviewed the movie and asks to reveal them.
+func (s *MoviesService) Comments(ctx context.Context, id uuid.UUID, options *MovieCommentsOptions) (models.MovieResponseWithActorsWithComments, error) {
+	return call[models.MovieResponseWithActorsWithComments](ctx, s.client, http.MethodGet, "/movies/"+id.String()+"/comments", options.values(), nil)
+}
+
+func (s *MoviesService) Update(ctx context.Context, id uuid.UUID, movie models.MovieEditBody) (models.MovieResponse, error) {
+	return call[models.MovieResponse](ctx, s.client, http.MethodPatch, "/movies/"+id.String(), nil, movie)
+}
+
+func (s *MoviesService) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.client.do(ctx, http.MethodDelete, "/movies/"+id.String(), nil, nil, nil)
+}
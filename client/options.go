@@ -0,0 +1,304 @@
+package client
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// ListOptions are the pagination, sort and deleted params of the list routes. Routes only read the params they support,
+// and zero values are left out of the query, so the defaults of the server apply.
+type ListOptions struct {
+	Offset int
+	Limit  int
+	// Cursor is the Next or Prev of a page the same route sent back, which takes precedence over the offset
+	Cursor string
+	// Sort is one of the sorts of the route, like "created,desc"
+	Sort string
+	// Deleted lists the soft deleted rows instead of the active ones
+	Deleted bool
+}
+
+func (o *ListOptions) values() url.Values {
+	query := url.Values{}
+	if o == nil {
+		return query
+	}
+
+	setInt(query, "offset", o.Offset)
+	setInt(query, "limit", o.Limit)
+	setString(query, "cursor", o.Cursor)
+	setString(query, "sort", o.Sort)
+	setBool(query, "deleted", o.Deleted)
+	return query
+}
+
+type UserListOptions struct {
+	ListOptions
+	// IsAdm only lists admins when true and only users that aren't admins when false
+	IsAdm *bool
+	Name  string
+	Email string
+}
+
+func (o *UserListOptions) values() url.Values {
+	if o == nil {
+		return url.Values{}
+	}
+
+	query := o.ListOptions.values()
+	if o.IsAdm != nil {
+		query.Set("is_adm", strconv.FormatBool(*o.IsAdm))
+	}
+	setString(query, "name", o.Name)
+	setString(query, "email", o.Email)
+	return query
+}
+
+type PublicListOptions struct {
+	ListOptions
+	Owner uuid.UUID
+	Name  string
+}
+
+func (o *PublicListOptions) values() url.Values {
+	if o == nil {
+		return url.Values{}
+	}
+
+	query := o.ListOptions.values()
+	setUUID(query, "owner", o.Owner)
+	setString(query, "name", o.Name)
+	return query
+}
+
+type DiaryOptions struct {
+	ListOptions
+	Year int
+}
+
+func (o *DiaryOptions) values() url.Values {
+	if o == nil {
+		return url.Values{}
+	}
+
+	query := o.ListOptions.values()
+	setInt(query, "year", o.Year)
+	return query
+}
+
+type ActorListOptions struct {
+	ListOptions
+	// Movie only lists the actors cast in the movie
+	Movie uuid.UUID
+	Name  string
+}
+
+func (o *ActorListOptions) values() url.Values {
+	if o == nil {
+		return url.Values{}
+	}
+
+	query := o.ListOptions.values()
+	setUUID(query, "movie", o.Movie)
+	setString(query, "name", o.Name)
+	return query
+}
+
+type MovieListOptions struct {
+	ListOptions
+	// Release dates in the YYYY-MM-DD format
+	ReleasedFrom string
+	ReleasedTo   string
+	MinGrade     *float64
+	MaxGrade     *float64
+	Actor        uuid.UUID
+	Genre        uuid.UUID
+	Director     string
+	Title        string
+}
+
+func (o *MovieListOptions) values() url.Values {
+	if o == nil {
+		return url.Values{}
+	}
+
+	query := o.ListOptions.values()
+	setString(query, "released_from", o.ReleasedFrom)
+	setString(query, "released_to", o.ReleasedTo)
+	setFloat(query, "min_grade", o.MinGrade)
+	setFloat(query, "max_grade", o.MaxGrade)
+	setUUID(query, "actor", o.Actor)
+	setUUID(query, "genre", o.Genre)
+	setString(query, "director", o.Director)
+	setString(query, "title", o.Title)
+	return query
+}
+
+type TrendingOptions struct {
+	MovieListOptions
+	// Window is how many days of reviews are counted, 7 by default
+	Window int
+}
+
+func (o *TrendingOptions) values() url.Values {
+	if o == nil {
+		return url.Values{}
+	}
+
+	query := o.MovieListOptions.values()
+	setInt(query, "window", o.Window)
+	return query
+}
+
+type MovieCommentsOptions struct {
+	Sort    string
+	Deleted bool
+	// Threaded nests the replies under the comment they answer
+	Threaded bool
+	// RevealSpoilers shows the spoilers, which only works for users that reviewed the movie
+	RevealSpoilers bool
+}
+
+func (o *MovieCommentsOptions) values() url.Values {
+	query := url.Values{}
+	if o == nil {
+		return query
+	}
+
+	setString(query, "sort", o.Sort)
+	setBool(query, "deleted", o.Deleted)
+	setBool(query, "threaded", o.Threaded)
+	setBool(query, "reveal_spoilers", o.RevealSpoilers)
+	return query
+}
+
+type CommentListOptions struct {
+	ListOptions
+	User  uuid.UUID
+	Movie uuid.UUID
+	// Status is the moderation status, one of visible, pending or hidden
+	Status   string
+	MinGrade *float64
+	MaxGrade *float64
+}
+
+func (o *CommentListOptions) values() url.Values {
+	if o == nil {
+		return url.Values{}
+	}
+
+	query := o.ListOptions.values()
+	setUUID(query, "user", o.User)
+	setUUID(query, "movie", o.Movie)
+	setString(query, "status", o.Status)
+	setFloat(query, "min_grade", o.MinGrade)
+	setFloat(query, "max_grade", o.MaxGrade)
+	return query
+}
+
+type AuditLogOptions struct {
+	ListOptions
+	TargetId uuid.UUID
+	ActorId  uuid.UUID
+	// Action is one of the models.Audit* actions
+	Action string
+}
+
+func (o *AuditLogOptions) values() url.Values {
+	if o == nil {
+		return url.Values{}
+	}
+
+	query := o.ListOptions.values()
+	setUUID(query, "target_id", o.TargetId)
+	setUUID(query, "actor_id", o.ActorId)
+	setString(query, "action", o.Action)
+	return query
+}
+
+type SearchOptions struct {
+	// Type only searches one of models.SearchTypeMovie or models.SearchTypeActor
+	Type   string
+	Offset int
+	Limit  int
+	// Cursor is the Next or Prev of a previous page of the same search
+	Cursor string
+}
+
+func (o *SearchOptions) values() url.Values {
+	query := url.Values{}
+	if o == nil {
+		return query
+	}
+
+	setString(query, "type", o.Type)
+	setInt(query, "offset", o.Offset)
+	setInt(query, "limit", o.Limit)
+	setString(query, "cursor", o.Cursor)
+	return query
+}
+
+type GenreListOptions struct {
+	ListOptions
+	Name string
+}
+
+func (o *GenreListOptions) values() url.Values {
+	if o == nil {
+		return url.Values{}
+	}
+
+	query := o.ListOptions.values()
+	setString(query, "name", o.Name)
+	return query
+}
+
+type PersonListOptions struct {
+	ListOptions
+	Name string
+	// Role only lists people credited with one of the models.CrewRole* roles
+	Role string
+}
+
+func (o *PersonListOptions) values() url.Values {
+	if o == nil {
+		return url.Values{}
+	}
+
+	query := o.ListOptions.values()
+	setString(query, "name", o.Name)
+	setString(query, "role", o.Role)
+	return query
+}
+
+func setString(query url.Values, key string, value string) {
+	if value != "" {
+		query.Set(key, value)
+	}
+}
+
+func setInt(query url.Values, key string, value int) {
+	if value != 0 {
+		query.Set(key, strconv.Itoa(value))
+	}
+}
+
+func setBool(query url.Values, key string, value bool) {
+	if value {
+		query.Set(key, "true")
+	}
+}
+
+func setFloat(query url.Values, key string, value *float64) {
+	if value != nil {
+		query.Set(key, strconv.FormatFloat(*value, 'f', -1, 64))
+	}
+}
+
+func setUUID(query url.Values, key string, value uuid.UUID) {
+	if value != uuid.Nil {
+		query.Set(key, value.String())
+	}
+}
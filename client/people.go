@@ -0,0 +1,37 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/google/uuid"
+)
+
+type PeopleService struct {
+	client *Client
+}
+
+func (s *PeopleService) Create(ctx context.Context, person models.PersonBody) (models.PersonResponse, error) {
+	return call[models.PersonResponse](ctx, s.client, http.MethodPost, "/people", nil, person)
+}
+
+func (s *PeopleService) List(ctx context.Context, options *PersonListOptions) (models.Page[models.PersonResponse], error) {
+	return call[models.Page[models.PersonResponse]](ctx, s.client, http.MethodGet, "/people", options.values(), nil)
+}
+
+func (s *PeopleService) Get(ctx context.Context, id uuid.UUID) (models.PersonResponse, error) {
+	return call[models.PersonResponse](ctx, s.client, http.MethodGet, "/people/"+id.String(), nil, nil)
+}
+
+func (s *PeopleService) Filmography(ctx context.Context, id uuid.UUID) (models.PersonResponseWithFilmography, error) {
+	return call[models.PersonResponseWithFilmography](ctx, s.client, http.MethodGet, "/people/"+id.String()+"/filmography", nil, nil)
+}
+
+func (s *PeopleService) Update(ctx context.Context, id uuid.UUID, person models.PersonEditBody) (models.PersonResponse, error) {
+	return call[models.PersonResponse](ctx, s.client, http.MethodPatch, "/people/"+id.String(), nil, person)
+}
+
+func (s *PeopleService) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.client.do(ctx, http.MethodDelete, "/people/"+id.String(), nil, nil, nil)
+}
@@ -0,0 +1,20 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/VinOfSteel/cinemagrader/models"
+)
+
+type SearchService struct {
+	client *Client
+}
+
+// Query searches movies and actors by the words of the text, best matches first
+func (s *SearchService) Query(ctx context.Context, text string, options *SearchOptions) (models.Page[models.SearchResult], error) {
+	query := options.values()
+	query.Set("q", text)
+
+	return call[models.Page[models.SearchResult]](ctx, s.client, http.MethodGet, "/search", query, nil)
+}
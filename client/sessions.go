@@ -0,0 +1,62 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/VinOfSteel/cinemagrader/controllers"
+)
+
+type SessionsService struct {
+	client *Client
+}
+
+// Login starts a session, which the client uses in every request after it
+func (s *SessionsService) Login(ctx context.Context, email string, password string) (controllers.LoginResponse, error) {
+	session, err := call[controllers.LoginResponse](ctx, s.client, http.MethodPost, "/login", nil, controllers.LoginBody{
+		Email:    email,
+		Password: password,
+	})
+	if err != nil {
+		return controllers.LoginResponse{}, err
+	}
+
+	s.client.setSession(session)
+	return session, nil
+}
+
+// Refresh trades the refresh token for a new session. The client already does it by itself when the token expires.
+func (s *SessionsService) Refresh(ctx context.Context) (controllers.LoginResponse, error) {
+	s.client.refreshMu.Lock()
+	defer s.client.refreshMu.Unlock()
+
+	return s.client.refresh(ctx)
+}
+
+// Logout revokes the session and forgets its tokens
+func (s *SessionsService) Logout(ctx context.Context) error {
+	if err := s.client.do(ctx, http.MethodPost, "/logout", nil, nil, nil); err != nil {
+		return err
+	}
+
+	s.client.clearSession()
+	return nil
+}
+
+// LogoutAll revokes every session of the user, this one included
+func (s *SessionsService) LogoutAll(ctx context.Context) error {
+	if err := s.client.do(ctx, http.MethodPost, "/logout-all", nil, nil, nil); err != nil {
+		return err
+	}
+
+	s.client.clearSession()
+	return nil
+}
+
+// ResetPassword sets a new password with the reset token an admin got from Admin.ForcePasswordReset
+func (s *SessionsService) ResetPassword(ctx context.Context, resetToken string, password string) error {
+	return s.client.do(ctx, http.MethodPost, "/password-reset", nil, controllers.PasswordResetBody{
+		ResetToken: resetToken,
+		Password:   password,
+	}, nil)
+}
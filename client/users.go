@@ -0,0 +1,38 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/google/uuid"
+)
+
+type UsersService struct {
+	client *Client
+}
+
+func (s *UsersService) Create(ctx context.Context, user models.UserBody) (models.UserResponse, error) {
+	return call[models.UserResponse](ctx, s.client, http.MethodPost, "/users", nil, user)
+}
+
+func (s *UsersService) List(ctx context.Context, options *UserListOptions) (models.Page[models.UserResponse], error) {
+	return call[models.Page[models.UserResponse]](ctx, s.client, http.MethodGet, "/users", options.values(), nil)
+}
+
+func (s *UsersService) Get(ctx context.Context, id uuid.UUID) (models.UserResponse, error) {
+	return call[models.UserResponse](ctx, s.client, http.MethodGet, "/users/"+id.String(), nil, nil)
+}
+
+// Comments gets the user with their comments, reading the sort and deleted options
+func (s *UsersService) Comments(ctx context.Context, id uuid.UUID, options *ListOptions) (models.UserResponseWithComments, error) {
+	return call[models.UserResponseWithComments](ctx, s.client, http.MethodGet, "/users/"+id.String()+"/comments", options.values(), nil)
+}
+
+func (s *UsersService) Update(ctx context.Context, id uuid.UUID, user models.UserEditBody) (models.UserResponse, error) {
+	return call[models.UserResponse](ctx, s.client, http.MethodPatch, "/users/"+id.String(), nil, user)
+}
+
+func (s *UsersService) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.client.do(ctx, http.MethodDelete, "/users/"+id.String(), nil, nil, nil)
+}
@@ -0,0 +1,26 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/google/uuid"
+)
+
+type WatchlistService struct {
+	client *Client
+}
+
+func (s *WatchlistService) Add(ctx context.Context, userId uuid.UUID, item models.WatchlistBody) (models.WatchlistItemResponse, error) {
+	return call[models.WatchlistItemResponse](ctx, s.client, http.MethodPost, "/users/"+userId.String()+"/watchlist", nil, item)
+}
+
+// Get lists the watchlist of the user, one page at a time
+func (s *WatchlistService) Get(ctx context.Context, userId uuid.UUID, options *ListOptions) (models.Page[models.WatchlistItemResponse], error) {
+	return call[models.Page[models.WatchlistItemResponse]](ctx, s.client, http.MethodGet, "/users/"+userId.String()+"/watchlist", options.values(), nil)
+}
+
+func (s *WatchlistService) Remove(ctx context.Context, userId uuid.UUID, movieId uuid.UUID) error {
+	return s.client.do(ctx, http.MethodDelete, "/users/"+userId.String()+"/watchlist/"+movieId.String(), nil, nil, nil)
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bufio"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/VinOfSteel/cinemagrader/initializers"
+	"github.com/VinOfSteel/cinemagrader/migrations"
+	"github.com/VinOfSteel/cinemagrader/models"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const migrateUsage = `Usage: c_grader migrate <command>
+
+Commands:
+  up [n]         Apply all pending migrations, or only the next n
+  down [n]       Revert the latest applied migration, or the latest n
+  status         List every migration and whether it was applied
+  create <name>  Create a new empty up/down migration pair in ` + migrations.Dir
+
+const adminUsage = `Usage: c_grader admin create -name <name> -email <email> -birthday <YYYY-MM-DD> [-surname <surname>]
+
+Creates a user with the admin role, reading its password from stdin.
+Meant to bootstrap the first admin, who can then promote other users through the API.`
+
+// runCommand executes a CLI subcommand instead of starting the server.
+func runCommand(args []string) {
+	switch args[0] {
+	case "migrate":
+		runMigrateCommand(args[1:])
+	case "admin":
+		runAdminCommand(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n%s\n\n%s\n", args[0], migrateUsage, adminUsage)
+		os.Exit(2)
+	}
+}
+
+func parseSteps(args []string) int {
+	if len(args) == 0 {
+		return 0
+	}
+
+	steps, err := strconv.Atoi(args[0])
+	if err != nil || steps < 1 {
+		log.Fatalf("Number of steps needs to be a positive integer, got %q", args[0])
+	}
+
+	return steps
+}
+
+func runMigrateCommand(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		os.Exit(2)
+	}
+
+	// Create only writes files, so it does not need a DB connection nor environment variables
+	if args[0] == "create" {
+		if len(args) < 2 {
+			log.Fatal("A name is required to create a migration")
+		}
+
+		upPath, downPath, err := migrations.Create(migrations.Dir, args[1])
+		if err != nil {
+			log.Fatalf("Error creating migration: %v", err)
+		}
+
+		fmt.Printf("Created %s\nCreated %s\n", upPath, downPath)
+		return
+	}
+
+	initializers.StartEnvironmentVariables()
+	db := initializers.OpenDatabaseConn()
+	defer db.Close()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrations.Up(db, parseSteps(args[1:]))
+		if err != nil {
+			log.Fatalf("Error applying migrations: %v", err)
+		}
+		fmt.Printf("Applied %v migrations\n", applied)
+	case "down":
+		reverted, err := migrations.Down(db, parseSteps(args[1:]))
+		if err != nil {
+			log.Fatalf("Error reverting migrations: %v", err)
+		}
+		fmt.Printf("Reverted %v migrations\n", reverted)
+	case "status":
+		statuses, err := migrations.Status(db)
+		if err != nil {
+			log.Fatalf("Error getting migrations status: %v", err)
+		}
+
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.Applied {
+				appliedAt = "applied at " + status.AppliedAt.Time.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown migrate command %q\n\n%s\n", args[0], migrateUsage)
+		os.Exit(2)
+	}
+}
+
+func runAdminCommand(args []string) {
+	if len(args) == 0 || args[0] != "create" {
+		fmt.Fprintln(os.Stderr, adminUsage)
+		os.Exit(2)
+	}
+
+	flags := flag.NewFlagSet("admin create", flag.ExitOnError)
+	name := flags.String("name", "", "Name of the admin")
+	surname := flags.String("surname", "", "Surname of the admin")
+	email := flags.String("email", "", "Email the admin logs in with")
+	birthday := flags.String("birthday", "", "Birthday of the admin, as YYYY-MM-DD")
+	flags.Parse(args[1:])
+
+	// The password is read from stdin so it doesn't end up in the shell history
+	fmt.Fprint(os.Stderr, "Password: ")
+	password, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && password == "" {
+		log.Fatalf("Error reading password: %v", err)
+	}
+
+	userBody := models.UserBody{
+		Name:     *name,
+		Surname:  *surname,
+		Email:    *email,
+		Password: strings.TrimRight(password, "\r\n"),
+		Birthday: *birthday,
+	}
+
+	initializers.StartEnvironmentVariables()
+	db := initializers.NewDatabaseConn()
+	defer db.Close()
+
+	validate := initializers.NewValidator(db)
+	if err := validate.Struct(userBody); err != nil {
+		log.Fatalf("Invalid admin data: %v", err)
+	}
+
+	var userModel models.UserModel
+	if _, err := userModel.GetUserByEmail(db, userBody.Email); err != sql.ErrNoRows {
+		if err != nil {
+			log.Fatalf("Error getting user by email: %v", err)
+		}
+		log.Fatalf("User with email %s already exists", userBody.Email)
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(userBody.Password), 12)
+	if err != nil {
+		log.Fatalf("Error encrypting admin's password: %v", err)
+	}
+	userBody.Password = string(hashedPassword)
+
+	user, err := userModel.InsertUserInDB(db, userBody)
+	if err != nil {
+		log.Fatalf("Error inserting admin in DB: %v", err)
+	}
+
+	// Granted without an actor, so the audit log shows it came from the CLI
+	if err := userModel.UpdateUserToAdmById(db, user.ID); err != nil {
+		log.Fatalf("Error granting admin role: %v", err)
+	}
+
+	fmt.Printf("Created admin %s with id %s\n", user.Email, user.ID)
+}
@@ -3,9 +3,9 @@ package controllers
 import (
 	"database/sql"
 	"log"
-	"strconv"
 	"strings"
 
+	"github.com/VinOfSteel/cinemagrader/apierrors"
 	"github.com/VinOfSteel/cinemagrader/models"
 	"github.com/VinOfSteel/cinemagrader/validation"
 	"github.com/go-playground/validator/v10"
@@ -28,24 +28,18 @@ func (a *Actor) CreateActor(c *fiber.Ctx) error {
 	var actorBody models.ActorBody
 	if err := c.BodyParser(&actorBody); err != nil {
 		log.Println("Error parsing JSON body:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Error while parsing JSON body, check your request",
-		}
+		return apierrors.InvalidBody
 	}
 
-	// Validating input data. We return "nil" because the ValidateData function sends a response back by itself and we need to return here to stop the function.
-	if valid := validation.ValidateData(c, a.Validate, actorBody); !valid {
-		return nil
+	// Validating input data
+	if err := validation.ValidateData(a.Validate, actorBody); err != nil {
+		return err
 	}
 
 	actorResponse, err := ActorModel.InsertActorInDB(a.DB, actorBody)
 	if err != nil {
 		log.Println("Error inserting actor in DB:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Unknown error",
-		}
+		return apierrors.Internal
 	}
 
 	c.Status(fiber.StatusCreated).JSON(actorResponse)
@@ -56,29 +50,9 @@ func (a *Actor) ListAllActorsInDB(c *fiber.Ctx) error {
 	c.Accepts("application/json")
 
 	// Query params
-	offset := c.Query("offset", "0")
-	limit := c.Query("limit", "10")
 	orderBy := c.Query("sort", "created,desc")
 	deletedQuery := c.Query("deleted", "false")
 
-	offsetInt, err := strconv.Atoi(offset)
-	if err != nil {
-		log.Println("Invalid offset value:", offset)
-		return &fiber.Error{
-			Code:    fiber.StatusBadRequest,
-			Message: "Offset needs to be a valid integer",
-		}
-	}
-
-	limitInt, err := strconv.Atoi(limit)
-	if err != nil {
-		log.Println("Invalid limit value:", limit)
-		return &fiber.Error{
-			Code:    fiber.StatusBadRequest,
-			Message: "Limit needs to be a valid integer",
-		}
-	}
-
 	switch strings.ToLower(orderBy) {
 	case "created,asc":
 		orderBy = "created_at ASC"
@@ -98,19 +72,31 @@ func (a *Actor) ListAllActorsInDB(c *fiber.Ctx) error {
 		orderBy = "updated_at DESC"
 	}
 
+	page, err := pageRequest(c, orderBy)
+	if err != nil {
+		return err
+	}
+
 	var deleted bool
 	if deletedQuery == "true" {
 		deleted = true
 	}
 
-	actorsList, err := ActorModel.GetAllActors(a.DB, offsetInt, limitInt, orderBy, deleted)
+	movieId, err := queryUUID(c, "movie")
+	if err != nil {
+		return err
+	}
+
+	filters := models.ActorFilters{
+		Name:    c.Query("name"),
+		MovieId: movieId,
+	}
+
+	actorsList, err := ActorModel.GetAllActors(a.DB, page, orderBy, deleted, filters)
 	if err != nil {
 		if err != sql.ErrNoRows {
 			log.Println("Error getting all actors:", err)
-			return &fiber.Error{
-				Code:    fiber.StatusInternalServerError,
-				Message: "Unknown error",
-			}
+			return apierrors.Internal
 		}
 	}
 
@@ -125,27 +111,18 @@ func (a *Actor) GetActor(c *fiber.Ctx) error {
 	uuid, err := uuid.Parse(uuidParam)
 	if err != nil {
 		log.Println("Invalid uuid sent in param:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusBadRequest,
-			Message: "Invalid uuid parameter",
-		}
+		return apierrors.InvalidUUID
 	}
 
 	actorResponse, err := ActorModel.GetActorById(a.DB, uuid)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			log.Println("Actor id not found in database:", err)
-			return &fiber.Error{
-				Code:    fiber.StatusNotFound,
-				Message: "Actor id not found in database",
-			}
+			return apierrors.ActorNotFound
 		}
 
 		log.Println("Error getting actor by id:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Unknown error",
-		}
+		return apierrors.Internal
 	}
 
 	c.Status(fiber.StatusOK).JSON(actorResponse)
@@ -159,27 +136,18 @@ func (a *Actor) GetActorMovies(c *fiber.Ctx) error {
 	uuid, err := uuid.Parse(uuidParam)
 	if err != nil {
 		log.Println("Invalid uuid sent in param:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusBadRequest,
-			Message: "Invalid uuid parameter",
-		}
+		return apierrors.InvalidUUID
 	}
 
 	actorResponse, err := ActorModel.GetActorByIdWithMovies(a.DB, uuid)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			log.Println("Actor id not found in database:", err)
-			return &fiber.Error{
-				Code:    fiber.StatusNotFound,
-				Message: "Actor id not found in database",
-			}
+			return apierrors.ActorNotFound
 		}
 
 		log.Println("Error getting actor by id:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Unknown error",
-		}
+		return apierrors.Internal
 	}
 
 	c.Status(fiber.StatusOK).JSON(actorResponse)
@@ -193,35 +161,23 @@ func (a *Actor) DeleteActor(c *fiber.Ctx) error {
 	uuid, err := uuid.Parse(uuidParam)
 	if err != nil {
 		log.Println("Invalid uuid sent in param:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusBadRequest,
-			Message: "Invalid uuid parameter",
-		}
+		return apierrors.InvalidUUID
 	}
 
 	_, err = ActorModel.GetActorById(a.DB, uuid)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			log.Println("Actor id not found in database:", err)
-			return &fiber.Error{
-				Code:    fiber.StatusNotFound,
-				Message: "Actor id not found in database",
-			}
+			return apierrors.ActorNotFound
 		}
 
 		log.Println("Error getting actor by id:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Unknown error",
-		}
+		return apierrors.Internal
 	}
 
 	if err := ActorModel.DeleteActorById(a.DB, uuid); err != nil {
 		log.Println("Error deleting actor in DB:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Couldn't delete actor in DB",
-		}
+		return apierrors.Internal
 	}
 
 	c.Status(fiber.StatusNoContent)
@@ -235,50 +191,35 @@ func (a *Actor) UpdateActor(c *fiber.Ctx) error {
 	uuid, err := uuid.Parse(uuidParam)
 	if err != nil {
 		log.Println("Invalid uuid sent in param:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusBadRequest,
-			Message: "Invalid uuid parameter",
-		}
+		return apierrors.InvalidUUID
 	}
 
 	_, err = ActorModel.GetActorById(a.DB, uuid)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			log.Println("Actor id not found in database:", err)
-			return &fiber.Error{
-				Code:    fiber.StatusNotFound,
-				Message: "Actor id not found in database",
-			}
+			return apierrors.ActorNotFound
 		}
 
 		log.Println("Error getting actor by id:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Unknown error",
-		}
+		return apierrors.Internal
 	}
 
 	var actorBody models.ActorEditBody
 	if err := c.BodyParser(&actorBody); err != nil {
 		log.Println("Error parsing JSON body:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Error while parsing JSON body, check your request",
-		}
+		return apierrors.InvalidBody
 	}
 
-	// Validating input data. We return "nil" because the ValidateData function sends a response back by itself and we need to return here to stop the function.
-	if valid := validation.ValidateData(c, a.Validate, actorBody); !valid {
-		return nil
+	// Validating input data
+	if err := validation.ValidateData(a.Validate, actorBody); err != nil {
+		return err
 	}
 
 	actorResponse, err := ActorModel.UpdateActorById(a.DB, uuid, actorBody)
 	if err != nil {
 		log.Println("Error updating actor in DB:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Unknown error",
-		}
+		return apierrors.Internal
 	}
 
 	c.Status(fiber.StatusOK).JSON(actorResponse)
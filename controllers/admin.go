@@ -0,0 +1,283 @@
+package controllers
+
+import (
+	"database/sql"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/VinOfSteel/cinemagrader/apierrors"
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/VinOfSteel/cinemagrader/validation"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// Reset tokens are handed to the user by the admin, so they get a day to use it
+const passwordResetTokenDuration = 24 * time.Hour
+
+// Controller type
+type Admin struct {
+	DB       *sql.DB
+	Validate *validator.Validate
+}
+
+// Audit log model
+var AuditLogModel models.AuditLogModel
+
+type PasswordResetResponse struct {
+	ResetToken string    `json:"resetToken"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+// adminAndTarget gets the logged admin and the user of the param, which has to exist and not be deleted
+func (a *Admin) adminAndTarget(c *fiber.Ctx) (uuid.UUID, uuid.UUID, error) {
+	claims := c.Locals(ClaimsLocalsKey).(jwt.MapClaims)
+
+	adminId, err := uuid.Parse(claims["id"].(string))
+	if err != nil {
+		log.Println("Invalid user id in token claims:", err)
+		return uuid.Nil, uuid.Nil, apierrors.InvalidToken
+	}
+
+	targetId, err := activeUserFromParam(a.DB, c.Params("uuid"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	return adminId, targetId, nil
+}
+
+// userRolesResponse sends the roles of the user as they are after a promotion or demotion
+func (a *Admin) userRolesResponse(c *fiber.Ctx, userId uuid.UUID) error {
+	userRoles, err := RoleModel.GetUserRoles(a.DB, userId)
+	if err != nil {
+		log.Println("Error getting user roles:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusOK).JSON(userRoles)
+	return nil
+}
+
+// PromoteUser grants the role of the body to the user of the param
+func (a *Admin) PromoteUser(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+
+	adminId, targetId, err := a.adminAndTarget(c)
+	if err != nil {
+		return err
+	}
+
+	var roleBody models.RoleBody
+	if err := c.BodyParser(&roleBody); err != nil {
+		log.Println("Error parsing JSON body:", err)
+		return apierrors.InvalidBody
+	}
+
+	// Validating input data
+	if err := validation.ValidateData(a.Validate, roleBody); err != nil {
+		return err
+	}
+
+	userRoles, err := RoleModel.GetUserRoles(a.DB, targetId)
+	if err != nil {
+		log.Println("Error getting user roles:", err)
+		return apierrors.Internal
+	}
+
+	if slices.Contains(userRoles.Roles, roleBody.Role) {
+		return apierrors.RoleAlreadyGranted
+	}
+
+	if err := RoleModel.GrantRoleToUser(a.DB, uuid.NullUUID{UUID: adminId, Valid: true}, targetId, roleBody.Role); err != nil {
+		log.Println("Error granting role in DB:", err)
+		return apierrors.Internal
+	}
+
+	return a.userRolesResponse(c, targetId)
+}
+
+// DemoteUser takes the role of the param from the user of the param
+func (a *Admin) DemoteUser(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+	role := c.Params("role")
+
+	adminId, targetId, err := a.adminAndTarget(c)
+	if err != nil {
+		return err
+	}
+
+	if err := a.Validate.Var(role, "oneof=admin moderator curator"); err != nil {
+		return apierrors.InvalidRole
+	}
+
+	// Keeps at least the admin doing it around, so the last one can't lock everybody out
+	if adminId == targetId && role == models.RoleAdmin {
+		return apierrors.SelfDemotion
+	}
+
+	userRoles, err := RoleModel.GetUserRoles(a.DB, targetId)
+	if err != nil {
+		log.Println("Error getting user roles:", err)
+		return apierrors.Internal
+	}
+
+	if !slices.Contains(userRoles.Roles, role) {
+		return apierrors.RoleNotGranted
+	}
+
+	if err := RoleModel.RevokeRoleFromUser(a.DB, uuid.NullUUID{UUID: adminId, Valid: true}, targetId, role); err != nil {
+		log.Println("Error revoking role in DB:", err)
+		return apierrors.Internal
+	}
+
+	return a.userRolesResponse(c, targetId)
+}
+
+// BanUser bans the user of the param with the reason of the body, until the optional expiry of the body
+func (a *Admin) BanUser(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+
+	adminId, targetId, err := a.adminAndTarget(c)
+	if err != nil {
+		return err
+	}
+
+	if adminId == targetId {
+		return apierrors.SelfBan
+	}
+
+	var banBody models.BanBody
+	if err := c.BodyParser(&banBody); err != nil {
+		log.Println("Error parsing JSON body:", err)
+		return apierrors.InvalidBody
+	}
+
+	// Validating input data
+	if err := validation.ValidateData(a.Validate, banBody); err != nil {
+		return err
+	}
+
+	if banBody.Until != nil && !banBody.Until.After(time.Now()) {
+		return apierrors.BanExpiryInPast
+	}
+
+	userResponse, err := UserModel.BanUser(a.DB, adminId, targetId, banBody)
+	if err != nil {
+		log.Println("Error banning user in DB:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusOK).JSON(userResponse)
+	return nil
+}
+
+// UnbanUser lifts the ban of the user of the param
+func (a *Admin) UnbanUser(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+
+	adminId, targetId, err := a.adminAndTarget(c)
+	if err != nil {
+		return err
+	}
+
+	userResponse, err := UserModel.GetUserById(a.DB, targetId)
+	if err != nil {
+		log.Println("Error getting user by id:", err)
+		return apierrors.Internal
+	}
+
+	if !userResponse.Banned() {
+		return apierrors.UserNotBanned
+	}
+
+	userResponse, err = UserModel.UnbanUser(a.DB, adminId, targetId)
+	if err != nil {
+		log.Println("Error unbanning user in DB:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusOK).JSON(userResponse)
+	return nil
+}
+
+// ForcePasswordReset logs the user of the param out everywhere and sends back a reset token for the admin to hand over.
+// The user can only login again after setting a new password with it in POST /password-reset.
+func (a *Admin) ForcePasswordReset(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+
+	adminId, targetId, err := a.adminAndTarget(c)
+	if err != nil {
+		return err
+	}
+
+	resetToken, resetTokenHash, err := createOpaqueToken()
+	if err != nil {
+		log.Println("Couldn't create reset token:", err)
+		return apierrors.Internal
+	}
+
+	expiresAt := time.Now().Add(passwordResetTokenDuration)
+	if err := UserModel.RequirePasswordReset(a.DB, adminId, targetId, resetTokenHash, expiresAt); err != nil {
+		log.Println("Error requiring password reset in DB:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusCreated).JSON(PasswordResetResponse{
+		ResetToken: resetToken,
+		ExpiresAt:  expiresAt,
+	})
+	return nil
+}
+
+// GetAuditLog lists what admins did to users, most recent first by default
+func (a *Admin) GetAuditLog(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+
+	orderBy := c.Query("sort", "created,desc")
+
+	switch strings.ToLower(orderBy) {
+	case "created,asc":
+		orderBy = "created_at ASC"
+	default:
+		orderBy = "created_at DESC"
+	}
+
+	page, err := pageRequest(c, orderBy)
+	if err != nil {
+		return err
+	}
+
+	targetId, err := queryUUID(c, "target_id")
+	if err != nil {
+		return err
+	}
+
+	actorId, err := queryUUID(c, "actor_id")
+	if err != nil {
+		return err
+	}
+
+	filters := models.AuditLogFilters{
+		TargetId: targetId,
+		ActorId:  actorId,
+		Action:   strings.ToLower(c.Query("action")),
+	}
+
+	if filters.Action != "" && a.Validate.Var(filters.Action, "oneof=promote demote ban unban password_reset") != nil {
+		return apierrors.InvalidQueryParam("action", apierrors.OneOf, "promote, demote, ban, unban, password_reset")
+	}
+
+	auditLog, err := AuditLogModel.GetAuditLog(a.DB, page, orderBy, filters)
+	if err != nil {
+		log.Println("Error getting audit log:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusOK).JSON(auditLog)
+	return nil
+}
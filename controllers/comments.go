@@ -3,25 +3,51 @@ package controllers
 import (
 	"database/sql"
 	"log"
-	"strconv"
 	"strings"
 
+	"github.com/VinOfSteel/cinemagrader/apierrors"
 	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/VinOfSteel/cinemagrader/moderation"
 	"github.com/VinOfSteel/cinemagrader/validation"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 )
 
-// Controller type
+// Controller type. Comments that hit the blocklist are held for moderation.
 type Comment struct {
-	DB       *sql.DB
-	Validate *validator.Validate
+	DB        *sql.DB
+	Validate  *validator.Validate
+	Blocklist *moderation.Blocklist
 }
 
 // Comment model
 var CommentModel models.CommentModel
 
+// GetActiveComment gets the comment of the uuid param, deleted comments are treated as if they didn't exist.
+// It's also used by the resource middleware, which checks the author of the comment before the handlers run.
+func GetActiveComment(db *sql.DB, uuidParam string) (models.CommentResponse, error) {
+	commentId, err := uuid.Parse(uuidParam)
+	if err != nil {
+		log.Println("Invalid uuid sent in param:", err)
+		return models.CommentResponse{}, apierrors.InvalidUUID
+	}
+
+	comment, err := CommentModel.GetCommentById(db, commentId)
+	if err != nil && err != sql.ErrNoRows {
+		log.Println("Error getting comment by id:", err)
+		return models.CommentResponse{}, apierrors.Internal
+	}
+
+	if err == sql.ErrNoRows || comment.DeletedAt.Valid {
+		log.Println("Comment id not found in database:", commentId)
+		return models.CommentResponse{}, apierrors.CommentNotFound
+	}
+
+	return comment, nil
+}
+
 func (com *Comment) CreateComment(c *fiber.Ctx) error {
 	c.Accepts("application/json")
 	uuidParam := c.Params("uuid")
@@ -29,90 +55,260 @@ func (com *Comment) CreateComment(c *fiber.Ctx) error {
 	uuid, err := uuid.Parse(uuidParam)
 	if err != nil {
 		log.Println("Invalid uuid sent in param:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusBadRequest,
-			Message: "Invalid uuid parameter",
-		}
+		return apierrors.InvalidUUID
 	}
 
 	userResponse, err := UserModel.GetUserById(com.DB, uuid)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			log.Println("User id not found in database:", err)
-			return &fiber.Error{
-				Code:    fiber.StatusNotFound,
-				Message: "User id not found in database",
-			}
+			return apierrors.UserNotFound
 		}
 
 		log.Println("Error getting user by id:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Unknown error",
-		}
+		return apierrors.Internal
 	}
 
 	if userResponse.DeletedAt.Valid {
-		return &fiber.Error{
-			Code:    fiber.StatusBadRequest,
-			Message: "Trying to comment as a deleted user, check your request",
-		}
+		return apierrors.CommentAsDeletedUser
+	}
+
+	if userResponse.Banned() {
+		return apierrors.BannedFromCommenting
 	}
 
 	var commentBody models.CommentBody
 	if err := c.BodyParser(&commentBody); err != nil {
 		log.Println("Error parsing JSON body:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Error while parsing JSON body, check your request",
+		return apierrors.InvalidBody
+	}
+
+	// Validating input data
+	if err := validation.ValidateData(com.Validate, commentBody); err != nil {
+		return err
+	}
+
+	if commentBody.ParentId != "" {
+		if err := com.checkReplyParent(commentBody); err != nil {
+			return err
 		}
 	}
 
-	// Validating input data. We return "nil" because the ValidateData function sends a response back by itself and we need to return here to stop the function.
-	if valid := validation.ValidateData(c, com.Validate, commentBody); !valid {
-		return nil
+	commentBody.HeldForModeration = com.Blocklist.Matches(commentBody.Comment)
+
+	// Graded comments are reviews, and a user can only have one per movie
+	if commentBody.Grade != 0 {
+		found, err := com.hasReviewOfMovie(uuid.String(), commentBody.MovieId)
+		if err != nil {
+			return err
+		}
+
+		if found {
+			return apierrors.MovieAlreadyReviewed
+		}
 	}
 
 	commentResponse, err := CommentModel.InsertCommentInDB(com.DB, uuid, commentBody)
 	if err != nil {
-		log.Println("Error inserting comment in DB:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Unknown error",
+		if models.IsDuplicateReview(err) {
+			return apierrors.MovieAlreadyReviewed
 		}
+
+		log.Println("Error inserting comment in DB:", err)
+		return apierrors.Internal
 	}
 
 	c.Status(fiber.StatusCreated).JSON(commentResponse)
 	return nil
 }
 
-func (com *Comment) ListAllCommentsInDb(c *fiber.Ctx) error {
+// UpsertReview creates or replaces the review of the logged user on the movie of the param
+func (com *Comment) UpsertReview(c *fiber.Ctx) error {
 	c.Accepts("application/json")
+	uuidParam := c.Params("uuid")
+	claims := c.Locals(ClaimsLocalsKey).(jwt.MapClaims)
 
-	// Query params
-	offset := c.Query("offset", "0")
-	limit := c.Query("limit", "10")
-	orderBy := c.Query("sort", "created,desc")
-	deletedQuery := c.Query("deleted", "false")
+	movieId, err := uuid.Parse(uuidParam)
+	if err != nil {
+		log.Println("Invalid uuid sent in param:", err)
+		return apierrors.InvalidUUID
+	}
+
+	userId, err := uuid.Parse(claims["id"].(string))
+	if err != nil {
+		log.Println("Invalid user id in token claims:", err)
+		return apierrors.InvalidToken
+	}
 
-	offsetInt, err := strconv.Atoi(offset)
+	userResponse, err := UserModel.GetUserById(com.DB, userId)
 	if err != nil {
-		log.Println("Invalid offset value:", offset)
-		return &fiber.Error{
-			Code:    fiber.StatusBadRequest,
-			Message: "Offset needs to be a valid integer",
+		if err == sql.ErrNoRows {
+			log.Println("User id not found in database:", err)
+			return apierrors.UserNotFound
 		}
+
+		log.Println("Error getting user by id:", err)
+		return apierrors.Internal
 	}
 
-	limitInt, err := strconv.Atoi(limit)
+	if userResponse.DeletedAt.Valid {
+		return apierrors.CommentAsDeletedUser
+	}
+
+	if userResponse.Banned() {
+		return apierrors.BannedFromCommenting
+	}
+
+	movieResponse, err := MovieModel.GetMovieByIdWithActors(com.DB, movieId)
 	if err != nil {
-		log.Println("Invalid limit value:", limit)
-		return &fiber.Error{
-			Code:    fiber.StatusBadRequest,
-			Message: "Limit needs to be a valid integer",
+		if err == sql.ErrNoRows {
+			log.Println("Movie id not found in database:", err)
+			return apierrors.MovieNotFound
 		}
+
+		log.Println("Error getting movie by id:", err)
+		return apierrors.Internal
 	}
 
+	if movieResponse.DeletedAt.Valid {
+		return apierrors.ReviewOfDeletedMovie
+	}
+
+	var reviewBody models.ReviewBody
+	if err := c.BodyParser(&reviewBody); err != nil {
+		log.Println("Error parsing JSON body:", err)
+		return apierrors.InvalidBody
+	}
+
+	// Validating input data
+	if err := validation.ValidateData(com.Validate, reviewBody); err != nil {
+		return err
+	}
+
+	reviewBody.HeldForModeration = com.Blocklist.Matches(reviewBody.Comment)
+
+	reviewResponse, created, err := CommentModel.UpsertReviewInDB(com.DB, userId, movieId, reviewBody)
+	if err != nil {
+		log.Println("Error upserting review in DB:", err)
+		return apierrors.Internal
+	}
+
+	if created {
+		c.Status(fiber.StatusCreated).JSON(reviewResponse)
+		return nil
+	}
+
+	c.Status(fiber.StatusOK).JSON(reviewResponse)
+	return nil
+}
+
+// hasReviewOfMovie tells if the user already has an active review on the movie
+func (com *Comment) hasReviewOfMovie(userId string, movieId string) (bool, error) {
+	userUUID, err := uuid.Parse(userId)
+	if err != nil {
+		log.Println("Invalid user uuid in comment:", err)
+		return false, apierrors.Internal
+	}
+
+	movieUUID, err := uuid.Parse(movieId)
+	if err != nil {
+		log.Println("Invalid movie uuid in comment:", err)
+		return false, apierrors.Internal
+	}
+
+	_, err = CommentModel.GetUserReviewOfMovie(com.DB, userUUID, movieUUID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+
+		log.Println("Error getting review of user on movie:", err)
+		return false, apierrors.Internal
+	}
+
+	return true, nil
+}
+
+// checkReplyParent checks that the comment in the body can be a reply to its parent
+func (com *Comment) checkReplyParent(commentBody models.CommentBody) error {
+	if commentBody.Grade != 0 {
+		return apierrors.ReplyWithGrade
+	}
+
+	parentId, err := uuid.Parse(commentBody.ParentId)
+	if err != nil {
+		log.Println("Invalid parent uuid in comment:", err)
+		return apierrors.Internal
+	}
+
+	parentResponse, err := CommentModel.GetCommentById(com.DB, parentId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Println("Parent comment id not found in database:", err)
+			return apierrors.ParentCommentNotFound
+		}
+
+		log.Println("Error getting parent comment by id:", err)
+		return apierrors.Internal
+	}
+
+	if parentResponse.DeletedAt.Valid {
+		return apierrors.ReplyToDeletedComment
+	}
+
+	if parentResponse.Status != models.CommentStatusVisible {
+		return apierrors.ParentCommentNotFound
+	}
+
+	if parentResponse.MovieId != commentBody.MovieId {
+		return apierrors.ReplyOnOtherMovie
+	}
+
+	if parentResponse.Depth >= models.MaxCommentDepth {
+		return apierrors.ReplyTooDeep(models.MaxCommentDepth)
+	}
+
+	return nil
+}
+
+// commentFilters reads the optional filters of the comments list from the query params
+func commentFilters(c *fiber.Ctx) (models.CommentFilters, error) {
+	var filters models.CommentFilters
+	var err error
+
+	if filters.UserId, err = queryUUID(c, "user"); err != nil {
+		return models.CommentFilters{}, err
+	}
+
+	if filters.MovieId, err = queryUUID(c, "movie"); err != nil {
+		return models.CommentFilters{}, err
+	}
+
+	if filters.MinGrade, err = queryFloat(c, "min_grade"); err != nil {
+		return models.CommentFilters{}, err
+	}
+
+	if filters.MaxGrade, err = queryFloat(c, "max_grade"); err != nil {
+		return models.CommentFilters{}, err
+	}
+
+	filters.Status = strings.ToLower(c.Query("status"))
+	switch filters.Status {
+	case "", models.CommentStatusVisible, models.CommentStatusPending, models.CommentStatusHidden:
+	default:
+		return models.CommentFilters{}, apierrors.InvalidQueryParam("status", apierrors.OneOf, "visible, pending, hidden")
+	}
+
+	return filters, nil
+}
+
+func (com *Comment) ListAllCommentsInDb(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+
+	// Query params
+	orderBy := c.Query("sort", "created,desc")
+	deletedQuery := c.Query("deleted", "false")
+
 	switch strings.ToLower(orderBy) {
 	case "created,asc":
 		orderBy = "created_at ASC"
@@ -128,19 +324,26 @@ func (com *Comment) ListAllCommentsInDb(c *fiber.Ctx) error {
 		orderBy = "updated_at DESC"
 	}
 
+	page, err := pageRequest(c, orderBy)
+	if err != nil {
+		return err
+	}
+
 	var deleted bool
 	if deletedQuery == "true" {
 		deleted = true
 	}
 
-	commentsList, err := CommentModel.GetAllComments(com.DB, offsetInt, limitInt, orderBy, deleted)
+	filters, err := commentFilters(c)
+	if err != nil {
+		return err
+	}
+
+	commentsList, err := CommentModel.GetAllComments(com.DB, page, orderBy, deleted, filters)
 	if err != nil {
 		if err != sql.ErrNoRows {
 			log.Println("Error getting all comments:", err)
-			return &fiber.Error{
-				Code:    fiber.StatusInternalServerError,
-				Message: "Unknown error",
-			}
+			return apierrors.Internal
 		}
 	}
 
@@ -152,30 +355,42 @@ func (com *Comment) GetComment(c *fiber.Ctx) error {
 	c.Accepts("application/json")
 	uuidParam := c.Params("uuid")
 
-	uuid, err := uuid.Parse(uuidParam)
+	commentId, err := uuid.Parse(uuidParam)
 	if err != nil {
 		log.Println("Invalid uuid sent in param:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusBadRequest,
-			Message: "Invalid uuid parameter",
-		}
+		return apierrors.InvalidUUID
 	}
 
-	commentResponse, err := CommentModel.GetCommentById(com.DB, uuid)
+	commentResponse, err := CommentModel.GetCommentById(com.DB, commentId)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			log.Println("Comment id not found in database:", err)
-			return &fiber.Error{
-				Code:    fiber.StatusNotFound,
-				Message: "Comment id not found in database",
-			}
+			return apierrors.CommentNotFound
 		}
 
 		log.Println("Error getting comment by id:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Unknown error",
-		}
+		return apierrors.Internal
+	}
+
+	// Comments waiting for or removed by moderation aren't public
+	if commentResponse.Status != models.CommentStatusVisible {
+		return apierrors.CommentNotFound
+	}
+
+	movieId, err := uuid.Parse(commentResponse.MovieId)
+	if err != nil {
+		log.Println("Invalid movie id of comment:", err)
+		return apierrors.Internal
+	}
+
+	canSeeSpoilers, viewerId, err := spoilerViewer(com.DB, c, movieId)
+	if err != nil {
+		return err
+	}
+
+	// Authors always see their own comments as they wrote them
+	if !canSeeSpoilers && commentResponse.UserId != viewerId {
+		commentResponse = models.RedactComment(commentResponse)
 	}
 
 	c.Status(fiber.StatusOK).JSON(commentResponse)
@@ -184,99 +399,245 @@ func (com *Comment) GetComment(c *fiber.Ctx) error {
 
 func (com *Comment) DeleteComment(c *fiber.Ctx) error {
 	c.Accepts("application/json")
-	uuidParam := c.Params("uuid")
 
-	uuid, err := uuid.Parse(uuidParam)
+	commentResponse, err := GetActiveComment(com.DB, c.Params("uuid"))
 	if err != nil {
-		log.Println("Invalid uuid sent in param:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusBadRequest,
-			Message: "Invalid uuid parameter",
-		}
+		return err
 	}
 
-	_, err = CommentModel.GetCommentById(com.DB, uuid)
+	if err := CommentModel.DeleteCommentById(com.DB, commentResponse.ID); err != nil {
+		log.Println("Error deleting comment in DB:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusNoContent)
+	return nil
+}
+
+func (com *Comment) UpdateComment(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+
+	commentResponse, err := GetActiveComment(com.DB, c.Params("uuid"))
 	if err != nil {
-		if err == sql.ErrNoRows {
-			log.Println("Comment id not found in database:", err)
-			return &fiber.Error{
-				Code:    fiber.StatusNotFound,
-				Message: "Comment id not found in database",
-			}
+		return err
+	}
+
+	var commentBody models.CommentEditBody
+	if err := c.BodyParser(&commentBody); err != nil {
+		log.Println("Error parsing JSON body:", err)
+		return apierrors.InvalidBody
+	}
+
+	// Validating input data
+	if err := validation.ValidateData(com.Validate, commentBody); err != nil {
+		return err
+	}
+
+	if commentBody.Grade != 0 && commentResponse.ParentId.Valid {
+		return apierrors.ReplyWithGrade
+	}
+
+	commentBody.HeldForModeration = com.Blocklist.Matches(commentBody.Comment)
+
+	claims := c.Locals(ClaimsLocalsKey).(jwt.MapClaims)
+	commentBody.EditedByOther = claims["id"].(string) != commentResponse.UserId
+
+	// Grading a discussion comment turns it into a review, which can't happen if the user already has one on the movie
+	if commentBody.Grade != 0 && commentResponse.Grade == 0 {
+		found, err := com.hasReviewOfMovie(commentResponse.UserId, commentResponse.MovieId)
+		if err != nil {
+			return err
 		}
 
-		log.Println("Error getting comment by id:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Unknown error",
+		if found {
+			return apierrors.MovieAlreadyReviewed
 		}
 	}
 
-	if err := CommentModel.DeleteCommentById(com.DB, uuid); err != nil {
-		log.Println("Error deleting comment in DB:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Couldn't delete comment in DB",
+	commentResponse, err = CommentModel.UpdateCommentsById(com.DB, commentResponse.ID, commentBody)
+	if err != nil {
+		if models.IsDuplicateReview(err) {
+			return apierrors.MovieAlreadyReviewed
 		}
+
+		log.Println("Error updating comment in DB:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusOK).JSON(commentResponse)
+	return nil
+}
+
+// likedComment gets the logged user and the comment of the param, checking that the user can like the comment
+func (com *Comment) likedComment(c *fiber.Ctx) (uuid.UUID, uuid.UUID, error) {
+	claims := c.Locals(ClaimsLocalsKey).(jwt.MapClaims)
+
+	userId, err := activeUserFromParam(com.DB, claims["id"].(string))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	commentId, err := uuid.Parse(c.Params("uuid"))
+	if err != nil {
+		log.Println("Invalid uuid sent in param:", err)
+		return uuid.Nil, uuid.Nil, apierrors.InvalidUUID
+	}
+
+	commentResponse, err := CommentModel.GetCommentById(com.DB, commentId)
+	if err != nil && err != sql.ErrNoRows {
+		log.Println("Error getting comment by id:", err)
+		return uuid.Nil, uuid.Nil, apierrors.Internal
+	}
+
+	// Deleted comments and comments that aren't public can't be liked, so they are treated as not found
+	if err == sql.ErrNoRows || commentResponse.DeletedAt.Valid || commentResponse.Status != models.CommentStatusVisible {
+		log.Println("Comment id not found in database:", err)
+		return uuid.Nil, uuid.Nil, apierrors.CommentNotFound
+	}
+
+	return userId, commentId, nil
+}
+
+// LikeComment makes the logged user like the comment of the param
+func (com *Comment) LikeComment(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+
+	userId, commentId, err := com.likedComment(c)
+	if err != nil {
+		return err
+	}
+
+	liked, err := CommentModel.IsCommentLiked(com.DB, userId, commentId)
+	if err != nil {
+		log.Println("Error checking comment like:", err)
+		return apierrors.Internal
+	}
+
+	if liked {
+		return apierrors.CommentAlreadyLiked
+	}
+
+	if err := CommentModel.InsertCommentLike(com.DB, userId, commentId); err != nil {
+		log.Println("Error inserting comment like in DB:", err)
+		return apierrors.Internal
 	}
 
 	c.Status(fiber.StatusNoContent)
 	return nil
 }
 
-func (com *Comment) UpdateComment(c *fiber.Ctx) error {
+// UnlikeComment removes the like of the logged user from the comment of the param
+func (com *Comment) UnlikeComment(c *fiber.Ctx) error {
 	c.Accepts("application/json")
-	uuidParam := c.Params("uuid")
 
-	uuid, err := uuid.Parse(uuidParam)
+	userId, commentId, err := com.likedComment(c)
 	if err != nil {
-		log.Println("Invalid uuid sent in param:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusBadRequest,
-			Message: "Invalid uuid parameter",
-		}
+		return err
+	}
+
+	liked, err := CommentModel.IsCommentLiked(com.DB, userId, commentId)
+	if err != nil {
+		log.Println("Error checking comment like:", err)
+		return apierrors.Internal
+	}
+
+	if !liked {
+		return apierrors.CommentNotLiked
+	}
+
+	if err := CommentModel.DeleteCommentLike(com.DB, userId, commentId); err != nil {
+		log.Println("Error deleting comment like in DB:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusNoContent)
+	return nil
+}
+
+// spoilerViewer tells if the caller can read the spoilers of the comments on the movie, which they can by asking
+// for them with reveal_spoilers=true or by having reviewed the movie. It also returns the id of the logged user, if any.
+func spoilerViewer(db *sql.DB, c *fiber.Ctx, movieId uuid.UUID) (bool, string, error) {
+	reveal, err := queryBool(c, "reveal_spoilers")
+	if err != nil {
+		return false, "", err
+	}
+
+	if reveal != nil && *reveal {
+		return true, "", nil
+	}
+
+	// The route is public, so there are only claims when a token was sent
+	claims, ok := c.Locals(ClaimsLocalsKey).(jwt.MapClaims)
+	if !ok {
+		return false, "", nil
 	}
 
-	_, err = CommentModel.GetCommentById(com.DB, uuid)
+	viewerId, _ := claims["id"].(string)
+	userId, err := uuid.Parse(viewerId)
 	if err != nil {
+		return false, "", nil
+	}
+
+	reviewed, err := reviewedMovie(db, userId, movieId)
+	if err != nil {
+		return false, "", err
+	}
+
+	return reviewed, viewerId, nil
+}
+
+// reviewedMovie tells if the user reviewed the movie, which lets them read the spoilers of the comments on it
+func reviewedMovie(db *sql.DB, userId uuid.UUID, movieId uuid.UUID) (bool, error) {
+	if _, err := CommentModel.GetUserReviewOfMovie(db, userId, movieId); err != nil {
 		if err == sql.ErrNoRows {
-			log.Println("Comment id not found in database:", err)
-			return &fiber.Error{
-				Code:    fiber.StatusNotFound,
-				Message: "Comment id not found in database",
-			}
+			return false, nil
 		}
 
-		log.Println("Error getting comment by id:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Unknown error",
-		}
+		log.Println("Error getting review of user on movie:", err)
+		return false, apierrors.Internal
 	}
 
-	var commentBody models.CommentEditBody
-	if err := c.BodyParser(&commentBody); err != nil {
-		log.Println("Error parsing JSON body:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Error while parsing JSON body, check your request",
-		}
+	return true, nil
+}
+
+// setSpoilers flags or unflags the comment of the param as a spoiler
+func (com *Comment) setSpoilers(c *fiber.Ctx, containsSpoilers bool) error {
+	c.Accepts("application/json")
+	uuidParam := c.Params("uuid")
+
+	uuid, err := uuid.Parse(uuidParam)
+	if err != nil {
+		log.Println("Invalid uuid sent in param:", err)
+		return apierrors.InvalidUUID
 	}
 
-	// Validating input data. We return "nil" because the ValidateData function sends a response back by itself and we need to return here to stop the function.
-	if valid := validation.ValidateData(c, com.Validate, commentBody); !valid {
-		return nil
+	commentResponse, err := CommentModel.GetCommentById(com.DB, uuid)
+	if err != nil && err != sql.ErrNoRows {
+		log.Println("Error getting comment by id:", err)
+		return apierrors.Internal
 	}
 
-	commentResponse, err := CommentModel.UpdateCommentsById(com.DB, uuid, commentBody)
+	if err == sql.ErrNoRows || commentResponse.DeletedAt.Valid {
+		log.Println("Comment id not found in database:", err)
+		return apierrors.CommentNotFound
+	}
+
+	commentResponse, err = CommentModel.SetCommentSpoilers(com.DB, uuid, containsSpoilers)
 	if err != nil {
-		log.Println("Error updating comment in DB:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Unknown error",
-		}
+		log.Println("Error setting spoiler flag of comment in DB:", err)
+		return apierrors.Internal
 	}
 
 	c.Status(fiber.StatusOK).JSON(commentResponse)
 	return nil
 }
+
+// FlagSpoilers marks an existing comment as a spoiler, so it is redacted in the movie comments
+func (com *Comment) FlagSpoilers(c *fiber.Ctx) error {
+	return com.setSpoilers(c, true)
+}
+
+// UnflagSpoilers removes the spoiler mark of a comment
+func (com *Comment) UnflagSpoilers(c *fiber.Ctx) error {
+	return com.setSpoilers(c, false)
+}
@@ -0,0 +1,288 @@
+package controllers
+
+import (
+	"database/sql"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/VinOfSteel/cinemagrader/apierrors"
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/VinOfSteel/cinemagrader/validation"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// Controller type
+type Diary struct {
+	DB       *sql.DB
+	Validate *validator.Validate
+}
+
+// Diary model
+var DiaryModel models.DiaryModel
+
+// Error sent when the review linked to a diary entry isn't a comment of the same user on the same movie
+var errInvalidDiaryReview = apierrors.InvalidDiaryReview
+
+// checkDiaryReview verifies that the review linked to a diary entry was written by the user about the movie
+func (d *Diary) checkDiaryReview(reviewId string, userId uuid.UUID, movieId string) error {
+	reviewUUID, err := uuid.Parse(reviewId)
+	if err != nil {
+		log.Println("Invalid review uuid in body:", err)
+		return errInvalidDiaryReview
+	}
+
+	review, err := CommentModel.GetCommentById(d.DB, reviewUUID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Println("Review of diary entry not found in database:", err)
+			return errInvalidDiaryReview
+		}
+
+		log.Println("Error getting comment by id:", err)
+		return apierrors.Internal
+	}
+
+	if review.DeletedAt.Valid || review.UserId != userId.String() || review.MovieId != movieId {
+		return errInvalidDiaryReview
+	}
+
+	return nil
+}
+
+// yearQuery reads the year query param, using fallback when it isn't sent
+func yearQuery(c *fiber.Ctx, fallback int) (int, error) {
+	yearQuery := c.Query("year")
+	if yearQuery == "" {
+		return fallback, nil
+	}
+
+	year, err := strconv.Atoi(yearQuery)
+	if err != nil || year < 1 || year > 9999 {
+		return 0, apierrors.InvalidQueryParam("year", apierrors.WholeNumberBetween, "1", "9999")
+	}
+
+	return year, nil
+}
+
+// userDiaryEntry gets the entry of the route, answering as if it didn't exist when it belongs to another user
+func (d *Diary) userDiaryEntry(userId uuid.UUID, entryParam string) (models.DiaryEntryResponse, error) {
+	entryId, err := uuid.Parse(entryParam)
+	if err != nil {
+		log.Println("Invalid uuid sent in param:", err)
+		return models.DiaryEntryResponse{}, apierrors.InvalidUUID
+	}
+
+	entry, err := DiaryModel.GetDiaryEntryById(d.DB, entryId)
+	if err != nil && err != sql.ErrNoRows {
+		log.Println("Error getting diary entry by id:", err)
+		return models.DiaryEntryResponse{}, apierrors.Internal
+	}
+
+	if err == sql.ErrNoRows || entry.DeletedAt.Valid || entry.UserId != userId.String() {
+		log.Println("Diary entry id not found in database:", entryId)
+		return models.DiaryEntryResponse{}, apierrors.DiaryEntryNotFound
+	}
+
+	return entry, nil
+}
+
+func (d *Diary) CreateDiaryEntry(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+
+	userId, err := activeUserFromParam(d.DB, c.Params("uuid"))
+	if err != nil {
+		return err
+	}
+
+	var diaryBody models.DiaryBody
+	if err := c.BodyParser(&diaryBody); err != nil {
+		log.Println("Error parsing JSON body:", err)
+		return apierrors.InvalidBody
+	}
+
+	// Validating input data
+	if err := validation.ValidateData(d.Validate, diaryBody); err != nil {
+		return err
+	}
+
+	if _, err := activeMovie(d.DB, diaryBody.MovieId); err != nil {
+		return err
+	}
+
+	if diaryBody.ReviewId != "" {
+		if err := d.checkDiaryReview(diaryBody.ReviewId, userId, diaryBody.MovieId); err != nil {
+			return err
+		}
+	}
+
+	entryResponse, err := DiaryModel.InsertDiaryEntryInDB(d.DB, userId, diaryBody)
+	if err != nil {
+		log.Println("Error inserting diary entry in DB:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusCreated).JSON(entryResponse)
+	return nil
+}
+
+func (d *Diary) GetUserDiary(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+	uuidParam := c.Params("uuid")
+
+	uuid, err := uuid.Parse(uuidParam)
+	if err != nil {
+		log.Println("Invalid uuid sent in param:", err)
+		return apierrors.InvalidUUID
+	}
+
+	// Query params
+	orderBy := c.Query("sort", "watched,desc")
+
+	switch strings.ToLower(orderBy) {
+	case "watched,asc":
+		orderBy = "watched_on ASC"
+	case "logged,asc":
+		orderBy = "created_at ASC"
+	case "logged,desc":
+		orderBy = "created_at DESC"
+	case "title,asc":
+		orderBy = "title ASC"
+	case "title,desc":
+		orderBy = "title DESC"
+	default:
+		orderBy = "watched_on DESC"
+	}
+
+	// Without a year the whole diary is listed
+	year, err := yearQuery(c, 0)
+	if err != nil {
+		return err
+	}
+
+	page, err := pageRequest(c, orderBy)
+	if err != nil {
+		return err
+	}
+
+	_, err = UserModel.GetUserById(d.DB, uuid)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Println("User id not found in database:", err)
+			return apierrors.UserNotFound
+		}
+
+		log.Println("Error getting user by id:", err)
+		return apierrors.Internal
+	}
+
+	diaryResponse, err := DiaryModel.GetUserDiary(d.DB, uuid, page, orderBy, year)
+	if err != nil {
+		log.Println("Error getting diary of user:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusOK).JSON(diaryResponse)
+	return nil
+}
+
+func (d *Diary) GetUserDiaryStats(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+	uuidParam := c.Params("uuid")
+
+	uuid, err := uuid.Parse(uuidParam)
+	if err != nil {
+		log.Println("Invalid uuid sent in param:", err)
+		return apierrors.InvalidUUID
+	}
+
+	year, err := yearQuery(c, time.Now().Year())
+	if err != nil {
+		return err
+	}
+
+	_, err = UserModel.GetUserById(d.DB, uuid)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Println("User id not found in database:", err)
+			return apierrors.UserNotFound
+		}
+
+		log.Println("Error getting user by id:", err)
+		return apierrors.Internal
+	}
+
+	statsResponse, err := DiaryModel.GetUserDiaryStats(d.DB, uuid, year)
+	if err != nil {
+		log.Println("Error getting diary stats of user:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusOK).JSON(statsResponse)
+	return nil
+}
+
+func (d *Diary) UpdateDiaryEntry(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+
+	userId, err := activeUserFromParam(d.DB, c.Params("uuid"))
+	if err != nil {
+		return err
+	}
+
+	entry, err := d.userDiaryEntry(userId, c.Params("entryUuid"))
+	if err != nil {
+		return err
+	}
+
+	var diaryBody models.DiaryEditBody
+	if err := c.BodyParser(&diaryBody); err != nil {
+		log.Println("Error parsing JSON body:", err)
+		return apierrors.InvalidBody
+	}
+
+	// Validating input data
+	if err := validation.ValidateData(d.Validate, diaryBody); err != nil {
+		return err
+	}
+
+	if diaryBody.ReviewId != "" {
+		if err := d.checkDiaryReview(diaryBody.ReviewId, userId, entry.Movie.ID.String()); err != nil {
+			return err
+		}
+	}
+
+	entryResponse, err := DiaryModel.UpdateDiaryEntryById(d.DB, entry.ID, diaryBody)
+	if err != nil {
+		log.Println("Error updating diary entry in DB:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusOK).JSON(entryResponse)
+	return nil
+}
+
+func (d *Diary) DeleteDiaryEntry(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+
+	userId, err := activeUserFromParam(d.DB, c.Params("uuid"))
+	if err != nil {
+		return err
+	}
+
+	entry, err := d.userDiaryEntry(userId, c.Params("entryUuid"))
+	if err != nil {
+		return err
+	}
+
+	if err := DiaryModel.DeleteDiaryEntryById(d.DB, entry.ID); err != nil {
+		log.Println("Error deleting diary entry in DB:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusNoContent)
+	return nil
+}
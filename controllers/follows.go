@@ -0,0 +1,220 @@
+package controllers
+
+import (
+	"database/sql"
+	"log"
+	"strings"
+
+	"github.com/VinOfSteel/cinemagrader/apierrors"
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// Controller type
+type Follow struct {
+	DB       *sql.DB
+	Validate *validator.Validate
+}
+
+// Follow and activity models
+var FollowModel models.FollowModel
+var ActivityModel models.ActivityModel
+
+// followPair gets the logged user and the user of the param, checking that both can be part of a follow
+func (f *Follow) followPair(c *fiber.Ctx) (uuid.UUID, uuid.UUID, error) {
+	claims := c.Locals(ClaimsLocalsKey).(jwt.MapClaims)
+
+	followerId, err := activeUserFromParam(f.DB, claims["id"].(string))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	followedId, err := activeUserFromParam(f.DB, c.Params("uuid"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	if followerId == followedId {
+		return uuid.Nil, uuid.Nil, apierrors.SelfFollow
+	}
+
+	return followerId, followedId, nil
+}
+
+// followOrderBy turns the sort query param of the follow lists into an order by clause
+func followOrderBy(sort string) string {
+	switch strings.ToLower(sort) {
+	case "followed,asc":
+		return "followed_at ASC"
+	case "name,asc":
+		return "name ASC"
+	case "name,desc":
+		return "name DESC"
+	default:
+		return "followed_at DESC"
+	}
+}
+
+// FollowUser makes the logged user follow the user of the param
+func (f *Follow) FollowUser(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+
+	followerId, followedId, err := f.followPair(c)
+	if err != nil {
+		return err
+	}
+
+	following, err := FollowModel.IsFollowing(f.DB, followerId, followedId)
+	if err != nil {
+		log.Println("Error checking follow:", err)
+		return apierrors.Internal
+	}
+
+	if following {
+		return apierrors.UserAlreadyFollowed
+	}
+
+	if err := FollowModel.InsertFollowInDB(f.DB, followerId, followedId); err != nil {
+		log.Println("Error inserting follow in DB:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusNoContent)
+	return nil
+}
+
+// UnfollowUser makes the logged user stop following the user of the param
+func (f *Follow) UnfollowUser(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+
+	followerId, followedId, err := f.followPair(c)
+	if err != nil {
+		return err
+	}
+
+	following, err := FollowModel.IsFollowing(f.DB, followerId, followedId)
+	if err != nil {
+		log.Println("Error checking follow:", err)
+		return apierrors.Internal
+	}
+
+	if !following {
+		return apierrors.UserNotFollowed
+	}
+
+	if err := FollowModel.DeleteFollow(f.DB, followerId, followedId); err != nil {
+		log.Println("Error deleting follow in DB:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusNoContent)
+	return nil
+}
+
+func (f *Follow) GetFollowers(c *fiber.Ctx) error {
+	return f.listFollows(c, FollowModel.GetFollowers)
+}
+
+func (f *Follow) GetFollowing(c *fiber.Ctx) error {
+	return f.listFollows(c, FollowModel.GetFollowing)
+}
+
+func (f *Follow) listFollows(c *fiber.Ctx, getFollows func(*sql.DB, uuid.UUID, models.PageRequest, string) (models.Page[models.FollowResponse], error)) error {
+	c.Accepts("application/json")
+	uuidParam := c.Params("uuid")
+
+	uuid, err := uuid.Parse(uuidParam)
+	if err != nil {
+		log.Println("Invalid uuid sent in param:", err)
+		return apierrors.InvalidUUID
+	}
+
+	// Query params
+	orderBy := followOrderBy(c.Query("sort", "followed,desc"))
+
+	page, err := pageRequest(c, orderBy)
+	if err != nil {
+		return err
+	}
+
+	_, err = UserModel.GetUserById(f.DB, uuid)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Println("User id not found in database:", err)
+			return apierrors.UserNotFound
+		}
+
+		log.Println("Error getting user by id:", err)
+		return apierrors.Internal
+	}
+
+	follows, err := getFollows(f.DB, uuid, page, orderBy)
+	if err != nil {
+		log.Println("Error getting follows of user:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusOK).JSON(follows)
+	return nil
+}
+
+// GetFeed lists the activity of the users the logged user follows, most recent first
+func (f *Follow) GetFeed(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+	claims := c.Locals(ClaimsLocalsKey).(jwt.MapClaims)
+
+	userId, err := uuid.Parse(claims["id"].(string))
+	if err != nil {
+		log.Println("Invalid user id in token claims:", err)
+		return apierrors.InvalidToken
+	}
+
+	// The feed only goes from the newest activity to the oldest
+	orderBy := "created_at DESC"
+
+	page, err := pageRequest(c, orderBy)
+	if err != nil {
+		return err
+	}
+
+	reveal, err := queryBool(c, "reveal_spoilers")
+	if err != nil {
+		return err
+	}
+
+	feed, err := ActivityModel.GetFeed(f.DB, userId, page, orderBy)
+	if err != nil {
+		log.Println("Error getting feed of user:", err)
+		return apierrors.Internal
+	}
+
+	// Spoilers are redacted like in the comments of each movie, unless the user asks for them or reviewed the movie
+	if reveal == nil || !*reveal {
+		reviewedMovies := map[string]bool{}
+		for i, activity := range feed.Data {
+			reviewed, checked := reviewedMovies[activity.MovieId]
+			if !checked {
+				movieId, err := uuid.Parse(activity.MovieId)
+				if err != nil {
+					log.Println("Invalid movie id of activity:", err)
+					return apierrors.Internal
+				}
+
+				if reviewed, err = reviewedMovie(f.DB, userId, movieId); err != nil {
+					return err
+				}
+				reviewedMovies[activity.MovieId] = reviewed
+			}
+
+			if !reviewed {
+				feed.Data[i] = models.RedactActivity(activity)
+			}
+		}
+	}
+
+	c.Status(fiber.StatusOK).JSON(feed)
+	return nil
+}
@@ -0,0 +1,236 @@
+package controllers
+
+import (
+	"database/sql"
+	"log"
+	"strings"
+
+	"github.com/VinOfSteel/cinemagrader/apierrors"
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/VinOfSteel/cinemagrader/validation"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// Controller type
+type Genre struct {
+	DB       *sql.DB
+	Validate *validator.Validate
+}
+
+// Genre model
+var GenreModel models.GenreModel
+
+func (g *Genre) CreateGenre(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+
+	var genreBody models.GenreBody
+	if err := c.BodyParser(&genreBody); err != nil {
+		log.Println("Error parsing JSON body:", err)
+		return apierrors.InvalidBody
+	}
+
+	// Validating input data
+	if err := validation.ValidateData(g.Validate, genreBody); err != nil {
+		return err
+	}
+
+	existingGenre, err := GenreModel.GetGenreByName(g.DB, genreBody.Name)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Println("Error getting genre by name:", err)
+			return apierrors.Internal
+		}
+	}
+
+	if existingGenre.ID != uuid.Nil {
+		log.Println("Trying to create a genre with duplicate name in DB")
+		return apierrors.GenreNameTaken
+	}
+
+	genreResponse, err := GenreModel.InsertGenreInDB(g.DB, genreBody)
+	if err != nil {
+		log.Println("Error inserting genre in DB:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusCreated).JSON(genreResponse)
+	return nil
+}
+
+func (g *Genre) ListAllGenresInDB(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+
+	// Query params
+	orderBy := c.Query("sort", "name,asc")
+	deletedQuery := c.Query("deleted", "false")
+
+	switch strings.ToLower(orderBy) {
+	case "created,asc":
+		orderBy = "created_at ASC"
+	case "created,desc":
+		orderBy = "created_at DESC"
+	case "updated,asc":
+		orderBy = "updated_at ASC"
+	case "updated,desc":
+		orderBy = "updated_at DESC"
+	case "name,desc":
+		orderBy = "name DESC"
+	default:
+		orderBy = "name ASC"
+	}
+
+	page, err := pageRequest(c, orderBy)
+	if err != nil {
+		return err
+	}
+
+	var deleted bool
+	if deletedQuery == "true" {
+		deleted = true
+	}
+
+	filters := models.GenreFilters{
+		Name: c.Query("name"),
+	}
+
+	genresList, err := GenreModel.GetAllGenres(g.DB, page, orderBy, deleted, filters)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Println("Error getting all genres:", err)
+			return apierrors.Internal
+		}
+	}
+
+	c.Status(fiber.StatusOK).JSON(genresList)
+	return nil
+}
+
+func (g *Genre) GetGenre(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+	uuidParam := c.Params("uuid")
+
+	uuid, err := uuid.Parse(uuidParam)
+	if err != nil {
+		log.Println("Invalid uuid sent in param:", err)
+		return apierrors.InvalidUUID
+	}
+
+	genreResponse, err := GenreModel.GetGenreById(g.DB, uuid)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Println("Genre id not found in database:", err)
+			return apierrors.GenreNotFound
+		}
+
+		log.Println("Error getting genre by id:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusOK).JSON(genreResponse)
+	return nil
+}
+
+func (g *Genre) GetGenreMovies(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+	uuidParam := c.Params("uuid")
+
+	uuid, err := uuid.Parse(uuidParam)
+	if err != nil {
+		log.Println("Invalid uuid sent in param:", err)
+		return apierrors.InvalidUUID
+	}
+
+	genreResponse, err := GenreModel.GetGenreByIdWithMovies(g.DB, uuid)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Println("Genre id not found in database:", err)
+			return apierrors.GenreNotFound
+		}
+
+		log.Println("Error getting genre by id with movies:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusOK).JSON(genreResponse)
+	return nil
+}
+
+func (g *Genre) DeleteGenre(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+	uuidParam := c.Params("uuid")
+
+	uuid, err := uuid.Parse(uuidParam)
+	if err != nil {
+		log.Println("Invalid uuid sent in param:", err)
+		return apierrors.InvalidUUID
+	}
+
+	_, err = GenreModel.GetGenreById(g.DB, uuid)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Println("Genre id not found in database:", err)
+			return apierrors.GenreNotFound
+		}
+
+		log.Println("Error getting genre by id:", err)
+		return apierrors.Internal
+	}
+
+	if err := GenreModel.DeleteGenreById(g.DB, uuid); err != nil {
+		log.Println("Error deleting genre in DB:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusNoContent)
+	return nil
+}
+
+func (g *Genre) UpdateGenre(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+	uuidParam := c.Params("uuid")
+
+	uuid, err := uuid.Parse(uuidParam)
+	if err != nil {
+		log.Println("Invalid uuid sent in param:", err)
+		return apierrors.InvalidUUID
+	}
+
+	_, err = GenreModel.GetGenreById(g.DB, uuid)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Println("Genre id not found in database:", err)
+			return apierrors.GenreNotFound
+		}
+
+		log.Println("Error getting genre by id:", err)
+		return apierrors.Internal
+	}
+
+	var genreBody models.GenreEditBody
+	if err := c.BodyParser(&genreBody); err != nil {
+		log.Println("Error parsing JSON body:", err)
+		return apierrors.InvalidBody
+	}
+
+	// Validating input data
+	if err := validation.ValidateData(g.Validate, genreBody); err != nil {
+		return err
+	}
+
+	// Verifying that the name is not a duplicate
+	existingGenre, err := GenreModel.GetGenreByName(g.DB, genreBody.Name)
+	if err == nil && existingGenre.ID != uuid {
+		return apierrors.GenreNameTaken
+	}
+
+	genreResponse, err := GenreModel.UpdateGenreById(g.DB, uuid, genreBody)
+	if err != nil {
+		log.Println("Error updating genre in DB:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusOK).JSON(genreResponse)
+	return nil
+}
@@ -0,0 +1,369 @@
+package controllers
+
+import (
+	"database/sql"
+	"log"
+	"strings"
+
+	"github.com/VinOfSteel/cinemagrader/apierrors"
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/VinOfSteel/cinemagrader/validation"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// Controller type
+type List struct {
+	DB       *sql.DB
+	Validate *validator.Validate
+}
+
+// List model
+var ListModel models.ListModel
+
+// GetActiveList gets the list of the uuid param, deleted lists are treated as if they didn't exist.
+// It's also used by the list middlewares, which check the owner of the list before the handlers run.
+func GetActiveList(db *sql.DB, uuidParam string) (models.ListResponse, error) {
+	listId, err := uuid.Parse(uuidParam)
+	if err != nil {
+		log.Println("Invalid uuid sent in param:", err)
+		return models.ListResponse{}, apierrors.InvalidUUID
+	}
+
+	list, err := ListModel.GetListById(db, listId)
+	if err != nil && err != sql.ErrNoRows {
+		log.Println("Error getting list by id:", err)
+		return models.ListResponse{}, apierrors.Internal
+	}
+
+	if err == sql.ErrNoRows || list.DeletedAt.Valid {
+		log.Println("List id not found in database:", listId)
+		return models.ListResponse{}, apierrors.ListNotFound
+	}
+
+	return list, nil
+}
+
+// listOrderBy turns the sort query param of the list routes into an order by clause
+func listOrderBy(sort string) string {
+	switch strings.ToLower(sort) {
+	case "created,asc":
+		return "created_at ASC"
+	case "updated,asc":
+		return "updated_at ASC"
+	case "updated,desc":
+		return "updated_at DESC"
+	case "name,asc":
+		return "name ASC"
+	case "name,desc":
+		return "name DESC"
+	case "movies,asc":
+		return "movie_count ASC"
+	case "movies,desc":
+		return "movie_count DESC"
+	default:
+		return "created_at DESC"
+	}
+}
+
+func (l *List) CreateList(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+
+	userId, err := activeUserFromParam(l.DB, c.Params("uuid"))
+	if err != nil {
+		return err
+	}
+
+	var listBody models.ListBody
+	if err := c.BodyParser(&listBody); err != nil {
+		log.Println("Error parsing JSON body:", err)
+		return apierrors.InvalidBody
+	}
+
+	// Validating input data
+	if err := validation.ValidateData(l.Validate, listBody); err != nil {
+		return err
+	}
+
+	listResponse, err := ListModel.InsertListInDB(l.DB, userId, listBody)
+	if err != nil {
+		log.Println("Error inserting list in DB:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusCreated).JSON(listResponse)
+	return nil
+}
+
+func (l *List) ListPublicLists(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+
+	// Query params
+	orderBy := listOrderBy(c.Query("sort", "created,desc"))
+
+	page, err := pageRequest(c, orderBy)
+	if err != nil {
+		return err
+	}
+
+	ownerId, err := queryUUID(c, "owner")
+	if err != nil {
+		return err
+	}
+
+	filters := models.ListFilters{
+		Name:    c.Query("name"),
+		OwnerId: ownerId,
+	}
+
+	lists, err := ListModel.GetAllPublicLists(l.DB, page, orderBy, filters)
+	if err != nil {
+		log.Println("Error getting all public lists:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusOK).JSON(lists)
+	return nil
+}
+
+func (l *List) GetUserLists(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+	uuidParam := c.Params("uuid")
+
+	uuid, err := uuid.Parse(uuidParam)
+	if err != nil {
+		log.Println("Invalid uuid sent in param:", err)
+		return apierrors.InvalidUUID
+	}
+
+	// Query params
+	orderBy := listOrderBy(c.Query("sort", "created,desc"))
+	deleted := c.Query("deleted", "false") == "true"
+
+	userWithLists, err := ListModel.GetUserLists(l.DB, uuid, orderBy, deleted)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Println("User id not found in database:", err)
+			return apierrors.UserNotFound
+		}
+
+		log.Println("Error getting lists of user:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusOK).JSON(userWithLists)
+	return nil
+}
+
+func (l *List) GetList(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+
+	list, err := GetActiveList(l.DB, c.Params("uuid"))
+	if err != nil {
+		return err
+	}
+
+	listResponse, err := ListModel.GetListByIdWithItems(l.DB, list.ID)
+	if err != nil {
+		log.Println("Error getting list by id with items:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusOK).JSON(listResponse)
+	return nil
+}
+
+func (l *List) UpdateList(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+
+	list, err := GetActiveList(l.DB, c.Params("uuid"))
+	if err != nil {
+		return err
+	}
+
+	var listBody models.ListEditBody
+	if err := c.BodyParser(&listBody); err != nil {
+		log.Println("Error parsing JSON body:", err)
+		return apierrors.InvalidBody
+	}
+
+	// Validating input data
+	if err := validation.ValidateData(l.Validate, listBody); err != nil {
+		return err
+	}
+
+	listResponse, err := ListModel.UpdateListById(l.DB, list.ID, listBody)
+	if err != nil {
+		log.Println("Error updating list in DB:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusOK).JSON(listResponse)
+	return nil
+}
+
+func (l *List) DeleteList(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+
+	list, err := GetActiveList(l.DB, c.Params("uuid"))
+	if err != nil {
+		return err
+	}
+
+	if err := ListModel.DeleteListById(l.DB, list.ID); err != nil {
+		log.Println("Error deleting list in DB:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusNoContent)
+	return nil
+}
+
+func (l *List) AddMovieToList(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+
+	list, err := GetActiveList(l.DB, c.Params("uuid"))
+	if err != nil {
+		return err
+	}
+
+	var itemBody models.ListItemBody
+	if err := c.BodyParser(&itemBody); err != nil {
+		log.Println("Error parsing JSON body:", err)
+		return apierrors.InvalidBody
+	}
+
+	// Validating input data
+	if err := validation.ValidateData(l.Validate, itemBody); err != nil {
+		return err
+	}
+
+	movieId, err := activeMovie(l.DB, itemBody.MovieId)
+	if err != nil {
+		return err
+	}
+
+	found, err := ListModel.IsMovieInList(l.DB, list.ID, movieId)
+	if err != nil {
+		log.Println("Error checking if movie is in list:", err)
+		return apierrors.Internal
+	}
+
+	if found {
+		return apierrors.MovieAlreadyInList
+	}
+
+	listResponse, err := ListModel.InsertListItemInDB(l.DB, list.ID, itemBody)
+	if err != nil {
+		log.Println("Error adding movie to list in DB:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusCreated).JSON(listResponse)
+	return nil
+}
+
+func (l *List) RemoveMovieFromList(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+
+	list, err := GetActiveList(l.DB, c.Params("uuid"))
+	if err != nil {
+		return err
+	}
+
+	movieId, err := uuid.Parse(c.Params("movieUuid"))
+	if err != nil {
+		log.Println("Invalid uuid sent in param:", err)
+		return apierrors.InvalidUUID
+	}
+
+	found, err := ListModel.IsMovieInList(l.DB, list.ID, movieId)
+	if err != nil {
+		log.Println("Error checking if movie is in list:", err)
+		return apierrors.Internal
+	}
+
+	if !found {
+		return apierrors.MovieNotInList
+	}
+
+	if err := ListModel.DeleteListItem(l.DB, list.ID, movieId); err != nil {
+		log.Println("Error removing movie from list in DB:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusNoContent)
+	return nil
+}
+
+// ReorderList sets the position of every movie of the list at once
+func (l *List) ReorderList(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+
+	list, err := GetActiveList(l.DB, c.Params("uuid"))
+	if err != nil {
+		return err
+	}
+
+	var orderBody models.ListOrderBody
+	if err := c.BodyParser(&orderBody); err != nil {
+		log.Println("Error parsing JSON body:", err)
+		return apierrors.InvalidBody
+	}
+
+	// Validating input data
+	if err := validation.ValidateData(l.Validate, orderBody); err != nil {
+		return err
+	}
+
+	reordered, err := ListModel.ReorderListItems(l.DB, list.ID, orderBody.MovieIds)
+	if err != nil {
+		log.Println("Error reordering list in DB:", err)
+		return apierrors.Internal
+	}
+
+	if !reordered {
+		return apierrors.InvalidListOrder
+	}
+
+	listResponse, err := ListModel.GetListByIdWithItems(l.DB, list.ID)
+	if err != nil {
+		log.Println("Error getting list by id with items:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusOK).JSON(listResponse)
+	return nil
+}
+
+// ForkList copies a list the logged user can see into a new private list of theirs
+func (l *List) ForkList(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+	claims := c.Locals(ClaimsLocalsKey).(jwt.MapClaims)
+
+	list, err := GetActiveList(l.DB, c.Params("uuid"))
+	if err != nil {
+		return err
+	}
+
+	userId, err := activeUserFromParam(l.DB, claims["id"].(string))
+	if err != nil {
+		return err
+	}
+
+	// Private lists of other users are hidden, as if they didn't exist
+	if !list.IsPublic && list.OwnerId != userId.String() && !HasPermission(claims, models.PermissionManageUsers) {
+		return apierrors.ListNotFound
+	}
+
+	listResponse, err := ListModel.ForkListInDB(l.DB, list.ID, userId)
+	if err != nil {
+		log.Println("Error forking list in DB:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusCreated).JSON(listResponse)
+	return nil
+}
@@ -0,0 +1,178 @@
+package controllers
+
+import (
+	"database/sql"
+	"log"
+	"strings"
+
+	"github.com/VinOfSteel/cinemagrader/apierrors"
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/VinOfSteel/cinemagrader/validation"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// Controller type
+type Moderation struct {
+	DB       *sql.DB
+	Validate *validator.Validate
+}
+
+// Moderation model
+var ModerationModel models.ModerationModel
+
+// ReportComment stores a report of the logged user on the comment of the param, sending it to the moderation queue
+func (m *Moderation) ReportComment(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+	claims := c.Locals(ClaimsLocalsKey).(jwt.MapClaims)
+
+	userId, err := activeUserFromParam(m.DB, claims["id"].(string))
+	if err != nil {
+		return err
+	}
+
+	commentId, err := uuid.Parse(c.Params("uuid"))
+	if err != nil {
+		log.Println("Invalid uuid sent in param:", err)
+		return apierrors.InvalidUUID
+	}
+
+	commentResponse, err := CommentModel.GetCommentById(m.DB, commentId)
+	if err != nil && err != sql.ErrNoRows {
+		log.Println("Error getting comment by id:", err)
+		return apierrors.Internal
+	}
+
+	// Only public comments can be reported
+	if err == sql.ErrNoRows || commentResponse.DeletedAt.Valid || commentResponse.Status == models.CommentStatusHidden {
+		log.Println("Comment id not found in database:", err)
+		return apierrors.CommentNotFound
+	}
+
+	if commentResponse.UserId == userId.String() {
+		return apierrors.SelfReport
+	}
+
+	reported, err := ModerationModel.HasOpenReport(m.DB, userId, commentId)
+	if err != nil {
+		log.Println("Error checking open report:", err)
+		return apierrors.Internal
+	}
+
+	if reported {
+		return apierrors.CommentAlreadyReported
+	}
+
+	var reportBody models.ReportBody
+	if err := c.BodyParser(&reportBody); err != nil {
+		log.Println("Error parsing JSON body:", err)
+		return apierrors.InvalidBody
+	}
+
+	// Validating input data
+	if err := validation.ValidateData(m.Validate, reportBody); err != nil {
+		return err
+	}
+
+	reportResponse, err := ModerationModel.InsertReportInDB(m.DB, userId, commentId, reportBody)
+	if err != nil {
+		log.Println("Error inserting report in DB:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusCreated).JSON(reportResponse)
+	return nil
+}
+
+// GetModerationQueue lists the comments held by the blocklist or reported by users, oldest first by default
+func (m *Moderation) GetModerationQueue(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+
+	orderBy := c.Query("sort", "flagged,asc")
+
+	switch strings.ToLower(orderBy) {
+	case "flagged,desc":
+		orderBy = "flagged_at DESC"
+	case "reports,desc":
+		orderBy = "report_count DESC"
+	case "reports,asc":
+		orderBy = "report_count ASC"
+	default:
+		orderBy = "flagged_at ASC"
+	}
+
+	page, err := pageRequest(c, orderBy)
+	if err != nil {
+		return err
+	}
+
+	queue, err := ModerationModel.GetModerationQueue(m.DB, page, orderBy)
+	if err != nil {
+		log.Println("Error getting moderation queue:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusOK).JSON(queue)
+	return nil
+}
+
+// ModerateComment applies the action of the logged admin to the comment of the param, resolving its reports
+func (m *Moderation) ModerateComment(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+	uuidParam := c.Params("uuid")
+	claims := c.Locals(ClaimsLocalsKey).(jwt.MapClaims)
+
+	uuid, err := uuid.Parse(uuidParam)
+	if err != nil {
+		log.Println("Invalid uuid sent in param:", err)
+		return apierrors.InvalidUUID
+	}
+
+	commentResponse, err := CommentModel.GetCommentById(m.DB, uuid)
+	if err != nil && err != sql.ErrNoRows {
+		log.Println("Error getting comment by id:", err)
+		return apierrors.Internal
+	}
+
+	if err == sql.ErrNoRows || commentResponse.DeletedAt.Valid {
+		log.Println("Comment id not found in database:", err)
+		return apierrors.CommentNotFound
+	}
+
+	var actionBody models.ModerationActionBody
+	if err := c.BodyParser(&actionBody); err != nil {
+		log.Println("Error parsing JSON body:", err)
+		return apierrors.InvalidBody
+	}
+
+	// Validating input data
+	if err := validation.ValidateData(m.Validate, actionBody); err != nil {
+		return err
+	}
+
+	// Moderators can hide comments, but banning their authors is up to who manages users
+	if actionBody.Action == models.ModerationBan && !HasPermission(claims, models.PermissionManageUsers) {
+		return apierrors.BanPermissionDenied
+	}
+
+	resolverId, err := activeUserFromParam(m.DB, claims["id"].(string))
+	if err != nil {
+		return err
+	}
+
+	// Like on the admin routes, the one banning can't be the one banned
+	if actionBody.Action == models.ModerationBan && commentResponse.UserId == resolverId.String() {
+		return apierrors.SelfBan
+	}
+
+	commentResponse, err = ModerationModel.ModerateComment(m.DB, uuid, resolverId, actionBody.Action)
+	if err != nil {
+		log.Println("Error moderating comment in DB:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusOK).JSON(commentResponse)
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/VinOfSteel/cinemagrader/apierrors"
 	"github.com/VinOfSteel/cinemagrader/models"
 	"github.com/VinOfSteel/cinemagrader/validation"
 	"github.com/go-playground/validator/v10"
@@ -20,107 +21,120 @@ type Movie struct {
 
 var MovieModel models.MovieModel
 
+// movieFilters reads the optional filters of the movies list from the query params
+func movieFilters(c *fiber.Ctx) (models.MovieFilters, error) {
+	var filters models.MovieFilters
+	var err error
+
+	if filters.ReleasedFrom, err = queryDate(c, "released_from"); err != nil {
+		return models.MovieFilters{}, err
+	}
+
+	if filters.ReleasedTo, err = queryDate(c, "released_to"); err != nil {
+		return models.MovieFilters{}, err
+	}
+
+	if filters.MinGrade, err = queryFloat(c, "min_grade"); err != nil {
+		return models.MovieFilters{}, err
+	}
+
+	if filters.MaxGrade, err = queryFloat(c, "max_grade"); err != nil {
+		return models.MovieFilters{}, err
+	}
+
+	if filters.ActorId, err = queryUUID(c, "actor"); err != nil {
+		return models.MovieFilters{}, err
+	}
+
+	if filters.GenreId, err = queryUUID(c, "genre"); err != nil {
+		return models.MovieFilters{}, err
+	}
+
+	filters.Director = c.Query("director")
+	filters.Title = c.Query("title")
+
+	return filters, nil
+}
+
 func (m *Movie) CreateMovie(c *fiber.Ctx) error {
 	c.Accepts("application/json")
 
 	var movieBody models.MovieBody
 	if err := c.BodyParser(&movieBody); err != nil {
 		log.Println("Error parsing JSON body:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Error while parsing JSON body, check your request",
-		}
+		return apierrors.InvalidBody
 	}
 
-	// Validating input data. We return "nil" because the ValidateData function sends a response back by itself and we need to return here to stop the function.
-	if valid := validation.ValidateData(c, m.Validate, movieBody); !valid {
-		return nil
+	// Validating input data
+	if err := validation.ValidateData(m.Validate, movieBody); err != nil {
+		return err
 	}
 
 	existingMovie, err := MovieModel.GetMovieByTitle(m.DB, movieBody.Title)
 	if err != nil {
 		if err != sql.ErrNoRows {
 			log.Println("Error getting movie by title:", err)
-			return &fiber.Error{
-				Code:    fiber.StatusInternalServerError,
-				Message: "Unknown error",
-			}
+			return apierrors.Internal
 		}
 	}
 
 	if existingMovie.ID != uuid.Nil {
 		log.Println("Trying to create a movie with duplicate title in DB")
-		return &fiber.Error{
-			Code:    fiber.StatusBadRequest,
-			Message: "Movie with this title already exists",
-		}
+		return apierrors.MovieTitleTaken
 	}
 
 	movieResponse, err := MovieModel.InsertMovieInDB(m.DB, movieBody)
 	if err != nil {
 		log.Println("Error inserting movie in DB:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Unknown error",
-		}
+		return apierrors.Internal
 	}
 
 	c.Status(fiber.StatusCreated).JSON(movieResponse)
 	return nil
 }
 
-func (m *Movie) ListAllMoviesInDB(c *fiber.Ctx) error {
-	c.Accepts("application/json")
-
-	offset := c.Query("offset", "0")
-	limit := c.Query("limit", "10")
-	orderBy := c.Query("sort", "created,desc")
-	deletedQuery := c.Query("deleted", "false")
-	actorsQuery := c.Query("with_actors", "false")
-
-	offsetInt, err := strconv.Atoi(offset)
-	if err != nil {
-		log.Println("Invalid offset value:", offset)
-		return &fiber.Error{
-			Code:    fiber.StatusBadRequest,
-			Message: "Offset needs to be a valid integer",
-		}
-	}
-
-	limitInt, err := strconv.Atoi(limit)
-	if err != nil {
-		log.Println("Invalid limit value:", limit)
-		return &fiber.Error{
-			Code:    fiber.StatusBadRequest,
-			Message: "Limit needs to be a valid integer",
-		}
-	}
-
-	switch strings.ToLower(orderBy) {
+// movieOrderBy turns the sort query param of the movies lists into an order by clause
+func movieOrderBy(sort string) string {
+	switch strings.ToLower(sort) {
 	case "created,asc":
-		orderBy = "created_at ASC"
+		return "created_at ASC"
 	case "created,desc":
-		orderBy = "created_at DESC"
+		return "created_at DESC"
 	case "title,asc":
-		orderBy = "title ASC"
+		return "title ASC"
 	case "title,desc":
-		orderBy = "title DESC"
+		return "title DESC"
 	case "director,asc":
-		orderBy = "director ASC"
+		return "director ASC"
 	case "director,desc":
-		orderBy = "director DESC"
+		return "director DESC"
 	case "release_date,asc":
-		orderBy = "release_date ASC"
+		return "release_date ASC"
 	case "release_date,desc":
-		orderBy = "release_date DESC"
+		return "release_date DESC"
 	case "average_grade,asc":
-		orderBy = "average_grade ASC"
+		return "average_grade ASC"
 	case "average_grade,desc":
-		orderBy = "average_grade DESC"
+		return "average_grade DESC"
+	case "weighted_rating,asc":
+		return "weighted_rating ASC"
+	case "weighted_rating,desc":
+		return "weighted_rating DESC"
 	case "updated,asc":
-		orderBy = "updated_at ASC"
+		return "updated_at ASC"
 	default:
-		orderBy = "updated_at DESC"
+		return "updated_at DESC"
+	}
+}
+
+// listMovies sends a page of the movies that match the filters, with their actors if the with_actors query param is true
+func (m *Movie) listMovies(c *fiber.Ctx, orderBy string, filters models.MovieFilters) error {
+	deletedQuery := c.Query("deleted", "false")
+	actorsQuery := c.Query("with_actors", "false")
+
+	page, err := pageRequest(c, orderBy)
+	if err != nil {
+		return err
 	}
 
 	var deleted bool
@@ -134,14 +148,11 @@ func (m *Movie) ListAllMoviesInDB(c *fiber.Ctx) error {
 	}
 
 	if withActors {
-		moviesList, err := MovieModel.GetAllMoviesWithActors(m.DB, offsetInt, limitInt, orderBy, deleted)
+		moviesList, err := MovieModel.GetAllMoviesWithActors(m.DB, page, orderBy, deleted, filters)
 		if err != nil {
 			if err != sql.ErrNoRows {
 				log.Println("Error getting all movies with actors:", err)
-				return &fiber.Error{
-					Code:    fiber.StatusInternalServerError,
-					Message: "Unknown error",
-				}
+				return apierrors.Internal
 			}
 		}
 
@@ -149,14 +160,11 @@ func (m *Movie) ListAllMoviesInDB(c *fiber.Ctx) error {
 		return nil
 	}
 
-	moviesList, err := MovieModel.GetAllMovies(m.DB, offsetInt, limitInt, orderBy, deleted)
+	moviesList, err := MovieModel.GetAllMovies(m.DB, page, orderBy, deleted, filters)
 	if err != nil {
 		if err != sql.ErrNoRows {
 			log.Println("Error getting all movies:", err)
-			return &fiber.Error{
-				Code:    fiber.StatusInternalServerError,
-				Message: "Unknown error",
-			}
+			return apierrors.Internal
 		}
 	}
 
@@ -164,6 +172,67 @@ func (m *Movie) ListAllMoviesInDB(c *fiber.Ctx) error {
 	return nil
 }
 
+func (m *Movie) ListAllMoviesInDB(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+
+	orderBy := movieOrderBy(c.Query("sort", "created,desc"))
+
+	filters, err := movieFilters(c)
+	if err != nil {
+		return err
+	}
+
+	return m.listMovies(c, orderBy, filters)
+}
+
+// ListTopMovies lists the reviewed movies, best weighted rating first unless another sort is sent
+func (m *Movie) ListTopMovies(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+
+	orderBy := movieOrderBy(c.Query("sort", "weighted_rating,desc"))
+
+	filters, err := movieFilters(c)
+	if err != nil {
+		return err
+	}
+	filters.RatedOnly = true
+
+	return m.listMovies(c, orderBy, filters)
+}
+
+// ListTrendingMovies lists the movies reviewed in the last "window" days (7 by default),
+// the ones with the most reviews per day first unless another sort is sent
+func (m *Movie) ListTrendingMovies(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+
+	sort := c.Query("sort", "trending,desc")
+	window := c.Query("window", "7")
+
+	var orderBy string
+	switch strings.ToLower(sort) {
+	case "trending,asc":
+		orderBy = "review_velocity ASC"
+	case "trending,desc":
+		orderBy = "review_velocity DESC"
+	default:
+		orderBy = movieOrderBy(sort)
+	}
+
+	windowDays, err := strconv.Atoi(window)
+	if err != nil || windowDays < 1 || windowDays > 365 {
+		log.Println("Invalid window value:", window)
+		return apierrors.InvalidQueryParam("window", apierrors.DaysBetween, "1", "365")
+	}
+
+	filters, err := movieFilters(c)
+	if err != nil {
+		return err
+	}
+	filters.TrendingDays = windowDays
+
+	return m.listMovies(c, orderBy, filters)
+}
+
 func (m *Movie) GetMovie(c *fiber.Ctx) error {
 	c.Accepts("application/json")
 	uuidParam := c.Params("uuid")
@@ -171,27 +240,18 @@ func (m *Movie) GetMovie(c *fiber.Ctx) error {
 	uuid, err := uuid.Parse(uuidParam)
 	if err != nil {
 		log.Println("Invalid uuid sent in param:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusBadRequest,
-			Message: "Invalid uuid parameter",
-		}
+		return apierrors.InvalidUUID
 	}
 
 	movieResponse, err := MovieModel.GetMovieByIdWithActors(m.DB, uuid)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			log.Println("Movie id not found in database:", err)
-			return &fiber.Error{
-				Code:    fiber.StatusNotFound,
-				Message: "Movie id not found in database",
-			}
+			return apierrors.MovieNotFound
 		}
 
 		log.Println("Error getting movie by id:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Unknown error",
-		}
+		return apierrors.Internal
 	}
 
 	c.Status(fiber.StatusOK).JSON(movieResponse)
@@ -205,35 +265,23 @@ func (m *Movie) DeleteMovie(c *fiber.Ctx) error {
 	uuid, err := uuid.Parse(uuidParam)
 	if err != nil {
 		log.Println("Invalid uuid sent in param:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusBadRequest,
-			Message: "Invalid uuid parameter",
-		}
+		return apierrors.InvalidUUID
 	}
 
 	_, err = MovieModel.GetMovieByIdWithActors(m.DB, uuid)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			log.Println("Movie id not found in database:", err)
-			return &fiber.Error{
-				Code:    fiber.StatusNotFound,
-				Message: "Movie id not found in database",
-			}
+			return apierrors.MovieNotFound
 		}
 
 		log.Println("Error getting movie by id:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Unknown error",
-		}
+		return apierrors.Internal
 	}
 
 	if err := MovieModel.DeleteMovieById(m.DB, uuid); err != nil {
 		log.Println("Error deleting movie in DB:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Couldn't delete movie in DB",
-		}
+		return apierrors.Internal
 	}
 
 	c.Status(fiber.StatusNoContent)
@@ -247,59 +295,41 @@ func (m *Movie) UpdateMovie(c *fiber.Ctx) error {
 	uuid, err := uuid.Parse(uuidParam)
 	if err != nil {
 		log.Println("Invalid uuid sent in param:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusBadRequest,
-			Message: "Invalid uuid parameter",
-		}
+		return apierrors.InvalidUUID
 	}
 
 	_, err = MovieModel.GetMovieByIdWithActors(m.DB, uuid)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			log.Println("Movie id not found in database:", err)
-			return &fiber.Error{
-				Code:    fiber.StatusNotFound,
-				Message: "Movie id not found in database",
-			}
+			return apierrors.MovieNotFound
 		}
 
 		log.Println("Error getting movie by id:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Unknown error",
-		}
+		return apierrors.Internal
 	}
 
 	var movieBody models.MovieEditBody
 	if err := c.BodyParser(&movieBody); err != nil {
 		log.Println("Error parsing JSON body:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Error while parsing JSON body, check your request",
-		}
+		return apierrors.InvalidBody
 	}
 
-	// Validating input data. We return "nil" because the ValidateData function sends a response back by itself and we need to return here to stop the function.
-	if valid := validation.ValidateData(c, m.Validate, movieBody); !valid {
-		return nil
+	// Validating input data
+	if err := validation.ValidateData(m.Validate, movieBody); err != nil {
+		return err
 	}
 
 	// Verifying that the title is not a duplicate
 	existingMovie, err := MovieModel.GetMovieByTitle(m.DB, movieBody.Title)
 	if err == nil && existingMovie.ID != uuid {
-		return &fiber.Error{
-			Code:    fiber.StatusBadRequest,
-			Message: "Movie with this title already exists",
-		}
+		return apierrors.MovieTitleTaken
 	}
 
 	movieResponse, err := MovieModel.UpdateMovieById(m.DB, uuid, movieBody)
 	if err != nil {
 		log.Println("Error updating movie in DB:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Unknown error",
-		}
+		return apierrors.Internal
 	}
 
 	c.Status(fiber.StatusOK).JSON(movieResponse)
@@ -313,48 +343,33 @@ func (m *Movie) CreateActorsRelationshipsWithMovie(c *fiber.Ctx) error {
 	uuid, err := uuid.Parse(uuidParam)
 	if err != nil {
 		log.Println("Invalid uuid sent in param:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusBadRequest,
-			Message: "Invalid uuid parameter",
-		}
+		return apierrors.InvalidUUID
 	}
 
 	movieResponse, err := MovieModel.GetMovieByIdWithActors(m.DB, uuid)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			log.Println("Movie id not found in database:", err)
-			return &fiber.Error{
-				Code:    fiber.StatusNotFound,
-				Message: "Movie id not found in database",
-			}
+			return apierrors.MovieNotFound
 		}
 
 		log.Println("Error getting movie by id:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Unknown error",
-		}
+		return apierrors.Internal
 	}
 
 	if movieResponse.DeletedAt.Valid {
-		return &fiber.Error{
-			Code:    fiber.StatusBadRequest,
-			Message: "Trying to create an actor relationship on a deleted movie, check your request",
-		}
+		return apierrors.CastOnDeletedMovie
 	}
 
 	var movieActorsBody models.MovieActorsBody
 	if err := c.BodyParser(&movieActorsBody); err != nil {
 		log.Println("Error parsing JSON body:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Error while parsing JSON body, check your request",
-		}
+		return apierrors.InvalidBody
 	}
 
-	// Validating input data. We return "nil" because the ValidateData function sends a response back by itself and we need to return here to stop the function.
-	if valid := validation.ValidateData(c, m.Validate, movieActorsBody); !valid {
-		return nil
+	// Validating input data
+	if err := validation.ValidateData(m.Validate, movieActorsBody); err != nil {
+		return err
 	}
 
 	// Checking if trying to add an actor already in the movie to the movie
@@ -363,21 +378,15 @@ func (m *Movie) CreateActorsRelationshipsWithMovie(c *fiber.Ctx) error {
 		actorUUIDs[actorUUID.ID.String()] = struct{}{}
 	}
 
-	for _, actorIDInBody := range movieActorsBody.Actors {
-		if _, ok := actorUUIDs[actorIDInBody]; ok {
-			return &fiber.Error{
-				Code:    fiber.StatusBadRequest,
-				Message: "There is an actor already in the movie on the request",
-			}
+	for _, castingInBody := range movieActorsBody.Actors {
+		if _, ok := actorUUIDs[castingInBody.ActorId]; ok {
+			return apierrors.ActorAlreadyInMovie
 		}
 	}
 
 	if err := MovieModel.InsertActorsRelationshipsWithMovie(m.DB, uuid, movieActorsBody); err != nil {
 		log.Println("Error associating actors with movie in DB:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Couldn't associate actors with movie, check your request",
-		}
+		return apierrors.Internal
 	}
 
 	c.Status(fiber.StatusNoContent)
@@ -391,41 +400,29 @@ func (m *Movie) DeleteActorsRelationshipsWithMovie(c *fiber.Ctx) error {
 	uuid, err := uuid.Parse(uuidParam)
 	if err != nil {
 		log.Println("Invalid uuid sent in param:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusBadRequest,
-			Message: "Invalid uuid parameter",
-		}
+		return apierrors.InvalidUUID
 	}
 
 	movieResponse, err := MovieModel.GetMovieByIdWithActors(m.DB, uuid)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			log.Println("Movie id not found in database:", err)
-			return &fiber.Error{
-				Code:    fiber.StatusNotFound,
-				Message: "Movie id not found in database",
-			}
+			return apierrors.MovieNotFound
 		}
 
 		log.Println("Error getting movie by id:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Unknown error",
-		}
+		return apierrors.Internal
 	}
 
 	var movieActorsBody models.MovieActorsBody
 	if err := c.BodyParser(&movieActorsBody); err != nil {
 		log.Println("Error parsing JSON body:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Error while parsing JSON body, check your request",
-		}
+		return apierrors.InvalidBody
 	}
 
-	// Validating input data. We return "nil" because the ValidateData function sends a response back by itself and we need to return here to stop the function.
-	if valid := validation.ValidateData(c, m.Validate, movieActorsBody); !valid {
-		return nil
+	// Validating input data
+	if err := validation.ValidateData(m.Validate, movieActorsBody); err != nil {
+		return err
 	}
 
 	// Checking if trying to delete an actor that is not on the movie
@@ -434,38 +431,312 @@ func (m *Movie) DeleteActorsRelationshipsWithMovie(c *fiber.Ctx) error {
 		actorUUIDs[actorUUID.ID.String()] = struct{}{}
 	}
 
-	for _, actorIDInBody := range movieActorsBody.Actors {
-		if _, ok := actorUUIDs[actorIDInBody]; !ok {
-			return &fiber.Error{
-				Code:    fiber.StatusBadRequest,
-				Message: "Trying to delete an actor that is already not on the movie",
-			}
+	for _, castingInBody := range movieActorsBody.Actors {
+		if _, ok := actorUUIDs[castingInBody.ActorId]; !ok {
+			return apierrors.ActorNotInMovie
 		}
 	}
 
 	if err := MovieModel.DeleteActorsRelationshipsWithMovie(m.DB, uuid, movieActorsBody); err != nil {
 		log.Println("Error deleting actors from movie in DB:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Couldn't delete actors from movie, check your request",
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusNoContent)
+	return nil
+}
+
+func (m *Movie) UpdateActorCastingInMovie(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+
+	movieUUID, err := uuid.Parse(c.Params("uuid"))
+	if err != nil {
+		log.Println("Invalid uuid sent in param:", err)
+		return apierrors.InvalidUUID
+	}
+
+	actorUUID, err := uuid.Parse(c.Params("actorUuid"))
+	if err != nil {
+		log.Println("Invalid actor uuid sent in param:", err)
+		return apierrors.InvalidUUID
+	}
+
+	movieResponse, err := MovieModel.GetMovieByIdWithActors(m.DB, movieUUID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Println("Movie id not found in database:", err)
+			return apierrors.MovieNotFound
+		}
+
+		log.Println("Error getting movie by id:", err)
+		return apierrors.Internal
+	}
+
+	var actorIsInTheCast bool
+	for _, actor := range movieResponse.Actors {
+		if actor.ID == actorUUID {
+			actorIsInTheCast = true
+			break
+		}
+	}
+
+	if !actorIsInTheCast {
+		return apierrors.CastingNotFound
+	}
+
+	var castingBody models.CastingEditBody
+	if err := c.BodyParser(&castingBody); err != nil {
+		log.Println("Error parsing JSON body:", err)
+		return apierrors.InvalidBody
+	}
+
+	// Validating input data
+	if err := validation.ValidateData(m.Validate, castingBody); err != nil {
+		return err
+	}
+
+	if castingBody.CharacterName == "" && castingBody.BillingOrder == 0 {
+		return apierrors.EmptyCastingUpdate
+	}
+
+	castingResponse, err := MovieModel.UpdateCastingOfMovie(m.DB, movieUUID, actorUUID, castingBody)
+	if err != nil {
+		log.Println("Error updating casting of movie in DB:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusOK).JSON(castingResponse)
+	return nil
+}
+
+func (m *Movie) CreateGenresRelationshipsWithMovie(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+	uuidParam := c.Params("uuid")
+
+	uuid, err := uuid.Parse(uuidParam)
+	if err != nil {
+		log.Println("Invalid uuid sent in param:", err)
+		return apierrors.InvalidUUID
+	}
+
+	movieResponse, err := MovieModel.GetMovieByIdWithActors(m.DB, uuid)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Println("Movie id not found in database:", err)
+			return apierrors.MovieNotFound
+		}
+
+		log.Println("Error getting movie by id:", err)
+		return apierrors.Internal
+	}
+
+	if movieResponse.DeletedAt.Valid {
+		return apierrors.GenresOnDeletedMovie
+	}
+
+	var movieGenresBody models.MovieGenresBody
+	if err := c.BodyParser(&movieGenresBody); err != nil {
+		log.Println("Error parsing JSON body:", err)
+		return apierrors.InvalidBody
+	}
+
+	// Validating input data
+	if err := validation.ValidateData(m.Validate, movieGenresBody); err != nil {
+		return err
+	}
+
+	// Checking if trying to add a genre already in the movie to the movie
+	genreUUIDs := make(map[string]struct{})
+	for _, genre := range movieResponse.Genres {
+		genreUUIDs[genre.ID.String()] = struct{}{}
+	}
+
+	for _, genreIDInBody := range movieGenresBody.Genres {
+		if _, ok := genreUUIDs[genreIDInBody]; ok {
+			return apierrors.GenreAlreadyInMovie
 		}
 	}
 
+	if err := MovieModel.InsertGenresRelationshipsWithMovie(m.DB, uuid, movieGenresBody); err != nil {
+		log.Println("Error associating genres with movie in DB:", err)
+		return apierrors.Internal
+	}
+
 	c.Status(fiber.StatusNoContent)
 	return nil
 }
 
-func (m *Movie) GetMovieComments(c *fiber.Ctx) error {
+func (m *Movie) DeleteGenresRelationshipsWithMovie(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+	uuidParam := c.Params("uuid")
+
+	uuid, err := uuid.Parse(uuidParam)
+	if err != nil {
+		log.Println("Invalid uuid sent in param:", err)
+		return apierrors.InvalidUUID
+	}
+
+	movieResponse, err := MovieModel.GetMovieByIdWithActors(m.DB, uuid)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Println("Movie id not found in database:", err)
+			return apierrors.MovieNotFound
+		}
+
+		log.Println("Error getting movie by id:", err)
+		return apierrors.Internal
+	}
+
+	var movieGenresBody models.MovieGenresBody
+	if err := c.BodyParser(&movieGenresBody); err != nil {
+		log.Println("Error parsing JSON body:", err)
+		return apierrors.InvalidBody
+	}
+
+	// Validating input data
+	if err := validation.ValidateData(m.Validate, movieGenresBody); err != nil {
+		return err
+	}
+
+	// Checking if trying to delete a genre that is not on the movie
+	genreUUIDs := make(map[string]struct{})
+	for _, genre := range movieResponse.Genres {
+		genreUUIDs[genre.ID.String()] = struct{}{}
+	}
+
+	for _, genreIDInBody := range movieGenresBody.Genres {
+		if _, ok := genreUUIDs[genreIDInBody]; !ok {
+			return apierrors.GenreNotInMovie
+		}
+	}
+
+	if err := MovieModel.DeleteGenresRelationshipsWithMovie(m.DB, uuid, movieGenresBody); err != nil {
+		log.Println("Error deleting genres from movie in DB:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusNoContent)
+	return nil
+}
+
+func (m *Movie) CreateCrewRelationshipsWithMovie(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+	uuidParam := c.Params("uuid")
+
+	uuid, err := uuid.Parse(uuidParam)
+	if err != nil {
+		log.Println("Invalid uuid sent in param:", err)
+		return apierrors.InvalidUUID
+	}
+
+	movieResponse, err := MovieModel.GetMovieByIdWithActors(m.DB, uuid)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Println("Movie id not found in database:", err)
+			return apierrors.MovieNotFound
+		}
+
+		log.Println("Error getting movie by id:", err)
+		return apierrors.Internal
+	}
+
+	if movieResponse.DeletedAt.Valid {
+		return apierrors.CrewOnDeletedMovie
+	}
+
+	var movieCrewBody models.MovieCrewBody
+	if err := c.BodyParser(&movieCrewBody); err != nil {
+		log.Println("Error parsing JSON body:", err)
+		return apierrors.InvalidBody
+	}
+
+	// Validating input data
+	if err := validation.ValidateData(m.Validate, movieCrewBody); err != nil {
+		return err
+	}
+
+	// Checking if trying to add a person to the movie with a role they already have in it
+	crewCredits := make(map[string]struct{})
+	for _, member := range movieResponse.Crew {
+		crewCredits[member.ID.String()+member.Role] = struct{}{}
+	}
+
+	for _, memberInBody := range movieCrewBody.Crew {
+		if _, ok := crewCredits[memberInBody.PersonId+memberInBody.Role]; ok {
+			return apierrors.CrewAlreadyInMovie
+		}
+	}
+
+	if err := MovieModel.InsertCrewRelationshipsWithMovie(m.DB, uuid, movieCrewBody); err != nil {
+		log.Println("Error associating crew with movie in DB:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusNoContent)
+	return nil
+}
+
+func (m *Movie) DeleteCrewRelationshipsWithMovie(c *fiber.Ctx) error {
 	c.Accepts("application/json")
 	uuidParam := c.Params("uuid")
 
 	uuid, err := uuid.Parse(uuidParam)
 	if err != nil {
 		log.Println("Invalid uuid sent in param:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusBadRequest,
-			Message: "Invalid uuid parameter",
+		return apierrors.InvalidUUID
+	}
+
+	movieResponse, err := MovieModel.GetMovieByIdWithActors(m.DB, uuid)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Println("Movie id not found in database:", err)
+			return apierrors.MovieNotFound
 		}
+
+		log.Println("Error getting movie by id:", err)
+		return apierrors.Internal
+	}
+
+	var movieCrewBody models.MovieCrewBody
+	if err := c.BodyParser(&movieCrewBody); err != nil {
+		log.Println("Error parsing JSON body:", err)
+		return apierrors.InvalidBody
+	}
+
+	// Validating input data
+	if err := validation.ValidateData(m.Validate, movieCrewBody); err != nil {
+		return err
+	}
+
+	// Checking if trying to delete a credit that the movie doesn't have
+	crewCredits := make(map[string]struct{})
+	for _, member := range movieResponse.Crew {
+		crewCredits[member.ID.String()+member.Role] = struct{}{}
+	}
+
+	for _, memberInBody := range movieCrewBody.Crew {
+		if _, ok := crewCredits[memberInBody.PersonId+memberInBody.Role]; !ok {
+			return apierrors.CrewNotInMovie
+		}
+	}
+
+	if err := MovieModel.DeleteCrewRelationshipsWithMovie(m.DB, uuid, movieCrewBody); err != nil {
+		log.Println("Error deleting crew from movie in DB:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusNoContent)
+	return nil
+}
+
+func (m *Movie) GetMovieComments(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+	uuidParam := c.Params("uuid")
+
+	uuid, err := uuid.Parse(uuidParam)
+	if err != nil {
+		log.Println("Invalid uuid sent in param:", err)
+		return apierrors.InvalidUUID
 	}
 
 	// Query params
@@ -483,6 +754,8 @@ func (m *Movie) GetMovieComments(c *fiber.Ctx) error {
 		orderBy = "grade DESC"
 	case "updated,asc":
 		orderBy = "updated_at ASC"
+	case "top":
+		orderBy = "top_score DESC"
 	default:
 		orderBy = "updated_at DESC"
 	}
@@ -492,40 +765,70 @@ func (m *Movie) GetMovieComments(c *fiber.Ctx) error {
 		deleted = true
 	}
 
+	threaded, err := queryBool(c, "threaded")
+	if err != nil {
+		return err
+	}
+
 	_, err = MovieModel.GetMovieByIdWithActors(m.DB, uuid)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			log.Println("Movie id not found in database:", err)
-			return &fiber.Error{
-				Code:    fiber.StatusNotFound,
-				Message: "Movie id not found in database",
-			}
+			return apierrors.MovieNotFound
 		}
 
 		log.Println("Error getting movie by id:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Unknown error",
-		}
+		return apierrors.Internal
 	}
 
 	movieWithCommentsResponse, err := CommentModel.GetAllCommentsInAMovieInDb(m.DB, uuid, orderBy, deleted)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			log.Println("Movie id not found in database:", err)
-			return &fiber.Error{
-				Code:    fiber.StatusNotFound,
-				Message: "Movie id not found in database",
-			}
+			return apierrors.MovieNotFound
 		}
 
 		log.Println("Error getting movie by id:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Unknown error",
+		return apierrors.Internal
+	}
+
+	canSeeSpoilers, viewerId, err := spoilerViewer(m.DB, c, uuid)
+	if err != nil {
+		return err
+	}
+
+	// Users always see their own comments as they wrote them
+	if !canSeeSpoilers {
+		for i, comment := range movieWithCommentsResponse.Comments {
+			if comment.UserId != viewerId {
+				movieWithCommentsResponse.Comments[i] = models.RedactComment(comment)
+			}
 		}
 	}
 
+	// Threads only have the comments on the movie at the top, with the replies nested inside them
+	if threaded != nil && *threaded {
+		movieWithCommentsResponse.Comments = models.NestComments(movieWithCommentsResponse.Comments)
+	}
+
 	c.Status(fiber.StatusOK).JSON(movieWithCommentsResponse)
 	return nil
 }
+
+// Response of the recompute route, with how many movies had their rating stats rebuilt
+type RecomputeStatsResponse struct {
+	MoviesRecomputed int `json:"moviesRecomputed"`
+}
+
+func (m *Movie) RecomputeMoviesStats(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+
+	count, err := MovieModel.RecomputeMoviesRatings(m.DB)
+	if err != nil {
+		log.Println("Error recomputing rating stats of movies:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusOK).JSON(RecomputeStatsResponse{MoviesRecomputed: count})
+	return nil
+}
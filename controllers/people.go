@@ -0,0 +1,224 @@
+package controllers
+
+import (
+	"database/sql"
+	"log"
+	"strings"
+
+	"github.com/VinOfSteel/cinemagrader/apierrors"
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/VinOfSteel/cinemagrader/validation"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// Controller type
+type Person struct {
+	DB       *sql.DB
+	Validate *validator.Validate
+}
+
+// Person model
+var PersonModel models.PersonModel
+
+func (p *Person) CreatePerson(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+
+	var personBody models.PersonBody
+	if err := c.BodyParser(&personBody); err != nil {
+		log.Println("Error parsing JSON body:", err)
+		return apierrors.InvalidBody
+	}
+
+	// Validating input data
+	if err := validation.ValidateData(p.Validate, personBody); err != nil {
+		return err
+	}
+
+	personResponse, err := PersonModel.InsertPersonInDB(p.DB, personBody)
+	if err != nil {
+		log.Println("Error inserting person in DB:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusCreated).JSON(personResponse)
+	return nil
+}
+
+func (p *Person) ListAllPeopleInDB(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+
+	// Query params
+	orderBy := c.Query("sort", "name,asc")
+	deletedQuery := c.Query("deleted", "false")
+
+	switch strings.ToLower(orderBy) {
+	case "created,asc":
+		orderBy = "created_at ASC"
+	case "created,desc":
+		orderBy = "created_at DESC"
+	case "updated,asc":
+		orderBy = "updated_at ASC"
+	case "updated,desc":
+		orderBy = "updated_at DESC"
+	case "name,desc":
+		orderBy = "name DESC"
+	default:
+		orderBy = "name ASC"
+	}
+
+	page, err := pageRequest(c, orderBy)
+	if err != nil {
+		return err
+	}
+
+	var deleted bool
+	if deletedQuery == "true" {
+		deleted = true
+	}
+
+	filters := models.PersonFilters{
+		Name: c.Query("name"),
+		Role: strings.ToLower(c.Query("role")),
+	}
+
+	switch filters.Role {
+	case "", models.CrewRoleDirector, models.CrewRoleWriter, models.CrewRoleProducer, models.CrewRoleComposer, models.CrewRoleCinematographer:
+	default:
+		return apierrors.InvalidQueryParam("role", apierrors.OneOf, "director, writer, producer, composer, cinematographer")
+	}
+
+	peopleList, err := PersonModel.GetAllPeople(p.DB, page, orderBy, deleted, filters)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Println("Error getting all people:", err)
+			return apierrors.Internal
+		}
+	}
+
+	c.Status(fiber.StatusOK).JSON(peopleList)
+	return nil
+}
+
+func (p *Person) GetPerson(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+	uuidParam := c.Params("uuid")
+
+	uuid, err := uuid.Parse(uuidParam)
+	if err != nil {
+		log.Println("Invalid uuid sent in param:", err)
+		return apierrors.InvalidUUID
+	}
+
+	personResponse, err := PersonModel.GetPersonById(p.DB, uuid)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Println("Person id not found in database:", err)
+			return apierrors.PersonNotFound
+		}
+
+		log.Println("Error getting person by id:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusOK).JSON(personResponse)
+	return nil
+}
+
+func (p *Person) GetPersonFilmography(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+	uuidParam := c.Params("uuid")
+
+	uuid, err := uuid.Parse(uuidParam)
+	if err != nil {
+		log.Println("Invalid uuid sent in param:", err)
+		return apierrors.InvalidUUID
+	}
+
+	personResponse, err := PersonModel.GetPersonFilmography(p.DB, uuid)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Println("Person id not found in database:", err)
+			return apierrors.PersonNotFound
+		}
+
+		log.Println("Error getting filmography of person:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusOK).JSON(personResponse)
+	return nil
+}
+
+func (p *Person) DeletePerson(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+	uuidParam := c.Params("uuid")
+
+	uuid, err := uuid.Parse(uuidParam)
+	if err != nil {
+		log.Println("Invalid uuid sent in param:", err)
+		return apierrors.InvalidUUID
+	}
+
+	_, err = PersonModel.GetPersonById(p.DB, uuid)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Println("Person id not found in database:", err)
+			return apierrors.PersonNotFound
+		}
+
+		log.Println("Error getting person by id:", err)
+		return apierrors.Internal
+	}
+
+	if err := PersonModel.DeletePersonById(p.DB, uuid); err != nil {
+		log.Println("Error deleting person in DB:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusNoContent)
+	return nil
+}
+
+func (p *Person) UpdatePerson(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+	uuidParam := c.Params("uuid")
+
+	uuid, err := uuid.Parse(uuidParam)
+	if err != nil {
+		log.Println("Invalid uuid sent in param:", err)
+		return apierrors.InvalidUUID
+	}
+
+	_, err = PersonModel.GetPersonById(p.DB, uuid)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Println("Person id not found in database:", err)
+			return apierrors.PersonNotFound
+		}
+
+		log.Println("Error getting person by id:", err)
+		return apierrors.Internal
+	}
+
+	var personBody models.PersonEditBody
+	if err := c.BodyParser(&personBody); err != nil {
+		log.Println("Error parsing JSON body:", err)
+		return apierrors.InvalidBody
+	}
+
+	// Validating input data
+	if err := validation.ValidateData(p.Validate, personBody); err != nil {
+		return err
+	}
+
+	personResponse, err := PersonModel.UpdatePersonById(p.DB, uuid, personBody)
+	if err != nil {
+		log.Println("Error updating person in DB:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusOK).JSON(personResponse)
+	return nil
+}
@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/VinOfSteel/cinemagrader/apierrors"
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// pageRequest reads the pagination params of the list routes. A cursor, taken from the "next" or "prev"
+// of a previous response, takes precedence over the offset, which is kept for older clients.
+func pageRequest(c *fiber.Ctx, orderBy string) (models.PageRequest, error) {
+	offset := c.Query("offset", "0")
+	limit := c.Query("limit", "10")
+	cursorQuery := c.Query("cursor")
+
+	offsetInt, err := strconv.Atoi(offset)
+	if err != nil {
+		log.Println("Invalid offset value:", offset)
+		return models.PageRequest{}, apierrors.InvalidOffset
+	}
+
+	limitInt, err := strconv.Atoi(limit)
+	if err != nil {
+		log.Println("Invalid limit value:", limit)
+		return models.PageRequest{}, apierrors.InvalidLimit
+	}
+
+	if offsetInt < 0 || limitInt < 0 {
+		log.Printf("Negative offset %v or limit %v\n", offsetInt, limitInt)
+		return models.PageRequest{}, apierrors.NegativePagination
+	}
+
+	page := models.PageRequest{Offset: offsetInt, Limit: limitInt}
+	if cursorQuery == "" {
+		return page, nil
+	}
+
+	cursor, err := models.DecodeCursor(cursorQuery)
+	if err != nil {
+		log.Println("Invalid cursor value:", err)
+		return models.PageRequest{}, apierrors.InvalidCursor
+	}
+
+	if cursor.Sort != orderBy {
+		log.Printf("Cursor created for sort %v used with sort %v\n", cursor.Sort, orderBy)
+		return models.PageRequest{}, apierrors.CursorSortMismatch
+	}
+
+	page.Cursor = &cursor
+	return page, nil
+}
+
+// Helpers for the optional filter params of the list routes.
+// An absent param returns the zero value, which the models ignore when building the query.
+
+func queryDate(c *fiber.Ctx, key string) (string, error) {
+	value := c.Query(key)
+	if value == "" {
+		return "", nil
+	}
+
+	if _, err := time.Parse("2006-01-02", value); err != nil {
+		log.Printf("Invalid %s value: %s\n", key, value)
+		return "", apierrors.InvalidQueryParam(key, apierrors.ValidDate)
+	}
+
+	return value, nil
+}
+
+func queryFloat(c *fiber.Ctx, key string) (*float64, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.Printf("Invalid %s value: %s\n", key, value)
+		return nil, apierrors.InvalidQueryParam(key, apierrors.ValidNumber)
+	}
+
+	return &number, nil
+}
+
+func queryBool(c *fiber.Ctx, key string) (*bool, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	boolean, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Invalid %s value: %s\n", key, value)
+		return nil, apierrors.InvalidQueryParam(key, apierrors.ValidBool)
+	}
+
+	return &boolean, nil
+}
+
+func queryUUID(c *fiber.Ctx, key string) (uuid.UUID, error) {
+	value := c.Query(key)
+	if value == "" {
+		return uuid.Nil, nil
+	}
+
+	id, err := uuid.Parse(value)
+	if err != nil {
+		log.Printf("Invalid %s value: %s\n", key, value)
+		return uuid.Nil, apierrors.InvalidQueryParam(key, apierrors.ValidUUID)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"database/sql"
+	"log"
+	"strings"
+
+	"github.com/VinOfSteel/cinemagrader/apierrors"
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Controller type
+type Search struct {
+	DB       *sql.DB
+	Validate *validator.Validate
+}
+
+// Search model
+var SearchModel models.SearchModel
+
+func (s *Search) SearchMoviesAndActors(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+
+	// Query params
+	text := c.Query("q", "")
+	searchType := strings.ToLower(c.Query("type", ""))
+
+	if models.PrefixTsQuery(text) == "" {
+		log.Println("Search without any word in the q param:", text)
+		return apierrors.InvalidSearchQuery
+	}
+
+	if searchType != "" && searchType != models.SearchTypeMovie && searchType != models.SearchTypeActor {
+		log.Println("Invalid search type value:", searchType)
+		return apierrors.InvalidSearchType
+	}
+
+	page, err := pageRequest(c, models.SearchOrderBy)
+	if err != nil {
+		return err
+	}
+
+	results, err := SearchModel.Search(s.DB, text, searchType, page)
+	if err != nil {
+		log.Println("Error searching movies and actors:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusOK).JSON(results)
+	return nil
+}
@@ -1,12 +1,18 @@
 package controllers
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"log"
 	"os"
 	"time"
 
+	"github.com/VinOfSteel/cinemagrader/apierrors"
+	"github.com/VinOfSteel/cinemagrader/models"
 	"github.com/VinOfSteel/cinemagrader/validation"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
@@ -15,12 +21,28 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+// Access tokens are short lived because they are only checked against the DB through their session.
+// Refresh tokens are long lived, rotated on every use and can be revoked server side.
+const (
+	accessTokenDuration  = 15 * time.Minute
+	refreshTokenDuration = 30 * 24 * time.Hour
+)
+
+// Key used by the auth middlewares to store the verified token claims in the request locals
+const ClaimsLocalsKey = "claims"
+
 // Controller type
 type Session struct {
 	DB       *sql.DB
 	Validate *validator.Validate
 }
 
+// Session model
+var SessionModel models.SessionModel
+
+// Role model
+var RoleModel models.RoleModel
+
 // Login types
 type LoginBody struct {
 	Email    string `json:"email" validate:"required,email"`
@@ -28,16 +50,41 @@ type LoginBody struct {
 }
 
 type LoginResponse struct {
-	UserID uuid.UUID `json:"userId"`
-	Token  string    `json:"token"`
+	UserID         uuid.UUID `json:"userId"`
+	Token          string    `json:"token"`
+	TokenExpiresAt time.Time `json:"tokenExpiresAt"`
+	RefreshToken   string    `json:"refreshToken"`
+}
+
+// Refresh types
+type RefreshBody struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
 }
 
-func createToken(uuid uuid.UUID, email string, isAdm bool) (string, error) {
+// Password reset types
+type PasswordResetBody struct {
+	ResetToken string `json:"resetToken" validate:"required"`
+	Password   string `json:"password" validate:"required,password"`
+}
+
+// BannedUserError is sent to banned users on login, refresh and on every authenticated route
+func BannedUserError(ban models.UserBan) error {
+	if ban.BannedUntil.Valid {
+		return apierrors.UserBannedUntil(ban.BannedUntil.Time)
+	}
+
+	return apierrors.UserBanned
+}
+
+func createToken(uuid uuid.UUID, email string, userRoles models.UserRoles, sessionId uuid.UUID, expiresAt time.Time) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"id":         uuid,
-		"email":      email,
-		"isAdm":      isAdm,
-		"expiration": time.Now().Add(time.Hour * 24 * 30).Unix(),
+		"id":          uuid,
+		"email":       email,
+		"roles":       userRoles.Roles,
+		"permissions": userRoles.Permissions,
+		"sid":         sessionId,
+		"iat":         jwt.NewNumericDate(time.Now()),
+		"exp":         jwt.NewNumericDate(expiresAt),
 	})
 
 	tokenString, err := token.SignedString([]byte(os.Getenv("SECRET_KEY")))
@@ -48,11 +95,27 @@ func createToken(uuid uuid.UUID, email string, isAdm bool) (string, error) {
 	return tokenString, nil
 }
 
+// Refresh and password reset tokens are opaque random strings, only their hash is persisted.
+func createOpaqueToken() (string, string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(bytes)
+	return token, hashOpaqueToken(token), nil
+}
+
+func hashOpaqueToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
 func (s *Session) VerifyToken(tokenString string) (jwt.MapClaims, error) {
 	claims := jwt.MapClaims{}
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
 		return []byte(os.Getenv("SECRET_KEY")), nil
-	})
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
 
 	if err != nil {
 		return nil, err
@@ -65,20 +128,67 @@ func (s *Session) VerifyToken(tokenString string) (jwt.MapClaims, error) {
 	return claims, nil
 }
 
+// HasPermission tells if the token claims carry at least one of the permissions
+func HasPermission(claims jwt.MapClaims, permissions ...string) bool {
+	// Decoded tokens hold []interface{}, while authenticate replaces it with the []string read from the DB
+	var granted []string
+	switch claim := claims["permissions"].(type) {
+	case []string:
+		granted = claim
+	case []interface{}:
+		for _, value := range claim {
+			if permission, ok := value.(string); ok {
+				granted = append(granted, permission)
+			}
+		}
+	}
+
+	for _, claim := range granted {
+		for _, permission := range permissions {
+			if claim == permission {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// sessionResponse creates the access token of a session and packs it with the refresh token.
+// The roles in the token are informative, authenticate reloads them from the DB on every request.
+func (s *Session) sessionResponse(userId uuid.UUID, email string, session models.SessionModel, refreshToken string) (LoginResponse, error) {
+	tokenExpiresAt := time.Now().Add(accessTokenDuration)
+
+	userRoles, err := RoleModel.GetUserRoles(s.DB, userId)
+	if err != nil {
+		return LoginResponse{}, err
+	}
+
+	token, err := createToken(userId, email, userRoles, session.ID, tokenExpiresAt)
+	if err != nil {
+		return LoginResponse{}, err
+	}
+
+	return LoginResponse{
+		UserID:         userId,
+		Token:          token,
+		TokenExpiresAt: tokenExpiresAt,
+		RefreshToken:   refreshToken,
+	}, nil
+}
+
 func (s *Session) HandleLogin(c *fiber.Ctx) error {
 	c.Accepts("application/json")
 
 	var loginData LoginBody
 	if err := c.BodyParser(&loginData); err != nil {
 		log.Println("Error parsing JSON body:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Error while parsing JSON body, check your request",
-		}
+		return apierrors.InvalidBody
 	}
 
-	if valid := validation.ValidateData(c, s.Validate, loginData); !valid {
-		return nil
+	// Validating input data
+	if err := validation.ValidateData(s.Validate, loginData); err != nil {
+		return err
 	}
 
 	// Verifying if user exists in DB
@@ -86,43 +196,181 @@ func (s *Session) HandleLogin(c *fiber.Ctx) error {
 	if err != nil {
 		if err != sql.ErrNoRows {
 			log.Println("Error getting user by email:", err)
-			return &fiber.Error{
-				Code:    fiber.StatusInternalServerError,
-				Message: "Unknown error",
-			}
+			return apierrors.Internal
 		}
 	}
 
-	if existingUser.ID == uuid.Nil {
+	if existingUser.ID == uuid.Nil || existingUser.DeletedAt.Valid {
 		log.Println("Trying to login with an email that does not exist in DB")
-		return &fiber.Error{
-			Code:    fiber.StatusBadRequest,
-			Message: "Invalid email/password",
-		}
+		return apierrors.InvalidCredentials
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(existingUser.Password), []byte(loginData.Password)); err != nil {
 		log.Println("Password does not match:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusBadRequest,
-			Message: "Invalid email/password",
+		return apierrors.InvalidCredentials
+	}
+
+	// Only told after the password matched, so the state of an account isn't leaked to whoever knows the email
+	if existingUser.Banned() {
+		log.Printf("Banned user %s tried to login\n", existingUser.ID)
+		return BannedUserError(existingUser.UserBan)
+	}
+
+	if existingUser.PasswordResetPending {
+		return apierrors.PasswordResetRequired
+	}
+
+	refreshToken, refreshTokenHash, err := createOpaqueToken()
+	if err != nil {
+		log.Println("Couldn't create refresh token:", err)
+		return apierrors.Internal
+	}
+
+	session, err := SessionModel.InsertSessionInDB(s.DB, existingUser.ID, refreshTokenHash, time.Now().Add(refreshTokenDuration))
+	if err != nil {
+		log.Println("Error inserting session in DB:", err)
+		return apierrors.Internal
+	}
+
+	loginResponse, err := s.sessionResponse(existingUser.ID, existingUser.Email, session, refreshToken)
+	if err != nil {
+		log.Println("Couldn't create JWT:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusOK).JSON(loginResponse)
+
+	return nil
+}
+
+func (s *Session) HandleRefresh(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+
+	var refreshData RefreshBody
+	if err := c.BodyParser(&refreshData); err != nil {
+		log.Println("Error parsing JSON body:", err)
+		return apierrors.InvalidBody
+	}
+
+	// Validating input data
+	if err := validation.ValidateData(s.Validate, refreshData); err != nil {
+		return err
+	}
+
+	refreshToken, refreshTokenHash, err := createOpaqueToken()
+	if err != nil {
+		log.Println("Couldn't create refresh token:", err)
+		return apierrors.Internal
+	}
+
+	session, err := SessionModel.RotateSessionRefreshToken(s.DB, hashOpaqueToken(refreshData.RefreshToken), refreshTokenHash, time.Now().Add(refreshTokenDuration))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Println("Trying to refresh with an invalid, expired or revoked refresh token")
+			return apierrors.InvalidRefreshToken
+		}
+
+		log.Println("Error rotating refresh token:", err)
+		return apierrors.Internal
+	}
+
+	// User info is read again so the new access token reflects deletions and permission changes
+	user, err := UserModel.GetUserById(s.DB, session.UserId)
+	if err != nil {
+		log.Println("Error getting user of refreshed session:", err)
+		return apierrors.Internal
+	}
+
+	if user.DeletedAt.Valid {
+		if err := SessionModel.RevokeSessionById(s.DB, session.ID); err != nil {
+			log.Println("Error revoking session of deleted user:", err)
 		}
+
+		return apierrors.InvalidRefreshToken
 	}
 
-	token, err := createToken(existingUser.ID, existingUser.Email, existingUser.IsAdm)
+	if user.Banned() {
+		return BannedUserError(user.UserBan)
+	}
+
+	refreshResponse, err := s.sessionResponse(user.ID, user.Email, session, refreshToken)
 	if err != nil {
 		log.Println("Couldn't create JWT:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Couldn't create user token",
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusOK).JSON(refreshResponse)
+	return nil
+}
+
+// HandlePasswordReset sets a new password with the reset token given by an admin, after which the user can login again
+func (s *Session) HandlePasswordReset(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+
+	var resetData PasswordResetBody
+	if err := c.BodyParser(&resetData); err != nil {
+		log.Println("Error parsing JSON body:", err)
+		return apierrors.InvalidBody
+	}
+
+	// Validating input data
+	if err := validation.ValidateData(s.Validate, resetData); err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(resetData.Password), 12)
+	if err != nil {
+		log.Println("Error encrypting user's password:", err)
+		return apierrors.Internal
+	}
+
+	if _, err := UserModel.ResetPassword(s.DB, hashOpaqueToken(resetData.ResetToken), string(hashedPassword)); err != nil {
+		if err == sql.ErrNoRows {
+			return apierrors.InvalidResetToken
 		}
+
+		log.Println("Error resetting password in DB:", err)
+		return apierrors.Internal
 	}
 
-	loginResponse := LoginResponse{
-		UserID: existingUser.ID,
-		Token:  token,
+	c.Status(fiber.StatusNoContent)
+	return nil
+}
+
+func (s *Session) HandleLogout(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+	claims := c.Locals(ClaimsLocalsKey).(jwt.MapClaims)
+
+	sessionId, err := uuid.Parse(claims["sid"].(string))
+	if err != nil {
+		log.Println("Invalid session id in token claims:", err)
+		return apierrors.InvalidToken
+	}
+
+	if err := SessionModel.RevokeSessionById(s.DB, sessionId); err != nil {
+		log.Println("Error revoking session in DB:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusNoContent)
+	return nil
+}
+
+func (s *Session) HandleLogoutAll(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+	claims := c.Locals(ClaimsLocalsKey).(jwt.MapClaims)
+
+	userId, err := uuid.Parse(claims["id"].(string))
+	if err != nil {
+		log.Println("Invalid user id in token claims:", err)
+		return apierrors.InvalidToken
+	}
+
+	if err := SessionModel.RevokeAllUserSessions(s.DB, userId); err != nil {
+		log.Println("Error revoking all user sessions in DB:", err)
+		return apierrors.Internal
 	}
-	c.Status(fiber.StatusOK).JSON(loginResponse)
 
+	c.Status(fiber.StatusNoContent)
 	return nil
 }
@@ -3,9 +3,9 @@ package controllers
 import (
 	"database/sql"
 	"log"
-	"strconv"
 	"strings"
 
+	"github.com/VinOfSteel/cinemagrader/apierrors"
 	"github.com/VinOfSteel/cinemagrader/models"
 	"github.com/VinOfSteel/cinemagrader/validation"
 	"github.com/go-playground/validator/v10"
@@ -29,15 +29,12 @@ func (u *User) CreateUser(c *fiber.Ctx) error {
 	var userBody models.UserBody
 	if err := c.BodyParser(&userBody); err != nil {
 		log.Println("Error parsing JSON body:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Error while parsing JSON body, check your request",
-		}
+		return apierrors.InvalidBody
 	}
 
-	// Validating input data. We return "nil" because the ValidateData function sends a response back by itself and we need to return here to stop the function.
-	if valid := validation.ValidateData(c, u.Validate, userBody); !valid {
-		return nil
+	// Validating input data
+	if err := validation.ValidateData(u.Validate, userBody); err != nil {
+		return err
 	}
 
 	// Checking if user already exists in DB
@@ -45,39 +42,27 @@ func (u *User) CreateUser(c *fiber.Ctx) error {
 	if err != nil {
 		if err != sql.ErrNoRows {
 			log.Println("Error getting user by email:", err)
-			return &fiber.Error{
-				Code:    fiber.StatusInternalServerError,
-				Message: "Unknown error",
-			}
+			return apierrors.Internal
 		}
 	}
 
 	if existingUser.ID != uuid.Nil {
 		log.Println("Trying to create user with existing email in DB")
-		return &fiber.Error{
-			Code:    fiber.StatusBadRequest,
-			Message: "User with this email already exists",
-		}
+		return apierrors.UserEmailTaken
 	}
 
 	// Encrypting user's password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(userBody.Password), 12)
 	if err != nil {
 		log.Println("Error encrypting user's password:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Unknown error",
-		}
+		return apierrors.Internal
 	}
 	userBody.Password = string(hashedPassword)
 
 	userResponse, err := UserModel.InsertUserInDB(u.DB, userBody)
 	if err != nil {
 		log.Println("Error inserting user in DB:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Unknown error",
-		}
+		return apierrors.Internal
 	}
 
 	c.Status(fiber.StatusCreated).JSON(userResponse)
@@ -88,29 +73,9 @@ func (u *User) ListAllUsersInDB(c *fiber.Ctx) error {
 	c.Accepts("application/json")
 
 	// Query params
-	offset := c.Query("offset", "0")
-	limit := c.Query("limit", "10")
 	orderBy := c.Query("sort", "created,desc")
 	deletedQuery := c.Query("deleted", "false")
 
-	offsetInt, err := strconv.Atoi(offset)
-	if err != nil {
-		log.Println("Invalid offset value:", offset)
-		return &fiber.Error{
-			Code:    fiber.StatusBadRequest,
-			Message: "Offset needs to be a valid integer",
-		}
-	}
-
-	limitInt, err := strconv.Atoi(limit)
-	if err != nil {
-		log.Println("Invalid limit value:", limit)
-		return &fiber.Error{
-			Code:    fiber.StatusBadRequest,
-			Message: "Limit needs to be a valid integer",
-		}
-	}
-
 	switch strings.ToLower(orderBy) {
 	case "created,asc":
 		orderBy = "created_at ASC"
@@ -134,19 +99,32 @@ func (u *User) ListAllUsersInDB(c *fiber.Ctx) error {
 		orderBy = "updated_at DESC"
 	}
 
+	page, err := pageRequest(c, orderBy)
+	if err != nil {
+		return err
+	}
+
 	var deleted bool
 	if deletedQuery == "true" {
 		deleted = true
 	}
 
-	usersList, err := UserModel.GetAllUsers(u.DB, offsetInt, limitInt, orderBy, deleted)
+	isAdm, err := queryBool(c, "is_adm")
+	if err != nil {
+		return err
+	}
+
+	filters := models.UserFilters{
+		Name:  c.Query("name"),
+		Email: c.Query("email"),
+		IsAdm: isAdm,
+	}
+
+	usersList, err := UserModel.GetAllUsers(u.DB, page, orderBy, deleted, filters)
 	if err != nil {
 		if err != sql.ErrNoRows {
 			log.Println("Error getting all users:", err)
-			return &fiber.Error{
-				Code:    fiber.StatusInternalServerError,
-				Message: "Unknown error",
-			}
+			return apierrors.Internal
 		}
 	}
 
@@ -161,27 +139,18 @@ func (u *User) GetUser(c *fiber.Ctx) error {
 	uuid, err := uuid.Parse(uuidParam)
 	if err != nil {
 		log.Println("Invalid uuid sent in param:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusBadRequest,
-			Message: "Invalid uuid parameter",
-		}
+		return apierrors.InvalidUUID
 	}
 
 	userResponse, err := UserModel.GetUserById(u.DB, uuid)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			log.Println("User id not found in database:", err)
-			return &fiber.Error{
-				Code:    fiber.StatusNotFound,
-				Message: "User id not found in database",
-			}
+			return apierrors.UserNotFound
 		}
 
 		log.Println("Error getting user by id:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Unknown error",
-		}
+		return apierrors.Internal
 	}
 
 	c.Status(fiber.StatusOK).JSON(userResponse)
@@ -195,35 +164,23 @@ func (u *User) DeleteUser(c *fiber.Ctx) error {
 	uuid, err := uuid.Parse(uuidParam)
 	if err != nil {
 		log.Println("Invalid uuid sent in param:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusBadRequest,
-			Message: "Invalid uuid parameter",
-		}
+		return apierrors.InvalidUUID
 	}
 
 	_, err = UserModel.GetUserById(u.DB, uuid)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			log.Println("User id not found in database:", err)
-			return &fiber.Error{
-				Code:    fiber.StatusNotFound,
-				Message: "User id not found in database",
-			}
+			return apierrors.UserNotFound
 		}
 
 		log.Println("Error getting user by id:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Unknown error",
-		}
+		return apierrors.Internal
 	}
 
 	if err := UserModel.DeleteUserById(u.DB, uuid); err != nil {
 		log.Println("Error deleting user in DB:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Couldn't delete user in DB",
-		}
+		return apierrors.Internal
 	}
 
 	c.Status(fiber.StatusNoContent)
@@ -237,50 +194,35 @@ func (u *User) UpdateUser(c *fiber.Ctx) error {
 	uuid, err := uuid.Parse(uuidParam)
 	if err != nil {
 		log.Println("Invalid uuid sent in param:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusBadRequest,
-			Message: "Invalid uuid parameter",
-		}
+		return apierrors.InvalidUUID
 	}
 
 	_, err = UserModel.GetUserById(u.DB, uuid)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			log.Println("User id not found in database:", err)
-			return &fiber.Error{
-				Code:    fiber.StatusNotFound,
-				Message: "User id not found in database",
-			}
+			return apierrors.UserNotFound
 		}
 
 		log.Println("Error getting user by id:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Unknown error",
-		}
+		return apierrors.Internal
 	}
 
 	var userBody models.UserEditBody
 	if err := c.BodyParser(&userBody); err != nil {
 		log.Println("Error parsing JSON body:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Error while parsing JSON body, check your request",
-		}
+		return apierrors.InvalidBody
 	}
 
-	// Validating input data. We return "nil" because the ValidateData function sends a response back by itself and we need to return here to stop the function.
-	if valid := validation.ValidateData(c, u.Validate, userBody); !valid {
-		return nil
+	// Validating input data
+	if err := validation.ValidateData(u.Validate, userBody); err != nil {
+		return err
 	}
 
 	userResponse, err := UserModel.UpdateUserById(u.DB, uuid, userBody)
 	if err != nil {
 		log.Println("Error updating user in DB:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Unknown error",
-		}
+		return apierrors.Internal
 	}
 
 	c.Status(fiber.StatusOK).JSON(userResponse)
@@ -294,10 +236,7 @@ func (u *User) GetUserComments(c *fiber.Ctx) error {
 	uuid, err := uuid.Parse(uuidParam)
 	if err != nil {
 		log.Println("Invalid uuid sent in param:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusBadRequest,
-			Message: "Invalid uuid parameter",
-		}
+		return apierrors.InvalidUUID
 	}
 
 	// Query params
@@ -328,34 +267,22 @@ func (u *User) GetUserComments(c *fiber.Ctx) error {
 	if err != nil {
 		if err == sql.ErrNoRows {
 			log.Println("User id not found in database:", err)
-			return &fiber.Error{
-				Code:    fiber.StatusNotFound,
-				Message: "User id not found in database",
-			}
+			return apierrors.UserNotFound
 		}
 
 		log.Println("Error getting user by id:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Unknown error",
-		}
+		return apierrors.Internal
 	}
 
 	userWithCommentsResponse, err := CommentModel.GetAllUserCommentsInDb(u.DB, uuid, orderBy, deleted)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			log.Println("User id not found in database:", err)
-			return &fiber.Error{
-				Code:    fiber.StatusNotFound,
-				Message: "User id not found in database",
-			}
+			return apierrors.UserNotFound
 		}
 
 		log.Println("Error getting user by id:", err)
-		return &fiber.Error{
-			Code:    fiber.StatusInternalServerError,
-			Message: "Unknown error",
-		}
+		return apierrors.Internal
 	}
 
 	c.Status(fiber.StatusOK).JSON(userWithCommentsResponse)
@@ -0,0 +1,209 @@
+package controllers
+
+import (
+	"database/sql"
+	"log"
+	"strings"
+
+	"github.com/VinOfSteel/cinemagrader/apierrors"
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/VinOfSteel/cinemagrader/validation"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// Controller type
+type Watchlist struct {
+	DB       *sql.DB
+	Validate *validator.Validate
+}
+
+// Watchlist model
+var WatchlistModel models.WatchlistModel
+
+// activeUserFromParam parses the user of the route and checks that they were not deleted, so they can still make changes
+func activeUserFromParam(db *sql.DB, uuidParam string) (uuid.UUID, error) {
+	userId, err := uuid.Parse(uuidParam)
+	if err != nil {
+		log.Println("Invalid uuid sent in param:", err)
+		return uuid.Nil, apierrors.InvalidUUID
+	}
+
+	userResponse, err := UserModel.GetUserById(db, userId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Println("User id not found in database:", err)
+			return uuid.Nil, apierrors.UserNotFound
+		}
+
+		log.Println("Error getting user by id:", err)
+		return uuid.Nil, apierrors.Internal
+	}
+
+	if userResponse.DeletedAt.Valid {
+		return uuid.Nil, apierrors.UserDeleted
+	}
+
+	return userId, nil
+}
+
+// activeMovie checks that the movie of a watchlist or diary body exists and was not deleted
+func activeMovie(db *sql.DB, movieId string) (uuid.UUID, error) {
+	movieUUID, err := uuid.Parse(movieId)
+	if err != nil {
+		log.Println("Invalid movie uuid in body:", err)
+		return uuid.Nil, apierrors.InvalidMovieUUID
+	}
+
+	movieResponse, err := MovieModel.GetMovieByIdWithActors(db, movieUUID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Println("Movie id not found in database:", err)
+			return uuid.Nil, apierrors.MovieNotFound
+		}
+
+		log.Println("Error getting movie by id:", err)
+		return uuid.Nil, apierrors.Internal
+	}
+
+	if movieResponse.DeletedAt.Valid {
+		return uuid.Nil, apierrors.DeletedMovieWatchlist
+	}
+
+	return movieUUID, nil
+}
+
+func (w *Watchlist) AddToWatchlist(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+
+	userId, err := activeUserFromParam(w.DB, c.Params("uuid"))
+	if err != nil {
+		return err
+	}
+
+	var watchlistBody models.WatchlistBody
+	if err := c.BodyParser(&watchlistBody); err != nil {
+		log.Println("Error parsing JSON body:", err)
+		return apierrors.InvalidBody
+	}
+
+	// Validating input data
+	if err := validation.ValidateData(w.Validate, watchlistBody); err != nil {
+		return err
+	}
+
+	movieId, err := activeMovie(w.DB, watchlistBody.MovieId)
+	if err != nil {
+		return err
+	}
+
+	_, err = WatchlistModel.GetWatchlistItem(w.DB, userId, movieId)
+	if err == nil {
+		return apierrors.MovieAlreadyWatchlist
+	}
+
+	if err != sql.ErrNoRows {
+		log.Println("Error getting watchlist item:", err)
+		return apierrors.Internal
+	}
+
+	itemResponse, err := WatchlistModel.InsertWatchlistItemInDB(w.DB, userId, watchlistBody)
+	if err != nil {
+		log.Println("Error inserting watchlist item in DB:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusCreated).JSON(itemResponse)
+	return nil
+}
+
+func (w *Watchlist) GetUserWatchlist(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+	uuidParam := c.Params("uuid")
+
+	uuid, err := uuid.Parse(uuidParam)
+	if err != nil {
+		log.Println("Invalid uuid sent in param:", err)
+		return apierrors.InvalidUUID
+	}
+
+	// Query params
+	orderBy := c.Query("sort", "added,desc")
+
+	switch strings.ToLower(orderBy) {
+	case "added,asc":
+		orderBy = "created_at ASC"
+	case "title,asc":
+		orderBy = "title ASC"
+	case "title,desc":
+		orderBy = "title DESC"
+	case "release,asc":
+		orderBy = "release_date ASC"
+	case "release,desc":
+		orderBy = "release_date DESC"
+	case "rating,desc":
+		orderBy = "weighted_rating DESC"
+	default:
+		orderBy = "created_at DESC"
+	}
+
+	page, err := pageRequest(c, orderBy)
+	if err != nil {
+		return err
+	}
+
+	_, err = UserModel.GetUserById(w.DB, uuid)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Println("User id not found in database:", err)
+			return apierrors.UserNotFound
+		}
+
+		log.Println("Error getting user by id:", err)
+		return apierrors.Internal
+	}
+
+	watchlistResponse, err := WatchlistModel.GetUserWatchlist(w.DB, uuid, page, orderBy)
+	if err != nil {
+		log.Println("Error getting watchlist of user:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusOK).JSON(watchlistResponse)
+	return nil
+}
+
+func (w *Watchlist) RemoveFromWatchlist(c *fiber.Ctx) error {
+	c.Accepts("application/json")
+
+	userId, err := activeUserFromParam(w.DB, c.Params("uuid"))
+	if err != nil {
+		return err
+	}
+
+	movieId, err := uuid.Parse(c.Params("movieUuid"))
+	if err != nil {
+		log.Println("Invalid uuid sent in param:", err)
+		return apierrors.InvalidUUID
+	}
+
+	_, err = WatchlistModel.GetWatchlistItem(w.DB, userId, movieId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Println("Movie not found in the watchlist:", err)
+			return apierrors.MovieNotInWatchlist
+		}
+
+		log.Println("Error getting watchlist item:", err)
+		return apierrors.Internal
+	}
+
+	if err := WatchlistModel.DeleteWatchlistItem(w.DB, userId, movieId); err != nil {
+		log.Println("Error deleting watchlist item in DB:", err)
+		return apierrors.Internal
+	}
+
+	c.Status(fiber.StatusNoContent)
+	return nil
+}
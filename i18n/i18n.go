@@ -0,0 +1,67 @@
+// Package i18n keeps the translators of the languages the API answers in. The packages with messages add their
+// catalogues to the translators, and the language of each request is picked from its Accept-Language header.
+package i18n
+
+import (
+	"strings"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/pt_BR"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Locales of the catalogues, English being the default for requests that don't ask for a language the API has
+const (
+	English             = "en"
+	BrazilianPortuguese = "pt_BR"
+)
+
+var universal = ut.New(en.New(), en.New(), pt_BR.New())
+
+// Translator returns the translator of the locale, or the English one for locales without a catalogue
+func Translator(locale string) ut.Translator {
+	trans, _ := universal.GetTranslator(locale)
+	return trans
+}
+
+// Translators returns the translator of every locale, for the packages to add their catalogues to
+func Translators() []ut.Translator {
+	return []ut.Translator{Translator(English), Translator(BrazilianPortuguese)}
+}
+
+// FromRequest returns the translator of the language the request prefers. Any Portuguese gets the Brazilian catalogue.
+func FromRequest(c *fiber.Ctx) ut.Translator {
+	if c.AcceptsLanguages("en", "pt") == "pt" {
+		return Translator(BrazilianPortuguese)
+	}
+
+	return Translator(English)
+}
+
+// LanguageTag is the locale of the translator as a language tag, like pt-BR, for the Content-Language header
+func LanguageTag(trans ut.Translator) string {
+	return strings.ReplaceAll(trans.Locale(), "_", "-")
+}
+
+// Add adds the messages, keyed by what they are looked up with, to the catalogue of the translator
+func Add(trans ut.Translator, messages map[string]string) error {
+	for key, message := range messages {
+		if err := trans.Add(key, message, false); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// T translates the message of the key, filling its {0}, {1}... placeholders with the params.
+// Keys missing from the catalogue of the translator fall back to the English one.
+func T(trans ut.Translator, key string, params ...string) string {
+	if message, err := trans.T(key, params...); err == nil {
+		return message
+	}
+
+	message, _ := Translator(English).T(key, params...)
+	return message
+}
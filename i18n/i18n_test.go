@@ -0,0 +1,69 @@
+package i18n
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FromRequest(t *testing.T) {
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString(FromRequest(c).Locale())
+	})
+
+	testCases := []struct {
+		description    string
+		acceptLanguage string
+		expectedLocale string
+	}{
+		{description: "No header", acceptLanguage: "", expectedLocale: English},
+		{description: "Brazilian Portuguese", acceptLanguage: "pt-BR", expectedLocale: BrazilianPortuguese},
+		{description: "Any Portuguese", acceptLanguage: "pt", expectedLocale: BrazilianPortuguese},
+		{description: "Preferred Portuguese", acceptLanguage: "en;q=0.5, pt-BR;q=0.9", expectedLocale: BrazilianPortuguese},
+		{description: "Preferred English", acceptLanguage: "en-US, pt-BR;q=0.8", expectedLocale: English},
+		{description: "Language without a catalogue", acceptLanguage: "fr-FR", expectedLocale: English},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			request := httptest.NewRequest(fiber.MethodGet, "/", nil)
+			if testCase.acceptLanguage != "" {
+				request.Header.Set(fiber.HeaderAcceptLanguage, testCase.acceptLanguage)
+			}
+
+			response, err := app.Test(request)
+			if err != nil {
+				t.Fatalf("Error sending request: %v", err)
+			}
+			defer response.Body.Close()
+
+			body, err := io.ReadAll(response.Body)
+			if err != nil {
+				t.Fatalf("Error reading response: %v", err)
+			}
+
+			assert.Equal(t, testCase.expectedLocale, string(body), "Locale mismatch")
+		})
+	}
+}
+
+func Test_T(t *testing.T) {
+	english := Translator(English)
+	portuguese := Translator(BrazilianPortuguese)
+
+	if err := Add(english, map[string]string{"test.greeting": "Hello {0}", "test.english_only": "Only in English"}); err != nil {
+		t.Fatalf("Error adding English messages: %v", err)
+	}
+	if err := Add(portuguese, map[string]string{"test.greeting": "Olá {0}"}); err != nil {
+		t.Fatalf("Error adding Portuguese messages: %v", err)
+	}
+
+	assert.Equal(t, "Olá Ana", T(portuguese, "test.greeting", "Ana"), "Message should be translated")
+	assert.Equal(t, "Only in English", T(portuguese, "test.english_only"), "Missing messages should fall back to English")
+	assert.Empty(t, T(portuguese, "test.missing"), "Unknown keys should be empty")
+	assert.Equal(t, "pt-BR", LanguageTag(portuguese), "Language tag mismatch")
+}
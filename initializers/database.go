@@ -6,11 +6,13 @@ import (
 	"log"
 	"os"
 
-	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/VinOfSteel/cinemagrader/migrations"
 	_ "github.com/lib/pq"
 )
 
-func NewDatabaseConn() *sql.DB {
+// OpenDatabaseConn opens and pings the DB without touching its schema.
+// Used by the migrate command, which decides by itself which migrations to run.
+func OpenDatabaseConn() *sql.DB {
 	var (
 		user     string = os.Getenv("PGUSER")
 		password string = os.Getenv("PGPASSWORD")
@@ -34,24 +36,21 @@ func NewDatabaseConn() *sql.DB {
 
 	log.Println("Connection opened succesfully!")
 
-	// Executing table creation queries as soon as DB is opened
-	executeInitialQueries(db)
-
 	return db
 }
 
-func executeInitialQueries(db *sql.DB) {
-	// Creating uuid extension on DB
-	extensionQuery := "CREATE EXTENSION IF NOT EXISTS \"uuid-ossp\";"
-	if _, err := db.Exec(extensionQuery); err != nil {
-		log.Fatalf("Error creating uuid extension: %v", err)
+func NewDatabaseConn() *sql.DB {
+	db := OpenDatabaseConn()
+
+	// Applying pending migrations as soon as DB is opened
+	applied, err := migrations.Up(db, 0)
+	if err != nil {
+		log.Fatalf("Error applying migrations: %v", err)
 	}
 
-	// Creating application tables
-	for i, query := range models.Queries {
-		log.Printf("Executing query on index %v", i)
-		if _, err := db.Exec(query); err != nil {
-			log.Fatalf("Error creating tables: %v", err)
-		}
+	if applied > 0 {
+		log.Printf("Applied %v pending migrations\n", applied)
 	}
+
+	return db
 }
@@ -36,13 +36,13 @@ func TestMain(m *testing.M) {
 
 	os.Setenv("PGDATABASE", testDb)
 
-	// Validator setup
-	validate = NewValidator()
-
 	// Creating a new admin user to use on the validation tests
 	db := NewDatabaseConn()
 	defer db.Close()
 
+	// Validator setup
+	validate = NewValidator(db)
+
 	var adminUser = models.UserBody{
 		Name:     "The",
 		Surname:  "Admin",
@@ -136,6 +136,7 @@ var actorUuidSliceItems = []struct {
 	{[]string{"batata", "banana", "a61b6ed8-cd86-4bd9-833b-910b485471c6"}, false},
 	{[]string{"cebola"}, false},
 	{[]string{}, false},
+	{[]string{"actor1", "a61b6ed8-cd86-4bd9-833b-910b485471c6"}, false},
 	{[]string{"actor1", "actor2"}, true},
 }
 
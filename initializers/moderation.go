@@ -0,0 +1,25 @@
+package initializers
+
+import (
+	"log"
+	"os"
+
+	"github.com/VinOfSteel/cinemagrader/moderation"
+)
+
+// NewBlocklist loads the moderation blocklist from the file in MODERATION_BLOCKLIST_FILE.
+// Without the variable the blocklist is empty and no comment is held for moderation on write.
+func NewBlocklist() *moderation.Blocklist {
+	path := os.Getenv("MODERATION_BLOCKLIST_FILE")
+	if path == "" {
+		return &moderation.Blocklist{}
+	}
+
+	blocklist, err := moderation.LoadBlocklist(path)
+	if err != nil {
+		log.Fatalf("Error loading moderation blocklist: %v", err)
+	}
+
+	log.Printf("Moderation blocklist loaded with %d entries\n", blocklist.Len())
+	return blocklist
+}
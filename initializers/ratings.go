@@ -0,0 +1,46 @@
+package initializers
+
+import (
+	"database/sql"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/VinOfSteel/cinemagrader/models"
+)
+
+// SyncRatingSettings stores the weighted rating parameters of the environment in the DB.
+// Unset variables keep the value the DB already has.
+func SyncRatingSettings(db *sql.DB) {
+	var ratingSettingsModel models.RatingSettingsModel
+
+	settings, err := ratingSettingsModel.GetRatingSettings(db)
+	if err != nil {
+		log.Fatalf("Error getting rating settings: %v", err)
+	}
+
+	if value := os.Getenv("RATING_PRIOR_MEAN"); value != "" {
+		priorMean, err := strconv.ParseFloat(value, 64)
+		if err != nil || priorMean < 1 || priorMean > 5 {
+			log.Fatalf("RATING_PRIOR_MEAN needs to be a number between 1 and 5, got %q", value)
+		}
+		settings.PriorMean = priorMean
+	}
+
+	if value := os.Getenv("RATING_MIN_VOTES"); value != "" {
+		minVotes, err := strconv.Atoi(value)
+		if err != nil || minVotes < 0 {
+			log.Fatalf("RATING_MIN_VOTES needs to be a whole number of at least 0, got %q", value)
+		}
+		settings.MinVotes = minVotes
+	}
+
+	changed, err := ratingSettingsModel.UpdateRatingSettings(db, settings.PriorMean, settings.MinVotes)
+	if err != nil {
+		log.Fatalf("Error updating rating settings: %v", err)
+	}
+
+	if changed {
+		log.Printf("Rating settings changed to prior mean %v and min votes %v, weighted ratings recomputed\n", settings.PriorMean, settings.MinVotes)
+	}
+}
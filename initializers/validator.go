@@ -1,9 +1,9 @@
 package initializers
 
 import (
+	"database/sql"
 	"log"
 	"regexp"
-	"sync"
 
 	"github.com/VinOfSteel/cinemagrader/models"
 	"github.com/go-playground/validator/v10"
@@ -12,6 +12,9 @@ import (
 
 var UserModel models.UserModel
 var ActorModel models.ActorModel
+var GenreModel models.GenreModel
+var PersonModel models.PersonModel
+var RoleModel models.RoleModel
 
 func passwordValidation(fl validator.FieldLevel) bool {
 	password := fl.Field().String()
@@ -29,10 +32,13 @@ func passwordValidation(fl validator.FieldLevel) bool {
 	return hasSymbolRegex.MatchString(password) && hasUppercaseRegex.MatchString(password) && hasNumberRegex.MatchString(password)
 }
 
-func adminUuidValidation(fl validator.FieldLevel) bool {
-	db := NewDatabaseConn()
-	defer db.Close()
+// dbValidations are the validations that look at the DB. They share the connection pool of the app instead of opening
+// one per validation, and check slices of ids with a single query.
+type dbValidations struct {
+	db *sql.DB
+}
 
+func (v *dbValidations) adminUuidValidation(fl validator.FieldLevel) bool {
 	idField := fl.Field().String()
 
 	uuid, err := uuid.Parse(idField)
@@ -41,14 +47,15 @@ func adminUuidValidation(fl validator.FieldLevel) bool {
 		return false
 	}
 
-	userResponse, err := UserModel.GetUserById(db, uuid)
+	// Curators create catalog entries too, so any user with the permission is a valid creator
+	allowed, err := RoleModel.UserHasPermission(v.db, uuid, models.PermissionEditCatalog)
 	if err != nil {
-		log.Println("Error getting user by id when validating admin uuid:", err)
+		log.Println("Error checking permission of user when validating admin uuid:", err)
 		return false
 	}
 
-	if !userResponse.IsAdm {
-		log.Println("Valid and existing user uuid was passed in validation, but user isn't admin", err)
+	if !allowed {
+		log.Println("Valid user uuid was passed in validation, but user can't edit the catalog")
 		return false
 	}
 
@@ -67,47 +74,106 @@ func uuidValidation(fl validator.FieldLevel) bool {
 	return true
 }
 
-func actorsUuidSliceValidation(fl validator.FieldLevel) bool {
-	db := NewDatabaseConn()
-	defer db.Close()
+// parseUuids parses the ids of a slice field, dropping the repeated ones so they can be compared with a count of rows
+func parseUuids(ids []string) ([]uuid.UUID, error) {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	uuids := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		uuid, err := uuid.Parse(id)
+		if err != nil {
+			return nil, err
+		}
+
+		if !seen[uuid] {
+			seen[uuid] = true
+			uuids = append(uuids, uuid)
+		}
+	}
+
+	return uuids, nil
+}
+
+func (v *dbValidations) actorsUuidSliceValidation(fl validator.FieldLevel) bool {
+	// Movie bodies send castings, but a plain slice of actor uuids is still accepted
+	var actorsField []string
+	switch field := fl.Field().Interface().(type) {
+	case []string:
+		actorsField = field
+	case []models.CastingBody:
+		for _, casting := range field {
+			actorsField = append(actorsField, casting.ActorId)
+		}
+	default:
+		log.Printf("Unexpected type %T in actors slice validation\n", field)
+		return false
+	}
 
-	field := fl.Field()
-	actorsField := field.Interface().([]string)
 	if len(actorsField) == 0 {
 		log.Println("Actors field cannot be empty when creating a movie")
 		return false
 	}
 
-	var wg sync.WaitGroup
-	errCh := make(chan error, len(actorsField))
-	for _, actorID := range actorsField {
-		wg.Add(1)
-		go func(actorID string) {
-			defer wg.Done()
-			uuid, err := uuid.Parse(actorID)
-			if err != nil {
-				log.Println("Error parsing actor uuid:", err)
-				errCh <- err
-				return
-			}
+	actorIds, err := parseUuids(actorsField)
+	if err != nil {
+		log.Println("Error parsing actor uuid:", err)
+		return false
+	}
+
+	count, err := ActorModel.CountActorsByIds(v.db, actorIds)
+	if err != nil {
+		log.Println("Error counting actors when validating actor uuids:", err)
+		return false
+	}
 
-			_, err = ActorModel.GetActorById(db, uuid)
-			if err != nil {
-				log.Println("Error getting actor by id when validating actor uuids:", err)
-				errCh <- err
-				return
-			}
-		}(actorID)
+	if count != len(actorIds) {
+		log.Printf("%v of the actor uuids passed in validation don't exist\n", len(actorIds)-count)
+		return false
 	}
 
-	wg.Wait()
-	close(errCh)
+	return true
+}
 
-	for err := range errCh {
-		if err != nil {
-			log.Printf("Error in actor verification goroutine: %v", err)
-			return false
-		}
+func (v *dbValidations) genresUuidSliceValidation(fl validator.FieldLevel) bool {
+	genreIds, err := parseUuids(fl.Field().Interface().([]string))
+	if err != nil {
+		log.Println("Error parsing genre uuid:", err)
+		return false
+	}
+
+	if len(genreIds) == 0 {
+		return true
+	}
+
+	count, err := GenreModel.CountActiveGenresByIds(v.db, genreIds)
+	if err != nil {
+		log.Println("Error counting genres when validating genre uuids:", err)
+		return false
+	}
+
+	if count != len(genreIds) {
+		log.Printf("%v of the genre uuids passed in validation don't exist or were deleted\n", len(genreIds)-count)
+		return false
+	}
+
+	return true
+}
+
+func (v *dbValidations) personUuidValidation(fl validator.FieldLevel) bool {
+	uuid, err := uuid.Parse(fl.Field().String())
+	if err != nil {
+		log.Println("Error parsing person uuid:", err)
+		return false
+	}
+
+	personResponse, err := PersonModel.GetPersonById(v.db, uuid)
+	if err != nil {
+		log.Println("Error getting person by id when validating person uuid:", err)
+		return false
+	}
+
+	if personResponse.DeletedAt.Valid {
+		log.Printf("Person %v passed in validation was deleted\n", personResponse.ID)
+		return false
 	}
 
 	return true
@@ -128,14 +194,19 @@ func gradeValidation(fl validator.FieldLevel) bool {
 	return true
 }
 
-func NewValidator() *validator.Validate {
+// NewValidator registers the custom validations, the ones that look at the DB using db. It should be the same
+// connection pool the app uses, since it's kept for the lifetime of the validator.
+func NewValidator(db *sql.DB) *validator.Validate {
 	// Initializing a single instance of the validator
 	validate := validator.New(validator.WithRequiredStructEnabled())
+	dbValidations := &dbValidations{db: db}
 
 	// Validator custom functions
 	validate.RegisterValidation("password", passwordValidation)
-	validate.RegisterValidation("isadminuuid", adminUuidValidation)
-	validate.RegisterValidation("validactorslice", actorsUuidSliceValidation)
+	validate.RegisterValidation("isadminuuid", dbValidations.adminUuidValidation)
+	validate.RegisterValidation("validactorslice", dbValidations.actorsUuidSliceValidation)
+	validate.RegisterValidation("validgenreslice", dbValidations.genresUuidSliceValidation)
+	validate.RegisterValidation("ispersonuuid", dbValidations.personUuidValidation)
 	validate.RegisterValidation("isvaliduuid", uuidValidation)
 	validate.RegisterValidation("isvalidgrade", gradeValidation)
 
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/VinOfSteel/cinemagrader/apierrors"
+	"github.com/VinOfSteel/cinemagrader/controllers"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// Middleware type. Holds the DB so tokens can be checked against their server side session.
+type Auth struct {
+	DB *sql.DB
+}
+
+// authenticate verifies the bearer token of the request and the session it belongs to.
+// On success the claims are stored in the request locals under controllers.ClaimsLocalsKey.
+func (a *Auth) authenticate(c *fiber.Ctx) (jwt.MapClaims, error) {
+	sessionController := controllers.Session{}
+	authHeader := c.Get("Authorization")
+
+	if authHeader == "" {
+		return nil, apierrors.MissingAuthHeader
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return nil, apierrors.InvalidAuthHeader
+	}
+	tokenString := parts[1]
+
+	claims, err := sessionController.VerifyToken(tokenString)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			log.Println("Token has expired")
+			return nil, apierrors.TokenExpired
+		}
+
+		return nil, apierrors.InvalidToken
+	}
+
+	// Tokens issued before sessions existed have no session id and can't be revoked, so they are refused
+	sessionIdClaim, _ := claims["sid"].(string)
+	sessionId, err := uuid.Parse(sessionIdClaim)
+	if err != nil {
+		log.Println("Token without a valid session id:", err)
+		return nil, apierrors.InvalidToken
+	}
+
+	session, err := controllers.SessionModel.GetSessionById(a.DB, sessionId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apierrors.InvalidToken
+		}
+
+		log.Println("Error getting session by id:", err)
+		return nil, apierrors.Internal
+	}
+
+	if !session.Active() {
+		log.Printf("Token of revoked or expired session %s was used\n", sessionId)
+		return nil, apierrors.SessionRevoked
+	}
+
+	// Bans are checked on every request, so they take effect without waiting for the token to expire
+	user, err := controllers.UserModel.GetUserById(a.DB, session.UserId)
+	if err != nil {
+		log.Println("Error getting user of session:", err)
+		return nil, apierrors.Internal
+	}
+
+	if user.Banned() {
+		log.Printf("Banned user %s tried to access a route\n", user.ID)
+		return nil, controllers.BannedUserError(user.UserBan)
+	}
+
+	// Roles are read on every request too, so granting or revoking one applies to the tokens already issued
+	userRoles, err := controllers.RoleModel.GetUserRoles(a.DB, user.ID)
+	if err != nil {
+		log.Println("Error getting roles of user:", err)
+		return nil, apierrors.Internal
+	}
+	claims["roles"] = userRoles.Roles
+	claims["permissions"] = userRoles.Permissions
+
+	c.Locals(controllers.ClaimsLocalsKey, claims)
+
+	return claims, nil
+}
@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// OptionalUser lets requests without a token through, and authenticates the ones that have it,
+// so public routes can tailor the response to the logged user. A token that is sent has to be valid.
+func (a *Auth) OptionalUser(c *fiber.Ctx) error {
+	if c.Get("Authorization") == "" {
+		return c.Next()
+	}
+
+	if _, err := a.authenticate(c); err != nil {
+		return err
+	}
+
+	return c.Next()
+}
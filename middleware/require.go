@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"log"
+
+	"github.com/VinOfSteel/cinemagrader/apierrors"
+	"github.com/VinOfSteel/cinemagrader/controllers"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// Require only lets through users whose token carries at least one of the permissions.
+func (a *Auth) Require(permissions ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims, err := a.authenticate(c)
+		if err != nil {
+			return err
+		}
+
+		if !controllers.HasPermission(claims, permissions...) {
+			log.Printf("User with id %s and email %s tried to access a route that requires one of %v.\n", claims["id"].(string), claims["email"].(string), permissions)
+			return apierrors.PermissionDenied
+		}
+
+		return c.Next()
+	}
+}
+
+// RequireSelfOr lets through the user with the same id as the param, and other users only with one of the permissions.
+func (a *Auth) RequireSelfOr(permissions ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		queryId := c.Params("uuid")
+
+		claims, err := a.authenticate(c)
+		if err != nil {
+			return err
+		}
+
+		if _, err := uuid.Parse(queryId); err != nil {
+			log.Println("Invalid uuid sent in param:", err)
+			return apierrors.InvalidUUID
+		}
+
+		id := claims["id"].(string)
+
+		if id != queryId && !controllers.HasPermission(claims, permissions...) {
+			log.Printf("User with id %s trying to access user with id %s doesn't have one of %v.\n", id, queryId, permissions)
+			return apierrors.NotSelf
+		}
+
+		return c.Next()
+	}
+}
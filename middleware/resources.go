@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/VinOfSteel/cinemagrader/apierrors"
+	"github.com/VinOfSteel/cinemagrader/controllers"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Resource is something owned by a user that routes act on through their uuid param.
+// Owner loads the resource of the param and returns the id of the user who owns it, failing with an apierrors error if it doesn't exist.
+type Resource struct {
+	Name  string
+	Owner func(db *sql.DB, uuidParam string) (string, error)
+}
+
+// Comments are owned by their author. Reviews are graded comments, so they are edited and deleted through this resource too.
+var CommentResource = Resource{
+	Name: "comment",
+	Owner: func(db *sql.DB, uuidParam string) (string, error) {
+		comment, err := controllers.GetActiveComment(db, uuidParam)
+		return comment.UserId, err
+	},
+}
+
+var ListResource = Resource{
+	Name: "list",
+	Owner: func(db *sql.DB, uuidParam string) (string, error) {
+		list, err := controllers.GetActiveList(db, uuidParam)
+		return list.OwnerId, err
+	},
+}
+
+// verifyOwner lets the owner of the resource through, and other users only with one of the permissions
+func verifyOwner(claims jwt.MapClaims, resourceName string, ownerId string, permissions ...string) error {
+	id := claims["id"].(string)
+
+	if id != ownerId && !controllers.HasPermission(claims, permissions...) {
+		log.Printf("User with id %s trying to access %s of user %s is not its owner and doesn't have one of %v.\n", id, resourceName, ownerId, permissions)
+		return apierrors.NotOwner(resourceName)
+	}
+
+	return nil
+}
+
+// RequireOwnerOr loads the resource of the param and only lets its owner, or users with one of the permissions, through.
+func (a *Auth) RequireOwnerOr(resource Resource, permissions ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims, err := a.authenticate(c)
+		if err != nil {
+			return err
+		}
+
+		ownerId, err := resource.Owner(a.DB, c.Params("uuid"))
+		if err != nil {
+			return err
+		}
+
+		if err := verifyOwner(claims, resource.Name, ownerId, permissions...); err != nil {
+			return err
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"github.com/VinOfSteel/cinemagrader/controllers"
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/gofiber/fiber/v2"
+)
+
+// VerifyListVisible lets anyone see public lists, private ones work like RequireOwnerOr with ListResource.
+func (a *Auth) VerifyListVisible(c *fiber.Ctx) error {
+	list, err := controllers.GetActiveList(a.DB, c.Params("uuid"))
+	if err != nil {
+		return err
+	}
+
+	if list.IsPublic {
+		return c.Next()
+	}
+
+	claims, err := a.authenticate(c)
+	if err != nil {
+		return err
+	}
+
+	if err := verifyOwner(claims, ListResource.Name, list.OwnerId, models.PermissionManageUsers); err != nil {
+		return err
+	}
+
+	return c.Next()
+}
@@ -0,0 +1,14 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// VerifyUser only requires a valid token of an active session, no matter who the user is.
+func (a *Auth) VerifyUser(c *fiber.Ctx) error {
+	if _, err := a.authenticate(c); err != nil {
+		return err
+	}
+
+	return c.Next()
+}
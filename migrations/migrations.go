@@ -0,0 +1,318 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed sql/*.sql
+var files embed.FS
+
+// Directory in which the create command writes new migration files, relative to the repo root
+const Dir = "migrations/sql"
+
+// Arbitrary key used with pg_advisory_lock so two instances never migrate the same DB at the same time
+const advisoryLockKey int64 = 4_317_202_401
+
+const schemaMigrationsTableQuery = `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version BIGINT PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TIMESTAMP NOT NULL DEFAULT NOW()
+	);
+`
+
+var fileNameRegex = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt sql.NullTime
+}
+
+// Load reads every embedded migration and returns them sorted by version.
+// Each version must have both an up and a down file.
+func Load() ([]Migration, error) {
+	entries, err := fs.ReadDir(files, "sql")
+	if err != nil {
+		return nil, fmt.Errorf("error reading embedded migrations: %v", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		matches := fileNameRegex.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+
+		version, err := strconv.Atoi(matches[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %q: %v", entry.Name(), err)
+		}
+
+		content, err := files.ReadFile("sql/" + entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("error reading migration %q: %v", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: matches[2]}
+			byVersion[version] = migration
+		}
+
+		if migration.Name != matches[2] {
+			return nil, fmt.Errorf("migration version %d has conflicting names %q and %q", version, migration.Name, matches[2])
+		}
+
+		if matches[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s needs both an up and a down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// withLock runs fn on a single connection holding the migrations advisory lock.
+// Session level advisory locks belong to a connection, so everything has to run on the same one.
+func withLock(db *sql.DB, fn func(conn *sql.Conn, applied map[int]time.Time) error) error {
+	ctx := context.Background()
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting connection for migrations: %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1);", advisoryLockKey); err != nil {
+		return fmt.Errorf("error acquiring migrations lock: %v", err)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1);", advisoryLockKey); err != nil {
+			log.Printf("Error releasing migrations lock: %v\n", err)
+		}
+	}()
+
+	if _, err := conn.ExecContext(ctx, schemaMigrationsTableQuery); err != nil {
+		return fmt.Errorf("error creating schema_migrations table: %v", err)
+	}
+
+	applied, err := appliedVersions(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	return fn(conn, applied)
+}
+
+func appliedVersions(ctx context.Context, conn *sql.Conn) (map[int]time.Time, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations;")
+	if err != nil {
+		return nil, fmt.Errorf("error getting applied migrations: %v", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("error scanning applied migration: %v", err)
+		}
+		applied[version] = appliedAt
+	}
+
+	return applied, rows.Err()
+}
+
+// Each migration runs in its own transaction together with its schema_migrations bookkeeping,
+// so a failing migration leaves the DB exactly at the previous version.
+func runInTransaction(conn *sql.Conn, migration Migration, statement, bookkeepingQuery string, bookkeepingArgs ...interface{}) error {
+	ctx := context.Background()
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction for migration %04d_%s: %v", migration.Version, migration.Name, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, statement); err != nil {
+		return fmt.Errorf("error executing migration %04d_%s: %v", migration.Version, migration.Name, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, bookkeepingQuery, bookkeepingArgs...); err != nil {
+		return fmt.Errorf("error recording migration %04d_%s: %v", migration.Version, migration.Name, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing migration %04d_%s: %v", migration.Version, migration.Name, err)
+	}
+
+	return nil
+}
+
+// Up applies pending migrations in ascending order. A steps value of 0 or less applies all of them.
+func Up(db *sql.DB, steps int) (int, error) {
+	migrations, err := Load()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	err = withLock(db, func(conn *sql.Conn, applied map[int]time.Time) error {
+		for _, migration := range migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+
+			if steps > 0 && count >= steps {
+				break
+			}
+
+			log.Printf("Applying migration %04d_%s...\n", migration.Version, migration.Name)
+			if err := runInTransaction(conn, migration, migration.Up, "INSERT INTO schema_migrations (version, name) VALUES ($1, $2);", migration.Version, migration.Name); err != nil {
+				return err
+			}
+			count++
+		}
+
+		return nil
+	})
+
+	return count, err
+}
+
+// Down reverts applied migrations in descending order. A steps value of 0 or less reverts only the latest one.
+func Down(db *sql.DB, steps int) (int, error) {
+	migrations, err := Load()
+	if err != nil {
+		return 0, err
+	}
+
+	if steps <= 0 {
+		steps = 1
+	}
+
+	count := 0
+	err = withLock(db, func(conn *sql.Conn, applied map[int]time.Time) error {
+		for i := len(migrations) - 1; i >= 0 && count < steps; i-- {
+			migration := migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+
+			log.Printf("Reverting migration %04d_%s...\n", migration.Version, migration.Name)
+			if err := runInTransaction(conn, migration, migration.Down, "DELETE FROM schema_migrations WHERE version = $1;", migration.Version); err != nil {
+				return err
+			}
+			count++
+		}
+
+		return nil
+	})
+
+	return count, err
+}
+
+// Status lists every known migration and whether it was applied to the DB.
+func Status(db *sql.DB) ([]MigrationStatus, error) {
+	migrations, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []MigrationStatus
+	err = withLock(db, func(conn *sql.Conn, applied map[int]time.Time) error {
+		for _, migration := range migrations {
+			status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+			if appliedAt, ok := applied[migration.Version]; ok {
+				status.Applied = true
+				status.AppliedAt = sql.NullTime{Time: appliedAt, Valid: true}
+			}
+			statuses = append(statuses, status)
+		}
+
+		return nil
+	})
+
+	return statuses, err
+}
+
+func slugify(name string) string {
+	nonAlphanumericRegex := regexp.MustCompile(`[^a-z0-9]+`)
+	return strings.Trim(nonAlphanumericRegex.ReplaceAllString(strings.ToLower(name), "_"), "_")
+}
+
+// Create writes an empty up/down pair in dir, numbered after the highest version found there.
+// It returns the paths of the created files.
+func Create(dir, name string) (string, string, error) {
+	slug := slugify(name)
+	if slug == "" {
+		return "", "", fmt.Errorf("migration name %q has no valid characters", name)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", "", fmt.Errorf("error reading migrations directory %s: %v", dir, err)
+	}
+
+	nextVersion := 1
+	for _, entry := range entries {
+		matches := fileNameRegex.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			continue
+		}
+
+		version, _ := strconv.Atoi(matches[1])
+		if version >= nextVersion {
+			nextVersion = version + 1
+		}
+	}
+
+	base := fmt.Sprintf("%04d_%s", nextVersion, slug)
+	upPath := filepath.Join(dir, base+".up.sql")
+	downPath := filepath.Join(dir, base+".down.sql")
+
+	if err := os.WriteFile(upPath, []byte("-- Write the "+base+" migration here\n"), 0o644); err != nil {
+		return "", "", fmt.Errorf("error creating %s: %v", upPath, err)
+	}
+
+	if err := os.WriteFile(downPath, []byte("-- Revert the "+base+" migration here\n"), 0o644); err != nil {
+		return "", "", fmt.Errorf("error creating %s: %v", downPath, err)
+	}
+
+	return upPath, downPath, nil
+}
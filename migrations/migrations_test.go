@@ -0,0 +1,60 @@
+package migrations
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Load(t *testing.T) {
+	migrations, err := Load()
+	if err != nil {
+		t.Fatalf("Error loading embedded migrations: %v", err)
+	}
+
+	assert.NotEmpty(t, migrations, "Expected at least one embedded migration")
+
+	for i, migration := range migrations {
+		assert.Equal(t, i+1, migration.Version, "Migration versions should be sequential starting at 1")
+		assert.NotEmpty(t, migration.Name, "Migration %d has no name", migration.Version)
+		assert.NotEmpty(t, migration.Up, "Migration %d has no up statement", migration.Version)
+		assert.NotEmpty(t, migration.Down, "Migration %d has no down statement", migration.Version)
+	}
+}
+
+func Test_slugify(t *testing.T) {
+	testCases := []struct {
+		Have string
+		Want string
+	}{
+		{"add genres", "add_genres"},
+		{"Add Genres Table", "add_genres_table"},
+		{"  movies--actors billing  ", "movies_actors_billing"},
+		{"!!!", ""},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.Want, slugify(testCase.Have), "Unexpected slug for %q", testCase.Have)
+	}
+}
+
+func Test_Create(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(dir, "0007_existing.up.sql"), []byte(""), 0o644); err != nil {
+		t.Fatalf("Error writing existing migration: %v", err)
+	}
+
+	upPath, downPath, err := Create(dir, "Add Genres")
+	assert.NoError(t, err, "Unexpected error creating migration")
+	assert.Equal(t, filepath.Join(dir, "0008_add_genres.up.sql"), upPath)
+	assert.Equal(t, filepath.Join(dir, "0008_add_genres.down.sql"), downPath)
+
+	assert.FileExists(t, upPath)
+	assert.FileExists(t, downPath)
+
+	_, _, err = Create(dir, "???")
+	assert.Error(t, err, "Expected error for a name without valid characters")
+}
@@ -0,0 +1,10 @@
+DROP TRIGGER IF EXISTS comment_delete_trigger ON comments;
+DROP TRIGGER IF EXISTS comment_update_trigger ON comments;
+DROP TRIGGER IF EXISTS comment_insert_trigger ON comments;
+DROP FUNCTION IF EXISTS update_average_grade();
+
+DROP TABLE IF EXISTS comments;
+DROP TABLE IF EXISTS movies_actors;
+DROP TABLE IF EXISTS actors;
+DROP TABLE IF EXISTS movies;
+DROP TABLE IF EXISTS users;
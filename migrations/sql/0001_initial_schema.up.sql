@@ -0,0 +1,100 @@
+-- Initial schema, formerly executed on every boot from models/entities.go.
+-- Statements keep their IF NOT EXISTS guards so databases created before the
+-- migration subsystem existed can adopt this version without failing.
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+CREATE TABLE IF NOT EXISTS users (
+	id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+	name VARCHAR(50) NOT NULL,
+	surname VARCHAR(70),
+	email VARCHAR(100) NOT NULL UNIQUE,
+	password VARCHAR(200) NOT NULL,
+	birthday DATE NOT NULL,
+	is_adm BOOLEAN DEFAULT false,
+	picture TEXT DEFAULT '',
+	created_at TIMESTAMP DEFAULT NOW(),
+	updated_at TIMESTAMP DEFAULT NOW(),
+	deleted_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS movies (
+	id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+	title VARCHAR(50) NOT NULL UNIQUE,
+	director VARCHAR(50) NOT NULL,
+	release_date DATE NOT NULL,
+	picture TEXT DEFAULT '',
+	synopsis TEXT DEFAULT '',
+	created_at TIMESTAMP DEFAULT NOW(),
+	updated_at TIMESTAMP DEFAULT NOW(),
+	deleted_at TIMESTAMP,
+
+	creator_id UUID NOT NULL,
+	FOREIGN KEY (creator_id) REFERENCES users(id)
+);
+
+CREATE TABLE IF NOT EXISTS actors (
+	id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+	name VARCHAR(50) NOT NULL,
+	surname VARCHAR(70),
+	birthday DATE NOT NULL,
+	picture TEXT DEFAULT '',
+	created_at TIMESTAMP DEFAULT NOW(),
+	updated_at TIMESTAMP DEFAULT NOW(),
+	deleted_at TIMESTAMP,
+
+	creator_id UUID NOT NULL,
+	FOREIGN KEY (creator_id) REFERENCES users(id)
+);
+
+CREATE TABLE IF NOT EXISTS movies_actors(
+	id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+
+	actor_id UUID NOT NULL,
+	movie_id UUID,
+	FOREIGN KEY (actor_id) REFERENCES actors(id) ON DELETE RESTRICT,
+	FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE RESTRICT
+);
+
+CREATE TABLE IF NOT EXISTS comments(
+	id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+	comment TEXT NOT NULL,
+	grade DECIMAL(3, 1) CHECK (grade >= 1 AND grade <= 5),
+	created_at TIMESTAMP DEFAULT NOW(),
+	updated_at TIMESTAMP DEFAULT NOW(),
+	deleted_at TIMESTAMP,
+
+	user_id UUID NOT NULL,
+	movie_id UUID NOT NULL,
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT,
+	FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE RESTRICT
+);
+
+-- Movies - comments grade relationship.
+-- The comments table has to be created after the movies table because of the relationship,
+-- so the average_grade column is added to movies afterwards and kept up to date by triggers.
+ALTER TABLE movies ADD COLUMN IF NOT EXISTS average_grade DECIMAL(3, 1) DEFAULT 0;
+
+CREATE OR REPLACE FUNCTION update_average_grade()
+RETURNS TRIGGER AS $$
+BEGIN
+	UPDATE movies
+	SET average_grade = (
+		SELECT COALESCE(AVG(grade), 0) FROM comments WHERE movie_id = NEW.movie_id
+	)
+	WHERE id = NEW.movie_id;
+
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER comment_insert_trigger
+AFTER INSERT ON comments
+	FOR EACH ROW EXECUTE FUNCTION update_average_grade();
+
+CREATE OR REPLACE TRIGGER comment_update_trigger
+AFTER UPDATE ON comments
+	FOR EACH ROW EXECUTE FUNCTION update_average_grade();
+
+CREATE OR REPLACE TRIGGER comment_delete_trigger
+AFTER DELETE ON comments
+	FOR EACH ROW EXECUTE FUNCTION update_average_grade();
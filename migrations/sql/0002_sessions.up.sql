@@ -0,0 +1,15 @@
+-- Server side sessions backing refresh tokens. Only a SHA-256 hash of the refresh token is stored,
+-- and every access token carries the id of its session so revoking the session revokes the token.
+CREATE TABLE IF NOT EXISTS sessions (
+	id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+	refresh_token_hash VARCHAR(64) NOT NULL UNIQUE,
+	created_at TIMESTAMP DEFAULT NOW(),
+	updated_at TIMESTAMP DEFAULT NOW(),
+	expires_at TIMESTAMP NOT NULL,
+	revoked_at TIMESTAMP,
+
+	user_id UUID NOT NULL,
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS sessions_user_id_idx ON sessions (user_id);
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS actors_search_vector_idx;
+ALTER TABLE actors DROP COLUMN IF EXISTS search_vector;
+
+DROP INDEX IF EXISTS movies_search_vector_idx;
+ALTER TABLE movies DROP COLUMN IF EXISTS search_vector;
+
+DROP FUNCTION IF EXISTS immutable_unaccent(text);
+DROP EXTENSION IF EXISTS unaccent;
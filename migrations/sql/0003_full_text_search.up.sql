@@ -0,0 +1,27 @@
+-- Full text search over movies and actors.
+-- Vectors use the 'simple' configuration (no stemming) because titles and names mix languages,
+-- and accents are stripped so "acao" matches "ação".
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- unaccent() is only STABLE, but generated columns and index expressions require IMMUTABLE functions.
+-- Pinning the dictionary makes the wrapper safe to declare as IMMUTABLE.
+CREATE OR REPLACE FUNCTION immutable_unaccent(text)
+RETURNS text AS $$
+	SELECT public.unaccent('public.unaccent'::regdictionary, $1);
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT;
+
+ALTER TABLE movies ADD COLUMN IF NOT EXISTS search_vector tsvector
+	GENERATED ALWAYS AS (
+		setweight(to_tsvector('simple', immutable_unaccent(COALESCE(title, ''))), 'A') ||
+		setweight(to_tsvector('simple', immutable_unaccent(COALESCE(director, ''))), 'B') ||
+		setweight(to_tsvector('simple', immutable_unaccent(COALESCE(synopsis, ''))), 'C')
+	) STORED;
+
+CREATE INDEX IF NOT EXISTS movies_search_vector_idx ON movies USING GIN (search_vector);
+
+ALTER TABLE actors ADD COLUMN IF NOT EXISTS search_vector tsvector
+	GENERATED ALWAYS AS (
+		setweight(to_tsvector('simple', immutable_unaccent(COALESCE(name, '') || ' ' || COALESCE(surname, ''))), 'A')
+	) STORED;
+
+CREATE INDEX IF NOT EXISTS actors_search_vector_idx ON actors USING GIN (search_vector);
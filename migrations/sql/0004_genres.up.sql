@@ -0,0 +1,27 @@
+-- Genres and the movies_genres pivot, modeled after actors and movies_actors.
+-- Names are unique among active genres regardless of case, so a deleted genre can be created again.
+CREATE TABLE IF NOT EXISTS genres (
+	id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+	name VARCHAR(50) NOT NULL,
+	description TEXT DEFAULT '',
+	created_at TIMESTAMP DEFAULT NOW(),
+	updated_at TIMESTAMP DEFAULT NOW(),
+	deleted_at TIMESTAMP,
+
+	creator_id UUID NOT NULL,
+	FOREIGN KEY (creator_id) REFERENCES users(id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS genres_name_idx ON genres (LOWER(name)) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS movies_genres (
+	id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+
+	genre_id UUID NOT NULL,
+	movie_id UUID NOT NULL,
+	FOREIGN KEY (genre_id) REFERENCES genres(id) ON DELETE RESTRICT,
+	FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE RESTRICT,
+	UNIQUE (movie_id, genre_id)
+);
+
+CREATE INDEX IF NOT EXISTS movies_genres_genre_id_idx ON movies_genres (genre_id);
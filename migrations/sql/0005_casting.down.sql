@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS movies_actors_movie_id_billing_order_idx;
+ALTER TABLE movies_actors DROP COLUMN IF EXISTS billing_order;
+ALTER TABLE movies_actors DROP COLUMN IF EXISTS character_name;
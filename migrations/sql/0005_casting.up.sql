@@ -0,0 +1,15 @@
+-- Casting details on the movies_actors pivot: who the actor played and where they are billed.
+ALTER TABLE movies_actors ADD COLUMN IF NOT EXISTS character_name VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE movies_actors ADD COLUMN IF NOT EXISTS billing_order INTEGER NOT NULL DEFAULT 0;
+
+-- Existing casts had no order at all, so they are billed in the order their actors were created.
+UPDATE movies_actors ma
+	SET billing_order = ordered.position
+	FROM (
+		SELECT ma.id, ROW_NUMBER() OVER (PARTITION BY ma.movie_id ORDER BY a.created_at, a.id) AS position
+			FROM movies_actors ma
+				JOIN actors a ON a.id = ma.actor_id
+	) AS ordered
+	WHERE ma.id = ordered.id;
+
+CREATE INDEX IF NOT EXISTS movies_actors_movie_id_billing_order_idx ON movies_actors (movie_id, billing_order);
@@ -0,0 +1,3 @@
+ALTER TABLE actors DROP COLUMN IF EXISTS person_id;
+DROP TABLE IF EXISTS movie_crew;
+DROP TABLE IF EXISTS people;
@@ -0,0 +1,46 @@
+-- People that work on movies, linked to them through a role-aware pivot.
+-- The free text movies.director column stays for compatibility, but every director is now also a person.
+CREATE TABLE IF NOT EXISTS people (
+	id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+	name VARCHAR(100) NOT NULL,
+	birthday DATE,
+	picture TEXT DEFAULT '',
+	biography TEXT DEFAULT '',
+	created_at TIMESTAMP DEFAULT NOW(),
+	updated_at TIMESTAMP DEFAULT NOW(),
+	deleted_at TIMESTAMP,
+
+	creator_id UUID NOT NULL,
+	FOREIGN KEY (creator_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS people_name_idx ON people (LOWER(name));
+
+CREATE TABLE IF NOT EXISTS movie_crew (
+	id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+	role VARCHAR(20) NOT NULL CHECK (role IN ('director', 'writer', 'producer', 'composer', 'cinematographer')),
+
+	person_id UUID NOT NULL,
+	movie_id UUID NOT NULL,
+	FOREIGN KEY (person_id) REFERENCES people(id) ON DELETE RESTRICT,
+	FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE RESTRICT,
+	UNIQUE (movie_id, person_id, role)
+);
+
+CREATE INDEX IF NOT EXISTS movie_crew_person_id_idx ON movie_crew (person_id);
+
+-- Actors can optionally be the same person as a crew member, so their filmography shows both sides
+ALTER TABLE actors ADD COLUMN IF NOT EXISTS person_id UUID REFERENCES people(id) ON DELETE RESTRICT;
+
+-- Backfilling one person per distinct director, ignoring case and surrounding spaces.
+-- The person belongs to whoever created the first movie of that director.
+INSERT INTO people (name, creator_id)
+	SELECT DISTINCT ON (LOWER(TRIM(director))) TRIM(director), creator_id
+		FROM movies
+			WHERE TRIM(COALESCE(director, '')) <> ''
+				ORDER BY LOWER(TRIM(director)), created_at;
+
+INSERT INTO movie_crew (person_id, movie_id, role)
+	SELECT p.id, m.id, 'director'
+		FROM movies m
+			JOIN people p ON LOWER(p.name) = LOWER(TRIM(m.director));
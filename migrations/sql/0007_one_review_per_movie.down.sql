@@ -0,0 +1 @@
+DROP INDEX IF EXISTS comments_one_review_per_user_movie_idx;
@@ -0,0 +1,15 @@
+-- Ungraded comments are stored with a NULL grade, so only graded comments count as reviews.
+-- Users that already reviewed a movie more than once keep their latest review,
+-- the older ones stay as ungraded discussion comments.
+UPDATE comments c
+	SET grade = NULL, updated_at = NOW()
+	FROM (
+		SELECT id, ROW_NUMBER() OVER (PARTITION BY user_id, movie_id ORDER BY updated_at DESC, id) AS position
+			FROM comments
+			WHERE grade IS NOT NULL AND deleted_at IS NULL
+	) AS reviews
+	WHERE c.id = reviews.id AND reviews.position > 1;
+
+-- One active review per user and movie. Also the conflict target of the review upsert.
+CREATE UNIQUE INDEX IF NOT EXISTS comments_one_review_per_user_movie_idx ON comments (user_id, movie_id)
+	WHERE grade IS NOT NULL AND deleted_at IS NULL;
@@ -0,0 +1,16 @@
+CREATE OR REPLACE FUNCTION update_average_grade()
+RETURNS TRIGGER AS $$
+BEGIN
+	UPDATE movies
+	SET average_grade = (
+		SELECT COALESCE(AVG(grade), 0) FROM comments WHERE movie_id = NEW.movie_id
+	)
+	WHERE id = NEW.movie_id;
+
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP FUNCTION IF EXISTS refresh_movie_rating(UUID);
+ALTER TABLE movies DROP COLUMN IF EXISTS grade_histogram;
+ALTER TABLE movies DROP COLUMN IF EXISTS rating_count;
@@ -0,0 +1,48 @@
+-- Rating stats of a movie, all computed from its active reviews (graded comments that were not deleted).
+-- The histogram counts reviews by grade rounded to the nearest whole grade.
+ALTER TABLE movies ADD COLUMN IF NOT EXISTS rating_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE movies ADD COLUMN IF NOT EXISTS grade_histogram JSONB NOT NULL DEFAULT '{"1": 0, "2": 0, "3": 0, "4": 0, "5": 0}';
+
+CREATE OR REPLACE FUNCTION refresh_movie_rating(target_movie_id UUID)
+RETURNS VOID AS $$
+BEGIN
+	UPDATE movies
+	SET average_grade = stats.average_grade,
+		rating_count = stats.rating_count,
+		grade_histogram = stats.grade_histogram
+	FROM (
+		SELECT
+			COALESCE(AVG(grade), 0) AS average_grade,
+			COUNT(*) AS rating_count,
+			jsonb_build_object(
+				'1', COUNT(*) FILTER (WHERE ROUND(grade) = 1),
+				'2', COUNT(*) FILTER (WHERE ROUND(grade) = 2),
+				'3', COUNT(*) FILTER (WHERE ROUND(grade) = 3),
+				'4', COUNT(*) FILTER (WHERE ROUND(grade) = 4),
+				'5', COUNT(*) FILTER (WHERE ROUND(grade) = 5)
+			) AS grade_histogram
+			FROM comments
+			WHERE movie_id = target_movie_id AND grade IS NOT NULL AND deleted_at IS NULL
+	) AS stats
+	WHERE id = target_movie_id;
+END;
+$$ LANGUAGE plpgsql;
+
+-- NEW is NULL on deletes and OLD on inserts, so each operation refreshes the movies it can have changed
+CREATE OR REPLACE FUNCTION update_average_grade()
+RETURNS TRIGGER AS $$
+BEGIN
+	IF TG_OP IN ('UPDATE', 'DELETE') THEN
+		PERFORM refresh_movie_rating(OLD.movie_id);
+	END IF;
+
+	IF TG_OP = 'INSERT' OR (TG_OP = 'UPDATE' AND NEW.movie_id IS DISTINCT FROM OLD.movie_id) THEN
+		PERFORM refresh_movie_rating(NEW.movie_id);
+	END IF;
+
+	RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Averages computed by the old function counted deleted comments, so every movie is recomputed
+SELECT refresh_movie_rating(id) FROM movies;
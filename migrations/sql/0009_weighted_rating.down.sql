@@ -0,0 +1,29 @@
+CREATE OR REPLACE FUNCTION refresh_movie_rating(target_movie_id UUID)
+RETURNS VOID AS $$
+BEGIN
+	UPDATE movies
+	SET average_grade = stats.average_grade,
+		rating_count = stats.rating_count,
+		grade_histogram = stats.grade_histogram
+	FROM (
+		SELECT
+			COALESCE(AVG(grade), 0) AS average_grade,
+			COUNT(*) AS rating_count,
+			jsonb_build_object(
+				'1', COUNT(*) FILTER (WHERE ROUND(grade) = 1),
+				'2', COUNT(*) FILTER (WHERE ROUND(grade) = 2),
+				'3', COUNT(*) FILTER (WHERE ROUND(grade) = 3),
+				'4', COUNT(*) FILTER (WHERE ROUND(grade) = 4),
+				'5', COUNT(*) FILTER (WHERE ROUND(grade) = 5)
+			) AS grade_histogram
+			FROM comments
+			WHERE movie_id = target_movie_id AND grade IS NOT NULL AND deleted_at IS NULL
+	) AS stats
+	WHERE id = target_movie_id;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP INDEX IF EXISTS comments_active_reviews_created_at_idx;
+DROP INDEX IF EXISTS movies_weighted_rating_idx;
+ALTER TABLE movies DROP COLUMN IF EXISTS weighted_rating;
+DROP TABLE IF EXISTS rating_settings;
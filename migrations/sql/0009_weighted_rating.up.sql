@@ -0,0 +1,51 @@
+-- Settings of the weighted rating, a single row synced from the environment when the API starts.
+-- The weighted rating pulls the average of movies with few reviews towards prior_mean,
+-- and min_votes is how many reviews a movie needs for its own average to weigh as much as the prior.
+CREATE TABLE IF NOT EXISTS rating_settings (
+	id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+	prior_mean DECIMAL(3, 2) NOT NULL DEFAULT 3 CHECK (prior_mean >= 1 AND prior_mean <= 5),
+	min_votes INTEGER NOT NULL DEFAULT 10 CHECK (min_votes >= 0),
+	updated_at TIMESTAMP DEFAULT NOW()
+);
+
+INSERT INTO rating_settings (id) VALUES (TRUE) ON CONFLICT (id) DO NOTHING;
+
+ALTER TABLE movies ADD COLUMN IF NOT EXISTS weighted_rating DECIMAL(5, 3) NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS movies_weighted_rating_idx ON movies (weighted_rating);
+
+-- Trending movies count the reviews created inside a time window
+CREATE INDEX IF NOT EXISTS comments_active_reviews_created_at_idx ON comments (created_at, movie_id)
+	WHERE grade IS NOT NULL AND deleted_at IS NULL;
+
+-- Same stats as before, plus the weighted rating: (v / (v + m)) * R + (m / (v + m)) * C,
+-- where v is the review count, R the average grade, m the min votes and C the prior mean
+CREATE OR REPLACE FUNCTION refresh_movie_rating(target_movie_id UUID)
+RETURNS VOID AS $$
+BEGIN
+	UPDATE movies
+	SET average_grade = COALESCE(stats.average_grade, 0),
+		rating_count = stats.rating_count,
+		grade_histogram = stats.grade_histogram,
+		weighted_rating = CASE
+			WHEN stats.rating_count + settings.min_votes = 0 THEN 0
+			ELSE (stats.rating_count * COALESCE(stats.average_grade, 0) + settings.min_votes * settings.prior_mean) / (stats.rating_count + settings.min_votes)
+		END
+	FROM (
+		SELECT
+			AVG(grade) AS average_grade,
+			COUNT(*) AS rating_count,
+			jsonb_build_object(
+				'1', COUNT(*) FILTER (WHERE ROUND(grade) = 1),
+				'2', COUNT(*) FILTER (WHERE ROUND(grade) = 2),
+				'3', COUNT(*) FILTER (WHERE ROUND(grade) = 3),
+				'4', COUNT(*) FILTER (WHERE ROUND(grade) = 4),
+				'5', COUNT(*) FILTER (WHERE ROUND(grade) = 5)
+			) AS grade_histogram
+			FROM comments
+			WHERE movie_id = target_movie_id AND grade IS NOT NULL AND deleted_at IS NULL
+	) AS stats, rating_settings AS settings
+	WHERE movies.id = target_movie_id;
+END;
+$$ LANGUAGE plpgsql;
+
+SELECT refresh_movie_rating(id) FROM movies;
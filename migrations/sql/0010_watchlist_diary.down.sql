@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS diary;
+DROP TABLE IF EXISTS watchlist;
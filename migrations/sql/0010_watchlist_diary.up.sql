@@ -0,0 +1,32 @@
+-- Movies a user wants to watch. Removing a movie from the watchlist deletes the row.
+CREATE TABLE IF NOT EXISTS watchlist (
+	id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+	note TEXT NOT NULL DEFAULT '',
+	created_at TIMESTAMP DEFAULT NOW(),
+
+	user_id UUID NOT NULL,
+	movie_id UUID NOT NULL,
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT,
+	FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE RESTRICT,
+	UNIQUE (user_id, movie_id)
+);
+
+-- Every time a user watched a movie, optionally linked to the review they wrote about it
+CREATE TABLE IF NOT EXISTS diary (
+	id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+	watched_on DATE NOT NULL,
+	rewatch BOOLEAN NOT NULL DEFAULT false,
+	notes TEXT NOT NULL DEFAULT '',
+	created_at TIMESTAMP DEFAULT NOW(),
+	updated_at TIMESTAMP DEFAULT NOW(),
+	deleted_at TIMESTAMP,
+
+	user_id UUID NOT NULL,
+	movie_id UUID NOT NULL,
+	review_id UUID,
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT,
+	FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE RESTRICT,
+	FOREIGN KEY (review_id) REFERENCES comments(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS diary_user_id_watched_on_idx ON diary (user_id, watched_on) WHERE deleted_at IS NULL;
@@ -0,0 +1,33 @@
+-- Movie lists curated by users. Private lists are only seen by their owner and administrators.
+CREATE TABLE IF NOT EXISTS lists (
+	id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+	name VARCHAR(100) NOT NULL,
+	description TEXT NOT NULL DEFAULT '',
+	is_public BOOLEAN NOT NULL DEFAULT false,
+	created_at TIMESTAMP DEFAULT NOW(),
+	updated_at TIMESTAMP DEFAULT NOW(),
+	deleted_at TIMESTAMP,
+
+	owner_id UUID NOT NULL,
+	forked_from_id UUID,
+	FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE RESTRICT,
+	FOREIGN KEY (forked_from_id) REFERENCES lists(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS lists_owner_id_idx ON lists (owner_id);
+CREATE INDEX IF NOT EXISTS lists_public_idx ON lists (created_at) WHERE is_public AND deleted_at IS NULL;
+
+-- Positions start at 1 and have no gaps. The unique position is deferrable so a single UPDATE can shift or
+-- reorder every item of a list, only checking for duplicates at the end of the statement.
+CREATE TABLE IF NOT EXISTS list_items (
+	position INTEGER NOT NULL CHECK (position >= 1),
+	note TEXT NOT NULL DEFAULT '',
+	created_at TIMESTAMP DEFAULT NOW(),
+
+	list_id UUID NOT NULL,
+	movie_id UUID NOT NULL,
+	PRIMARY KEY (list_id, movie_id),
+	FOREIGN KEY (list_id) REFERENCES lists(id) ON DELETE CASCADE,
+	FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE RESTRICT,
+	CONSTRAINT list_items_position_key UNIQUE (list_id, position) DEFERRABLE INITIALLY IMMEDIATE
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS activity;
+DROP FUNCTION IF EXISTS prevent_activity_changes();
+DROP TABLE IF EXISTS follows;
@@ -0,0 +1,42 @@
+-- Users following other users
+CREATE TABLE IF NOT EXISTS follows (
+	created_at TIMESTAMP DEFAULT NOW(),
+
+	follower_id UUID NOT NULL,
+	followed_id UUID NOT NULL,
+	PRIMARY KEY (follower_id, followed_id),
+	FOREIGN KEY (follower_id) REFERENCES users(id) ON DELETE RESTRICT,
+	FOREIGN KEY (followed_id) REFERENCES users(id) ON DELETE RESTRICT,
+	CHECK (follower_id <> followed_id)
+);
+
+CREATE INDEX IF NOT EXISTS follows_followed_id_idx ON follows (followed_id);
+
+-- Append only log of what users did, read by the feed of their followers.
+-- Every row is written in the same transaction as the change it describes, and is never updated or deleted.
+CREATE TABLE IF NOT EXISTS activity (
+	id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+	type VARCHAR(20) NOT NULL CHECK (type IN ('review', 'comment', 'comment_edit', 'grade')),
+	grade DECIMAL(3, 1),
+	created_at TIMESTAMP NOT NULL DEFAULT CLOCK_TIMESTAMP(),
+
+	user_id UUID NOT NULL,
+	movie_id UUID NOT NULL,
+	comment_id UUID,
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT,
+	FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE RESTRICT,
+	FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE RESTRICT
+);
+
+CREATE INDEX IF NOT EXISTS activity_user_id_created_at_idx ON activity (user_id, created_at);
+
+CREATE OR REPLACE FUNCTION prevent_activity_changes()
+RETURNS TRIGGER AS $$
+BEGIN
+	RAISE EXCEPTION 'activity is append only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER activity_append_only
+BEFORE UPDATE OR DELETE ON activity
+	FOR EACH ROW EXECUTE FUNCTION prevent_activity_changes();
@@ -0,0 +1,15 @@
+CREATE OR REPLACE TRIGGER comment_update_trigger
+AFTER UPDATE ON comments
+	FOR EACH ROW EXECUTE FUNCTION update_average_grade();
+
+DROP TRIGGER IF EXISTS comment_reply_delete_trigger ON comments;
+DROP TRIGGER IF EXISTS comment_reply_insert_trigger ON comments;
+DROP FUNCTION IF EXISTS update_comment_reply_count();
+DROP TABLE IF EXISTS comment_likes;
+DROP FUNCTION IF EXISTS update_comment_like_count();
+
+ALTER TABLE comments DROP COLUMN IF EXISTS reply_count;
+ALTER TABLE comments DROP COLUMN IF EXISTS like_count;
+ALTER TABLE comments DROP CONSTRAINT IF EXISTS comments_replies_without_grade;
+ALTER TABLE comments DROP COLUMN IF EXISTS depth;
+ALTER TABLE comments DROP COLUMN IF EXISTS parent_id;
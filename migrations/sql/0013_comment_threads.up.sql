@@ -0,0 +1,73 @@
+-- Replies to comments. Depth is 0 for comments on the movie and grows by one on each reply, up to 3.
+-- Replies are discussion, so they can't have a grade, and they always belong to the movie of their parent.
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES comments(id) ON DELETE RESTRICT;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS depth SMALLINT NOT NULL DEFAULT 0 CHECK (depth BETWEEN 0 AND 3);
+ALTER TABLE comments ADD CONSTRAINT comments_replies_without_grade CHECK (parent_id IS NULL OR grade IS NULL);
+
+CREATE INDEX IF NOT EXISTS comments_parent_id_idx ON comments (parent_id) WHERE parent_id IS NOT NULL;
+
+-- Counters kept up to date by the triggers below. Likes of deleted users still count, deleted replies do not.
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS like_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS reply_count INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS comment_likes (
+	created_at TIMESTAMP DEFAULT NOW(),
+
+	user_id UUID NOT NULL,
+	comment_id UUID NOT NULL,
+	PRIMARY KEY (user_id, comment_id),
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT,
+	FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS comment_likes_comment_id_idx ON comment_likes (comment_id);
+
+CREATE OR REPLACE FUNCTION update_comment_like_count()
+RETURNS TRIGGER AS $$
+BEGIN
+	IF TG_OP = 'INSERT' THEN
+		UPDATE comments SET like_count = like_count + 1 WHERE id = NEW.comment_id;
+	ELSE
+		UPDATE comments SET like_count = like_count - 1 WHERE id = OLD.comment_id;
+	END IF;
+
+	RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER comment_like_insert_trigger
+AFTER INSERT ON comment_likes
+	FOR EACH ROW EXECUTE FUNCTION update_comment_like_count();
+
+CREATE OR REPLACE TRIGGER comment_like_delete_trigger
+AFTER DELETE ON comment_likes
+	FOR EACH ROW EXECUTE FUNCTION update_comment_like_count();
+
+-- Only active replies are counted, so soft deleting or restoring a reply also refreshes its parent
+CREATE OR REPLACE FUNCTION update_comment_reply_count()
+RETURNS TRIGGER AS $$
+BEGIN
+	IF NEW.parent_id IS NOT NULL THEN
+		UPDATE comments
+		SET reply_count = (
+			SELECT COUNT(*) FROM comments WHERE parent_id = NEW.parent_id AND deleted_at IS NULL
+		)
+		WHERE id = NEW.parent_id;
+	END IF;
+
+	RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER comment_reply_insert_trigger
+AFTER INSERT ON comments
+	FOR EACH ROW EXECUTE FUNCTION update_comment_reply_count();
+
+CREATE OR REPLACE TRIGGER comment_reply_delete_trigger
+AFTER UPDATE OF deleted_at ON comments
+	FOR EACH ROW EXECUTE FUNCTION update_comment_reply_count();
+
+-- Likes and replies update the counters of the comment, which shouldn't recompute the rating of the movie
+CREATE OR REPLACE TRIGGER comment_update_trigger
+AFTER UPDATE OF grade, deleted_at, movie_id ON comments
+	FOR EACH ROW EXECUTE FUNCTION update_average_grade();
@@ -0,0 +1,59 @@
+CREATE OR REPLACE TRIGGER comment_reply_delete_trigger
+AFTER UPDATE OF deleted_at ON comments
+	FOR EACH ROW EXECUTE FUNCTION update_comment_reply_count();
+
+CREATE OR REPLACE FUNCTION update_comment_reply_count()
+RETURNS TRIGGER AS $$
+BEGIN
+	IF NEW.parent_id IS NOT NULL THEN
+		UPDATE comments
+		SET reply_count = (
+			SELECT COUNT(*) FROM comments WHERE parent_id = NEW.parent_id AND deleted_at IS NULL
+		)
+		WHERE id = NEW.parent_id;
+	END IF;
+
+	RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER comment_update_trigger
+AFTER UPDATE OF grade, deleted_at, movie_id ON comments
+	FOR EACH ROW EXECUTE FUNCTION update_average_grade();
+
+CREATE OR REPLACE FUNCTION refresh_movie_rating(target_movie_id UUID)
+RETURNS VOID AS $$
+BEGIN
+	UPDATE movies
+	SET average_grade = COALESCE(stats.average_grade, 0),
+		rating_count = stats.rating_count,
+		grade_histogram = stats.grade_histogram,
+		weighted_rating = CASE
+			WHEN stats.rating_count + settings.min_votes = 0 THEN 0
+			ELSE (stats.rating_count * COALESCE(stats.average_grade, 0) + settings.min_votes * settings.prior_mean) / (stats.rating_count + settings.min_votes)
+		END
+	FROM (
+		SELECT
+			AVG(grade) AS average_grade,
+			COUNT(*) AS rating_count,
+			jsonb_build_object(
+				'1', COUNT(*) FILTER (WHERE ROUND(grade) = 1),
+				'2', COUNT(*) FILTER (WHERE ROUND(grade) = 2),
+				'3', COUNT(*) FILTER (WHERE ROUND(grade) = 3),
+				'4', COUNT(*) FILTER (WHERE ROUND(grade) = 4),
+				'5', COUNT(*) FILTER (WHERE ROUND(grade) = 5)
+			) AS grade_histogram
+			FROM comments
+			WHERE movie_id = target_movie_id AND grade IS NOT NULL AND deleted_at IS NULL
+	) AS stats, rating_settings AS settings
+	WHERE movies.id = target_movie_id;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TABLE IF EXISTS comment_reports;
+
+DROP INDEX IF EXISTS comments_pending_idx;
+ALTER TABLE users DROP COLUMN IF EXISTS banned_at;
+ALTER TABLE comments DROP COLUMN IF EXISTS status;
+
+SELECT refresh_movie_rating(id) FROM movies;
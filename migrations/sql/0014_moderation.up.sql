@@ -0,0 +1,83 @@
+-- Moderation status of comments. Comments that hit the blocklist wait as pending until an admin reviews them,
+-- and hidden comments were removed by an admin. Only visible comments are public and count in the ratings.
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS status VARCHAR(10) NOT NULL DEFAULT 'visible' CHECK (status IN ('visible', 'pending', 'hidden'));
+
+CREATE INDEX IF NOT EXISTS comments_pending_idx ON comments (created_at) WHERE status = 'pending' AND deleted_at IS NULL;
+
+-- Users banned by an admin can't write comments anymore
+ALTER TABLE users ADD COLUMN IF NOT EXISTS banned_at TIMESTAMP;
+
+-- Comments reported by users. A report stays open until an admin acts on the comment, which resolves all of its open reports at once.
+CREATE TABLE IF NOT EXISTS comment_reports (
+	id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+	reason TEXT NOT NULL,
+	created_at TIMESTAMP DEFAULT NOW(),
+	resolved_at TIMESTAMP,
+	resolution VARCHAR(10) CHECK (resolution IN ('approve', 'hide', 'ban')),
+
+	user_id UUID NOT NULL,
+	comment_id UUID NOT NULL,
+	resolver_id UUID,
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT,
+	FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE,
+	FOREIGN KEY (resolver_id) REFERENCES users(id) ON DELETE RESTRICT,
+	CHECK ((resolved_at IS NULL) = (resolution IS NULL))
+);
+
+-- A user can only have one open report per comment
+CREATE UNIQUE INDEX IF NOT EXISTS comment_reports_open_idx ON comment_reports (user_id, comment_id) WHERE resolved_at IS NULL;
+CREATE INDEX IF NOT EXISTS comment_reports_comment_id_idx ON comment_reports (comment_id) WHERE resolved_at IS NULL;
+
+-- Same stats as before, only from visible reviews
+CREATE OR REPLACE FUNCTION refresh_movie_rating(target_movie_id UUID)
+RETURNS VOID AS $$
+BEGIN
+	UPDATE movies
+	SET average_grade = COALESCE(stats.average_grade, 0),
+		rating_count = stats.rating_count,
+		grade_histogram = stats.grade_histogram,
+		weighted_rating = CASE
+			WHEN stats.rating_count + settings.min_votes = 0 THEN 0
+			ELSE (stats.rating_count * COALESCE(stats.average_grade, 0) + settings.min_votes * settings.prior_mean) / (stats.rating_count + settings.min_votes)
+		END
+	FROM (
+		SELECT
+			AVG(grade) AS average_grade,
+			COUNT(*) AS rating_count,
+			jsonb_build_object(
+				'1', COUNT(*) FILTER (WHERE ROUND(grade) = 1),
+				'2', COUNT(*) FILTER (WHERE ROUND(grade) = 2),
+				'3', COUNT(*) FILTER (WHERE ROUND(grade) = 3),
+				'4', COUNT(*) FILTER (WHERE ROUND(grade) = 4),
+				'5', COUNT(*) FILTER (WHERE ROUND(grade) = 5)
+			) AS grade_histogram
+			FROM comments
+			WHERE movie_id = target_movie_id AND grade IS NOT NULL AND deleted_at IS NULL AND status = 'visible'
+	) AS stats, rating_settings AS settings
+	WHERE movies.id = target_movie_id;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER comment_update_trigger
+AFTER UPDATE OF grade, deleted_at, movie_id, status ON comments
+	FOR EACH ROW EXECUTE FUNCTION update_average_grade();
+
+-- Replies that aren't visible are left out of the reply count too
+CREATE OR REPLACE FUNCTION update_comment_reply_count()
+RETURNS TRIGGER AS $$
+BEGIN
+	IF NEW.parent_id IS NOT NULL THEN
+		UPDATE comments
+		SET reply_count = (
+			SELECT COUNT(*) FROM comments WHERE parent_id = NEW.parent_id AND deleted_at IS NULL AND status = 'visible'
+		)
+		WHERE id = NEW.parent_id;
+	END IF;
+
+	RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER comment_reply_delete_trigger
+AFTER UPDATE OF deleted_at, status ON comments
+	FOR EACH ROW EXECUTE FUNCTION update_comment_reply_count();
@@ -0,0 +1,3 @@
+-- Comments flagged as spoilers, by their author or by an admin, are redacted as a whole in the movie comments.
+-- Inline ||spoiler|| markup is redacted when reading and needs no column.
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS contains_spoilers BOOLEAN NOT NULL DEFAULT FALSE;
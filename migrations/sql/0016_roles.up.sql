@@ -0,0 +1,28 @@
+-- Roles group the permissions checked by the routes, replacing the single is_adm flag.
+-- Routes only ever check permissions, so what a role can do is changed here and not in the code.
+CREATE TABLE IF NOT EXISTS roles (
+	name VARCHAR(30) PRIMARY KEY,
+	permissions TEXT[] NOT NULL DEFAULT '{}',
+	created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+	created_at TIMESTAMP DEFAULT NOW(),
+
+	user_id UUID NOT NULL,
+	role_name VARCHAR(30) NOT NULL,
+	PRIMARY KEY (user_id, role_name),
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT,
+	FOREIGN KEY (role_name) REFERENCES roles(name) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+INSERT INTO roles (name, permissions) VALUES
+	('admin', ARRAY['users:manage', 'catalog:edit', 'comments:moderate']),
+	('moderator', ARRAY['comments:moderate']),
+	('curator', ARRAY['catalog:edit'])
+	ON CONFLICT (name) DO NOTHING;
+
+-- Existing admins keep their access. is_adm stays in sync with the admin role from now on.
+INSERT INTO user_roles (user_id, role_name)
+	SELECT id, 'admin' FROM users WHERE is_adm
+	ON CONFLICT DO NOTHING;
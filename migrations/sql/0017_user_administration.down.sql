@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS admin_audit_log;
+DROP FUNCTION IF EXISTS prevent_admin_audit_log_changes();
+
+ALTER TABLE users DROP COLUMN IF EXISTS password_reset_expires_at;
+ALTER TABLE users DROP COLUMN IF EXISTS password_reset_token_hash;
+ALTER TABLE users DROP COLUMN IF EXISTS ban_reason;
+ALTER TABLE users DROP COLUMN IF EXISTS banned_until;
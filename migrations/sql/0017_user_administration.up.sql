@@ -0,0 +1,35 @@
+-- Bans can expire and carry the reason given by the admin. Bans without banned_until never expire.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS banned_until TIMESTAMP;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS ban_reason TEXT;
+
+-- Password resets forced by an admin. The user can't login until a new password is set with the reset token.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_reset_token_hash VARCHAR(64) UNIQUE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_reset_expires_at TIMESTAMP;
+
+-- Append only log of what admins did to users. Actions run from the CLI have no actor.
+CREATE TABLE IF NOT EXISTS admin_audit_log (
+	id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+	action VARCHAR(20) NOT NULL CHECK (action IN ('promote', 'demote', 'ban', 'unban', 'password_reset')),
+	role VARCHAR(30),
+	reason TEXT,
+	expires_at TIMESTAMP,
+	created_at TIMESTAMP NOT NULL DEFAULT CLOCK_TIMESTAMP(),
+
+	actor_id UUID,
+	target_id UUID NOT NULL,
+	FOREIGN KEY (actor_id) REFERENCES users(id) ON DELETE RESTRICT,
+	FOREIGN KEY (target_id) REFERENCES users(id) ON DELETE RESTRICT
+);
+
+CREATE INDEX IF NOT EXISTS admin_audit_log_target_id_idx ON admin_audit_log (target_id);
+
+CREATE OR REPLACE FUNCTION prevent_admin_audit_log_changes()
+RETURNS TRIGGER AS $$
+BEGIN
+	RAISE EXCEPTION 'admin_audit_log is append only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER admin_audit_log_append_only
+BEFORE UPDATE OR DELETE ON admin_audit_log
+	FOR EACH ROW EXECUTE FUNCTION prevent_admin_audit_log_changes();
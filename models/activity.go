@@ -0,0 +1,124 @@
+package models
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Types of activity shown in the feed
+const (
+	ActivityReview      = "review"
+	ActivityComment     = "comment"
+	ActivityCommentEdit = "comment_edit"
+	ActivityGrade       = "grade"
+)
+
+type ActivityModel struct {
+	ID        uuid.UUID `json:"id"`
+	Type      string    `json:"type"`
+	Grade     float64   `json:"grade"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	UserId    string        `json:"userId"`
+	MovieId   string        `json:"movieId"`
+	CommentId uuid.NullUUID `json:"commentId"`
+}
+
+// ActivityResponse is an item of the feed. Grade is the grade given by the activity, 0 when it has none,
+// and Comment is the current text of the comment it refers to, redacted like the comments of the movie
+// when it has spoilers.
+type ActivityResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Type      string    `json:"type"`
+	Grade     float64   `json:"grade"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	UserId     string        `json:"userId"`
+	UserName   string        `json:"userName"`
+	MovieId    string        `json:"movieId"`
+	MovieTitle string        `json:"movieTitle"`
+	CommentId  uuid.NullUUID `json:"commentId"`
+	Comment    string        `json:"comment"`
+
+	ContainsSpoilers bool `json:"containsSpoilers"`
+	SpoilersRedacted bool `json:"spoilersRedacted"`
+}
+
+// Columns the feed can be sorted by
+var activitySortColumns = []string{"created_at"}
+
+const activityColumns = "id, type, grade, created_at, user_id, user_name, movie_id, movie_title, comment_id, comment, contains_spoilers"
+
+// Activity of deleted users, movies and comments stays in the table but is left out of the feed, as does the activity of comments that aren't visible
+const activityTable = `(SELECT a.id, a.type, COALESCE(a.grade, 0) AS grade, a.created_at,
+		a.user_id, u.name AS user_name, a.movie_id, m.title AS movie_title, a.comment_id, COALESCE(c.comment, '') AS comment,
+		COALESCE(c.contains_spoilers, false) AS contains_spoilers
+		FROM activity a
+			JOIN users u ON u.id = a.user_id
+			JOIN movies m ON m.id = a.movie_id
+			LEFT JOIN comments c ON c.id = a.comment_id
+				WHERE u.deleted_at IS NULL AND m.deleted_at IS NULL AND c.deleted_at IS NULL AND COALESCE(c.status, 'visible') = 'visible') AS activity`
+
+// Internal methods
+// insertActivity appends to the activity table, inside the transaction of the change it describes. A grade of 0 is stored as NULL.
+func insertActivity(tx *sql.Tx, activityType string, comment CommentResponse, grade float64) error {
+	query := `INSERT INTO activity
+			(type, grade, user_id, movie_id, comment_id)
+			VALUES ($1, NULLIF($2, 0), $3, $4, $5);`
+
+	if _, err := tx.Exec(query, activityType, grade, comment.UserId, comment.MovieId, comment.ID); err != nil {
+		log.Printf("Error inserting %s activity of comment %v: %v\n", activityType, comment.ID, err)
+		return err
+	}
+
+	return nil
+}
+
+// Public methods
+// GetFeed returns the activity of the users followed by the user, most recent first
+func (a *ActivityModel) GetFeed(db *sql.DB, userId uuid.UUID, page PageRequest, orderBy string) (Page[ActivityResponse], error) {
+	log.Printf("Getting feed of user %s in DB, with page %+v...\n", userId, page)
+
+	queryBuilder := NewSelect(activityColumns, activityTable).
+		Where("user_id IN (SELECT followed_id FROM follows WHERE follower_id = ?)", userId).
+		OrderBy(orderBy, activitySortColumns)
+
+	total, err := countRows(db, queryBuilder)
+	if err != nil {
+		log.Println("Error counting feed in db:", err)
+		return Page[ActivityResponse]{}, err
+	}
+
+	query, args, err := queryBuilder.Page(page).Build()
+	if err != nil {
+		log.Println("Error building feed query:", err)
+		return Page[ActivityResponse]{}, err
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		log.Println("Error getting feed from db:", err)
+		return Page[ActivityResponse]{}, err
+	}
+	defer rows.Close()
+
+	var feed []ActivityResponse
+	var keys []rowKey
+	for rows.Next() {
+		var activity ActivityResponse
+		var key rowKey
+		if err := rows.Scan(&activity.ID, &activity.Type, &activity.Grade, &activity.CreatedAt, &activity.UserId, &activity.UserName, &activity.MovieId, &activity.MovieTitle, &activity.CommentId, &activity.Comment, &activity.ContainsSpoilers, &key.value); err != nil {
+			log.Println("Error scanning activity from db:", err)
+			return Page[ActivityResponse]{}, err
+		}
+		key.id = activity.ID
+
+		feed = append(feed, activity)
+		keys = append(keys, key)
+	}
+
+	return newPage(feed, keys, page, orderBy, total), nil
+}
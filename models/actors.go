@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type ActorModel struct {
@@ -20,7 +21,8 @@ type ActorModel struct {
 	UpdatedAt time.Time    `json:"updatedAt"`
 	DeletedAt sql.NullTime `json:"deletedAt"`
 
-	CreatorId string `json:"creatorId"`
+	CreatorId string        `json:"creatorId"`
+	PersonId  uuid.NullUUID `json:"personId"`
 }
 
 type ActorBody struct {
@@ -30,6 +32,7 @@ type ActorBody struct {
 	Picture  string `json:"picture" validate:"omitempty"`
 
 	CreatorId string `json:"creatorId" validate:"required,isadminuuid"`
+	PersonId  string `json:"personId" validate:"omitempty,ispersonuuid"`
 }
 
 type ActorEditBody struct {
@@ -37,6 +40,7 @@ type ActorEditBody struct {
 	Surname  string `json:"surname" validate:"omitempty"`
 	Birthday string `json:"birthday" validate:"omitempty,datetime=2006-01-02"`
 	Picture  string `json:"picture" validate:"omitempty"`
+	PersonId string `json:"personId" validate:"omitempty,ispersonuuid"`
 }
 
 type ActorResponse struct {
@@ -49,7 +53,8 @@ type ActorResponse struct {
 	UpdatedAt time.Time    `json:"updatedAt"`
 	DeletedAt sql.NullTime `json:"deletedAt"`
 
-	CreatorId string `json:"creatorId"`
+	CreatorId string        `json:"creatorId"`
+	PersonId  uuid.NullUUID `json:"personId"`
 }
 
 type ActorResponseWithMovies struct {
@@ -63,20 +68,30 @@ type ActorResponseWithMovies struct {
 	DeletedAt sql.NullTime `json:"deletedAt"`
 
 	CreatorId string          `json:"creatorId"`
+	PersonId  uuid.NullUUID   `json:"personId"`
 	Movies    []MovieResponse `json:"movies"`
 }
 
+// ActorFilters narrows the actors list. Zero values leave the filter out of the query.
+type ActorFilters struct {
+	Name    string
+	MovieId uuid.UUID
+}
+
+// Columns the actors list can be sorted by
+var actorSortColumns = []string{"created_at", "updated_at", "name", "surname"}
+
 func (a *ActorModel) InsertActorInDB(db *sql.DB, actorInfo ActorBody) (ActorResponse, error) {
 	log.Printf("Inserting actor with name %s in DB by user %s...\n", actorInfo.Name, actorInfo.CreatorId)
 
 	query := `INSERT INTO actors
-			(name, surname, birthday, picture, creator_id)
-			VALUES ($1, $2, $3, $4, $5)
-				RETURNING id, name, surname, birthday, picture, created_at, updated_at, deleted_at, creator_id;`
+			(name, surname, birthday, picture, creator_id, person_id)
+			VALUES ($1, $2, $3, $4, $5, NULLIF($6, '')::uuid)
+				RETURNING id, name, surname, birthday, picture, created_at, updated_at, deleted_at, creator_id, person_id;`
 
 	var actor ActorResponse
 
-	if err := db.QueryRow(query, actorInfo.Name, actorInfo.Surname, actorInfo.Birthday, actorInfo.Picture, actorInfo.CreatorId).Scan(&actor.ID, &actor.Name, &actor.Surname, &actor.Birthday, &actor.Picture, &actor.CreatedAt, &actor.UpdatedAt, &actor.DeletedAt, &actor.CreatorId); err != nil {
+	if err := db.QueryRow(query, actorInfo.Name, actorInfo.Surname, actorInfo.Birthday, actorInfo.Picture, actorInfo.CreatorId, actorInfo.PersonId).Scan(&actor.ID, &actor.Name, &actor.Surname, &actor.Birthday, &actor.Picture, &actor.CreatedAt, &actor.UpdatedAt, &actor.DeletedAt, &actor.CreatorId, &actor.PersonId); err != nil {
 		log.Printf("Error inserting actor into database: %v\n", err)
 		return ActorResponse{}, err
 	}
@@ -84,51 +99,71 @@ func (a *ActorModel) InsertActorInDB(db *sql.DB, actorInfo ActorBody) (ActorResp
 	return actor, nil
 }
 
-func (a *ActorModel) GetAllActors(db *sql.DB, offset, limit int, orderBy string, deleted bool) ([]ActorResponse, error) {
-	log.Printf("Getting all actors in DB, with offset %v, limit %v, orderBy %v and deleted %v...\n", offset, limit, orderBy, deleted)
+func (a *ActorModel) GetAllActors(db *sql.DB, page PageRequest, orderBy string, deleted bool, filters ActorFilters) (Page[ActorResponse], error) {
+	log.Printf("Getting all actors in DB, with page %+v, orderBy %v, deleted %v and filters %+v...\n", page, orderBy, deleted, filters)
 
-	var getActorsQueryBuilder strings.Builder
-	getActorsQueryBuilder.WriteString(`SELECT 
-	id, name, surname, birthday, picture, created_at, updated_at, deleted_at, creator_id 
-	FROM actors`)
+	queryBuilder := NewSelect("id, name, surname, birthday, picture, created_at, updated_at, deleted_at, creator_id, person_id", "actors")
 
 	if !deleted {
-		getActorsQueryBuilder.WriteString(" WHERE deleted_at IS NULL")
+		queryBuilder.Where("deleted_at IS NULL")
 	}
 
-	getActorsQueryBuilder.WriteString(" ORDER BY " + orderBy + " OFFSET $1 LIMIT $2;")
+	if filters.Name != "" {
+		queryBuilder.Where("name || ' ' || surname ILIKE ?", containsPattern(filters.Name))
+	}
+
+	if filters.MovieId != uuid.Nil {
+		queryBuilder.Where("EXISTS (SELECT 1 FROM movies_actors ma WHERE ma.actor_id = actors.id AND ma.movie_id = ?)", filters.MovieId)
+	}
+
+	queryBuilder.OrderBy(orderBy, actorSortColumns)
+	total, err := countRows(db, queryBuilder)
+	if err != nil {
+		log.Println("Error counting all actors in db:", err)
+		return Page[ActorResponse]{}, err
+	}
+
+	query, args, err := queryBuilder.Page(page).Build()
+	if err != nil {
+		log.Println("Error building all actors query:", err)
+		return Page[ActorResponse]{}, err
+	}
 
-	query := getActorsQueryBuilder.String()
-	rows, err := db.Query(query, offset, limit)
+	rows, err := db.Query(query, args...)
 	if err != nil {
 		log.Println("Error getting all actors from db:", err)
-		return nil, err
+		return Page[ActorResponse]{}, err
 	}
 	defer rows.Close()
 
 	var actors []ActorResponse
+	var keys []rowKey
 	for rows.Next() {
 		var actor ActorResponse
-		if err := rows.Scan(&actor.ID, &actor.Name, &actor.Surname, &actor.Birthday, &actor.Picture, &actor.CreatedAt, &actor.UpdatedAt, &actor.DeletedAt, &actor.CreatorId); err != nil {
+		var key rowKey
+		if err := rows.Scan(&actor.ID, &actor.Name, &actor.Surname, &actor.Birthday, &actor.Picture, &actor.CreatedAt, &actor.UpdatedAt, &actor.DeletedAt, &actor.CreatorId, &actor.PersonId, &key.value); err != nil {
 			log.Println("Error scanning actor from db:", err)
-			return nil, err
+			return Page[ActorResponse]{}, err
 		}
+		key.id = actor.ID
+
 		actors = append(actors, actor)
+		keys = append(keys, key)
 	}
 
-	return actors, nil
+	return newPage(actors, keys, page, orderBy, total), nil
 }
 
 func (a *ActorModel) GetActorById(db *sql.DB, uuid uuid.UUID) (ActorResponse, error) {
 	log.Printf("Getting actor with uuid %s in DB... \n", uuid)
 
 	query := `SELECT 
-		id, name, surname, birthday, picture, created_at, updated_at, deleted_at, creator_id
+		id, name, surname, birthday, picture, created_at, updated_at, deleted_at, creator_id, person_id
         FROM actors
         	WHERE id = $1;`
 
 	var actor ActorResponse
-	if err := db.QueryRow(query, uuid).Scan(&actor.ID, &actor.Name, &actor.Surname, &actor.Birthday, &actor.Picture, &actor.CreatedAt, &actor.UpdatedAt, &actor.DeletedAt, &actor.CreatorId); err != nil {
+	if err := db.QueryRow(query, uuid).Scan(&actor.ID, &actor.Name, &actor.Surname, &actor.Birthday, &actor.Picture, &actor.CreatedAt, &actor.UpdatedAt, &actor.DeletedAt, &actor.CreatorId, &actor.PersonId); err != nil {
 		log.Printf("Error getting actor by id in the database: %v\n", err)
 		return ActorResponse{}, err
 	}
@@ -136,6 +171,21 @@ func (a *ActorModel) GetActorById(db *sql.DB, uuid uuid.UUID) (ActorResponse, er
 	return actor, nil
 }
 
+// CountActorsByIds counts how many of the ids belong to actors, checking a whole slice of ids with a single query
+func (a *ActorModel) CountActorsByIds(db *sql.DB, ids []uuid.UUID) (int, error) {
+	log.Printf("Counting %v actors by id in DB... \n", len(ids))
+
+	query := `SELECT COUNT(*) FROM actors WHERE id = ANY($1::uuid[]);`
+
+	var count int
+	if err := db.QueryRow(query, pq.Array(ids)).Scan(&count); err != nil {
+		log.Printf("Error counting actors by id in the database: %v\n", err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
 func (a *ActorModel) GetActorByIdWithMovies(db *sql.DB, uuid uuid.UUID) (ActorResponseWithMovies, error) {
 	log.Printf("Getting actor with uuid %s in DB with movies... \n", uuid)
 
@@ -149,11 +199,11 @@ func (a *ActorModel) GetActorByIdWithMovies(db *sql.DB, uuid uuid.UUID) (ActorRe
 	query := `SELECT 
 		a.id, a.name, a.surname, a.birthday, a.picture,
 		a.created_at, a.updated_at, a.deleted_at, 
-		a.creator_id, 
+		a.person_id, a.creator_id, 
 		m.id, m.title, m.director, m.release_date, 
 		m.average_grade, m.picture,
 		m.created_at, m.updated_at, m.deleted_at,
-		m.creator_id 
+		m.creator_id, m.rating_count, m.grade_histogram, m.weighted_rating
 			FROM actors a
 				LEFT JOIN movies_actors ma ON a.id = ma.actor_id
 				LEFT JOIN movies m ON ma.movie_id = m.id
@@ -170,7 +220,7 @@ func (a *ActorModel) GetActorByIdWithMovies(db *sql.DB, uuid uuid.UUID) (ActorRe
 	movies := make([]MovieResponse, 0)
 	for rows.Next() {
 		var movie MovieResponse
-		err := rows.Scan(&actor.ID, &actor.Name, &actor.Surname, &actor.Birthday, &actor.Picture, &actor.CreatedAt, &actor.UpdatedAt, &actor.DeletedAt, &actor.CreatorId, &movie.ID, &movie.Title, &movie.Director, &movie.ReleaseDate, &movie.AverageGrade, &movie.Picture, &movie.CreatedAt, &movie.UpdatedAt, &movie.DeletedAt, &movie.CreatorId)
+		err := rows.Scan(&actor.ID, &actor.Name, &actor.Surname, &actor.Birthday, &actor.Picture, &actor.CreatedAt, &actor.UpdatedAt, &actor.DeletedAt, &actor.PersonId, &actor.CreatorId, &movie.ID, &movie.Title, &movie.Director, &movie.ReleaseDate, &movie.AverageGrade, &movie.Picture, &movie.CreatedAt, &movie.UpdatedAt, &movie.DeletedAt, &movie.CreatorId, &movie.RatingCount, &movie.GradeHistogram, &movie.WeightedRating)
 		if err != nil {
 			log.Printf("Error scanning movie row in GetActorByIdWithMovies: %v\n", err)
 			continue
@@ -267,13 +317,19 @@ func (a *ActorModel) UpdateActorById(db *sql.DB, uuid uuid.UUID, body ActorEditB
 		argIndex++
 	}
 
+	if body.PersonId != "" {
+		updateQueryBuilder.WriteString("person_id = $" + strconv.Itoa(argIndex) + ", ")
+		args = append(args, body.PersonId)
+		argIndex++
+	}
+
 	updateQueryBuilder.WriteString("updated_at = CURRENT_TIMESTAMP, ")
 	query := strings.TrimSuffix(updateQueryBuilder.String(), ", ")
-	query += " WHERE id = $" + strconv.Itoa(argIndex) + " AND deleted_at IS NULL RETURNING id, name, surname, birthday, picture, created_at, updated_at, deleted_at, creator_id;"
+	query += " WHERE id = $" + strconv.Itoa(argIndex) + " AND deleted_at IS NULL RETURNING id, name, surname, birthday, picture, created_at, updated_at, deleted_at, creator_id, person_id;"
 	args = append(args, uuid)
 
 	var actor ActorResponse
-	if err := db.QueryRow(query, args...).Scan(&actor.ID, &actor.Name, &actor.Surname, &actor.Birthday, &actor.Picture, &actor.CreatedAt, &actor.UpdatedAt, &actor.DeletedAt, &actor.CreatorId); err != nil {
+	if err := db.QueryRow(query, args...).Scan(&actor.ID, &actor.Name, &actor.Surname, &actor.Birthday, &actor.Picture, &actor.CreatedAt, &actor.UpdatedAt, &actor.DeletedAt, &actor.CreatorId, &actor.PersonId); err != nil {
 		log.Printf("Error updating actor by uuid: %v \n", err)
 		return ActorResponse{}, err
 	}
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Actions of admins on users kept in the audit log
+const (
+	AuditPromote       = "promote"
+	AuditDemote        = "demote"
+	AuditBan           = "ban"
+	AuditUnban         = "unban"
+	AuditPasswordReset = "password_reset"
+)
+
+// AuditLogModel is an action of an admin on a user. Role is set for promotions and demotions,
+// Reason and ExpiresAt for bans and ExpiresAt for password resets. Actions run from the CLI have no ActorId.
+type AuditLogModel struct {
+	ID        uuid.UUID    `json:"id"`
+	Action    string       `json:"action"`
+	Role      string       `json:"role"`
+	Reason    string       `json:"reason"`
+	ExpiresAt sql.NullTime `json:"expiresAt"`
+	CreatedAt time.Time    `json:"createdAt"`
+
+	ActorId  uuid.NullUUID `json:"actorId"`
+	TargetId string        `json:"targetId"`
+}
+
+// AuditLogFilters narrows the audit log. Zero values leave the filter out of the query.
+type AuditLogFilters struct {
+	TargetId uuid.UUID
+	ActorId  uuid.UUID
+	Action   string
+}
+
+// Columns the audit log can be sorted by
+var auditLogSortColumns = []string{"created_at"}
+
+const auditLogColumns = "id, action, COALESCE(role, ''), COALESCE(reason, ''), expires_at, created_at, actor_id, target_id"
+
+// Internal methods
+// insertAuditLog appends to the audit log, inside the transaction of the action it describes. Empty role and reason are stored as NULL.
+func insertAuditLog(tx *sql.Tx, entry AuditLogModel) error {
+	query := `INSERT INTO admin_audit_log
+			(action, role, reason, expires_at, actor_id, target_id)
+			VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), $4, $5, $6);`
+
+	if _, err := tx.Exec(query, entry.Action, entry.Role, entry.Reason, entry.ExpiresAt, entry.ActorId, entry.TargetId); err != nil {
+		log.Printf("Error inserting %s audit log of user %v: %v\n", entry.Action, entry.TargetId, err)
+		return err
+	}
+
+	return nil
+}
+
+// Public methods
+func (a *AuditLogModel) GetAuditLog(db *sql.DB, page PageRequest, orderBy string, filters AuditLogFilters) (Page[AuditLogModel], error) {
+	log.Printf("Getting audit log in DB, with page %+v, orderBy %v and filters %+v...\n", page, orderBy, filters)
+
+	queryBuilder := NewSelect(auditLogColumns, "admin_audit_log")
+
+	if filters.TargetId != uuid.Nil {
+		queryBuilder.Where("target_id = ?", filters.TargetId)
+	}
+
+	if filters.ActorId != uuid.Nil {
+		queryBuilder.Where("actor_id = ?", filters.ActorId)
+	}
+
+	if filters.Action != "" {
+		queryBuilder.Where("action = ?", filters.Action)
+	}
+
+	queryBuilder.OrderBy(orderBy, auditLogSortColumns)
+	total, err := countRows(db, queryBuilder)
+	if err != nil {
+		log.Println("Error counting audit log in db:", err)
+		return Page[AuditLogModel]{}, err
+	}
+
+	query, args, err := queryBuilder.Page(page).Build()
+	if err != nil {
+		log.Println("Error building audit log query:", err)
+		return Page[AuditLogModel]{}, err
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		log.Println("Error getting audit log from db:", err)
+		return Page[AuditLogModel]{}, err
+	}
+	defer rows.Close()
+
+	var entries []AuditLogModel
+	var keys []rowKey
+	for rows.Next() {
+		var entry AuditLogModel
+		var key rowKey
+		if err := rows.Scan(&entry.ID, &entry.Action, &entry.Role, &entry.Reason, &entry.ExpiresAt, &entry.CreatedAt, &entry.ActorId, &entry.TargetId, &key.value); err != nil {
+			log.Println("Error scanning audit log from db:", err)
+			return Page[AuditLogModel]{}, err
+		}
+		key.id = entry.ID
+
+		entries = append(entries, entry)
+		keys = append(keys, key)
+	}
+
+	return newPage(entries, keys, page, orderBy, total), nil
+}
@@ -2,48 +2,145 @@ package models
 
 import (
 	"database/sql"
+	"errors"
 	"log"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type CommentModel struct {
-	ID        uuid.UUID    `json:"id"`
-	Comment   string       `json:"comment"`
-	Grade     float64      `json:"grade"`
-	CreatedAt time.Time    `json:"createdAt"`
-	UpdatedAt time.Time    `json:"updatedAt"`
-	DeletedAt sql.NullTime `json:"deletedAt"`
-
-	UserId  string `json:"userId"`
-	MovieId string `json:"movieId"`
+	ID      uuid.UUID `json:"id"`
+	Comment string    `json:"comment"`
+	Grade   float64   `json:"grade"`
+	Status  string    `json:"status"`
+
+	ContainsSpoilers bool         `json:"containsSpoilers"`
+	CreatedAt        time.Time    `json:"createdAt"`
+	UpdatedAt        time.Time    `json:"updatedAt"`
+	DeletedAt        sql.NullTime `json:"deletedAt"`
+
+	UserId   string        `json:"userId"`
+	MovieId  string        `json:"movieId"`
+	ParentId uuid.NullUUID `json:"parentId"`
 }
 
+// CommentBody with a ParentId is a reply to that comment, which can't have a grade
 type CommentBody struct {
 	Comment string  `json:"comment" validate:"required"`
 	Grade   float64 `json:"grade" validate:"omitempty,isvalidgrade"`
 
-	MovieId string `json:"movieId" validate:"required,isvaliduuid"`
+	MovieId  string `json:"movieId" validate:"required,isvaliduuid"`
+	ParentId string `json:"parentId" validate:"omitempty,isvaliduuid"`
+
+	// Flags the whole comment as a spoiler, parts of the text can also be marked inline as ||spoiler||
+	ContainsSpoilers bool `json:"containsSpoilers"`
+
+	// Set by the controller when the text hits the moderation blocklist, never read from the request
+	HeldForModeration bool `json:"-"`
+}
+
+// ReviewBody is the graded comment of a user on a movie, which can only exist once per user and movie
+type ReviewBody struct {
+	Comment string  `json:"comment" validate:"required"`
+	Grade   float64 `json:"grade" validate:"required,isvalidgrade"`
+
+	ContainsSpoilers bool `json:"containsSpoilers"`
+
+	HeldForModeration bool `json:"-"`
 }
 
 type CommentEditBody struct {
 	Comment string  `json:"comment" validate:"omitempty"`
-	Grade   float64 `json:"grade" validate:"omitempty"`
+	Grade   float64 `json:"grade" validate:"omitempty,isvalidgrade"`
+
+	ContainsSpoilers *bool `json:"containsSpoilers"`
+
+	HeldForModeration bool `json:"-"`
+	// Edits made by someone other than the author, like an admin, are kept out of the activity of the author
+	EditedByOther bool `json:"-"`
 }
 
+// CommentResponse has the replies of the comment only when comments are listed as threads.
+// SpoilersRedacted tells that spoilers were taken out of the comment text for the caller.
 type CommentResponse struct {
-	ID        uuid.UUID    `json:"id"`
-	Comment   string       `json:"comment"`
-	Grade     float64      `json:"grade"`
-	CreatedAt time.Time    `json:"createdAt"`
-	UpdatedAt time.Time    `json:"updatedAt"`
-	DeletedAt sql.NullTime `json:"deletedAt"`
-
-	UserId  string `json:"userId"`
-	MovieId string `json:"movieId"`
+	ID         uuid.UUID    `json:"id"`
+	Comment    string       `json:"comment"`
+	Grade      float64      `json:"grade"`
+	Depth      int          `json:"depth"`
+	LikeCount  int          `json:"likeCount"`
+	ReplyCount int          `json:"replyCount"`
+	Status     string       `json:"status"`
+	CreatedAt  time.Time    `json:"createdAt"`
+	UpdatedAt  time.Time    `json:"updatedAt"`
+	DeletedAt  sql.NullTime `json:"deletedAt"`
+
+	ContainsSpoilers bool `json:"containsSpoilers"`
+	SpoilersRedacted bool `json:"spoilersRedacted"`
+
+	UserId   string            `json:"userId"`
+	MovieId  string            `json:"movieId"`
+	ParentId uuid.NullUUID     `json:"parentId"`
+	Replies  []CommentResponse `json:"replies,omitempty"`
+}
+
+// CommentFilters narrows the comments list. Zero values leave the filter out of the query.
+type CommentFilters struct {
+	UserId   uuid.UUID
+	MovieId  uuid.UUID
+	MinGrade *float64
+	MaxGrade *float64
+	Status   string
+}
+
+// Columns the comments lists can be sorted by
+var commentSortColumns = []string{"created_at", "updated_at", "grade"}
+
+// Moderation status of a comment. Only visible comments are public and count in the ratings.
+const (
+	CommentStatusVisible = "visible"
+	CommentStatusPending = "pending"
+	CommentStatusHidden  = "hidden"
+)
+
+// Deepest level a reply can be at, comments on the movie itself are at depth 0
+const MaxCommentDepth = 3
+
+// Ungraded comments are stored with a NULL grade, so they stay out of the averages and of the one review per movie index.
+// They are read as a 0 grade, which keeps the responses the same and the grade sortable.
+const commentColumns = "id, comment, COALESCE(grade, 0) AS grade, depth, like_count, reply_count, status, contains_spoilers, created_at, updated_at, deleted_at, user_id, movie_id, parent_id"
+
+// The coalesced grade is a column of the subquery, so the filters and cursors read the same grade as the responses
+const commentsTable = "(SELECT " + commentColumns + " FROM comments) AS comments"
+
+// The top score ranks comments by likes, decaying with the hours since they were posted, so new liked comments can overtake old ones.
+// It changes with the time of the query, so a cursor taken from it wouldn't point to the same place on the next page. Only the
+// comments of a movie, which aren't paginated, can be sorted by it, and it is only computed for that sort.
+const commentTopScore = "(like_count + 1) / POWER(EXTRACT(EPOCH FROM NOW() - created_at) / 3600 + 2, 1.5)"
+const commentsWithTopScoreTable = "(SELECT " + commentColumns + ", " + commentTopScore + " AS top_score FROM comments) AS comments"
+
+var commentTopScoreSortColumns = []string{"top_score"}
+
+// movieCommentsFrom returns the table and sort columns of the comments of a movie, adding the top score only when sorting by it
+func movieCommentsFrom(orderBy string) (string, []string) {
+	if strings.HasPrefix(orderBy, "top_score ") {
+		return commentsWithTopScoreTable, commentTopScoreSortColumns
+	}
+
+	return commentsTable, commentSortColumns
+}
+
+// Unique index that keeps a single active review per user and movie, see migration 0007
+const oneReviewPerMovieIndex = "comments_one_review_per_user_movie_idx"
+
+// IsDuplicateReview tells if err came from writing a second review of a user on the same movie, which the controllers
+// check beforehand but concurrent requests can still get past
+func IsDuplicateReview(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation" && pqErr.Constraint == oneReviewPerMovieIndex
 }
 
 var userModel UserModel
@@ -53,65 +150,118 @@ var movieModel MovieModel
 func (c *CommentModel) InsertCommentInDB(db *sql.DB, uuid uuid.UUID, commentInfo CommentBody) (CommentResponse, error) {
 	log.Printf("Inserting comment in DB by user %s...\n", uuid)
 
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction to insert comment: %v\n", err)
+		return CommentResponse{}, err
+	}
+	defer tx.Rollback()
+
+	// Replies are one level deeper than their parent, and comments held by the moderation blocklist wait as pending
 	query := `INSERT INTO comments
-			(comment, grade, user_id, movie_id)
-			VALUES ($1, $2, $3, $4)
-				RETURNING id, comment, grade, created_at, updated_at, deleted_at, user_id, movie_id;`
+			(comment, grade, user_id, movie_id, parent_id, depth, status, contains_spoilers)
+			VALUES ($1, NULLIF($2, 0), $3, $4, NULLIF($5, '')::uuid, COALESCE((SELECT depth + 1 FROM comments WHERE id = NULLIF($5, '')::uuid), 0), CASE WHEN $6 THEN 'pending' ELSE 'visible' END, $7)
+				RETURNING ` + commentColumns + `;`
 
 	var comment CommentResponse
-	if err := db.QueryRow(query, commentInfo.Comment, commentInfo.Grade, uuid, commentInfo.MovieId).Scan(&comment.ID, &comment.Comment, &comment.Grade, &comment.CreatedAt, &comment.UpdatedAt, &comment.DeletedAt, &comment.UserId, &comment.MovieId); err != nil {
+	if err := tx.QueryRow(query, commentInfo.Comment, commentInfo.Grade, uuid, commentInfo.MovieId, commentInfo.ParentId, commentInfo.HeldForModeration, commentInfo.ContainsSpoilers).Scan(&comment.ID, &comment.Comment, &comment.Grade, &comment.Depth, &comment.LikeCount, &comment.ReplyCount, &comment.Status, &comment.ContainsSpoilers, &comment.CreatedAt, &comment.UpdatedAt, &comment.DeletedAt, &comment.UserId, &comment.MovieId, &comment.ParentId); err != nil {
 		log.Printf("Error inserting comment into database: %v\n", err)
 		return CommentResponse{}, err
 	}
 
+	activityType := ActivityComment
+	if comment.Grade != 0 {
+		activityType = ActivityReview
+	}
+
+	if err := insertActivity(tx, activityType, comment, comment.Grade); err != nil {
+		return CommentResponse{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing transaction while inserting comment: %v\n", err)
+		return CommentResponse{}, err
+	}
+
 	return comment, nil
 }
 
-func (c *CommentModel) GetAllComments(db *sql.DB, offset, limit int, orderBy string, deleted bool) ([]CommentResponse, error) {
-	log.Printf("Getting all comments in DB, with offset %v, limit %v, orderBy %v and deleted %v...\n", offset, limit, orderBy, deleted)
+func (c *CommentModel) GetAllComments(db *sql.DB, page PageRequest, orderBy string, deleted bool, filters CommentFilters) (Page[CommentResponse], error) {
+	log.Printf("Getting all comments in DB, with page %+v, orderBy %v, deleted %v and filters %+v...\n", page, orderBy, deleted, filters)
 
-	var getCommentsQueryBuilder strings.Builder
-	getCommentsQueryBuilder.WriteString(`SELECT 
-	id, comment, grade, created_at, updated_at, deleted_at, user_id, movie_id 
-	FROM comments`)
+	queryBuilder := NewSelect(commentColumns, commentsTable)
 
 	if !deleted {
-		getCommentsQueryBuilder.WriteString(" WHERE deleted_at IS NULL")
+		queryBuilder.Where("deleted_at IS NULL")
+	}
+
+	if filters.UserId != uuid.Nil {
+		queryBuilder.Where("user_id = ?", filters.UserId)
+	}
+
+	if filters.MovieId != uuid.Nil {
+		queryBuilder.Where("movie_id = ?", filters.MovieId)
+	}
+
+	if filters.Status != "" {
+		queryBuilder.Where("status = ?", filters.Status)
+	}
+
+	if filters.MinGrade != nil {
+		queryBuilder.Where("grade >= ?", *filters.MinGrade)
+	}
+
+	if filters.MaxGrade != nil {
+		queryBuilder.Where("grade <= ?", *filters.MaxGrade)
 	}
 
-	getCommentsQueryBuilder.WriteString(" ORDER BY " + orderBy + " OFFSET $1 LIMIT $2;")
+	queryBuilder.OrderBy(orderBy, commentSortColumns)
+	total, err := countRows(db, queryBuilder)
+	if err != nil {
+		log.Println("Error counting all comments in db:", err)
+		return Page[CommentResponse]{}, err
+	}
 
-	query := getCommentsQueryBuilder.String()
-	rows, err := db.Query(query, offset, limit)
+	query, args, err := queryBuilder.Page(page).Build()
+	if err != nil {
+		log.Println("Error building all comments query:", err)
+		return Page[CommentResponse]{}, err
+	}
+
+	rows, err := db.Query(query, args...)
 	if err != nil {
 		log.Println("Error getting all comments from db:", err)
-		return nil, err
+		return Page[CommentResponse]{}, err
 	}
 	defer rows.Close()
 
 	var comments []CommentResponse
+	var keys []rowKey
 	for rows.Next() {
 		var comment CommentResponse
-		if err := rows.Scan(&comment.ID, &comment.Comment, &comment.Grade, &comment.CreatedAt, &comment.UpdatedAt, &comment.DeletedAt, &comment.UserId, &comment.MovieId); err != nil {
+		var key rowKey
+		if err := rows.Scan(&comment.ID, &comment.Comment, &comment.Grade, &comment.Depth, &comment.LikeCount, &comment.ReplyCount, &comment.Status, &comment.ContainsSpoilers, &comment.CreatedAt, &comment.UpdatedAt, &comment.DeletedAt, &comment.UserId, &comment.MovieId, &comment.ParentId, &key.value); err != nil {
 			log.Println("Error scanning comment from db:", err)
-			return nil, err
+			return Page[CommentResponse]{}, err
 		}
+		key.id = comment.ID
+
 		comments = append(comments, comment)
+		keys = append(keys, key)
 	}
 
-	return comments, nil
+	return newPage(comments, keys, page, orderBy, total), nil
 }
 
 func (c *CommentModel) GetCommentById(db *sql.DB, uuid uuid.UUID) (CommentResponse, error) {
 	log.Printf("Getting comment with uuid %s in DB... \n", uuid)
 
-	query := `SELECT 
-		id, comment, grade, created_at, updated_at, deleted_at, user_id, movie_id 
+	query := `SELECT ` + commentColumns + `
         FROM comments
         	WHERE id = $1;`
 
 	var comment CommentResponse
-	if err := db.QueryRow(query, uuid).Scan(&comment.ID, &comment.Comment, &comment.Grade, &comment.CreatedAt, &comment.UpdatedAt, &comment.DeletedAt, &comment.UserId, &comment.MovieId); err != nil {
+	if err := db.QueryRow(query, uuid).Scan(&comment.ID, &comment.Comment, &comment.Grade, &comment.Depth, &comment.LikeCount, &comment.ReplyCount, &comment.Status, &comment.ContainsSpoilers, &comment.CreatedAt, &comment.UpdatedAt, &comment.DeletedAt, &comment.UserId, &comment.MovieId, &comment.ParentId); err != nil {
 		log.Printf("Error getting comment by id in the database: %v\n", err)
 		return CommentResponse{}, err
 	}
@@ -119,6 +269,69 @@ func (c *CommentModel) GetCommentById(db *sql.DB, uuid uuid.UUID) (CommentRespon
 	return comment, nil
 }
 
+// GetUserReviewOfMovie returns the active graded comment of the user on the movie, or sql.ErrNoRows if there is none
+func (c *CommentModel) GetUserReviewOfMovie(db *sql.DB, userId uuid.UUID, movieId uuid.UUID) (CommentResponse, error) {
+	log.Printf("Getting review of user %s on movie %s in DB... \n", userId, movieId)
+
+	query := `SELECT ` + commentColumns + `
+		FROM comments
+			WHERE user_id = $1 AND movie_id = $2 AND grade IS NOT NULL AND deleted_at IS NULL;`
+
+	var comment CommentResponse
+	if err := db.QueryRow(query, userId, movieId).Scan(&comment.ID, &comment.Comment, &comment.Grade, &comment.Depth, &comment.LikeCount, &comment.ReplyCount, &comment.Status, &comment.ContainsSpoilers, &comment.CreatedAt, &comment.UpdatedAt, &comment.DeletedAt, &comment.UserId, &comment.MovieId, &comment.ParentId); err != nil {
+		log.Printf("Error getting review of user on movie in the database: %v\n", err)
+		return CommentResponse{}, err
+	}
+
+	return comment, nil
+}
+
+// UpsertReviewInDB creates the review of the user on the movie, or replaces the one they already have.
+// The returned bool is true when the review was created.
+func (c *CommentModel) UpsertReviewInDB(db *sql.DB, userId uuid.UUID, movieId uuid.UUID, body ReviewBody) (CommentResponse, bool, error) {
+	log.Printf("Upserting review of user %s on movie %s in DB... \n", userId, movieId)
+
+	// xmax is only set on rows that already existed, so it tells inserts and updates apart
+	query := `INSERT INTO comments
+			(comment, grade, user_id, movie_id, status, contains_spoilers)
+			VALUES ($1, $2, $3, $4, CASE WHEN $5 THEN 'pending' ELSE 'visible' END, $6)
+			ON CONFLICT (user_id, movie_id) WHERE grade IS NOT NULL AND deleted_at IS NULL
+			DO UPDATE SET comment = EXCLUDED.comment, grade = EXCLUDED.grade, contains_spoilers = EXCLUDED.contains_spoilers, updated_at = CURRENT_TIMESTAMP,
+				status = CASE WHEN comments.status = 'visible' THEN EXCLUDED.status ELSE comments.status END
+				RETURNING ` + commentColumns + `, xmax = 0;`
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction to upsert review: %v\n", err)
+		return CommentResponse{}, false, err
+	}
+	defer tx.Rollback()
+
+	var comment CommentResponse
+	var created bool
+	if err := tx.QueryRow(query, body.Comment, body.Grade, userId, movieId, body.HeldForModeration, body.ContainsSpoilers).Scan(&comment.ID, &comment.Comment, &comment.Grade, &comment.Depth, &comment.LikeCount, &comment.ReplyCount, &comment.Status, &comment.ContainsSpoilers, &comment.CreatedAt, &comment.UpdatedAt, &comment.DeletedAt, &comment.UserId, &comment.MovieId, &comment.ParentId, &created); err != nil {
+		log.Printf("Error upserting review into database: %v\n", err)
+		return CommentResponse{}, false, err
+	}
+
+	// Replacing a review shows up in the feed as a new grade
+	activityType := ActivityGrade
+	if created {
+		activityType = ActivityReview
+	}
+
+	if err := insertActivity(tx, activityType, comment, comment.Grade); err != nil {
+		return CommentResponse{}, false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing transaction while upserting review: %v\n", err)
+		return CommentResponse{}, false, err
+	}
+
+	return comment, created, nil
+}
+
 func (c *CommentModel) DeleteCommentById(db *sql.DB, uuid uuid.UUID) error {
 	log.Printf("Deleting comment with uuid %s in DB... \n", uuid)
 
@@ -156,17 +369,52 @@ func (c *CommentModel) UpdateCommentsById(db *sql.DB, uuid uuid.UUID, body Comme
 		argIndex++
 	}
 
+	if body.ContainsSpoilers != nil {
+		updateQueryBuilder.WriteString("contains_spoilers = $" + strconv.Itoa(argIndex) + ", ")
+		args = append(args, *body.ContainsSpoilers)
+		argIndex++
+	}
+
+	// Held edits send visible comments back to the queue, and leave the ones an admin already hid as they are
+	if body.HeldForModeration {
+		updateQueryBuilder.WriteString("status = CASE WHEN status = 'visible' THEN 'pending' ELSE status END, ")
+	}
+
 	updateQueryBuilder.WriteString("updated_at = CURRENT_TIMESTAMP, ")
 	query := strings.TrimSuffix(updateQueryBuilder.String(), ", ")
-	query += " WHERE id = $" + strconv.Itoa(argIndex) + " AND deleted_at IS NULL RETURNING id, comment, grade, created_at, updated_at, deleted_at, user_id, movie_id;"
+	query += " WHERE id = $" + strconv.Itoa(argIndex) + " AND deleted_at IS NULL RETURNING " + commentColumns + ";"
 	args = append(args, uuid)
 
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction to update comment: %v\n", err)
+		return CommentResponse{}, err
+	}
+	defer tx.Rollback()
+
 	var comment CommentResponse
-	if err := db.QueryRow(query, args...).Scan(&comment.ID, &comment.Comment, &comment.Grade, &comment.CreatedAt, &comment.UpdatedAt, &comment.DeletedAt, &comment.UserId, &comment.MovieId); err != nil {
+	if err := tx.QueryRow(query, args...).Scan(&comment.ID, &comment.Comment, &comment.Grade, &comment.Depth, &comment.LikeCount, &comment.ReplyCount, &comment.Status, &comment.ContainsSpoilers, &comment.CreatedAt, &comment.UpdatedAt, &comment.DeletedAt, &comment.UserId, &comment.MovieId, &comment.ParentId); err != nil {
 		log.Printf("Error updating comment by uuid: %v \n", err)
 		return CommentResponse{}, err
 	}
 
+	if body.Comment != "" && !body.EditedByOther {
+		if err := insertActivity(tx, ActivityCommentEdit, comment, 0); err != nil {
+			return CommentResponse{}, err
+		}
+	}
+
+	if body.Grade != 0.0 && !body.EditedByOther {
+		if err := insertActivity(tx, ActivityGrade, comment, comment.Grade); err != nil {
+			return CommentResponse{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing transaction while updating comment: %v\n", err)
+		return CommentResponse{}, err
+	}
+
 	return comment, nil
 }
 
@@ -177,20 +425,20 @@ func (c *CommentModel) GetAllUserCommentsInDb(db *sql.DB, uuid uuid.UUID, orderB
 		return UserResponseWithComments{}, err
 	}
 
-	var getCommentsQueryBuilder strings.Builder
-	getCommentsQueryBuilder.WriteString(`SELECT 
-		id, comment, grade, created_at, updated_at, deleted_at, user_id, movie_id
-		FROM comments
-			WHERE user_id = $1`)
+	queryBuilder := NewSelect(commentColumns, commentsTable).
+		Where("user_id = ?", uuid)
 
 	if !deleted {
-		getCommentsQueryBuilder.WriteString(" AND deleted_at IS NULL")
+		queryBuilder.Where("deleted_at IS NULL")
 	}
 
-	getCommentsQueryBuilder.WriteString(" ORDER BY " + orderBy + ";")
+	query, args, err := queryBuilder.OrderBy(orderBy, commentSortColumns).Build()
+	if err != nil {
+		log.Printf("Error building comments query of user %v: %v \n", uuid, err)
+		return UserResponseWithComments{}, err
+	}
 
-	query := getCommentsQueryBuilder.String()
-	rows, err := db.Query(query, uuid)
+	rows, err := db.Query(query, args...)
 	if err != nil {
 		log.Printf("Error getting all comments of user %v from db: %v \n", uuid, err)
 		return UserResponseWithComments{}, err
@@ -203,7 +451,7 @@ func (c *CommentModel) GetAllUserCommentsInDb(db *sql.DB, uuid uuid.UUID, orderB
 	}
 	for rows.Next() {
 		var comment CommentResponse
-		if err := rows.Scan(&comment.ID, &comment.Comment, &comment.Grade, &comment.CreatedAt, &comment.UpdatedAt, &comment.DeletedAt, &comment.UserId, &comment.MovieId); err != nil {
+		if err := rows.Scan(&comment.ID, &comment.Comment, &comment.Grade, &comment.Depth, &comment.LikeCount, &comment.ReplyCount, &comment.Status, &comment.ContainsSpoilers, &comment.CreatedAt, &comment.UpdatedAt, &comment.DeletedAt, &comment.UserId, &comment.MovieId, &comment.ParentId); err != nil {
 			log.Printf("Error scanning rows while getting all comments of user %v from db: %v \n", uuid, err)
 			return UserResponseWithComments{}, err
 		}
@@ -220,20 +468,23 @@ func (c *CommentModel) GetAllCommentsInAMovieInDb(db *sql.DB, uuid uuid.UUID, or
 		return MovieResponseWithActorsWithComments{}, err
 	}
 
-	var getCommentsQueryBuilder strings.Builder
-	getCommentsQueryBuilder.WriteString(`SELECT 
-		id, comment, grade, created_at, updated_at, deleted_at, user_id, movie_id
-		FROM comments
-			WHERE movie_id = $1`)
+	// Comments on a movie are public, so the ones waiting for or removed by moderation are left out
+	table, sortColumns := movieCommentsFrom(orderBy)
+	queryBuilder := NewSelect(commentColumns, table).
+		Where("movie_id = ?", uuid).
+		Where("status = ?", CommentStatusVisible)
 
 	if !deleted {
-		getCommentsQueryBuilder.WriteString(" AND deleted_at IS NULL")
+		queryBuilder.Where("deleted_at IS NULL")
 	}
 
-	getCommentsQueryBuilder.WriteString(" ORDER BY " + orderBy + ";")
+	query, args, err := queryBuilder.OrderBy(orderBy, sortColumns).Build()
+	if err != nil {
+		log.Printf("Error building comments query of movie %v: %v \n", uuid, err)
+		return MovieResponseWithActorsWithComments{}, err
+	}
 
-	query := getCommentsQueryBuilder.String()
-	rows, err := db.Query(query, uuid)
+	rows, err := db.Query(query, args...)
 	if err != nil {
 		log.Printf("Error getting all comments of user %v from db: %v \n", uuid, err)
 		return MovieResponseWithActorsWithComments{}, err
@@ -246,7 +497,7 @@ func (c *CommentModel) GetAllCommentsInAMovieInDb(db *sql.DB, uuid uuid.UUID, or
 	}
 	for rows.Next() {
 		var comment CommentResponse
-		if err := rows.Scan(&comment.ID, &comment.Comment, &comment.Grade, &comment.CreatedAt, &comment.UpdatedAt, &comment.DeletedAt, &comment.UserId, &comment.MovieId); err != nil {
+		if err := rows.Scan(&comment.ID, &comment.Comment, &comment.Grade, &comment.Depth, &comment.LikeCount, &comment.ReplyCount, &comment.Status, &comment.ContainsSpoilers, &comment.CreatedAt, &comment.UpdatedAt, &comment.DeletedAt, &comment.UserId, &comment.MovieId, &comment.ParentId); err != nil {
 			log.Printf("Error scanning rows while getting all comments of user %v from db: %v \n", uuid, err)
 			return MovieResponseWithActorsWithComments{}, err
 		}
@@ -255,3 +506,83 @@ func (c *CommentModel) GetAllCommentsInAMovieInDb(db *sql.DB, uuid uuid.UUID, or
 
 	return movieWithComments, nil
 }
+
+// SetCommentSpoilers flags or unflags the comment as a spoiler, without counting as an edit of the comment
+func (c *CommentModel) SetCommentSpoilers(db *sql.DB, uuid uuid.UUID, containsSpoilers bool) (CommentResponse, error) {
+	log.Printf("Setting spoiler flag of comment %s to %v in DB...\n", uuid, containsSpoilers)
+
+	query := `UPDATE comments SET contains_spoilers = $1 WHERE id = $2 AND deleted_at IS NULL RETURNING ` + commentColumns + `;`
+
+	var comment CommentResponse
+	if err := db.QueryRow(query, containsSpoilers, uuid).Scan(&comment.ID, &comment.Comment, &comment.Grade, &comment.Depth, &comment.LikeCount, &comment.ReplyCount, &comment.Status, &comment.ContainsSpoilers, &comment.CreatedAt, &comment.UpdatedAt, &comment.DeletedAt, &comment.UserId, &comment.MovieId, &comment.ParentId); err != nil {
+		log.Printf("Error setting spoiler flag of comment: %v\n", err)
+		return CommentResponse{}, err
+	}
+
+	return comment, nil
+}
+
+func (c *CommentModel) InsertCommentLike(db *sql.DB, userId uuid.UUID, commentId uuid.UUID) error {
+	log.Printf("Making user %s like comment %s in DB...\n", userId, commentId)
+
+	query := `INSERT INTO comment_likes (user_id, comment_id) VALUES ($1, $2) ON CONFLICT DO NOTHING;`
+	if _, err := db.Exec(query, userId, commentId); err != nil {
+		log.Printf("Error inserting comment like into database: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
+func (c *CommentModel) DeleteCommentLike(db *sql.DB, userId uuid.UUID, commentId uuid.UUID) error {
+	log.Printf("Making user %s unlike comment %s in DB...\n", userId, commentId)
+
+	query := `DELETE FROM comment_likes WHERE user_id = $1 AND comment_id = $2;`
+	if _, err := db.Exec(query, userId, commentId); err != nil {
+		log.Printf("Error deleting comment like: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
+// IsCommentLiked tells if the user likes the comment
+func (c *CommentModel) IsCommentLiked(db *sql.DB, userId uuid.UUID, commentId uuid.UUID) (bool, error) {
+	var found bool
+	query := `SELECT EXISTS (SELECT 1 FROM comment_likes WHERE user_id = $1 AND comment_id = $2);`
+	if err := db.QueryRow(query, userId, commentId).Scan(&found); err != nil {
+		log.Printf("Error checking if user %v likes comment %v: %v\n", userId, commentId, err)
+		return false, err
+	}
+
+	return found, nil
+}
+
+// NestComments turns a flat list of comments into threads, with each reply in the Replies of its parent.
+// Comments keep the order they had in the list, and replies whose parent isn't in the list are dropped.
+func NestComments(comments []CommentResponse) []CommentResponse {
+	children := map[uuid.UUID][]CommentResponse{}
+	for _, comment := range comments {
+		if comment.ParentId.Valid {
+			children[comment.ParentId.UUID] = append(children[comment.ParentId.UUID], comment)
+		}
+	}
+
+	var attachReplies func(comment CommentResponse) CommentResponse
+	attachReplies = func(comment CommentResponse) CommentResponse {
+		for _, reply := range children[comment.ID] {
+			comment.Replies = append(comment.Replies, attachReplies(reply))
+		}
+		return comment
+	}
+
+	threads := []CommentResponse{}
+	for _, comment := range comments {
+		if comment.ParentId.Valid {
+			continue
+		}
+		threads = append(threads, attachReplies(comment))
+	}
+
+	return threads
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_IsDuplicateReview(t *testing.T) {
+	duplicateReview := &pq.Error{Code: "23505", Constraint: oneReviewPerMovieIndex}
+
+	assert.True(t, IsDuplicateReview(duplicateReview), "Unique violation of the review index")
+	assert.True(t, IsDuplicateReview(fmt.Errorf("inserting comment: %w", duplicateReview)), "Wrapped unique violation of the review index")
+	assert.False(t, IsDuplicateReview(&pq.Error{Code: "23505", Constraint: "users_email_key"}), "Unique violation of another index")
+	assert.False(t, IsDuplicateReview(&pq.Error{Code: "23503", Constraint: oneReviewPerMovieIndex}), "Other error code")
+	assert.False(t, IsDuplicateReview(errors.New("connection refused")), "Error that isn't from postgres")
+}
+
+func Test_movieCommentsFrom(t *testing.T) {
+	table, sortColumns := movieCommentsFrom("top_score DESC")
+	assert.Equal(t, commentsWithTopScoreTable, table, "Top sort should compute the score")
+	assert.Equal(t, []string{"top_score"}, sortColumns, "Top sort should only allow the score")
+
+	table, sortColumns = movieCommentsFrom("created_at DESC")
+	assert.Equal(t, commentsTable, table, "Other sorts shouldn't compute the score")
+	assert.Equal(t, commentSortColumns, sortColumns, "Other sorts should allow the comment columns")
+
+	assert.NotContains(t, commentSortColumns, "top_score", "Paginated lists can't be sorted by the top score")
+	assert.NotContains(t, commentsTable, "NOW()", "Paginated lists shouldn't depend on the time of the query")
+}
+
+func Test_NestComments(t *testing.T) {
+	newComment := func(parent *CommentResponse) CommentResponse {
+		comment := CommentResponse{ID: uuid.New()}
+		if parent != nil {
+			comment.ParentId = uuid.NullUUID{UUID: parent.ID, Valid: true}
+			comment.Depth = parent.Depth + 1
+		}
+		return comment
+	}
+
+	first := newComment(nil)
+	second := newComment(nil)
+	firstReply := newComment(&first)
+	nestedReply := newComment(&firstReply)
+	secondReply := newComment(&first)
+	orphan := newComment(&CommentResponse{ID: uuid.New()})
+
+	// Replies can come before their parents in the list, as they do when sorting by likes
+	threads := NestComments([]CommentResponse{nestedReply, second, firstReply, orphan, first, secondReply})
+
+	if !assert.Len(t, threads, 2, "Only top level comments should be threads") {
+		return
+	}
+	assert.Equal(t, second.ID, threads[0].ID, "Threads should keep the order of the list")
+	assert.Empty(t, threads[0].Replies, "Comment without replies should not have replies")
+
+	assert.Equal(t, first.ID, threads[1].ID, "Threads should keep the order of the list")
+	if !assert.Len(t, threads[1].Replies, 2, "Replies of the first comment mismatch") {
+		return
+	}
+	assert.Equal(t, firstReply.ID, threads[1].Replies[0].ID, "Replies should keep the order of the list")
+	assert.Equal(t, secondReply.ID, threads[1].Replies[1].ID, "Replies should keep the order of the list")
+
+	if !assert.Len(t, threads[1].Replies[0].Replies, 1, "Nested replies mismatch") {
+		return
+	}
+	assert.Equal(t, nestedReply.ID, threads[1].Replies[0].Replies[0].ID, "Nested reply mismatch")
+
+	assert.Equal(t, []CommentResponse{}, NestComments(nil), "Empty list should have no threads")
+}
@@ -0,0 +1,294 @@
+package models
+
+import (
+	"database/sql"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DiaryModel struct {
+	ID        uuid.UUID    `json:"id"`
+	WatchedOn string       `json:"watchedOn"`
+	Rewatch   bool         `json:"rewatch"`
+	Notes     string       `json:"notes"`
+	CreatedAt time.Time    `json:"createdAt"`
+	UpdatedAt time.Time    `json:"updatedAt"`
+	DeletedAt sql.NullTime `json:"deletedAt"`
+
+	UserId   string        `json:"userId"`
+	MovieId  string        `json:"movieId"`
+	ReviewId uuid.NullUUID `json:"reviewId"`
+}
+
+type DiaryBody struct {
+	MovieId   string `json:"movieId" validate:"required,isvaliduuid"`
+	WatchedOn string `json:"watchedOn" validate:"required,datetime=2006-01-02"`
+	Rewatch   bool   `json:"rewatch"`
+	Notes     string `json:"notes" validate:"omitempty,max=1000"`
+	ReviewId  string `json:"reviewId" validate:"omitempty,isvaliduuid"`
+}
+
+// DiaryEditBody leaves out the movie of the entry, Rewatch is a pointer so it can be set back to false
+type DiaryEditBody struct {
+	WatchedOn string `json:"watchedOn" validate:"omitempty,datetime=2006-01-02"`
+	Rewatch   *bool  `json:"rewatch" validate:"omitempty"`
+	Notes     string `json:"notes" validate:"omitempty,max=1000"`
+	ReviewId  string `json:"reviewId" validate:"omitempty,isvaliduuid"`
+}
+
+// DiaryEntryResponse has the watched on date in the YYYY-MM-DD format
+type DiaryEntryResponse struct {
+	ID        uuid.UUID    `json:"id"`
+	WatchedOn string       `json:"watchedOn"`
+	Rewatch   bool         `json:"rewatch"`
+	Notes     string       `json:"notes"`
+	CreatedAt time.Time    `json:"createdAt"`
+	UpdatedAt time.Time    `json:"updatedAt"`
+	DeletedAt sql.NullTime `json:"deletedAt"`
+
+	UserId   string        `json:"userId"`
+	ReviewId uuid.NullUUID `json:"reviewId"`
+	Movie    MovieResponse `json:"movie"`
+}
+
+// DiaryStatsResponse summarizes the diary of a user in a year.
+// FilmsPerMonth goes from January to December, and AverageGrade is the average of the grades
+// the user gave in their reviews of the movies watched in the year, 0 when there are none.
+type DiaryStatsResponse struct {
+	Year          int     `json:"year"`
+	FilmsWatched  int     `json:"filmsWatched"`
+	UniqueFilms   int     `json:"uniqueFilms"`
+	Rewatches     int     `json:"rewatches"`
+	AverageGrade  float64 `json:"averageGrade"`
+	FilmsPerMonth [12]int `json:"filmsPerMonth"`
+}
+
+// Columns the diary can be sorted by
+var diarySortColumns = []string{"watched_on", "created_at", "title"}
+
+const diaryColumns = `id, TO_CHAR(watched_on, 'YYYY-MM-DD'), rewatch, notes, created_at, updated_at, deleted_at, user_id, review_id,
+	movie_id, title, director, release_date, average_grade, picture, synopsis, movie_created_at, movie_updated_at, movie_deleted_at, creator_id, rating_count, grade_histogram, weighted_rating`
+
+// The entries are joined with their movies in a subquery, so they can be sorted and paginated by the columns of both
+const diaryTable = `(SELECT d.id, d.watched_on, d.rewatch, d.notes, d.created_at, d.updated_at, d.deleted_at, d.user_id, d.review_id,
+		m.id AS movie_id, m.title, m.director, m.release_date, m.average_grade, m.picture, m.synopsis, m.created_at AS movie_created_at,
+		m.updated_at AS movie_updated_at, m.deleted_at AS movie_deleted_at, m.creator_id, m.rating_count, m.grade_histogram, m.weighted_rating
+		FROM diary d
+			JOIN movies m ON m.id = d.movie_id) AS diary`
+
+func scanDiaryEntry(row rowScanner, extra ...interface{}) (DiaryEntryResponse, error) {
+	var entry DiaryEntryResponse
+	movie := &entry.Movie
+	dest := []interface{}{&entry.ID, &entry.WatchedOn, &entry.Rewatch, &entry.Notes, &entry.CreatedAt, &entry.UpdatedAt, &entry.DeletedAt, &entry.UserId, &entry.ReviewId, &movie.ID, &movie.Title, &movie.Director, &movie.ReleaseDate, &movie.AverageGrade, &movie.Picture, &movie.Synopsis, &movie.CreatedAt, &movie.UpdatedAt, &movie.DeletedAt, &movie.CreatorId, &movie.RatingCount, &movie.GradeHistogram, &movie.WeightedRating}
+	err := row.Scan(append(dest, extra...)...)
+
+	return entry, err
+}
+
+// Public methods
+// InsertDiaryEntryInDB logs the movie as watched and takes it out of the watchlist of the user, in the same transaction
+func (d *DiaryModel) InsertDiaryEntryInDB(db *sql.DB, userId uuid.UUID, body DiaryBody) (DiaryEntryResponse, error) {
+	log.Printf("Inserting diary entry of movie %s for user %s in DB...\n", body.MovieId, userId)
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error beginning transaction: %v\n", err)
+		return DiaryEntryResponse{}, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO diary
+			(watched_on, rewatch, notes, user_id, movie_id, review_id)
+			VALUES ($1, $2, $3, $4, $5, NULLIF($6, '')::uuid)
+				RETURNING id;`
+
+	var entryId uuid.UUID
+	if err := tx.QueryRow(query, body.WatchedOn, body.Rewatch, body.Notes, userId, body.MovieId, body.ReviewId).Scan(&entryId); err != nil {
+		log.Printf("Error inserting diary entry into database: %v\n", err)
+		return DiaryEntryResponse{}, err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM watchlist WHERE user_id = $1 AND movie_id = $2;`, userId, body.MovieId); err != nil {
+		log.Printf("Error removing watched movie from the watchlist: %v\n", err)
+		return DiaryEntryResponse{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing transaction made while inserting diary entry: %v\n", err)
+		return DiaryEntryResponse{}, err
+	}
+
+	return d.GetDiaryEntryById(db, entryId)
+}
+
+func (d *DiaryModel) GetDiaryEntryById(db *sql.DB, uuid uuid.UUID) (DiaryEntryResponse, error) {
+	log.Printf("Getting diary entry with uuid %s in DB... \n", uuid)
+
+	query := `SELECT ` + diaryColumns + `
+		FROM ` + diaryTable + `
+			WHERE id = $1;`
+
+	entry, err := scanDiaryEntry(db.QueryRow(query, uuid))
+	if err != nil {
+		log.Printf("Error getting diary entry by id in the database: %v\n", err)
+		return DiaryEntryResponse{}, err
+	}
+
+	return entry, nil
+}
+
+// GetUserDiary returns the diary entries of the user, only the ones watched in year when it isn't 0
+func (d *DiaryModel) GetUserDiary(db *sql.DB, userId uuid.UUID, page PageRequest, orderBy string, year int) (Page[DiaryEntryResponse], error) {
+	log.Printf("Getting diary of user %s in DB, with page %+v, orderBy %v and year %v...\n", userId, page, orderBy, year)
+
+	queryBuilder := NewSelect(diaryColumns, diaryTable).
+		Where("user_id = ?", userId).
+		Where("deleted_at IS NULL")
+
+	if year != 0 {
+		queryBuilder.Where("EXTRACT(YEAR FROM watched_on) = ?", year)
+	}
+
+	queryBuilder.OrderBy(orderBy, diarySortColumns)
+	total, err := countRows(db, queryBuilder)
+	if err != nil {
+		log.Printf("Error counting diary of user %v in db: %v \n", userId, err)
+		return Page[DiaryEntryResponse]{}, err
+	}
+
+	query, args, err := queryBuilder.Page(page).Build()
+	if err != nil {
+		log.Printf("Error building diary query of user %v: %v \n", userId, err)
+		return Page[DiaryEntryResponse]{}, err
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		log.Printf("Error getting diary of user %v from db: %v \n", userId, err)
+		return Page[DiaryEntryResponse]{}, err
+	}
+	defer rows.Close()
+
+	var entries []DiaryEntryResponse
+	var keys []rowKey
+	for rows.Next() {
+		var key rowKey
+		entry, err := scanDiaryEntry(rows, &key.value)
+		if err != nil {
+			log.Printf("Error scanning rows while getting diary of user %v from db: %v \n", userId, err)
+			return Page[DiaryEntryResponse]{}, err
+		}
+		key.id = entry.ID
+
+		entries = append(entries, entry)
+		keys = append(keys, key)
+	}
+
+	return newPage(entries, keys, page, orderBy, total), nil
+}
+
+func (d *DiaryModel) DeleteDiaryEntryById(db *sql.DB, uuid uuid.UUID) error {
+	log.Printf("Deleting diary entry with uuid %s in DB...\n", uuid)
+
+	query := `UPDATE diary
+		SET deleted_at = NOW()
+			WHERE id = $1;`
+
+	if _, err := db.Exec(query, uuid); err != nil {
+		log.Printf("Error deleting diary entry by uuid: %v \n", err)
+		return err
+	}
+
+	return nil
+}
+
+func (d *DiaryModel) UpdateDiaryEntryById(db *sql.DB, uuid uuid.UUID, body DiaryEditBody) (DiaryEntryResponse, error) {
+	log.Printf("Updating diary entry with uuid %s in DB...\n", uuid)
+
+	var query strings.Builder
+	var args []interface{}
+	query.WriteString("UPDATE diary SET")
+
+	if body.WatchedOn != "" {
+		args = append(args, body.WatchedOn)
+		query.WriteString(" watched_on = $" + strconv.Itoa(len(args)) + ",")
+	}
+	if body.Rewatch != nil {
+		args = append(args, *body.Rewatch)
+		query.WriteString(" rewatch = $" + strconv.Itoa(len(args)) + ",")
+	}
+	if body.Notes != "" {
+		args = append(args, body.Notes)
+		query.WriteString(" notes = $" + strconv.Itoa(len(args)) + ",")
+	}
+	if body.ReviewId != "" {
+		args = append(args, body.ReviewId)
+		query.WriteString(" review_id = $" + strconv.Itoa(len(args)) + ",")
+	}
+
+	args = append(args, uuid)
+	query.WriteString(" updated_at = NOW() WHERE id = $" + strconv.Itoa(len(args)) + ";")
+
+	if _, err := db.Exec(query.String(), args...); err != nil {
+		log.Printf("Error updating diary entry by uuid: %v \n", err)
+		return DiaryEntryResponse{}, err
+	}
+
+	return d.GetDiaryEntryById(db, uuid)
+}
+
+// GetUserDiaryStats counts the films the user watched in each month of year, and averages the grades of their reviews of them
+func (d *DiaryModel) GetUserDiaryStats(db *sql.DB, userId uuid.UUID, year int) (DiaryStatsResponse, error) {
+	log.Printf("Getting diary stats of user %s in %v in DB...\n", userId, year)
+
+	stats := DiaryStatsResponse{Year: year}
+
+	query := `SELECT COUNT(*), COUNT(DISTINCT movie_id), COUNT(*) FILTER (WHERE rewatch)
+		FROM diary
+			WHERE user_id = $1 AND deleted_at IS NULL AND EXTRACT(YEAR FROM watched_on) = $2;`
+
+	if err := db.QueryRow(query, userId, year).Scan(&stats.FilmsWatched, &stats.UniqueFilms, &stats.Rewatches); err != nil {
+		log.Printf("Error getting diary totals of user %v: %v \n", userId, err)
+		return DiaryStatsResponse{}, err
+	}
+
+	query = `SELECT EXTRACT(MONTH FROM watched_on)::int, COUNT(*)
+		FROM diary
+			WHERE user_id = $1 AND deleted_at IS NULL AND EXTRACT(YEAR FROM watched_on) = $2
+				GROUP BY 1;`
+
+	rows, err := db.Query(query, userId, year)
+	if err != nil {
+		log.Printf("Error getting diary films per month of user %v: %v \n", userId, err)
+		return DiaryStatsResponse{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var month, films int
+		if err := rows.Scan(&month, &films); err != nil {
+			log.Printf("Error scanning diary films per month of user %v: %v \n", userId, err)
+			return DiaryStatsResponse{}, err
+		}
+		stats.FilmsPerMonth[month-1] = films
+	}
+
+	query = `SELECT COALESCE(ROUND(AVG(c.grade), 2), 0)
+		FROM comments c
+			WHERE c.user_id = $1 AND c.grade IS NOT NULL AND c.deleted_at IS NULL
+				AND c.movie_id IN (
+					SELECT movie_id FROM diary
+						WHERE user_id = $1 AND deleted_at IS NULL AND EXTRACT(YEAR FROM watched_on) = $2
+				);`
+
+	if err := db.QueryRow(query, userId, year).Scan(&stats.AverageGrade); err != nil {
+		log.Printf("Error getting average grade of the diary of user %v: %v \n", userId, err)
+		return DiaryStatsResponse{}, err
+	}
+
+	return stats, nil
+}
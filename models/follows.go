@@ -0,0 +1,131 @@
+package models
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FollowModel struct {
+	CreatedAt time.Time `json:"createdAt"`
+
+	FollowerId string `json:"followerId"`
+	FollowedId string `json:"followedId"`
+}
+
+// FollowResponse is a user in the followers or following lists of another, without their private info
+type FollowResponse struct {
+	ID         uuid.UUID `json:"id"`
+	Name       string    `json:"name"`
+	Surname    string    `json:"surname"`
+	Picture    string    `json:"picture"`
+	FollowedAt time.Time `json:"followedAt"`
+}
+
+// Columns the follow lists can be sorted by
+var followSortColumns = []string{"followed_at", "name"}
+
+const followColumns = "id, name, surname, picture, followed_at"
+
+// Both lists read from the same table, joining the users on one side of the follow and filtering by the other
+const followersTable = `(SELECT u.id, u.name, u.surname, u.picture, f.created_at AS followed_at, f.followed_id AS of_user_id
+		FROM follows f
+			JOIN users u ON u.id = f.follower_id
+				WHERE u.deleted_at IS NULL) AS followers`
+
+const followingTable = `(SELECT u.id, u.name, u.surname, u.picture, f.created_at AS followed_at, f.follower_id AS of_user_id
+		FROM follows f
+			JOIN users u ON u.id = f.followed_id
+				WHERE u.deleted_at IS NULL) AS following`
+
+// Internal methods
+func (f *FollowModel) getFollows(db *sql.DB, table string, userId uuid.UUID, page PageRequest, orderBy string) (Page[FollowResponse], error) {
+	queryBuilder := NewSelect(followColumns, table).
+		Where("of_user_id = ?", userId).
+		OrderBy(orderBy, followSortColumns)
+
+	total, err := countRows(db, queryBuilder)
+	if err != nil {
+		log.Println("Error counting follows in db:", err)
+		return Page[FollowResponse]{}, err
+	}
+
+	query, args, err := queryBuilder.Page(page).Build()
+	if err != nil {
+		log.Println("Error building follows query:", err)
+		return Page[FollowResponse]{}, err
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		log.Println("Error getting follows from db:", err)
+		return Page[FollowResponse]{}, err
+	}
+	defer rows.Close()
+
+	var users []FollowResponse
+	var keys []rowKey
+	for rows.Next() {
+		var user FollowResponse
+		var key rowKey
+		if err := rows.Scan(&user.ID, &user.Name, &user.Surname, &user.Picture, &user.FollowedAt, &key.value); err != nil {
+			log.Println("Error scanning follow from db:", err)
+			return Page[FollowResponse]{}, err
+		}
+		key.id = user.ID
+
+		users = append(users, user)
+		keys = append(keys, key)
+	}
+
+	return newPage(users, keys, page, orderBy, total), nil
+}
+
+// Public methods
+func (f *FollowModel) InsertFollowInDB(db *sql.DB, followerId uuid.UUID, followedId uuid.UUID) error {
+	log.Printf("Making user %s follow user %s in DB...\n", followerId, followedId)
+
+	query := `INSERT INTO follows (follower_id, followed_id) VALUES ($1, $2) ON CONFLICT DO NOTHING;`
+	if _, err := db.Exec(query, followerId, followedId); err != nil {
+		log.Printf("Error inserting follow into database: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
+func (f *FollowModel) DeleteFollow(db *sql.DB, followerId uuid.UUID, followedId uuid.UUID) error {
+	log.Printf("Making user %s unfollow user %s in DB...\n", followerId, followedId)
+
+	query := `DELETE FROM follows WHERE follower_id = $1 AND followed_id = $2;`
+	if _, err := db.Exec(query, followerId, followedId); err != nil {
+		log.Printf("Error deleting follow: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
+// IsFollowing tells if the follower follows the followed user
+func (f *FollowModel) IsFollowing(db *sql.DB, followerId uuid.UUID, followedId uuid.UUID) (bool, error) {
+	var found bool
+	query := `SELECT EXISTS (SELECT 1 FROM follows WHERE follower_id = $1 AND followed_id = $2);`
+	if err := db.QueryRow(query, followerId, followedId).Scan(&found); err != nil {
+		log.Printf("Error checking if user %v follows user %v: %v\n", followerId, followedId, err)
+		return false, err
+	}
+
+	return found, nil
+}
+
+func (f *FollowModel) GetFollowers(db *sql.DB, userId uuid.UUID, page PageRequest, orderBy string) (Page[FollowResponse], error) {
+	log.Printf("Getting followers of user %s in DB, with page %+v and orderBy %v...\n", userId, page, orderBy)
+	return f.getFollows(db, followersTable, userId, page, orderBy)
+}
+
+func (f *FollowModel) GetFollowing(db *sql.DB, userId uuid.UUID, page PageRequest, orderBy string) (Page[FollowResponse], error) {
+	log.Printf("Getting users followed by user %s in DB, with page %+v and orderBy %v...\n", userId, page, orderBy)
+	return f.getFollows(db, followingTable, userId, page, orderBy)
+}
@@ -0,0 +1,301 @@
+package models
+
+import (
+	"database/sql"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type GenreModel struct {
+	ID          uuid.UUID    `json:"id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	CreatedAt   time.Time    `json:"createdAt"`
+	UpdatedAt   time.Time    `json:"updatedAt"`
+	DeletedAt   sql.NullTime `json:"deletedAt"`
+
+	CreatorId string `json:"creatorId"`
+}
+
+type GenreBody struct {
+	Name        string `json:"name" validate:"required,max=50"`
+	Description string `json:"description" validate:"omitempty"`
+
+	CreatorId string `json:"creatorId" validate:"required,isadminuuid"`
+}
+
+type GenreEditBody struct {
+	Name        string `json:"name" validate:"omitempty,max=50"`
+	Description string `json:"description" validate:"omitempty"`
+}
+
+type GenreResponse struct {
+	ID          uuid.UUID    `json:"id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	CreatedAt   time.Time    `json:"createdAt"`
+	UpdatedAt   time.Time    `json:"updatedAt"`
+	DeletedAt   sql.NullTime `json:"deletedAt"`
+
+	CreatorId string `json:"creatorId"`
+}
+
+// GenreResponseWithMovies carries the grade of the genre, averaged over every
+// active comment of its active movies, instead of an average of the movies averages.
+type GenreResponseWithMovies struct {
+	GenreResponse
+	AverageGrade float64         `json:"averageGrade"`
+	MovieCount   int             `json:"movieCount"`
+	Movies       []MovieResponse `json:"movies"`
+}
+
+// GenreFilters narrows the genres list. Zero values leave the filter out of the query.
+type GenreFilters struct {
+	Name string
+}
+
+// Columns the genres list can be sorted by
+var genreSortColumns = []string{"created_at", "updated_at", "name"}
+
+func (g *GenreModel) InsertGenreInDB(db *sql.DB, genreInfo GenreBody) (GenreResponse, error) {
+	log.Printf("Inserting genre with name %s in DB by user %s...\n", genreInfo.Name, genreInfo.CreatorId)
+
+	query := `INSERT INTO genres
+			(name, description, creator_id)
+			VALUES ($1, $2, $3)
+				RETURNING id, name, description, created_at, updated_at, deleted_at, creator_id;`
+
+	var genre GenreResponse
+	if err := db.QueryRow(query, genreInfo.Name, genreInfo.Description, genreInfo.CreatorId).Scan(&genre.ID, &genre.Name, &genre.Description, &genre.CreatedAt, &genre.UpdatedAt, &genre.DeletedAt, &genre.CreatorId); err != nil {
+		log.Printf("Error inserting genre into database: %v\n", err)
+		return GenreResponse{}, err
+	}
+
+	return genre, nil
+}
+
+func (g *GenreModel) GetAllGenres(db *sql.DB, page PageRequest, orderBy string, deleted bool, filters GenreFilters) (Page[GenreResponse], error) {
+	log.Printf("Getting all genres in DB, with page %+v, orderBy %v, deleted %v and filters %+v...\n", page, orderBy, deleted, filters)
+
+	queryBuilder := NewSelect("id, name, description, created_at, updated_at, deleted_at, creator_id", "genres")
+
+	if !deleted {
+		queryBuilder.Where("deleted_at IS NULL")
+	}
+
+	if filters.Name != "" {
+		queryBuilder.Where("name ILIKE ?", containsPattern(filters.Name))
+	}
+
+	queryBuilder.OrderBy(orderBy, genreSortColumns)
+	total, err := countRows(db, queryBuilder)
+	if err != nil {
+		log.Println("Error counting all genres in db:", err)
+		return Page[GenreResponse]{}, err
+	}
+
+	query, args, err := queryBuilder.Page(page).Build()
+	if err != nil {
+		log.Println("Error building all genres query:", err)
+		return Page[GenreResponse]{}, err
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		log.Println("Error getting all genres from db:", err)
+		return Page[GenreResponse]{}, err
+	}
+	defer rows.Close()
+
+	var genres []GenreResponse
+	var keys []rowKey
+	for rows.Next() {
+		var genre GenreResponse
+		var key rowKey
+		if err := rows.Scan(&genre.ID, &genre.Name, &genre.Description, &genre.CreatedAt, &genre.UpdatedAt, &genre.DeletedAt, &genre.CreatorId, &key.value); err != nil {
+			log.Println("Error scanning genre from db:", err)
+			return Page[GenreResponse]{}, err
+		}
+		key.id = genre.ID
+
+		genres = append(genres, genre)
+		keys = append(keys, key)
+	}
+
+	return newPage(genres, keys, page, orderBy, total), nil
+}
+
+func (g *GenreModel) GetGenreById(db *sql.DB, uuid uuid.UUID) (GenreResponse, error) {
+	log.Printf("Getting genre with uuid %s in DB... \n", uuid)
+
+	query := `SELECT
+		id, name, description, created_at, updated_at, deleted_at, creator_id
+		FROM genres
+			WHERE id = $1;`
+
+	var genre GenreResponse
+	if err := db.QueryRow(query, uuid).Scan(&genre.ID, &genre.Name, &genre.Description, &genre.CreatedAt, &genre.UpdatedAt, &genre.DeletedAt, &genre.CreatorId); err != nil {
+		log.Printf("Error getting genre by id in the database: %v\n", err)
+		return GenreResponse{}, err
+	}
+
+	return genre, nil
+}
+
+// CountActiveGenresByIds counts how many of the ids belong to genres that weren't deleted, with a single query
+func (g *GenreModel) CountActiveGenresByIds(db *sql.DB, ids []uuid.UUID) (int, error) {
+	log.Printf("Counting %v genres by id in DB... \n", len(ids))
+
+	query := `SELECT COUNT(*) FROM genres WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL;`
+
+	var count int
+	if err := db.QueryRow(query, pq.Array(ids)).Scan(&count); err != nil {
+		log.Printf("Error counting genres by id in the database: %v\n", err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetGenreByName only looks at active genres and ignores case, the same way the unique index does.
+func (g *GenreModel) GetGenreByName(db *sql.DB, name string) (GenreResponse, error) {
+	log.Printf("Getting genre with name %s in DB... \n", name)
+
+	query := `SELECT
+		id, name, description, created_at, updated_at, deleted_at, creator_id
+		FROM genres
+			WHERE LOWER(name) = LOWER($1) AND deleted_at IS NULL;`
+
+	var genre GenreResponse
+	if err := db.QueryRow(query, name).Scan(&genre.ID, &genre.Name, &genre.Description, &genre.CreatedAt, &genre.UpdatedAt, &genre.DeletedAt, &genre.CreatorId); err != nil {
+		log.Printf("Error getting genre by name in the database: %v\n", err)
+		return GenreResponse{}, err
+	}
+
+	return genre, nil
+}
+
+func (g *GenreModel) GetGenreByIdWithMovies(db *sql.DB, uuid uuid.UUID) (GenreResponseWithMovies, error) {
+	log.Printf("Getting genre with uuid %s in DB with movies... \n", uuid)
+
+	genre, err := g.GetGenreById(db, uuid)
+	if err != nil {
+		log.Printf("Error getting genre by id in the database: %v\n", err)
+		return GenreResponseWithMovies{}, err
+	}
+
+	genreWithMovies := GenreResponseWithMovies{
+		GenreResponse: genre,
+		Movies:        []MovieResponse{},
+	}
+
+	statsQuery := `SELECT
+		COALESCE(ROUND(AVG(c.grade), 1), 0), COUNT(DISTINCT m.id)
+			FROM movies_genres mg
+				JOIN movies m ON m.id = mg.movie_id AND m.deleted_at IS NULL
+				LEFT JOIN comments c ON c.movie_id = m.id AND c.deleted_at IS NULL AND c.status = 'visible'
+					WHERE mg.genre_id = $1;`
+
+	if err := db.QueryRow(statsQuery, uuid).Scan(&genreWithMovies.AverageGrade, &genreWithMovies.MovieCount); err != nil {
+		log.Printf("Error getting grade stats of genre %v: %v\n", uuid, err)
+		return GenreResponseWithMovies{}, err
+	}
+
+	moviesQuery := `SELECT
+		m.id, m.title, m.director, m.release_date, m.average_grade, m.picture, m.synopsis, m.created_at, m.updated_at, m.deleted_at, m.creator_id, m.rating_count, m.grade_histogram, m.weighted_rating
+			FROM movies m
+				JOIN movies_genres mg ON m.id = mg.movie_id
+					WHERE mg.genre_id = $1 AND m.deleted_at IS NULL
+						ORDER BY m.title ASC;`
+
+	rows, err := db.Query(moviesQuery, uuid)
+	if err != nil {
+		log.Printf("Error getting movies of genre %v: %v\n", uuid, err)
+		return GenreResponseWithMovies{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var movie MovieResponse
+		if err := rows.Scan(&movie.ID, &movie.Title, &movie.Director, &movie.ReleaseDate, &movie.AverageGrade, &movie.Picture, &movie.Synopsis, &movie.CreatedAt, &movie.UpdatedAt, &movie.DeletedAt, &movie.CreatorId, &movie.RatingCount, &movie.GradeHistogram, &movie.WeightedRating); err != nil {
+			log.Printf("Error scanning movie row in GetGenreByIdWithMovies: %v\n", err)
+			return GenreResponseWithMovies{}, err
+		}
+		genreWithMovies.Movies = append(genreWithMovies.Movies, movie)
+	}
+
+	return genreWithMovies, nil
+}
+
+func (g *GenreModel) DeleteGenreById(db *sql.DB, uuid uuid.UUID) error {
+	log.Printf("Deleting genre with uuid %s in DB... \n", uuid)
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error beginning transaction made while deleting genre by id: %v\n", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	// Untagging every movie of the genre, like deleted actors leave their movies
+	if _, err := tx.Exec(`DELETE FROM movies_genres WHERE genre_id = $1;`, uuid); err != nil {
+		log.Printf("Error deleting genre's associations with movies while deleting genre by id: %v\n", err)
+		return err
+	}
+
+	query := `UPDATE genres
+		SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND deleted_at IS NULL;`
+
+	if _, err := tx.Exec(query, uuid); err != nil {
+		log.Printf("Error deleting genre by uuid: %v\n", err)
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing transaction made while deleting genre by id: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
+func (g *GenreModel) UpdateGenreById(db *sql.DB, uuid uuid.UUID, body GenreEditBody) (GenreResponse, error) {
+	log.Printf("Updating genre with uuid %s in DB... \n", uuid)
+
+	var updateQueryBuilder strings.Builder
+	var args []interface{}
+
+	updateQueryBuilder.WriteString("UPDATE genres SET ")
+
+	argIndex := 1
+	if body.Name != "" {
+		updateQueryBuilder.WriteString("name = $" + strconv.Itoa(argIndex) + ", ")
+		args = append(args, body.Name)
+		argIndex++
+	}
+
+	if body.Description != "" {
+		updateQueryBuilder.WriteString("description = $" + strconv.Itoa(argIndex) + ", ")
+		args = append(args, body.Description)
+		argIndex++
+	}
+
+	updateQueryBuilder.WriteString("updated_at = CURRENT_TIMESTAMP, ")
+	query := strings.TrimSuffix(updateQueryBuilder.String(), ", ")
+	query += " WHERE id = $" + strconv.Itoa(argIndex) + " AND deleted_at IS NULL RETURNING id, name, description, created_at, updated_at, deleted_at, creator_id;"
+	args = append(args, uuid)
+
+	var genre GenreResponse
+	if err := db.QueryRow(query, args...).Scan(&genre.ID, &genre.Name, &genre.Description, &genre.CreatedAt, &genre.UpdatedAt, &genre.DeletedAt, &genre.CreatorId); err != nil {
+		log.Printf("Error updating genre by uuid: %v \n", err)
+		return GenreResponse{}, err
+	}
+
+	return genre, nil
+}
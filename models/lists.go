@@ -0,0 +1,533 @@
+package models
+
+import (
+	"database/sql"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type ListModel struct {
+	ID          uuid.UUID    `json:"id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	IsPublic    bool         `json:"isPublic"`
+	CreatedAt   time.Time    `json:"createdAt"`
+	UpdatedAt   time.Time    `json:"updatedAt"`
+	DeletedAt   sql.NullTime `json:"deletedAt"`
+
+	OwnerId      string        `json:"ownerId"`
+	ForkedFromId uuid.NullUUID `json:"forkedFromId"`
+}
+
+type ListBody struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description" validate:"omitempty,max=1000"`
+	IsPublic    bool   `json:"isPublic"`
+}
+
+// ListEditBody has IsPublic as a pointer so a list can be made private again
+type ListEditBody struct {
+	Name        string `json:"name" validate:"omitempty,max=100"`
+	Description string `json:"description" validate:"omitempty,max=1000"`
+	IsPublic    *bool  `json:"isPublic" validate:"omitempty"`
+}
+
+// ListItemBody adds a movie to a list. Without a position the movie goes to the end of the list,
+// otherwise it is inserted at the position and the movies after it are moved down.
+type ListItemBody struct {
+	MovieId  string `json:"movieId" validate:"required,isvaliduuid"`
+	Position int    `json:"position" validate:"omitempty,min=1"`
+	Note     string `json:"note" validate:"omitempty,max=500"`
+}
+
+// ListOrderBody has every movie of the list, in the new order
+type ListOrderBody struct {
+	MovieIds []string `json:"movieIds" validate:"required,min=1,dive,isvaliduuid"`
+}
+
+type ListResponse struct {
+	ID          uuid.UUID    `json:"id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	IsPublic    bool         `json:"isPublic"`
+	MovieCount  int          `json:"movieCount"`
+	CreatedAt   time.Time    `json:"createdAt"`
+	UpdatedAt   time.Time    `json:"updatedAt"`
+	DeletedAt   sql.NullTime `json:"deletedAt"`
+
+	OwnerId      string        `json:"ownerId"`
+	ForkedFromId uuid.NullUUID `json:"forkedFromId"`
+}
+
+type ListItemResponse struct {
+	Position int           `json:"position"`
+	Note     string        `json:"note"`
+	AddedAt  time.Time     `json:"addedAt"`
+	Movie    MovieResponse `json:"movie"`
+}
+
+type ListResponseWithItems struct {
+	ListResponse
+	Items []ListItemResponse `json:"items"`
+}
+
+type UserResponseWithLists struct {
+	UserResponse
+	Lists []ListResponse `json:"lists"`
+}
+
+// ListFilters narrows the public lists. Zero values leave the filter out of the query.
+type ListFilters struct {
+	Name    string
+	OwnerId uuid.UUID
+}
+
+// Columns the lists can be sorted by
+var listSortColumns = []string{"created_at", "updated_at", "name", "movie_count"}
+
+const listColumns = "id, name, description, is_public, movie_count, created_at, updated_at, deleted_at, owner_id, forked_from_id"
+
+// The movie count is a column of the subquery so the lists can be sorted and paginated by it
+const listsTable = `(SELECT lists.*, (SELECT COUNT(*) FROM list_items li WHERE li.list_id = lists.id) AS movie_count FROM lists) AS lists`
+
+const listItemColumns = `li.position, li.note, li.created_at,
+	m.id, m.title, m.director, m.release_date, m.average_grade, m.picture, m.synopsis, m.created_at, m.updated_at, m.deleted_at, m.creator_id, m.rating_count, m.grade_histogram, m.weighted_rating`
+
+func scanList(row rowScanner, extra ...interface{}) (ListResponse, error) {
+	var list ListResponse
+	dest := []interface{}{&list.ID, &list.Name, &list.Description, &list.IsPublic, &list.MovieCount, &list.CreatedAt, &list.UpdatedAt, &list.DeletedAt, &list.OwnerId, &list.ForkedFromId}
+	err := row.Scan(append(dest, extra...)...)
+
+	return list, err
+}
+
+// Internal methods
+func (l *ListModel) getItemsOfAList(db querier, listId uuid.UUID) ([]ListItemResponse, error) {
+	query := `SELECT ` + listItemColumns + `
+		FROM list_items li
+			JOIN movies m ON m.id = li.movie_id
+				WHERE li.list_id = $1
+					ORDER BY li.position ASC;`
+
+	rows, err := db.Query(query, listId)
+	if err != nil {
+		log.Printf("Error getting items of list %v: %v\n", listId, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []ListItemResponse{}
+	for rows.Next() {
+		var item ListItemResponse
+		movie := &item.Movie
+		if err := rows.Scan(&item.Position, &item.Note, &item.AddedAt, &movie.ID, &movie.Title, &movie.Director, &movie.ReleaseDate, &movie.AverageGrade, &movie.Picture, &movie.Synopsis, &movie.CreatedAt, &movie.UpdatedAt, &movie.DeletedAt, &movie.CreatorId, &movie.RatingCount, &movie.GradeHistogram, &movie.WeightedRating); err != nil {
+			log.Printf("Error scanning items of list %v: %v\n", listId, err)
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// Public methods
+func (l *ListModel) InsertListInDB(db *sql.DB, ownerId uuid.UUID, body ListBody) (ListResponse, error) {
+	log.Printf("Inserting list with name %s of user %s in DB...\n", body.Name, ownerId)
+
+	query := `INSERT INTO lists
+			(name, description, is_public, owner_id)
+			VALUES ($1, $2, $3, $4)
+				RETURNING id;`
+
+	var listId uuid.UUID
+	if err := db.QueryRow(query, body.Name, body.Description, body.IsPublic, ownerId).Scan(&listId); err != nil {
+		log.Printf("Error inserting list into database: %v\n", err)
+		return ListResponse{}, err
+	}
+
+	return l.GetListById(db, listId)
+}
+
+// GetAllPublicLists only returns the public lists that were not deleted
+func (l *ListModel) GetAllPublicLists(db *sql.DB, page PageRequest, orderBy string, filters ListFilters) (Page[ListResponse], error) {
+	log.Printf("Getting all public lists in DB, with page %+v, orderBy %v and filters %+v...\n", page, orderBy, filters)
+
+	queryBuilder := NewSelect(listColumns, listsTable).
+		Where("is_public").
+		Where("deleted_at IS NULL")
+
+	if filters.Name != "" {
+		queryBuilder.Where("name ILIKE ?", containsPattern(filters.Name))
+	}
+
+	if filters.OwnerId != uuid.Nil {
+		queryBuilder.Where("owner_id = ?", filters.OwnerId)
+	}
+
+	queryBuilder.OrderBy(orderBy, listSortColumns)
+	total, err := countRows(db, queryBuilder)
+	if err != nil {
+		log.Println("Error counting all public lists in db:", err)
+		return Page[ListResponse]{}, err
+	}
+
+	query, args, err := queryBuilder.Page(page).Build()
+	if err != nil {
+		log.Println("Error building all public lists query:", err)
+		return Page[ListResponse]{}, err
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		log.Println("Error getting all public lists from db:", err)
+		return Page[ListResponse]{}, err
+	}
+	defer rows.Close()
+
+	var lists []ListResponse
+	var keys []rowKey
+	for rows.Next() {
+		var key rowKey
+		list, err := scanList(rows, &key.value)
+		if err != nil {
+			log.Println("Error scanning list from db:", err)
+			return Page[ListResponse]{}, err
+		}
+		key.id = list.ID
+
+		lists = append(lists, list)
+		keys = append(keys, key)
+	}
+
+	return newPage(lists, keys, page, orderBy, total), nil
+}
+
+// GetUserLists returns every list of the user, private ones included
+func (l *ListModel) GetUserLists(db *sql.DB, userId uuid.UUID, orderBy string, deleted bool) (UserResponseWithLists, error) {
+	log.Printf("Getting lists of user %s in DB, with orderBy %v and deleted %v...\n", userId, orderBy, deleted)
+
+	user, err := userModel.GetUserById(db, userId)
+	if err != nil {
+		log.Printf("Error getting user info of user %v from db: %v \n", userId, err)
+		return UserResponseWithLists{}, err
+	}
+
+	queryBuilder := NewSelect(listColumns, listsTable).
+		Where("owner_id = ?", userId)
+
+	if !deleted {
+		queryBuilder.Where("deleted_at IS NULL")
+	}
+
+	query, args, err := queryBuilder.OrderBy(orderBy, listSortColumns).Build()
+	if err != nil {
+		log.Printf("Error building lists query of user %v: %v \n", userId, err)
+		return UserResponseWithLists{}, err
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		log.Printf("Error getting lists of user %v from db: %v \n", userId, err)
+		return UserResponseWithLists{}, err
+	}
+	defer rows.Close()
+
+	userWithLists := UserResponseWithLists{
+		UserResponse: user,
+		Lists:        []ListResponse{},
+	}
+	for rows.Next() {
+		list, err := scanList(rows)
+		if err != nil {
+			log.Printf("Error scanning rows while getting lists of user %v from db: %v \n", userId, err)
+			return UserResponseWithLists{}, err
+		}
+		userWithLists.Lists = append(userWithLists.Lists, list)
+	}
+
+	return userWithLists, nil
+}
+
+func (l *ListModel) GetListById(db *sql.DB, uuid uuid.UUID) (ListResponse, error) {
+	log.Printf("Getting list with uuid %s in DB... \n", uuid)
+
+	query := `SELECT ` + listColumns + `
+		FROM ` + listsTable + `
+			WHERE id = $1;`
+
+	list, err := scanList(db.QueryRow(query, uuid))
+	if err != nil {
+		log.Printf("Error getting list by id in the database: %v\n", err)
+		return ListResponse{}, err
+	}
+
+	return list, nil
+}
+
+func (l *ListModel) GetListByIdWithItems(db *sql.DB, uuid uuid.UUID) (ListResponseWithItems, error) {
+	list, err := l.GetListById(db, uuid)
+	if err != nil {
+		return ListResponseWithItems{}, err
+	}
+
+	items, err := l.getItemsOfAList(db, uuid)
+	if err != nil {
+		return ListResponseWithItems{}, err
+	}
+
+	return ListResponseWithItems{ListResponse: list, Items: items}, nil
+}
+
+func (l *ListModel) DeleteListById(db *sql.DB, uuid uuid.UUID) error {
+	log.Printf("Deleting list with uuid %s in DB...\n", uuid)
+
+	query := `UPDATE lists
+		SET deleted_at = NOW()
+			WHERE id = $1;`
+
+	if _, err := db.Exec(query, uuid); err != nil {
+		log.Printf("Error deleting list by uuid: %v \n", err)
+		return err
+	}
+
+	return nil
+}
+
+func (l *ListModel) UpdateListById(db *sql.DB, uuid uuid.UUID, body ListEditBody) (ListResponse, error) {
+	log.Printf("Updating list with uuid %s in DB...\n", uuid)
+
+	var query strings.Builder
+	var args []interface{}
+	query.WriteString("UPDATE lists SET")
+
+	if body.Name != "" {
+		args = append(args, body.Name)
+		query.WriteString(" name = $" + strconv.Itoa(len(args)) + ",")
+	}
+	if body.Description != "" {
+		args = append(args, body.Description)
+		query.WriteString(" description = $" + strconv.Itoa(len(args)) + ",")
+	}
+	if body.IsPublic != nil {
+		args = append(args, *body.IsPublic)
+		query.WriteString(" is_public = $" + strconv.Itoa(len(args)) + ",")
+	}
+
+	args = append(args, uuid)
+	query.WriteString(" updated_at = NOW() WHERE id = $" + strconv.Itoa(len(args)) + ";")
+
+	if _, err := db.Exec(query.String(), args...); err != nil {
+		log.Printf("Error updating list by uuid: %v \n", err)
+		return ListResponse{}, err
+	}
+
+	return l.GetListById(db, uuid)
+}
+
+// IsMovieInList tells if the movie is one of the items of the list
+func (l *ListModel) IsMovieInList(db *sql.DB, listId uuid.UUID, movieId uuid.UUID) (bool, error) {
+	var found bool
+	query := `SELECT EXISTS (SELECT 1 FROM list_items WHERE list_id = $1 AND movie_id = $2);`
+	if err := db.QueryRow(query, listId, movieId).Scan(&found); err != nil {
+		log.Printf("Error checking if movie %v is in list %v: %v\n", movieId, listId, err)
+		return false, err
+	}
+
+	return found, nil
+}
+
+func (l *ListModel) InsertListItemInDB(db *sql.DB, listId uuid.UUID, body ListItemBody) (ListResponseWithItems, error) {
+	log.Printf("Adding movie %s to list %s in DB...\n", body.MovieId, listId)
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction to add movie to list: %v\n", err)
+		return ListResponseWithItems{}, err
+	}
+	defer tx.Rollback()
+
+	// Locking the list so concurrent changes don't end up with the same position
+	var itemCount int
+	if err := tx.QueryRow(`SELECT (SELECT COUNT(*) FROM list_items WHERE list_id = lists.id) FROM lists WHERE id = $1 FOR UPDATE;`, listId).Scan(&itemCount); err != nil {
+		log.Printf("Error counting items of list %v: %v\n", listId, err)
+		return ListResponseWithItems{}, err
+	}
+
+	position := body.Position
+	if position == 0 || position > itemCount {
+		position = itemCount + 1
+	} else if _, err := tx.Exec(`UPDATE list_items SET position = position + 1 WHERE list_id = $1 AND position >= $2;`, listId, position); err != nil {
+		log.Printf("Error making room for movie in list %v: %v\n", listId, err)
+		return ListResponseWithItems{}, err
+	}
+
+	query := `INSERT INTO list_items (list_id, movie_id, position, note) VALUES ($1, $2, $3, $4);`
+	if _, err := tx.Exec(query, listId, body.MovieId, position, body.Note); err != nil {
+		log.Printf("Error inserting movie in list %v: %v\n", listId, err)
+		return ListResponseWithItems{}, err
+	}
+
+	if _, err := tx.Exec(`UPDATE lists SET updated_at = NOW() WHERE id = $1;`, listId); err != nil {
+		log.Printf("Error updating list %v: %v\n", listId, err)
+		return ListResponseWithItems{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing transaction while adding movie to list: %v\n", err)
+		return ListResponseWithItems{}, err
+	}
+
+	return l.GetListByIdWithItems(db, listId)
+}
+
+// DeleteListItem removes the movie from the list and moves the movies after it up, closing the gap
+func (l *ListModel) DeleteListItem(db *sql.DB, listId uuid.UUID, movieId uuid.UUID) error {
+	log.Printf("Removing movie %s from list %s in DB...\n", movieId, listId)
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction to remove movie from list: %v\n", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`SELECT id FROM lists WHERE id = $1 FOR UPDATE;`, listId); err != nil {
+		log.Printf("Error locking list %v: %v\n", listId, err)
+		return err
+	}
+
+	var position int
+	if err := tx.QueryRow(`DELETE FROM list_items WHERE list_id = $1 AND movie_id = $2 RETURNING position;`, listId, movieId).Scan(&position); err != nil {
+		log.Printf("Error removing movie from list %v: %v\n", listId, err)
+		return err
+	}
+
+	if _, err := tx.Exec(`UPDATE list_items SET position = position - 1 WHERE list_id = $1 AND position > $2;`, listId, position); err != nil {
+		log.Printf("Error closing the gap in list %v: %v\n", listId, err)
+		return err
+	}
+
+	if _, err := tx.Exec(`UPDATE lists SET updated_at = NOW() WHERE id = $1;`, listId); err != nil {
+		log.Printf("Error updating list %v: %v\n", listId, err)
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing transaction while removing movie from list: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
+// ReorderListItems sets the positions of the list to the order of movieIds in a single transaction.
+// It returns false, without changing anything, when movieIds isn't exactly the movies of the list.
+func (l *ListModel) ReorderListItems(db *sql.DB, listId uuid.UUID, movieIds []string) (bool, error) {
+	log.Printf("Reordering movies of list %s in DB...\n", listId)
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction to reorder list: %v\n", err)
+		return false, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`SELECT movie_id FROM list_items WHERE list_id = $1 FOR UPDATE;`, listId)
+	if err != nil {
+		log.Printf("Error getting movies of list %v: %v\n", listId, err)
+		return false, err
+	}
+
+	currentMovies := map[uuid.UUID]bool{}
+	for rows.Next() {
+		var movieId uuid.UUID
+		if err := rows.Scan(&movieId); err != nil {
+			rows.Close()
+			log.Printf("Error scanning movies of list %v: %v\n", listId, err)
+			return false, err
+		}
+		currentMovies[movieId] = true
+	}
+	rows.Close()
+
+	if len(movieIds) != len(currentMovies) {
+		return false, nil
+	}
+
+	seen := map[uuid.UUID]bool{}
+	for _, id := range movieIds {
+		movieId, err := uuid.Parse(id)
+		if err != nil || !currentMovies[movieId] || seen[movieId] {
+			return false, nil
+		}
+		seen[movieId] = true
+	}
+
+	query := `UPDATE list_items li
+		SET position = new_order.position
+			FROM UNNEST($2::uuid[]) WITH ORDINALITY AS new_order(movie_id, position)
+				WHERE li.list_id = $1 AND li.movie_id = new_order.movie_id;`
+
+	if _, err := tx.Exec(query, listId, pq.Array(movieIds)); err != nil {
+		log.Printf("Error reordering list %v: %v\n", listId, err)
+		return false, err
+	}
+
+	if _, err := tx.Exec(`UPDATE lists SET updated_at = NOW() WHERE id = $1;`, listId); err != nil {
+		log.Printf("Error updating list %v: %v\n", listId, err)
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing transaction while reordering list: %v\n", err)
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ForkListInDB copies the list and its movies to the user. Forks start private and keep a reference to the original list.
+func (l *ListModel) ForkListInDB(db *sql.DB, listId uuid.UUID, ownerId uuid.UUID) (ListResponseWithItems, error) {
+	log.Printf("Forking list %s for user %s in DB...\n", listId, ownerId)
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction to fork list: %v\n", err)
+		return ListResponseWithItems{}, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO lists (name, description, is_public, owner_id, forked_from_id)
+		SELECT name, description, false, $2, id
+			FROM lists
+				WHERE id = $1
+					RETURNING id;`
+
+	var forkId uuid.UUID
+	if err := tx.QueryRow(query, listId, ownerId).Scan(&forkId); err != nil {
+		log.Printf("Error forking list %v: %v\n", listId, err)
+		return ListResponseWithItems{}, err
+	}
+
+	query = `INSERT INTO list_items (list_id, movie_id, position, note)
+		SELECT $2, movie_id, position, note
+			FROM list_items
+				WHERE list_id = $1;`
+
+	if _, err := tx.Exec(query, listId, forkId); err != nil {
+		log.Printf("Error copying movies of list %v: %v\n", listId, err)
+		return ListResponseWithItems{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing transaction while forking list: %v\n", err)
+		return ListResponseWithItems{}, err
+	}
+
+	return l.GetListByIdWithItems(db, forkId)
+}
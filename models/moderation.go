@@ -0,0 +1,206 @@
+package models
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type ModerationModel struct {
+	ID         uuid.UUID    `json:"id"`
+	Reason     string       `json:"reason"`
+	CreatedAt  time.Time    `json:"createdAt"`
+	ResolvedAt sql.NullTime `json:"resolvedAt"`
+	Resolution string       `json:"resolution"`
+
+	UserId     string        `json:"userId"`
+	CommentId  string        `json:"commentId"`
+	ResolverId uuid.NullUUID `json:"resolverId"`
+}
+
+// Actions an admin can take on a comment of the moderation queue
+const (
+	ModerationApprove = "approve"
+	ModerationHide    = "hide"
+	ModerationBan     = "ban"
+)
+
+type ReportBody struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+// ModerationActionBody is the decision of an admin on a comment. Banning also hides the comment.
+type ModerationActionBody struct {
+	Action string `json:"action" validate:"required,oneof=approve hide ban"`
+}
+
+type ReportResponse struct {
+	ID         uuid.UUID    `json:"id"`
+	Reason     string       `json:"reason"`
+	CreatedAt  time.Time    `json:"createdAt"`
+	ResolvedAt sql.NullTime `json:"resolvedAt"`
+	Resolution string       `json:"resolution"`
+
+	UserId     string        `json:"userId"`
+	CommentId  string        `json:"commentId"`
+	ResolverId uuid.NullUUID `json:"resolverId"`
+}
+
+// ModerationQueueItem is a comment waiting for an admin, with the reasons of its open reports.
+// FlaggedAt is when it was last reported, or when it was held by the blocklist if nobody reported it.
+type ModerationQueueItem struct {
+	Comment     CommentResponse `json:"comment"`
+	ReportCount int             `json:"reportCount"`
+	Reasons     []string        `json:"reasons"`
+	FlaggedAt   time.Time       `json:"flaggedAt"`
+}
+
+// Columns the moderation queue can be sorted by
+var moderationQueueSortColumns = []string{"flagged_at", "report_count"}
+
+const reportColumns = "id, reason, created_at, resolved_at, COALESCE(resolution, ''), user_id, comment_id, resolver_id"
+
+// The queue has the active comments that are pending or have open reports
+const moderationQueueTable = `(SELECT c.*, COALESCE(r.report_count, 0) AS report_count, COALESCE(r.reasons, '{}') AS reasons,
+		COALESCE(r.last_reported_at, c.updated_at) AS flagged_at
+		FROM (SELECT ` + commentColumns + ` FROM comments WHERE deleted_at IS NULL) AS c
+			LEFT JOIN (
+				SELECT comment_id, COUNT(*) AS report_count, ARRAY_AGG(reason ORDER BY created_at) AS reasons, MAX(created_at) AS last_reported_at
+					FROM comment_reports
+						WHERE resolved_at IS NULL
+							GROUP BY comment_id
+			) AS r ON r.comment_id = c.id
+				WHERE c.status = 'pending' OR r.comment_id IS NOT NULL) AS queue`
+
+// Public methods
+func (m *ModerationModel) InsertReportInDB(db *sql.DB, userId uuid.UUID, commentId uuid.UUID, body ReportBody) (ReportResponse, error) {
+	log.Printf("Inserting report of user %s on comment %s in DB...\n", userId, commentId)
+
+	query := `INSERT INTO comment_reports
+			(reason, user_id, comment_id)
+			VALUES ($1, $2, $3)
+				RETURNING ` + reportColumns + `;`
+
+	var report ReportResponse
+	if err := db.QueryRow(query, body.Reason, userId, commentId).Scan(&report.ID, &report.Reason, &report.CreatedAt, &report.ResolvedAt, &report.Resolution, &report.UserId, &report.CommentId, &report.ResolverId); err != nil {
+		log.Printf("Error inserting report into database: %v\n", err)
+		return ReportResponse{}, err
+	}
+
+	return report, nil
+}
+
+// HasOpenReport tells if the user has a report on the comment that no admin acted on yet
+func (m *ModerationModel) HasOpenReport(db *sql.DB, userId uuid.UUID, commentId uuid.UUID) (bool, error) {
+	var found bool
+	query := `SELECT EXISTS (SELECT 1 FROM comment_reports WHERE user_id = $1 AND comment_id = $2 AND resolved_at IS NULL);`
+	if err := db.QueryRow(query, userId, commentId).Scan(&found); err != nil {
+		log.Printf("Error checking if user %v reported comment %v: %v\n", userId, commentId, err)
+		return false, err
+	}
+
+	return found, nil
+}
+
+func (m *ModerationModel) GetModerationQueue(db *sql.DB, page PageRequest, orderBy string) (Page[ModerationQueueItem], error) {
+	log.Printf("Getting moderation queue in DB, with page %+v and orderBy %v...\n", page, orderBy)
+
+	queryBuilder := NewSelect(commentColumns+", report_count, reasons, flagged_at", moderationQueueTable).
+		OrderBy(orderBy, moderationQueueSortColumns)
+
+	total, err := countRows(db, queryBuilder)
+	if err != nil {
+		log.Println("Error counting moderation queue in db:", err)
+		return Page[ModerationQueueItem]{}, err
+	}
+
+	query, args, err := queryBuilder.Page(page).Build()
+	if err != nil {
+		log.Println("Error building moderation queue query:", err)
+		return Page[ModerationQueueItem]{}, err
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		log.Println("Error getting moderation queue from db:", err)
+		return Page[ModerationQueueItem]{}, err
+	}
+	defer rows.Close()
+
+	var items []ModerationQueueItem
+	var keys []rowKey
+	for rows.Next() {
+		var item ModerationQueueItem
+		var key rowKey
+		comment := &item.Comment
+		if err := rows.Scan(&comment.ID, &comment.Comment, &comment.Grade, &comment.Depth, &comment.LikeCount, &comment.ReplyCount, &comment.Status, &comment.ContainsSpoilers, &comment.CreatedAt, &comment.UpdatedAt, &comment.DeletedAt, &comment.UserId, &comment.MovieId, &comment.ParentId, &item.ReportCount, pq.Array(&item.Reasons), &item.FlaggedAt, &key.value); err != nil {
+			log.Println("Error scanning moderation queue item from db:", err)
+			return Page[ModerationQueueItem]{}, err
+		}
+		key.id = comment.ID
+
+		items = append(items, item)
+		keys = append(keys, key)
+	}
+
+	return newPage(items, keys, page, orderBy, total), nil
+}
+
+// ModerateComment applies the action of the admin to the comment and resolves all of its open reports with it.
+// Approving makes the comment visible, hiding and banning hide it, and banning also bans its author.
+func (m *ModerationModel) ModerateComment(db *sql.DB, commentId uuid.UUID, resolverId uuid.UUID, action string) (CommentResponse, error) {
+	log.Printf("Moderating comment %s with action %s in DB...\n", commentId, action)
+
+	status := CommentStatusHidden
+	if action == ModerationApprove {
+		status = CommentStatusVisible
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction to moderate comment: %v\n", err)
+		return CommentResponse{}, err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE comments SET status = $1 WHERE id = $2 AND deleted_at IS NULL RETURNING ` + commentColumns + `;`
+
+	var comment CommentResponse
+	if err := tx.QueryRow(query, status, commentId).Scan(&comment.ID, &comment.Comment, &comment.Grade, &comment.Depth, &comment.LikeCount, &comment.ReplyCount, &comment.Status, &comment.ContainsSpoilers, &comment.CreatedAt, &comment.UpdatedAt, &comment.DeletedAt, &comment.UserId, &comment.MovieId, &comment.ParentId); err != nil {
+		log.Printf("Error updating status of comment: %v\n", err)
+		return CommentResponse{}, err
+	}
+
+	query = `UPDATE comment_reports
+		SET resolved_at = NOW(), resolution = $1, resolver_id = $2
+		WHERE comment_id = $3 AND resolved_at IS NULL;`
+
+	if _, err := tx.Exec(query, action, resolverId, commentId); err != nil {
+		log.Printf("Error resolving reports of comment: %v\n", err)
+		return CommentResponse{}, err
+	}
+
+	if action == ModerationBan {
+		authorId, err := uuid.Parse(comment.UserId)
+		if err != nil {
+			log.Printf("Error parsing author id of comment: %v\n", err)
+			return CommentResponse{}, err
+		}
+
+		body := BanBody{Reason: "Comment " + commentId.String() + " was moderated"}
+		if _, err := userModel.banUser(tx, resolverId, authorId, body); err != nil {
+			log.Printf("Error banning author of comment: %v\n", err)
+			return CommentResponse{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing transaction while moderating comment: %v\n", err)
+		return CommentResponse{}, err
+	}
+
+	return comment, nil
+}
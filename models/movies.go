@@ -2,34 +2,50 @@ package models
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"log"
-	"sort"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type MovieModel struct {
-	ID           uuid.UUID    `json:"id"`
-	Title        string       `json:"title"`
-	Director     string       `json:"director"`
-	ReleaseDate  string       `json:"releaseDate"`
-	AverageGrade float64      `json:"averageGrade"`
-	Picture      string       `json:"picture"`
-	Synopsis     string       `json:"synopsis"`
-	CreatedAt    time.Time    `json:"createdAt"`
-	UpdatedAt    time.Time    `json:"updatedAt"`
-	DeletedAt    sql.NullTime `json:"deletedAt"`
+	ID             uuid.UUID      `json:"id"`
+	Title          string         `json:"title"`
+	Director       string         `json:"director"`
+	ReleaseDate    string         `json:"releaseDate"`
+	AverageGrade   float64        `json:"averageGrade"`
+	RatingCount    int            `json:"ratingCount"`
+	GradeHistogram GradeHistogram `json:"gradeHistogram"`
+	WeightedRating float64        `json:"weightedRating"`
+	Picture        string         `json:"picture"`
+	Synopsis       string         `json:"synopsis"`
+	CreatedAt      time.Time      `json:"createdAt"`
+	UpdatedAt      time.Time      `json:"updatedAt"`
+	DeletedAt      sql.NullTime   `json:"deletedAt"`
 
 	CreatorId string         `json:"creatorId"`
 	Actors    []ActorModel   `json:"actors"`
 	Comments  []CommentModel `json:"comments"`
 }
 
+// GradeHistogram counts the reviews of a movie by grade, rounded to the nearest whole grade, from "1" to "5"
+type GradeHistogram map[string]int
+
+// Scan reads the JSONB histogram kept up to date by the refresh_movie_rating function of the database
+func (h *GradeHistogram) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("unexpected type %T for grade histogram", value)
+	}
+
+	return json.Unmarshal(bytes, h)
+}
+
 type MovieBody struct {
 	Title       string `json:"title" validate:"required"`
 	Director    string `json:"director" validate:"required"`
@@ -37,8 +53,10 @@ type MovieBody struct {
 	Picture     string `json:"picture" validate:"omitempty"`
 	Synopsis    string `json:"synopsis" validate:"omitempty"`
 
-	CreatorId string   `json:"creatorId" validate:"required,isadminuuid"`
-	Actors    []string `json:"actors" validate:"required,unique,validactorslice"`
+	CreatorId string        `json:"creatorId" validate:"required,isadminuuid"`
+	Actors    []CastingBody `json:"actors" validate:"required,unique=ActorId,validactorslice,dive"`
+	Genres    []string      `json:"genres" validate:"omitempty,unique,validgenreslice"`
+	Crew      []CrewBody    `json:"crew" validate:"omitempty,dive"`
 }
 
 type MovieEditBody struct {
@@ -50,38 +68,86 @@ type MovieEditBody struct {
 }
 
 type MovieActorsBody struct {
-	Actors []string `json:"actors" validate:"required,unique,validactorslice"`
+	Actors []CastingBody `json:"actors" validate:"required,unique=ActorId,validactorslice,dive"`
+}
+
+// CastingBody is one entry of a movie cast. A zero billing order bills the actor
+// after everyone already in the cast, in the order they were sent.
+type CastingBody struct {
+	ActorId       string `json:"actorId" validate:"required"`
+	CharacterName string `json:"characterName" validate:"omitempty,max=100"`
+	BillingOrder  int    `json:"billingOrder" validate:"omitempty,min=1"`
+}
+
+// CrewBody links a person to a movie with one of the crew roles
+type CrewBody struct {
+	PersonId string `json:"personId" validate:"required,ispersonuuid"`
+	Role     string `json:"role" validate:"required,oneof=director writer producer composer cinematographer"`
+}
+
+type MovieCrewBody struct {
+	Crew []CrewBody `json:"crew" validate:"required,min=1,dive"`
+}
+
+type CastingEditBody struct {
+	CharacterName string `json:"characterName" validate:"omitempty,max=100"`
+	BillingOrder  int    `json:"billingOrder" validate:"omitempty,min=1"`
+}
+
+type MovieGenresBody struct {
+	Genres []string `json:"genres" validate:"required,unique,validgenreslice"`
 }
 
 type MovieResponse struct {
-	ID           uuid.UUID    `json:"id"`
-	Title        string       `json:"title"`
-	Director     string       `json:"director"`
-	ReleaseDate  string       `json:"releaseDate"`
-	AverageGrade float64      `json:"averageGrade"`
-	Picture      string       `json:"picture"`
-	Synopsis     string       `json:"synopsis"`
-	CreatedAt    time.Time    `json:"createdAt"`
-	UpdatedAt    time.Time    `json:"updatedAt"`
-	DeletedAt    sql.NullTime `json:"deletedAt"`
+	ID             uuid.UUID      `json:"id"`
+	Title          string         `json:"title"`
+	Director       string         `json:"director"`
+	ReleaseDate    string         `json:"releaseDate"`
+	AverageGrade   float64        `json:"averageGrade"`
+	RatingCount    int            `json:"ratingCount"`
+	GradeHistogram GradeHistogram `json:"gradeHistogram"`
+	WeightedRating float64        `json:"weightedRating"`
+	Picture        string         `json:"picture"`
+	Synopsis       string         `json:"synopsis"`
+	CreatedAt      time.Time      `json:"createdAt"`
+	UpdatedAt      time.Time      `json:"updatedAt"`
+	DeletedAt      sql.NullTime   `json:"deletedAt"`
 
 	CreatorId string `json:"creatorId"`
 }
 
 type MovieResponseWithActors struct {
-	ID           uuid.UUID    `json:"id"`
-	Title        string       `json:"title"`
-	Director     string       `json:"director"`
-	ReleaseDate  string       `json:"releaseDate"`
-	AverageGrade float64      `json:"averageGrade"`
-	Picture      string       `json:"picture"`
-	Synopsis     string       `json:"synopsis"`
-	CreatedAt    time.Time    `json:"createdAt"`
-	UpdatedAt    time.Time    `json:"updatedAt"`
-	DeletedAt    sql.NullTime `json:"deletedAt"`
+	ID             uuid.UUID      `json:"id"`
+	Title          string         `json:"title"`
+	Director       string         `json:"director"`
+	ReleaseDate    string         `json:"releaseDate"`
+	AverageGrade   float64        `json:"averageGrade"`
+	RatingCount    int            `json:"ratingCount"`
+	GradeHistogram GradeHistogram `json:"gradeHistogram"`
+	WeightedRating float64        `json:"weightedRating"`
+	Picture        string         `json:"picture"`
+	Synopsis       string         `json:"synopsis"`
+	CreatedAt      time.Time      `json:"createdAt"`
+	UpdatedAt      time.Time      `json:"updatedAt"`
+	DeletedAt      sql.NullTime   `json:"deletedAt"`
+
+	CreatorId string            `json:"creatorId"`
+	Actors    []CastingResponse `json:"actors"`
+	Genres    []GenreResponse   `json:"genres"`
+	Crew      []CrewResponse    `json:"crew"`
+}
 
-	CreatorId string          `json:"creatorId"`
-	Actors    []ActorResponse `json:"actors"`
+// CrewResponse is a person with the role they had in a movie
+type CrewResponse struct {
+	PersonResponse
+	Role string `json:"role"`
+}
+
+// CastingResponse is an actor as billed in a movie
+type CastingResponse struct {
+	ActorResponse
+	CharacterName string `json:"characterName"`
+	BillingOrder  int    `json:"billingOrder"`
 }
 
 type MovieResponseWithActorsWithComments struct {
@@ -89,15 +155,41 @@ type MovieResponseWithActorsWithComments struct {
 	Comments []CommentResponse
 }
 
+// MovieFilters narrows the movies list. Zero values leave the filter out of the query.
+type MovieFilters struct {
+	ReleasedFrom string
+	ReleasedTo   string
+	MinGrade     *float64
+	MaxGrade     *float64
+	Director     string
+	Title        string
+	ActorId      uuid.UUID
+	GenreId      uuid.UUID
+	// Only movies with at least one review
+	RatedOnly bool
+	// Only movies reviewed in the last TrendingDays days, which can be sorted by review_velocity (reviews per day in the window)
+	TrendingDays int
+}
+
+// Columns the movies list can be sorted by
+var movieSortColumns = []string{"created_at", "updated_at", "title", "director", "release_date", "average_grade", "weighted_rating", "review_velocity"}
+
 var actorModel ActorModel
+var personModel PersonModel
 
 // Internal methods
-func (m *MovieModel) getActorsOfAMovie(db *sql.DB, movieID uuid.UUID) ([]ActorResponse, error) {
+// querier is satisfied by both *sql.DB and *sql.Tx, for reads that also run inside a transaction
+type querier interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (m *MovieModel) getActorsOfAMovie(db querier, movieID uuid.UUID) ([]CastingResponse, error) {
 	query := `SELECT 
-        a.id, a.name, a.surname, a.birthday, a.picture, a.created_at, a.updated_at, a.deleted_at, a.creator_id
+        a.id, a.name, a.surname, a.birthday, a.picture, a.created_at, a.updated_at, a.deleted_at, a.creator_id, a.person_id, ma.character_name, ma.billing_order
         FROM actors a
         	JOIN movies_actors ma ON a.id = ma.actor_id
-        		WHERE ma.movie_id = $1 AND a.deleted_at IS NULL;`
+        		WHERE ma.movie_id = $1 AND a.deleted_at IS NULL
+        			ORDER BY ma.billing_order ASC, a.name ASC, a.surname ASC;`
 
 	rows, err := db.Query(query, movieID)
 	if err != nil {
@@ -105,10 +197,10 @@ func (m *MovieModel) getActorsOfAMovie(db *sql.DB, movieID uuid.UUID) ([]ActorRe
 	}
 	defer rows.Close()
 
-	var actors []ActorResponse
+	var actors []CastingResponse
 	for rows.Next() {
-		var actor ActorResponse
-		if err := rows.Scan(&actor.ID, &actor.Name, &actor.Surname, &actor.Birthday, &actor.Picture, &actor.CreatedAt, &actor.UpdatedAt, &actor.DeletedAt, &actor.CreatorId); err != nil {
+		var actor CastingResponse
+		if err := rows.Scan(&actor.ID, &actor.Name, &actor.Surname, &actor.Birthday, &actor.Picture, &actor.CreatedAt, &actor.UpdatedAt, &actor.DeletedAt, &actor.CreatorId, &actor.PersonId, &actor.CharacterName, &actor.BillingOrder); err != nil {
 			return nil, err
 		}
 		actors = append(actors, actor)
@@ -117,6 +209,106 @@ func (m *MovieModel) getActorsOfAMovie(db *sql.DB, movieID uuid.UUID) ([]ActorRe
 	return actors, nil
 }
 
+func (m *MovieModel) getCrewOfAMovie(db querier, movieID uuid.UUID) ([]CrewResponse, error) {
+	query := `SELECT
+		p.id, p.name, COALESCE(TO_CHAR(p.birthday, 'YYYY-MM-DD'), ''), p.picture, p.biography, p.created_at, p.updated_at, p.deleted_at, p.creator_id, mc.role
+		FROM people p
+			JOIN movie_crew mc ON p.id = mc.person_id
+				WHERE mc.movie_id = $1 AND p.deleted_at IS NULL
+					ORDER BY mc.role ASC, p.name ASC;`
+
+	rows, err := db.Query(query, movieID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	crew := []CrewResponse{}
+	for rows.Next() {
+		var member CrewResponse
+		if err := rows.Scan(&member.ID, &member.Name, &member.Birthday, &member.Picture, &member.Biography, &member.CreatedAt, &member.UpdatedAt, &member.DeletedAt, &member.CreatorId, &member.Role); err != nil {
+			return nil, err
+		}
+		crew = append(crew, member)
+	}
+
+	return crew, nil
+}
+
+// insertCrew links every person of the body to the movie. ON CONFLICT skips credits the movie already has.
+func (m *MovieModel) insertCrew(tx *sql.Tx, movieID uuid.UUID, crew []CrewBody) error {
+	for _, member := range crew {
+		query := `INSERT INTO movie_crew (person_id, movie_id, role) VALUES ($1, $2, $3) ON CONFLICT (movie_id, person_id, role) DO NOTHING;`
+		if _, err := tx.Exec(query, member.PersonId, movieID, member.Role); err != nil {
+			log.Printf("Error associating person %v with movie as %v: %v\n", member.PersonId, member.Role, err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// insertCasting adds every actor of the body to the movie. Entries without a billing
+// order are billed after firstBillingOrder, following their position in the body.
+func (m *MovieModel) insertCasting(db *sql.DB, tx *sql.Tx, movieID uuid.UUID, castings []CastingBody, firstBillingOrder int) error {
+	for i, casting := range castings {
+		actorUUID, err := uuid.Parse(casting.ActorId)
+		if err != nil {
+			log.Printf("Error parsing actor id into uuid: %v\n", err)
+			return err
+		}
+
+		actorResponse, err := actorModel.GetActorById(db, actorUUID)
+		if err != nil {
+			log.Printf("Trying to associate non-existant actor %v to a movie: %v\n", actorUUID, err)
+			return err
+		}
+
+		if actorResponse.DeletedAt.Valid {
+			return fmt.Errorf("trying to insert deleted actor with ID %v and name %v into movie with ID %v", actorResponse.ID, actorResponse.Name, movieID)
+		}
+
+		billingOrder := casting.BillingOrder
+		if billingOrder == 0 {
+			billingOrder = firstBillingOrder + i + 1
+		}
+
+		query := `INSERT INTO movies_actors (actor_id, movie_id, character_name, billing_order) VALUES ($1, $2, $3, $4);`
+		if _, err := tx.Exec(query, actorUUID, movieID, casting.CharacterName, billingOrder); err != nil {
+			log.Printf("Error associating actor %v with movie: %v\n", actorUUID, err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *MovieModel) getGenresOfAMovie(db querier, movieID uuid.UUID) ([]GenreResponse, error) {
+	query := `SELECT
+		g.id, g.name, g.description, g.created_at, g.updated_at, g.deleted_at, g.creator_id
+		FROM genres g
+			JOIN movies_genres mg ON g.id = mg.genre_id
+				WHERE mg.movie_id = $1 AND g.deleted_at IS NULL
+					ORDER BY g.name ASC;`
+
+	rows, err := db.Query(query, movieID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	genres := []GenreResponse{}
+	for rows.Next() {
+		var genre GenreResponse
+		if err := rows.Scan(&genre.ID, &genre.Name, &genre.Description, &genre.CreatedAt, &genre.UpdatedAt, &genre.DeletedAt, &genre.CreatorId); err != nil {
+			return nil, err
+		}
+		genres = append(genres, genre)
+	}
+
+	return genres, nil
+}
+
 // Public methods
 func (m *MovieModel) InsertMovieInDB(db *sql.DB, movieInfo MovieBody) (MovieResponseWithActors, error) {
 	log.Printf("Inserting movie with title %s in DB by user %s...\n", movieInfo.Title, movieInfo.CreatorId)
@@ -132,81 +324,74 @@ func (m *MovieModel) InsertMovieInDB(db *sql.DB, movieInfo MovieBody) (MovieResp
 	query := `INSERT INTO movies
 			(title, director, release_date, picture, synopsis, creator_id)
 			VALUES ($1, $2, $3, $4, $5, $6)
-				RETURNING id, title, director, release_date, average_grade, picture, synopsis, created_at, updated_at, deleted_at, creator_id;`
+				RETURNING id, title, director, release_date, average_grade, picture, synopsis, created_at, updated_at, deleted_at, creator_id, rating_count, grade_histogram, weighted_rating;`
 
 	var movie MovieResponseWithActors
-	err = tx.QueryRow(query, movieInfo.Title, movieInfo.Director, movieInfo.ReleaseDate, movieInfo.Picture, movieInfo.Synopsis, movieInfo.CreatorId).Scan(&movie.ID, &movie.Title, &movie.Director, &movie.ReleaseDate, &movie.AverageGrade, &movie.Picture, &movie.Synopsis, &movie.CreatedAt, &movie.UpdatedAt, &movie.DeletedAt, &movie.CreatorId)
+	err = tx.QueryRow(query, movieInfo.Title, movieInfo.Director, movieInfo.ReleaseDate, movieInfo.Picture, movieInfo.Synopsis, movieInfo.CreatorId).Scan(&movie.ID, &movie.Title, &movie.Director, &movie.ReleaseDate, &movie.AverageGrade, &movie.Picture, &movie.Synopsis, &movie.CreatedAt, &movie.UpdatedAt, &movie.DeletedAt, &movie.CreatorId, &movie.RatingCount, &movie.GradeHistogram, &movie.WeightedRating)
 	if err != nil {
 		log.Printf("Error inserting movie into database: %v\n", err)
 		return MovieResponseWithActors{}, err
 	}
 
 	// Associate actors with the movie in the pivot table
-	var wg sync.WaitGroup
-	errCh := make(chan error, len(movieInfo.Actors))
-	actorInfoCh := make(chan ActorResponse, len(movieInfo.Actors))
-	actorIndexMap := make(map[uuid.UUID]int)
-	for i, actorID := range movieInfo.Actors {
-		actorUUID, err := uuid.Parse(actorID)
-		if err != nil {
-			log.Printf("Error parsing actor id into uuid: %v\n", err)
+	if err := m.insertCasting(db, tx, movie.ID, movieInfo.Actors, 0); err != nil {
+		log.Printf("Error inserting the cast of movie %v: %v\n", movie.Title, err)
+		return MovieResponseWithActors{}, err
+	}
+
+	actors, err := m.getActorsOfAMovie(tx, movie.ID)
+	if err != nil {
+		log.Printf("Error getting actors of inserted movie %v: %v\n", movie.Title, err)
+		return MovieResponseWithActors{}, err
+	}
+	movie.Actors = actors
+
+	// Genres are tagged in the same transaction, the validator already made sure they exist
+	for _, genreID := range movieInfo.Genres {
+		query := `INSERT INTO movies_genres (genre_id, movie_id) VALUES ($1, $2);`
+		if _, err := tx.Exec(query, genreID, movie.ID); err != nil {
+			log.Printf("Error associating genre %v with movie: %v\n", genreID, err)
 			return MovieResponseWithActors{}, err
 		}
+	}
+
+	// Movies created without a director in the crew get the person named in the director field,
+	// so every movie keeps showing up in the filmography of its director
+	crew := movieInfo.Crew
+	hasDirector := false
+	for _, member := range crew {
+		if member.Role == CrewRoleDirector {
+			hasDirector = true
+			break
+		}
+	}
 
-		wg.Add(1)
-		actorIndexMap[actorUUID] = i
-		go func(actorUUID uuid.UUID) {
-			defer wg.Done()
-
-			actorResponse, err := actorModel.GetActorById(db, actorUUID)
-			if err != nil {
-				log.Printf("Trying to associate non-existant actor %v to a movie: %v\n", actorUUID, err)
-				errCh <- err
-				return
-			}
-
-			if actorResponse.DeletedAt.Valid {
-				errCh <- fmt.Errorf("trying to insert deleted actor with ID %v and name %v into movie with title %v", actorResponse.ID, actorResponse.Name, movieInfo.Title)
-				return
-			}
-			actorInfoCh <- actorResponse
-
-			query := `INSERT INTO movies_actors (actor_id, movie_id) VALUES ($1, $2)`
-			_, err = tx.Exec(query, actorUUID, movie.ID)
-			if err != nil {
-				log.Printf("Error associating actor %v with movie: %v\n", actorUUID, err)
-				errCh <- err
-				return
-			}
-		}(actorUUID)
-	}
-
-	wg.Wait()
-	close(errCh)
-	close(actorInfoCh)
-
-	for err := range errCh {
+	if !hasDirector {
+		directorId, err := personModel.getOrInsertPersonByName(tx, movieInfo.Director, movieInfo.CreatorId)
 		if err != nil {
-			log.Printf("Error in insertion goroutine: %v", err)
-			tx.Rollback()
+			log.Printf("Error getting director %v of movie %v: %v\n", movieInfo.Director, movie.Title, err)
 			return MovieResponseWithActors{}, err
 		}
+		crew = append(crew, CrewBody{PersonId: directorId.String(), Role: CrewRoleDirector})
 	}
 
-	// Getting the actors of the movie to fill the Actors slice and sorting the returning slice
-	var actorResponses []ActorResponse
-	for actorResp := range actorInfoCh {
-		actorResponses = append(actorResponses, actorResp)
+	if err := m.insertCrew(tx, movie.ID, crew); err != nil {
+		log.Printf("Error inserting the crew of movie %v: %v\n", movie.Title, err)
+		return MovieResponseWithActors{}, err
 	}
 
-	sort.Slice(actorResponses, func(i, j int) bool {
-		indexI := actorIndexMap[actorResponses[i].ID]
-		indexJ := actorIndexMap[actorResponses[j].ID]
-
-		return indexI < indexJ
-	})
+	movie.Crew, err = m.getCrewOfAMovie(tx, movie.ID)
+	if err != nil {
+		log.Printf("Error getting crew of inserted movie %v: %v\n", movie.Title, err)
+		return MovieResponseWithActors{}, err
+	}
 
-	movie.Actors = actorResponses
+	genres, err := m.getGenresOfAMovie(tx, movie.ID)
+	if err != nil {
+		log.Printf("Error getting genres of inserted movie %v: %v\n", movie.Title, err)
+		return MovieResponseWithActors{}, err
+	}
+	movie.Genres = genres
 
 	if err := tx.Commit(); err != nil {
 		log.Printf("Error committing transaction while inserting movies in DB: %v\n", err)
@@ -216,92 +401,186 @@ func (m *MovieModel) InsertMovieInDB(db *sql.DB, movieInfo MovieBody) (MovieResp
 	return movie, nil
 }
 
-func (m *MovieModel) GetAllMovies(db *sql.DB, offset, limit int, orderBy string, deleted bool) ([]MovieResponse, error) {
-	log.Printf("Getting all movies in DB, with offset %v, limit %v, orderBy %v, no actors and deleted %v...\n", offset, limit, orderBy, deleted)
+// moviesListQuery builds the query shared by both movie listings, with or without actors.
+func (m *MovieModel) moviesListQuery(orderBy string, deleted bool, filters MovieFilters) *SelectBuilder {
+	from := "movies"
+	if filters.TrendingDays > 0 {
+		// The window is an int, so formatting it into the query is safe
+		from = `(SELECT m.*, COALESCE(recent.reviews, 0)::decimal / ` + strconv.Itoa(filters.TrendingDays) + ` AS review_velocity
+			FROM movies m
+				LEFT JOIN (
+					SELECT movie_id, COUNT(*) AS reviews
+						FROM comments
+							WHERE grade IS NOT NULL AND deleted_at IS NULL AND status = 'visible' AND created_at >= NOW() - INTERVAL '1 day' * ` + strconv.Itoa(filters.TrendingDays) + `
+								GROUP BY movie_id
+				) AS recent ON recent.movie_id = m.id
+		) AS movies`
+	}
 
-	var getMoviesQueryBuilder strings.Builder
-	getMoviesQueryBuilder.WriteString(`SELECT
-	id, title, director, release_date, average_grade, picture, synopsis, created_at, updated_at, deleted_at, creator_id 
-	FROM movies`)
+	query := NewSelect("id, title, director, release_date, average_grade, picture, synopsis, created_at, updated_at, deleted_at, creator_id, rating_count, grade_histogram, weighted_rating", from)
 
 	if !deleted {
-		getMoviesQueryBuilder.WriteString(" WHERE deleted_at IS NULL")
+		query.Where("deleted_at IS NULL")
+	}
+
+	if filters.RatedOnly {
+		query.Where("rating_count > 0")
+	}
+
+	if filters.TrendingDays > 0 {
+		query.Where("review_velocity > 0")
+	}
+
+	if filters.ReleasedFrom != "" {
+		query.Where("release_date >= ?", filters.ReleasedFrom)
+	}
+
+	if filters.ReleasedTo != "" {
+		query.Where("release_date <= ?", filters.ReleasedTo)
+	}
+
+	if filters.MinGrade != nil {
+		query.Where("average_grade >= ?", *filters.MinGrade)
+	}
+
+	if filters.MaxGrade != nil {
+		query.Where("average_grade <= ?", *filters.MaxGrade)
+	}
+
+	if filters.Director != "" {
+		query.Where("director ILIKE ?", containsPattern(filters.Director))
 	}
 
-	getMoviesQueryBuilder.WriteString(" ORDER BY " + orderBy + " OFFSET $1 LIMIT $2;")
+	if filters.Title != "" {
+		query.Where("title ILIKE ?", containsPattern(filters.Title))
+	}
+
+	if filters.ActorId != uuid.Nil {
+		query.Where("EXISTS (SELECT 1 FROM movies_actors ma WHERE ma.movie_id = movies.id AND ma.actor_id = ?)", filters.ActorId)
+	}
+
+	if filters.GenreId != uuid.Nil {
+		query.Where("EXISTS (SELECT 1 FROM movies_genres mg WHERE mg.movie_id = movies.id AND mg.genre_id = ?)", filters.GenreId)
+	}
+
+	return query.OrderBy(orderBy, movieSortColumns)
+}
+
+func (m *MovieModel) GetAllMovies(db *sql.DB, page PageRequest, orderBy string, deleted bool, filters MovieFilters) (Page[MovieResponse], error) {
+	log.Printf("Getting all movies in DB, with page %+v, orderBy %v, no actors, deleted %v and filters %+v...\n", page, orderBy, deleted, filters)
 
-	query := getMoviesQueryBuilder.String()
-	rows, err := db.Query(query, offset, limit)
+	queryBuilder := m.moviesListQuery(orderBy, deleted, filters)
+	total, err := countRows(db, queryBuilder)
+	if err != nil {
+		log.Println("Error counting all movies in db:", err)
+		return Page[MovieResponse]{}, err
+	}
+
+	query, args, err := queryBuilder.Page(page).Build()
+	if err != nil {
+		log.Println("Error building all movies query:", err)
+		return Page[MovieResponse]{}, err
+	}
+
+	rows, err := db.Query(query, args...)
 	if err != nil {
 		log.Println("Error getting all movies from db without actors:", err)
-		return nil, err
+		return Page[MovieResponse]{}, err
 	}
 	defer rows.Close()
 
 	var movies []MovieResponse
+	var keys []rowKey
 	for rows.Next() {
 		var movie MovieResponse
-		if err := rows.Scan(&movie.ID, &movie.Title, &movie.Director, &movie.ReleaseDate, &movie.AverageGrade, &movie.Picture, &movie.Synopsis, &movie.CreatedAt, &movie.UpdatedAt, &movie.DeletedAt, &movie.CreatorId); err != nil {
-			return nil, err
+		var key rowKey
+		if err := rows.Scan(&movie.ID, &movie.Title, &movie.Director, &movie.ReleaseDate, &movie.AverageGrade, &movie.Picture, &movie.Synopsis, &movie.CreatedAt, &movie.UpdatedAt, &movie.DeletedAt, &movie.CreatorId, &movie.RatingCount, &movie.GradeHistogram, &movie.WeightedRating, &key.value); err != nil {
+			return Page[MovieResponse]{}, err
 		}
+		key.id = movie.ID
+
 		movies = append(movies, movie)
+		keys = append(keys, key)
 	}
 
-	return movies, nil
+	return newPage(movies, keys, page, orderBy, total), nil
 }
 
-func (m *MovieModel) GetAllMoviesWithActors(db *sql.DB, offset, limit int, orderBy string, deleted bool) ([]MovieResponseWithActors, error) {
-	log.Printf("Getting all movies in DB, with offset %v, limit %v, orderBy %v, with actors and deleted %v...\n", offset, limit, orderBy, deleted)
-
-	var getMoviesQueryBuilder strings.Builder
-	getMoviesQueryBuilder.WriteString(`SELECT
-	id, title, director, release_date, average_grade, picture, synopsis, created_at, updated_at, deleted_at, creator_id 
-	FROM movies`)
+func (m *MovieModel) GetAllMoviesWithActors(db *sql.DB, page PageRequest, orderBy string, deleted bool, filters MovieFilters) (Page[MovieResponseWithActors], error) {
+	log.Printf("Getting all movies in DB, with page %+v, orderBy %v, with actors, deleted %v and filters %+v...\n", page, orderBy, deleted, filters)
 
-	if !deleted {
-		getMoviesQueryBuilder.WriteString(" WHERE deleted_at IS NULL")
+	queryBuilder := m.moviesListQuery(orderBy, deleted, filters)
+	total, err := countRows(db, queryBuilder)
+	if err != nil {
+		log.Println("Error counting all movies in db:", err)
+		return Page[MovieResponseWithActors]{}, err
 	}
 
-	getMoviesQueryBuilder.WriteString(" ORDER BY " + orderBy + " OFFSET $1 LIMIT $2;")
+	query, args, err := queryBuilder.Page(page).Build()
+	if err != nil {
+		log.Println("Error building all movies query:", err)
+		return Page[MovieResponseWithActors]{}, err
+	}
 
-	query := getMoviesQueryBuilder.String()
-	rows, err := db.Query(query, offset, limit)
+	rows, err := db.Query(query, args...)
 	if err != nil {
-		log.Println("Error getting all movies from db without actors:", err)
-		return nil, err
+		log.Println("Error getting all movies from db with actors:", err)
+		return Page[MovieResponseWithActors]{}, err
 	}
 	defer rows.Close()
 
 	var movies []MovieResponseWithActors
+	var keys []rowKey
 	for rows.Next() {
 		var movie MovieResponseWithActors
-		if err := rows.Scan(&movie.ID, &movie.Title, &movie.Director, &movie.ReleaseDate, &movie.AverageGrade, &movie.Picture, &movie.Synopsis, &movie.CreatedAt, &movie.UpdatedAt, &movie.DeletedAt, &movie.CreatorId); err != nil {
-			return nil, err
+		var key rowKey
+		if err := rows.Scan(&movie.ID, &movie.Title, &movie.Director, &movie.ReleaseDate, &movie.AverageGrade, &movie.Picture, &movie.Synopsis, &movie.CreatedAt, &movie.UpdatedAt, &movie.DeletedAt, &movie.CreatorId, &movie.RatingCount, &movie.GradeHistogram, &movie.WeightedRating, &key.value); err != nil {
+			return Page[MovieResponseWithActors]{}, err
 		}
+		key.id = movie.ID
 
-		actors, err := m.getActorsOfAMovie(db, movie.ID)
+		movies = append(movies, movie)
+		keys = append(keys, key)
+	}
+	rows.Close()
+
+	// Actors are fetched after the rows are closed so the page doesn't hold two connections per movie
+	for i := range movies {
+		actors, err := m.getActorsOfAMovie(db, movies[i].ID)
 		if err != nil {
-			log.Printf("Error getting actors of movie %v, %v", movie.Title, err)
-			return nil, err
+			log.Printf("Error getting actors of movie %v, %v", movies[i].Title, err)
+			return Page[MovieResponseWithActors]{}, err
 		}
-		movie.Actors = actors
+		movies[i].Actors = actors
 
-		movies = append(movies, movie)
+		genres, err := m.getGenresOfAMovie(db, movies[i].ID)
+		if err != nil {
+			log.Printf("Error getting genres of movie %v, %v", movies[i].Title, err)
+			return Page[MovieResponseWithActors]{}, err
+		}
+		movies[i].Genres = genres
+
+		crew, err := m.getCrewOfAMovie(db, movies[i].ID)
+		if err != nil {
+			log.Printf("Error getting crew of movie %v, %v", movies[i].Title, err)
+			return Page[MovieResponseWithActors]{}, err
+		}
+		movies[i].Crew = crew
 	}
 
-	return movies, nil
+	return newPage(movies, keys, page, orderBy, total), nil
 }
 
 func (m *MovieModel) GetMovieByTitle(db *sql.DB, title string) (MovieModel, error) {
 	log.Printf("Getting movie with title %s in DB... \n", title)
 
 	query := `SELECT 
-		id, title, director, release_date, average_grade, picture, synopsis, created_at, updated_at, deleted_at, creator_id 
+		id, title, director, release_date, average_grade, picture, synopsis, created_at, updated_at, deleted_at, creator_id, rating_count, grade_histogram, weighted_rating 
 		FROM movies 
 			WHERE title = $1;`
 
 	var movie MovieModel
-	err := db.QueryRow(query, title).Scan(&movie.ID, &movie.Title, &movie.Director, &movie.ReleaseDate, &movie.AverageGrade, &movie.Picture, &movie.Synopsis, &movie.CreatedAt, &movie.UpdatedAt, &movie.DeletedAt, &movie.CreatorId)
+	err := db.QueryRow(query, title).Scan(&movie.ID, &movie.Title, &movie.Director, &movie.ReleaseDate, &movie.AverageGrade, &movie.Picture, &movie.Synopsis, &movie.CreatedAt, &movie.UpdatedAt, &movie.DeletedAt, &movie.CreatorId, &movie.RatingCount, &movie.GradeHistogram, &movie.WeightedRating)
 	if err != nil {
 		log.Printf("Error getting movie by title: %v\n", err)
 		return MovieModel{}, err
@@ -314,12 +593,12 @@ func (m *MovieModel) GetMovieByIdWithActors(db *sql.DB, uuid uuid.UUID) (MovieRe
 	log.Printf("Getting movie with id %s in DB... \n", uuid)
 
 	query := `SELECT 
-		id, title, director, release_date, average_grade, picture, synopsis, created_at, updated_at, deleted_at, creator_id 
+		id, title, director, release_date, average_grade, picture, synopsis, created_at, updated_at, deleted_at, creator_id, rating_count, grade_histogram, weighted_rating 
 		FROM movies 
 			WHERE id = $1;`
 
 	var movie MovieResponseWithActors
-	err := db.QueryRow(query, uuid).Scan(&movie.ID, &movie.Title, &movie.Director, &movie.ReleaseDate, &movie.AverageGrade, &movie.Picture, &movie.Synopsis, &movie.CreatedAt, &movie.UpdatedAt, &movie.DeletedAt, &movie.CreatorId)
+	err := db.QueryRow(query, uuid).Scan(&movie.ID, &movie.Title, &movie.Director, &movie.ReleaseDate, &movie.AverageGrade, &movie.Picture, &movie.Synopsis, &movie.CreatedAt, &movie.UpdatedAt, &movie.DeletedAt, &movie.CreatorId, &movie.RatingCount, &movie.GradeHistogram, &movie.WeightedRating)
 	if err != nil {
 		log.Printf("Error getting movie by id: %v\n", err)
 		return MovieResponseWithActors{}, err
@@ -332,9 +611,38 @@ func (m *MovieModel) GetMovieByIdWithActors(db *sql.DB, uuid uuid.UUID) (MovieRe
 	}
 	movie.Actors = actors
 
+	genres, err := m.getGenresOfAMovie(db, uuid)
+	if err != nil {
+		log.Printf("Error getting genres of movie %v, %v", movie.Title, err)
+		return MovieResponseWithActors{}, err
+	}
+	movie.Genres = genres
+
+	crew, err := m.getCrewOfAMovie(db, uuid)
+	if err != nil {
+		log.Printf("Error getting crew of movie %v, %v", movie.Title, err)
+		return MovieResponseWithActors{}, err
+	}
+	movie.Crew = crew
+
 	return movie, nil
 }
 
+// RecomputeMoviesRatings rebuilds the rating stats of every movie from its reviews and returns how many movies were recomputed
+func (m *MovieModel) RecomputeMoviesRatings(db *sql.DB) (int, error) {
+	log.Println("Recomputing rating stats of all movies in DB...")
+
+	query := `SELECT COUNT(*) FROM (SELECT refresh_movie_rating(id) FROM movies) AS refreshed;`
+
+	var count int
+	if err := db.QueryRow(query).Scan(&count); err != nil {
+		log.Printf("Error recomputing rating stats of movies: %v\n", err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
 func (m *MovieModel) DeleteMovieById(db *sql.DB, uuid uuid.UUID) error {
 	log.Printf("Deleting movie with uuid %s in DB... \n", uuid)
 
@@ -392,11 +700,11 @@ func (m *MovieModel) UpdateMovieById(db *sql.DB, uuid uuid.UUID, body MovieEditB
 
 	updateQueryBuilder.WriteString("updated_at = CURRENT_TIMESTAMP, ")
 	query := strings.TrimSuffix(updateQueryBuilder.String(), ", ")
-	query += " WHERE id = $" + strconv.Itoa(argIndex) + " AND deleted_at IS NULL RETURNING id, title, director, release_date, average_grade, picture, synopsis, created_at, updated_at, deleted_at, creator_id;"
+	query += " WHERE id = $" + strconv.Itoa(argIndex) + " AND deleted_at IS NULL RETURNING id, title, director, release_date, average_grade, picture, synopsis, created_at, updated_at, deleted_at, creator_id, rating_count, grade_histogram, weighted_rating;"
 	args = append(args, uuid)
 
 	var movie MovieResponse
-	if err := db.QueryRow(query, args...).Scan(&movie.ID, &movie.Title, &movie.Director, &movie.ReleaseDate, &movie.AverageGrade, &movie.Picture, &movie.Synopsis, &movie.CreatedAt, &movie.UpdatedAt, &movie.DeletedAt, &movie.CreatorId); err != nil {
+	if err := db.QueryRow(query, args...).Scan(&movie.ID, &movie.Title, &movie.Director, &movie.ReleaseDate, &movie.AverageGrade, &movie.Picture, &movie.Synopsis, &movie.CreatedAt, &movie.UpdatedAt, &movie.DeletedAt, &movie.CreatorId, &movie.RatingCount, &movie.GradeHistogram, &movie.WeightedRating); err != nil {
 		log.Printf("Error updating movie by uuid: %v \n", err)
 		return MovieResponse{}, nil
 	}
@@ -407,54 +715,28 @@ func (m *MovieModel) UpdateMovieById(db *sql.DB, uuid uuid.UUID, body MovieEditB
 func (m *MovieModel) InsertActorsRelationshipsWithMovie(db *sql.DB, id uuid.UUID, body MovieActorsBody) error {
 	log.Printf("Associating actors to movie with uuid %s in DB... \n", id)
 
-	var wg sync.WaitGroup
-	errCh := make(chan error, len(body.Actors))
-	actorInfoCh := make(chan ActorResponse, len(body.Actors))
-	for _, actorID := range body.Actors {
-		actorUUID, err := uuid.Parse(actorID)
-		if err != nil {
-			log.Printf("Error parsing actor id into uuid: %v\n", err)
-			return err
-		}
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction to associate actors with movie: %v\n", err)
+		return err
+	}
+	defer tx.Rollback()
 
-		wg.Add(1)
-		go func(actorUUID uuid.UUID) {
-			defer wg.Done()
-
-			// This seems like error handling, but it's actually necessary because we
-			// only have the uuid and we need the full info of the actor.
-			actorResponse, err := actorModel.GetActorById(db, actorUUID)
-			if err != nil {
-				log.Printf("Trying to associate non-existant actor %v to a movie: %v\n", actorUUID, err)
-				errCh <- err
-				return
-			}
-
-			if actorResponse.DeletedAt.Valid {
-				errCh <- fmt.Errorf("trying to insert deleted actor with ID %v and name %v into movie with id %v", actorResponse.ID, actorResponse.Name, id)
-				return
-			}
-			actorInfoCh <- actorResponse
-
-			query := `INSERT INTO movies_actors (actor_id, movie_id) VALUES ($1, $2);`
-			_, err = db.Exec(query, actorUUID, id)
-			if err != nil {
-				log.Printf("Error associating actor %v with movie: %v\n", actorUUID, err)
-				errCh <- err
-				return
-			}
-		}(actorUUID)
-	}
-
-	wg.Wait()
-	close(errCh)
-	close(actorInfoCh)
-
-	for err := range errCh {
-		if err != nil {
-			log.Printf("Error in insertion goroutine: %v", err)
-			return err
-		}
+	// New actors are billed after the current cast unless the body says otherwise
+	var lastBillingOrder int
+	if err := tx.QueryRow(`SELECT COALESCE(MAX(billing_order), 0) FROM movies_actors WHERE movie_id = $1;`, id).Scan(&lastBillingOrder); err != nil {
+		log.Printf("Error getting last billing order of movie %v: %v\n", id, err)
+		return err
+	}
+
+	if err := m.insertCasting(db, tx, id, body.Actors, lastBillingOrder); err != nil {
+		log.Printf("Error associating actors with movie %v: %v\n", id, err)
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing transaction while associating actors with movie: %v\n", err)
+		return err
 	}
 
 	return nil
@@ -463,55 +745,144 @@ func (m *MovieModel) InsertActorsRelationshipsWithMovie(db *sql.DB, id uuid.UUID
 func (m *MovieModel) DeleteActorsRelationshipsWithMovie(db *sql.DB, id uuid.UUID, body MovieActorsBody) error {
 	log.Printf("Deleting actors associated movie with uuid %s in DB... \n", id)
 
-	var wg sync.WaitGroup
-	errCh := make(chan error, len(body.Actors))
-	actorInfoCh := make(chan ActorResponse, len(body.Actors))
-	for _, actorID := range body.Actors {
-		actorUUID, err := uuid.Parse(actorID)
-		if err != nil {
-			log.Printf("Error parsing actor id into uuid: %v\n", err)
+	var actorIds []string
+	for _, casting := range body.Actors {
+		actorIds = append(actorIds, casting.ActorId)
+	}
+
+	query := `DELETE FROM movies_actors WHERE movie_id = $1 AND actor_id = ANY($2);`
+	if _, err := db.Exec(query, id, pq.Array(actorIds)); err != nil {
+		log.Printf("Error deleting actors from movie: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
+func (m *MovieModel) UpdateCastingOfMovie(db *sql.DB, movieID, actorID uuid.UUID, body CastingEditBody) (CastingResponse, error) {
+	log.Printf("Updating casting of actor %s in movie with uuid %s in DB... \n", actorID, movieID)
+
+	var updateQueryBuilder strings.Builder
+	var args []interface{}
+
+	updateQueryBuilder.WriteString("UPDATE movies_actors SET ")
+
+	argIndex := 1
+	if body.CharacterName != "" {
+		updateQueryBuilder.WriteString("character_name = $" + strconv.Itoa(argIndex) + ", ")
+		args = append(args, body.CharacterName)
+		argIndex++
+	}
+
+	if body.BillingOrder != 0 {
+		updateQueryBuilder.WriteString("billing_order = $" + strconv.Itoa(argIndex) + ", ")
+		args = append(args, body.BillingOrder)
+		argIndex++
+	}
+
+	query := strings.TrimSuffix(updateQueryBuilder.String(), ", ")
+	query += " WHERE movie_id = $" + strconv.Itoa(argIndex) + " AND actor_id = $" + strconv.Itoa(argIndex+1) + " RETURNING character_name, billing_order;"
+	args = append(args, movieID, actorID)
+
+	var casting CastingResponse
+	if err := db.QueryRow(query, args...).Scan(&casting.CharacterName, &casting.BillingOrder); err != nil {
+		log.Printf("Error updating casting of movie: %v \n", err)
+		return CastingResponse{}, err
+	}
+
+	actorResponse, err := actorModel.GetActorById(db, actorID)
+	if err != nil {
+		log.Printf("Error getting actor of updated casting: %v \n", err)
+		return CastingResponse{}, err
+	}
+	casting.ActorResponse = actorResponse
+
+	return casting, nil
+}
+
+func (m *MovieModel) InsertGenresRelationshipsWithMovie(db *sql.DB, id uuid.UUID, body MovieGenresBody) error {
+	log.Printf("Associating genres to movie with uuid %s in DB... \n", id)
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction to associate genres with movie: %v\n", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, genreID := range body.Genres {
+		query := `INSERT INTO movies_genres (genre_id, movie_id) VALUES ($1, $2);`
+		if _, err := tx.Exec(query, genreID, id); err != nil {
+			log.Printf("Error associating genre %v with movie: %v\n", genreID, err)
 			return err
 		}
+	}
 
-		wg.Add(1)
-		go func(actorUUID uuid.UUID) {
-			defer wg.Done()
-
-			// This seems like error handling, but it's actually necessary because we
-			// only have the uuid and we need the full info of the actor.
-			actorResponse, err := actorModel.GetActorById(db, actorUUID)
-			if err != nil {
-				log.Printf("Trying to delete non-existant actor %v from movie with ID %v\n", actorUUID, err)
-				errCh <- err
-				return
-			}
-
-			if actorResponse.DeletedAt.Valid {
-				errCh <- fmt.Errorf("trying to delete actor with ID %v and name %v from movie with ID %v", actorResponse.ID, actorResponse.Name, id)
-				return
-			}
-			actorInfoCh <- actorResponse
-
-			query := `DELETE FROM movies_actors WHERE actor_id = $1;`
-			_, err = db.Exec(query, actorUUID)
-			if err != nil {
-				log.Printf("Error deleting actor %v from movie: %v\n", actorUUID, err)
-				errCh <- err
-				return
-			}
-		}(actorUUID)
-	}
-
-	wg.Wait()
-	close(errCh)
-	close(actorInfoCh)
-
-	for err := range errCh {
-		if err != nil {
-			log.Printf("Error in insertion goroutine: %v", err)
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing transaction while associating genres with movie: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
+func (m *MovieModel) DeleteGenresRelationshipsWithMovie(db *sql.DB, id uuid.UUID, body MovieGenresBody) error {
+	log.Printf("Deleting genres associated with movie with uuid %s in DB... \n", id)
+
+	query := `DELETE FROM movies_genres WHERE movie_id = $1 AND genre_id = ANY($2);`
+	if _, err := db.Exec(query, id, pq.Array(body.Genres)); err != nil {
+		log.Printf("Error deleting genres from movie: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
+func (m *MovieModel) InsertCrewRelationshipsWithMovie(db *sql.DB, id uuid.UUID, body MovieCrewBody) error {
+	log.Printf("Associating crew to movie with uuid %s in DB... \n", id)
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction to associate crew with movie: %v\n", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := m.insertCrew(tx, id, body.Crew); err != nil {
+		log.Printf("Error associating crew with movie %v: %v\n", id, err)
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing transaction while associating crew with movie: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
+func (m *MovieModel) DeleteCrewRelationshipsWithMovie(db *sql.DB, id uuid.UUID, body MovieCrewBody) error {
+	log.Printf("Deleting crew associated with movie with uuid %s in DB... \n", id)
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction to delete crew from movie: %v\n", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, member := range body.Crew {
+		query := `DELETE FROM movie_crew WHERE movie_id = $1 AND person_id = $2 AND role = $3;`
+		if _, err := tx.Exec(query, id, member.PersonId, member.Role); err != nil {
+			log.Printf("Error deleting person %v with role %v from movie: %v\n", member.PersonId, member.Role, err)
 			return err
 		}
 	}
 
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing transaction while deleting crew from movie: %v\n", err)
+		return err
+	}
+
 	return nil
 }
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// PageRequest is the pagination asked by a list route. When Cursor is set, Offset is ignored.
+type PageRequest struct {
+	Offset int
+	Limit  int
+	Cursor *Cursor
+}
+
+// Cursor points to the row a page starts after (or before, when going backwards).
+// It carries the sort it was created for, so it can't be used with a different one.
+type Cursor struct {
+	Sort      string    `json:"s"`
+	Value     string    `json:"v"`
+	ID        uuid.UUID `json:"i"`
+	Backwards bool      `json:"b,omitempty"`
+}
+
+// Cursors are opaque for clients, they only need to send them back as received
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(encoded string) (Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("error decoding cursor: %v", err)
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return Cursor{}, fmt.Errorf("error unmarshalling cursor: %v", err)
+	}
+
+	if cursor.Sort == "" || cursor.ID == uuid.Nil {
+		return Cursor{}, fmt.Errorf("cursor is missing its sort or id")
+	}
+
+	return cursor, nil
+}
+
+type Pagination struct {
+	Total int    `json:"total"`
+	Limit int    `json:"limit"`
+	Next  string `json:"next,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+}
+
+// Page is the envelope returned by every list route
+type Page[T any] struct {
+	Data       []T        `json:"data"`
+	Pagination Pagination `json:"pagination"`
+}
+
+// rowKey is the sort value and id of a fetched row, which is everything a cursor needs
+type rowKey struct {
+	value string
+	id    uuid.UUID
+}
+
+// newPage trims the extra row fetched by SelectBuilder.Page, which only tells if there is another page
+// in the direction being read, and creates the cursors around the remaining rows.
+func newPage[T any](items []T, keys []rowKey, request PageRequest, orderBy string, total int) Page[T] {
+	backwards := request.Cursor != nil && request.Cursor.Backwards
+
+	limit := max(request.Limit, 0)
+	hasMore := len(items) > limit
+	if hasMore {
+		items = items[:limit]
+		keys = keys[:limit]
+	}
+
+	hasNext, hasPrev := hasMore, request.Cursor != nil || request.Offset > 0
+	if backwards {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+			keys[i], keys[j] = keys[j], keys[i]
+		}
+		hasNext, hasPrev = true, hasMore
+	}
+
+	page := Page[T]{
+		Data:       items,
+		Pagination: Pagination{Total: total, Limit: request.Limit},
+	}
+
+	if page.Data == nil {
+		page.Data = []T{}
+	}
+
+	if len(keys) > 0 {
+		if hasNext {
+			last := keys[len(keys)-1]
+			page.Pagination.Next = Cursor{Sort: orderBy, Value: last.value, ID: last.id}.Encode()
+		}
+
+		if hasPrev {
+			first := keys[0]
+			page.Pagination.Prev = Cursor{Sort: orderBy, Value: first.value, ID: first.id, Backwards: true}.Encode()
+		}
+	}
+
+	return page
+}
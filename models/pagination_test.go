@@ -0,0 +1,58 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CursorEncoding(t *testing.T) {
+	cursor := Cursor{Sort: "title ASC", Value: "Alien", ID: uuid.New(), Backwards: true}
+
+	decoded, err := DecodeCursor(cursor.Encode())
+	assert.NoError(t, err)
+	assert.Equal(t, cursor, decoded)
+
+	_, err = DecodeCursor("not a cursor")
+	assert.Error(t, err, "Invalid base64 should not decode")
+
+	_, err = DecodeCursor(Cursor{Value: "Alien"}.Encode())
+	assert.Error(t, err, "Cursor without sort and id should not decode")
+}
+
+func Test_newPage(t *testing.T) {
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	keys := []rowKey{{"a", ids[0]}, {"b", ids[1]}, {"c", ids[2]}}
+
+	decode := func(encoded string) Cursor {
+		cursor, err := DecodeCursor(encoded)
+		assert.NoError(t, err)
+		return cursor
+	}
+
+	// First page with an extra row: only next cursor, pointing to the last returned row
+	page := newPage([]string{"a", "b", "c"}, keys, PageRequest{Limit: 2}, "title ASC", 7)
+	assert.Equal(t, []string{"a", "b"}, page.Data)
+	assert.Equal(t, 7, page.Pagination.Total)
+	assert.Equal(t, 2, page.Pagination.Limit)
+	assert.Empty(t, page.Pagination.Prev)
+	assert.Equal(t, Cursor{Sort: "title ASC", Value: "b", ID: ids[1]}, decode(page.Pagination.Next))
+
+	// Last page reached by cursor: only prev cursor, pointing backwards from the first returned row
+	page = newPage([]string{"a", "b"}, keys[:2], PageRequest{Limit: 2, Cursor: &Cursor{}}, "title ASC", 7)
+	assert.Empty(t, page.Pagination.Next)
+	assert.Equal(t, Cursor{Sort: "title ASC", Value: "a", ID: ids[0], Backwards: true}, decode(page.Pagination.Prev))
+
+	// Backwards page is read in reverse, so it is flipped back and the extra row means there is a previous page
+	page = newPage([]string{"c", "b", "a"}, []rowKey{keys[2], keys[1], keys[0]}, PageRequest{Limit: 2, Cursor: &Cursor{Backwards: true}}, "title ASC", 7)
+	assert.Equal(t, []string{"b", "c"}, page.Data)
+	assert.Equal(t, "b", decode(page.Pagination.Prev).Value)
+	assert.Equal(t, "c", decode(page.Pagination.Next).Value)
+
+	// Empty pages still send an empty list
+	emptyPage := newPage[string](nil, nil, PageRequest{Offset: 30, Limit: 10}, "title ASC", 7)
+	assert.Equal(t, []string{}, emptyPage.Data)
+	assert.Empty(t, emptyPage.Pagination.Next)
+	assert.Empty(t, emptyPage.Pagination.Prev)
+}
@@ -0,0 +1,342 @@
+package models
+
+import (
+	"database/sql"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Roles a person can have in the crew of a movie. Acting is not one of them,
+// actors keep their own table and link to a person through actors.person_id.
+const (
+	CrewRoleDirector        = "director"
+	CrewRoleWriter          = "writer"
+	CrewRoleProducer        = "producer"
+	CrewRoleComposer        = "composer"
+	CrewRoleCinematographer = "cinematographer"
+)
+
+// Role of the filmography credits that come from an actor linked to the person
+const CreditRoleActor = "actor"
+
+type PersonModel struct {
+	ID        uuid.UUID    `json:"id"`
+	Name      string       `json:"name"`
+	Birthday  string       `json:"birthday"`
+	Picture   string       `json:"picture"`
+	Biography string       `json:"biography"`
+	CreatedAt time.Time    `json:"createdAt"`
+	UpdatedAt time.Time    `json:"updatedAt"`
+	DeletedAt sql.NullTime `json:"deletedAt"`
+
+	CreatorId string `json:"creatorId"`
+}
+
+type PersonBody struct {
+	Name      string `json:"name" validate:"required,max=100"`
+	Birthday  string `json:"birthday" validate:"omitempty,datetime=2006-01-02"`
+	Picture   string `json:"picture" validate:"omitempty"`
+	Biography string `json:"biography" validate:"omitempty"`
+
+	CreatorId string `json:"creatorId" validate:"required,isadminuuid"`
+}
+
+type PersonEditBody struct {
+	Name      string `json:"name" validate:"omitempty,max=100"`
+	Birthday  string `json:"birthday" validate:"omitempty,datetime=2006-01-02"`
+	Picture   string `json:"picture" validate:"omitempty"`
+	Biography string `json:"biography" validate:"omitempty"`
+}
+
+// PersonResponse has the birthday in the YYYY-MM-DD format, or empty when it's unknown,
+// which is the case for every director created from the old free text column.
+type PersonResponse struct {
+	ID        uuid.UUID    `json:"id"`
+	Name      string       `json:"name"`
+	Birthday  string       `json:"birthday"`
+	Picture   string       `json:"picture"`
+	Biography string       `json:"biography"`
+	CreatedAt time.Time    `json:"createdAt"`
+	UpdatedAt time.Time    `json:"updatedAt"`
+	DeletedAt sql.NullTime `json:"deletedAt"`
+
+	CreatorId string `json:"creatorId"`
+}
+
+// CreditResponse is one movie of a filmography. CharacterName is only filled for acting credits.
+type CreditResponse struct {
+	Role          string        `json:"role"`
+	CharacterName string        `json:"characterName"`
+	Movie         MovieResponse `json:"movie"`
+}
+
+type PersonResponseWithFilmography struct {
+	PersonResponse
+	Filmography []CreditResponse `json:"filmography"`
+}
+
+// PersonFilters narrows the people list. Zero values leave the filter out of the query.
+type PersonFilters struct {
+	Name string
+	Role string
+}
+
+// Columns the people list can be sorted by
+var personSortColumns = []string{"created_at", "updated_at", "name"}
+
+const personColumns = "id, name, COALESCE(TO_CHAR(birthday, 'YYYY-MM-DD'), ''), picture, biography, created_at, updated_at, deleted_at, creator_id"
+
+func (p *PersonModel) InsertPersonInDB(db *sql.DB, personInfo PersonBody) (PersonResponse, error) {
+	log.Printf("Inserting person with name %s in DB by user %s...\n", personInfo.Name, personInfo.CreatorId)
+
+	query := `INSERT INTO people
+			(name, birthday, picture, biography, creator_id)
+			VALUES ($1, NULLIF($2, '')::date, $3, $4, $5)
+				RETURNING ` + personColumns + `;`
+
+	var person PersonResponse
+	if err := db.QueryRow(query, personInfo.Name, personInfo.Birthday, personInfo.Picture, personInfo.Biography, personInfo.CreatorId).Scan(&person.ID, &person.Name, &person.Birthday, &person.Picture, &person.Biography, &person.CreatedAt, &person.UpdatedAt, &person.DeletedAt, &person.CreatorId); err != nil {
+		log.Printf("Error inserting person into database: %v\n", err)
+		return PersonResponse{}, err
+	}
+
+	return person, nil
+}
+
+func (p *PersonModel) GetAllPeople(db *sql.DB, page PageRequest, orderBy string, deleted bool, filters PersonFilters) (Page[PersonResponse], error) {
+	log.Printf("Getting all people in DB, with page %+v, orderBy %v, deleted %v and filters %+v...\n", page, orderBy, deleted, filters)
+
+	queryBuilder := NewSelect(personColumns, "people")
+
+	if !deleted {
+		queryBuilder.Where("deleted_at IS NULL")
+	}
+
+	if filters.Name != "" {
+		queryBuilder.Where("name ILIKE ?", containsPattern(filters.Name))
+	}
+
+	if filters.Role != "" {
+		queryBuilder.Where("EXISTS (SELECT 1 FROM movie_crew mc WHERE mc.person_id = people.id AND mc.role = ?)", filters.Role)
+	}
+
+	queryBuilder.OrderBy(orderBy, personSortColumns)
+	total, err := countRows(db, queryBuilder)
+	if err != nil {
+		log.Println("Error counting all people in db:", err)
+		return Page[PersonResponse]{}, err
+	}
+
+	query, args, err := queryBuilder.Page(page).Build()
+	if err != nil {
+		log.Println("Error building all people query:", err)
+		return Page[PersonResponse]{}, err
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		log.Println("Error getting all people from db:", err)
+		return Page[PersonResponse]{}, err
+	}
+	defer rows.Close()
+
+	var people []PersonResponse
+	var keys []rowKey
+	for rows.Next() {
+		var person PersonResponse
+		var key rowKey
+		if err := rows.Scan(&person.ID, &person.Name, &person.Birthday, &person.Picture, &person.Biography, &person.CreatedAt, &person.UpdatedAt, &person.DeletedAt, &person.CreatorId, &key.value); err != nil {
+			log.Println("Error scanning person from db:", err)
+			return Page[PersonResponse]{}, err
+		}
+		key.id = person.ID
+
+		people = append(people, person)
+		keys = append(keys, key)
+	}
+
+	return newPage(people, keys, page, orderBy, total), nil
+}
+
+func (p *PersonModel) GetPersonById(db *sql.DB, uuid uuid.UUID) (PersonResponse, error) {
+	log.Printf("Getting person with uuid %s in DB... \n", uuid)
+
+	query := `SELECT ` + personColumns + `
+		FROM people
+			WHERE id = $1;`
+
+	var person PersonResponse
+	if err := db.QueryRow(query, uuid).Scan(&person.ID, &person.Name, &person.Birthday, &person.Picture, &person.Biography, &person.CreatedAt, &person.UpdatedAt, &person.DeletedAt, &person.CreatorId); err != nil {
+		log.Printf("Error getting person by id in the database: %v\n", err)
+		return PersonResponse{}, err
+	}
+
+	return person, nil
+}
+
+func (p *PersonModel) GetPersonFilmography(db *sql.DB, uuid uuid.UUID) (PersonResponseWithFilmography, error) {
+	log.Printf("Getting filmography of person with uuid %s in DB... \n", uuid)
+
+	person, err := p.GetPersonById(db, uuid)
+	if err != nil {
+		log.Printf("Error getting person by id in the database: %v\n", err)
+		return PersonResponseWithFilmography{}, err
+	}
+
+	// Crew credits and acting credits of every actor linked to the person, newest movies first
+	query := `SELECT
+		mc.role, '' AS character_name,
+		m.id, m.title, m.director, m.release_date, m.average_grade, m.picture, m.synopsis, m.created_at, m.updated_at, m.deleted_at, m.creator_id, m.rating_count, m.grade_histogram, m.weighted_rating
+			FROM movie_crew mc
+				JOIN movies m ON m.id = mc.movie_id
+					WHERE mc.person_id = $1 AND m.deleted_at IS NULL
+		UNION ALL
+		SELECT
+		'` + CreditRoleActor + `', ma.character_name,
+		m.id, m.title, m.director, m.release_date, m.average_grade, m.picture, m.synopsis, m.created_at, m.updated_at, m.deleted_at, m.creator_id, m.rating_count, m.grade_histogram, m.weighted_rating
+			FROM actors a
+				JOIN movies_actors ma ON ma.actor_id = a.id
+				JOIN movies m ON m.id = ma.movie_id
+					WHERE a.person_id = $1 AND a.deleted_at IS NULL AND m.deleted_at IS NULL
+		ORDER BY release_date DESC, title ASC, role ASC;`
+
+	rows, err := db.Query(query, uuid)
+	if err != nil {
+		log.Printf("Error getting filmography of person %v: %v\n", uuid, err)
+		return PersonResponseWithFilmography{}, err
+	}
+	defer rows.Close()
+
+	personWithFilmography := PersonResponseWithFilmography{
+		PersonResponse: person,
+		Filmography:    []CreditResponse{},
+	}
+
+	for rows.Next() {
+		var credit CreditResponse
+		movie := &credit.Movie
+		if err := rows.Scan(&credit.Role, &credit.CharacterName, &movie.ID, &movie.Title, &movie.Director, &movie.ReleaseDate, &movie.AverageGrade, &movie.Picture, &movie.Synopsis, &movie.CreatedAt, &movie.UpdatedAt, &movie.DeletedAt, &movie.CreatorId, &movie.RatingCount, &movie.GradeHistogram, &movie.WeightedRating); err != nil {
+			log.Printf("Error scanning credit row in GetPersonFilmography: %v\n", err)
+			return PersonResponseWithFilmography{}, err
+		}
+		personWithFilmography.Filmography = append(personWithFilmography.Filmography, credit)
+	}
+
+	return personWithFilmography, nil
+}
+
+// getOrInsertPersonByName is used to link the director of a new movie when no crew was sent.
+// It reuses the oldest active person with the same name, ignoring case, like the backfill migration.
+func (p *PersonModel) getOrInsertPersonByName(tx *sql.Tx, name, creatorId string) (uuid.UUID, error) {
+	var id uuid.UUID
+
+	query := `SELECT id FROM people
+		WHERE LOWER(name) = LOWER(TRIM($1)) AND deleted_at IS NULL
+			ORDER BY created_at ASC
+				LIMIT 1;`
+
+	err := tx.QueryRow(query, name).Scan(&id)
+	if err == nil {
+		return id, nil
+	}
+
+	if err != sql.ErrNoRows {
+		return uuid.Nil, err
+	}
+
+	insertQuery := `INSERT INTO people (name, creator_id) VALUES (TRIM($1), $2) RETURNING id;`
+	if err := tx.QueryRow(insertQuery, name, creatorId).Scan(&id); err != nil {
+		return uuid.Nil, err
+	}
+
+	return id, nil
+}
+
+func (p *PersonModel) DeletePersonById(db *sql.DB, uuid uuid.UUID) error {
+	log.Printf("Deleting person with uuid %s in DB... \n", uuid)
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error beginning transaction made while deleting person by id: %v\n", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM movie_crew WHERE person_id = $1;`, uuid); err != nil {
+		log.Printf("Error deleting person's crew credits while deleting person by id: %v\n", err)
+		return err
+	}
+
+	// Actors stay, they just stop pointing to the deleted person
+	if _, err := tx.Exec(`UPDATE actors SET person_id = NULL, updated_at = CURRENT_TIMESTAMP WHERE person_id = $1;`, uuid); err != nil {
+		log.Printf("Error unlinking actors while deleting person by id: %v\n", err)
+		return err
+	}
+
+	query := `UPDATE people
+		SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND deleted_at IS NULL;`
+
+	if _, err := tx.Exec(query, uuid); err != nil {
+		log.Printf("Error deleting person by uuid: %v\n", err)
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing transaction made while deleting person by id: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
+func (p *PersonModel) UpdatePersonById(db *sql.DB, uuid uuid.UUID, body PersonEditBody) (PersonResponse, error) {
+	log.Printf("Updating person with uuid %s in DB... \n", uuid)
+
+	var updateQueryBuilder strings.Builder
+	var args []interface{}
+
+	updateQueryBuilder.WriteString("UPDATE people SET ")
+
+	argIndex := 1
+	if body.Name != "" {
+		updateQueryBuilder.WriteString("name = $" + strconv.Itoa(argIndex) + ", ")
+		args = append(args, body.Name)
+		argIndex++
+	}
+
+	if body.Birthday != "" {
+		updateQueryBuilder.WriteString("birthday = $" + strconv.Itoa(argIndex) + ", ")
+		args = append(args, body.Birthday)
+		argIndex++
+	}
+
+	if body.Picture != "" {
+		updateQueryBuilder.WriteString("picture = $" + strconv.Itoa(argIndex) + ", ")
+		args = append(args, body.Picture)
+		argIndex++
+	}
+
+	if body.Biography != "" {
+		updateQueryBuilder.WriteString("biography = $" + strconv.Itoa(argIndex) + ", ")
+		args = append(args, body.Biography)
+		argIndex++
+	}
+
+	updateQueryBuilder.WriteString("updated_at = CURRENT_TIMESTAMP, ")
+	query := strings.TrimSuffix(updateQueryBuilder.String(), ", ")
+	query += " WHERE id = $" + strconv.Itoa(argIndex) + " AND deleted_at IS NULL RETURNING " + personColumns + ";"
+	args = append(args, uuid)
+
+	var person PersonResponse
+	if err := db.QueryRow(query, args...).Scan(&person.ID, &person.Name, &person.Birthday, &person.Picture, &person.Biography, &person.CreatedAt, &person.UpdatedAt, &person.DeletedAt, &person.CreatorId); err != nil {
+		log.Printf("Error updating person by uuid: %v \n", err)
+		return PersonResponse{}, err
+	}
+
+	return person, nil
+}
@@ -0,0 +1,211 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SelectBuilder composes SELECT statements for the list endpoints.
+// Values are never concatenated into the query: every "?" placeholder passed to Where is
+// rewritten to the next positional parameter ($1, $2...) and its value is bound as an argument.
+// Identifiers can't be bound, so ORDER BY only accepts columns from an allow list.
+type SelectBuilder struct {
+	columns    string
+	from       condition
+	conditions []condition
+	orderBy    string
+	direction  string
+	page       *PageRequest
+	err        error
+}
+
+type condition struct {
+	expression string
+	args       []interface{}
+}
+
+// NewSelect starts a query on from, which can be a subquery with "?" placeholders of its own, bound to args
+// before the ones of the conditions.
+func NewSelect(columns, from string, args ...interface{}) *SelectBuilder {
+	b := &SelectBuilder{columns: columns, from: condition{expression: from, args: args}}
+	if strings.Count(from, "?") != len(args) {
+		b.err = fmt.Errorf("from %q has %d placeholders but %d arguments", from, strings.Count(from, "?"), len(args))
+	}
+
+	return b
+}
+
+// Where adds a condition, joined to the previous ones with AND.
+func (b *SelectBuilder) Where(expression string, args ...interface{}) *SelectBuilder {
+	if strings.Count(expression, "?") != len(args) {
+		b.err = fmt.Errorf("expression %q has %d placeholders but %d arguments", expression, strings.Count(expression, "?"), len(args))
+		return b
+	}
+
+	b.conditions = append(b.conditions, condition{expression: expression, args: args})
+	return b
+}
+
+// OrderBy sets the "column ASC|DESC" clause, as produced by the sort switches of the controllers.
+// The column has to be one of allowedColumns.
+func (b *SelectBuilder) OrderBy(orderBy string, allowedColumns []string) *SelectBuilder {
+	parts := strings.Fields(orderBy)
+	if len(parts) != 2 || (parts[1] != "ASC" && parts[1] != "DESC") {
+		b.err = fmt.Errorf("invalid order by clause %q", orderBy)
+		return b
+	}
+
+	for _, column := range allowedColumns {
+		if column == parts[0] {
+			b.orderBy = parts[0]
+			b.direction = parts[1]
+			return b
+		}
+	}
+
+	b.err = fmt.Errorf("column %q is not allowed in order by", parts[0])
+	return b
+}
+
+// Page paginates the query, either by offset or by the keyset of a cursor.
+// Pages are ordered by the sort column plus id, so rows with the same sort value keep a stable order,
+// and the sort value of each row is selected as text after the other columns to create the cursors.
+// One row more than the limit is fetched, see newPage.
+func (b *SelectBuilder) Page(request PageRequest) *SelectBuilder {
+	b.page = &request
+	return b
+}
+
+// bindExpression rewrites the "?" of an expression into positional parameters, numbered after the ones in args.
+func bindExpression(condition condition, args []interface{}) (string, []interface{}) {
+	var expression strings.Builder
+	argIndex := 0
+	for _, char := range condition.expression {
+		if char == '?' {
+			args = append(args, condition.args[argIndex])
+			expression.WriteString("$" + strconv.Itoa(len(args)))
+			argIndex++
+			continue
+		}
+		expression.WriteRune(char)
+	}
+
+	return expression.String(), args
+}
+
+// bind rewrites the "?" of every condition into positional parameters, numbered after the ones in args.
+func bind(conditions []condition, args []interface{}) (string, []interface{}) {
+	bound := make([]string, 0, len(conditions))
+	for _, condition := range conditions {
+		var expression string
+		expression, args = bindExpression(condition, args)
+		bound = append(bound, "("+expression+")")
+	}
+
+	return strings.Join(bound, " AND "), args
+}
+
+// Build returns the final query and its arguments, or the first error found while composing it.
+func (b *SelectBuilder) Build() (string, []interface{}, error) {
+	if b.err != nil {
+		return "", nil, b.err
+	}
+
+	if b.page != nil && b.orderBy == "" {
+		return "", nil, fmt.Errorf("paginated queries need an order by clause")
+	}
+
+	columns := b.columns
+	conditions := b.conditions
+	direction := b.direction
+	if b.page != nil {
+		columns += ", " + b.orderBy + "::text"
+
+		if cursor := b.page.Cursor; cursor != nil {
+			// Going forward on an ascending sort means greater values, backwards or descending flips it
+			operator := ">"
+			if (direction == "DESC") != cursor.Backwards {
+				operator = "<"
+			}
+
+			conditions = append(append([]condition{}, b.conditions...), condition{
+				expression: "(" + b.orderBy + ", id) " + operator + " (?, ?)",
+				args:       []interface{}{cursor.Value, cursor.ID},
+			})
+
+			// Backwards pages are read in reverse and flipped back by newPage
+			if cursor.Backwards {
+				direction = map[string]string{"ASC": "DESC", "DESC": "ASC"}[direction]
+			}
+		}
+	}
+
+	from, args := bindExpression(b.from, nil)
+
+	var query strings.Builder
+	query.WriteString("SELECT " + columns + " FROM " + from)
+
+	where, args := bind(conditions, args)
+	if where != "" {
+		query.WriteString(" WHERE " + where)
+	}
+
+	if b.orderBy != "" {
+		query.WriteString(" ORDER BY " + b.orderBy + " " + direction)
+	}
+
+	if b.page != nil {
+		query.WriteString(", id " + direction)
+
+		if b.page.Cursor == nil {
+			args = append(args, b.page.Offset)
+			query.WriteString(" OFFSET $" + strconv.Itoa(len(args)))
+		}
+
+		args = append(args, b.page.Limit+1)
+		query.WriteString(" LIMIT $" + strconv.Itoa(len(args)))
+	}
+
+	query.WriteString(";")
+
+	return query.String(), args, nil
+}
+
+// BuildCount returns a query counting every row matched by the conditions, ignoring order and pagination.
+func (b *SelectBuilder) BuildCount() (string, []interface{}, error) {
+	if b.err != nil {
+		return "", nil, b.err
+	}
+
+	from, args := bindExpression(b.from, nil)
+	query := "SELECT COUNT(*) FROM " + from
+
+	where, args := bind(b.conditions, args)
+	if where != "" {
+		query += " WHERE " + where
+	}
+
+	return query + ";", args, nil
+}
+
+func countRows(db *sql.DB, queryBuilder *SelectBuilder) (int, error) {
+	query, args, err := queryBuilder.BuildCount()
+	if err != nil {
+		return 0, err
+	}
+
+	var total int
+	if err := db.QueryRow(query, args...).Scan(&total); err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// containsPattern escapes LIKE wildcards in a user supplied value and wraps it for substring matching.
+func containsPattern(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + replacer.Replace(value) + "%"
+}
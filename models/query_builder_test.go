@@ -0,0 +1,111 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SelectBuilder(t *testing.T) {
+	cursorId := uuid.New()
+
+	tests := []struct {
+		description   string
+		builder       *SelectBuilder
+		expectedQuery string
+		expectedArgs  []interface{}
+		expectError   bool
+	}{
+		{
+			description:   "Select without conditions",
+			builder:       NewSelect("id, title", "movies"),
+			expectedQuery: "SELECT id, title FROM movies;",
+			expectedArgs:  nil,
+		},
+		{
+			description:   "Conditions are numbered in order and joined with AND",
+			builder:       NewSelect("id", "movies").Where("deleted_at IS NULL").Where("release_date >= ?", "2000-01-01").Where("average_grade BETWEEN ? AND ?", 2.5, 4.0),
+			expectedQuery: "SELECT id FROM movies WHERE (deleted_at IS NULL) AND (release_date >= $1) AND (average_grade BETWEEN $2 AND $3);",
+			expectedArgs:  []interface{}{"2000-01-01", 2.5, 4.0},
+		},
+		{
+			description:   "Offset page is ordered by id after the sort column and fetches an extra row",
+			builder:       NewSelect("id", "movies").Where("title ILIKE ?", "%matrix%").OrderBy("title ASC", movieSortColumns).Page(PageRequest{Offset: 20, Limit: 10}),
+			expectedQuery: "SELECT id, title::text FROM movies WHERE (title ILIKE $1) ORDER BY title ASC, id ASC OFFSET $2 LIMIT $3;",
+			expectedArgs:  []interface{}{"%matrix%", 20, 11},
+		},
+		{
+			description:   "Cursor page ignores the offset and continues after the keyset",
+			builder:       NewSelect("id", "movies").Where("deleted_at IS NULL").OrderBy("created_at DESC", movieSortColumns).Page(PageRequest{Offset: 20, Limit: 5, Cursor: &Cursor{Sort: "created_at DESC", Value: "2024-01-01 10:00:00", ID: cursorId}}),
+			expectedQuery: "SELECT id, created_at::text FROM movies WHERE (deleted_at IS NULL) AND ((created_at, id) < ($1, $2)) ORDER BY created_at DESC, id DESC LIMIT $3;",
+			expectedArgs:  []interface{}{"2024-01-01 10:00:00", cursorId, 6},
+		},
+		{
+			description:   "Backwards cursor page reads the rows before the keyset in reverse",
+			builder:       NewSelect("id", "movies").OrderBy("title ASC", movieSortColumns).Page(PageRequest{Limit: 5, Cursor: &Cursor{Sort: "title ASC", Value: "Alien", ID: cursorId, Backwards: true}}),
+			expectedQuery: "SELECT id, title::text FROM movies WHERE ((title, id) < ($1, $2)) ORDER BY title DESC, id DESC LIMIT $3;",
+			expectedArgs:  []interface{}{"Alien", cursorId, 6},
+		},
+		{
+			description:   "Placeholders of the from are numbered before the ones of the conditions",
+			builder:       NewSelect("id", "(SELECT id, ts_rank(search_vector, to_tsquery(?)) AS rank FROM movies) AS results", "alien:*").Where("rank > ?", 0).OrderBy("rank DESC", searchSortColumns).Page(PageRequest{Limit: 5}),
+			expectedQuery: "SELECT id, rank::text FROM (SELECT id, ts_rank(search_vector, to_tsquery($1)) AS rank FROM movies) AS results WHERE (rank > $2) ORDER BY rank DESC, id DESC OFFSET $3 LIMIT $4;",
+			expectedArgs:  []interface{}{"alien:*", 0, 0, 6},
+		},
+		{
+			description: "Page without order by",
+			builder:     NewSelect("id", "movies").Page(PageRequest{Limit: 5}),
+			expectError: true,
+		},
+		{
+			description: "Order by column outside of the allow list",
+			builder:     NewSelect("id", "movies").OrderBy("password DESC", movieSortColumns),
+			expectError: true,
+		},
+		{
+			description: "Order by with injected SQL",
+			builder:     NewSelect("id", "movies").OrderBy("title; DROP TABLE movies; --", movieSortColumns),
+			expectError: true,
+		},
+		{
+			description: "Placeholders and arguments mismatch",
+			builder:     NewSelect("id", "movies").Where("title = ? OR director = ?", "Alien"),
+			expectError: true,
+		},
+		{
+			description: "Placeholders and arguments mismatch in the from",
+			builder:     NewSelect("id", "(SELECT id FROM movies WHERE title = ?) AS movies"),
+			expectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		query, args, err := test.builder.Build()
+
+		if test.expectError {
+			assert.Errorf(t, err, test.description)
+			continue
+		}
+
+		assert.NoErrorf(t, err, test.description)
+		assert.Equalf(t, test.expectedQuery, query, test.description)
+		assert.Equalf(t, test.expectedArgs, args, test.description)
+	}
+}
+
+func Test_SelectBuilderCount(t *testing.T) {
+	builder := NewSelect("id, title", "movies").Where("deleted_at IS NULL").Where("director ILIKE ?", "%nolan%").OrderBy("title ASC", movieSortColumns).Page(PageRequest{Limit: 10})
+
+	query, args, err := builder.BuildCount()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM movies WHERE (deleted_at IS NULL) AND (director ILIKE $1);", query)
+	assert.Equal(t, []interface{}{"%nolan%"}, args)
+}
+
+func Test_containsPattern(t *testing.T) {
+	assert.Equal(t, "%nolan%", containsPattern("nolan"))
+	assert.Equal(t, `%100\% pure\_fun%`, containsPattern("100% pure_fun"))
+	assert.Equal(t, `%back\\slash%`, containsPattern(`back\slash`))
+}
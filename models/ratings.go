@@ -0,0 +1,73 @@
+package models
+
+import (
+	"database/sql"
+	"log"
+	"time"
+)
+
+// RatingSettingsModel holds the parameters of the weighted rating of the movies.
+// The weighted rating is (v / (v + m)) * R + (m / (v + m)) * C, where v is the review count of a movie,
+// R its average grade, m is MinVotes and C is PriorMean.
+type RatingSettingsModel struct {
+	PriorMean float64   `json:"priorMean"`
+	MinVotes  int       `json:"minVotes"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func (r *RatingSettingsModel) GetRatingSettings(db *sql.DB) (RatingSettingsModel, error) {
+	query := `SELECT prior_mean, min_votes, updated_at FROM rating_settings;`
+
+	var settings RatingSettingsModel
+	if err := db.QueryRow(query).Scan(&settings.PriorMean, &settings.MinVotes, &settings.UpdatedAt); err != nil {
+		log.Printf("Error getting rating settings: %v\n", err)
+		return RatingSettingsModel{}, err
+	}
+
+	return settings, nil
+}
+
+// UpdateRatingSettings stores new weighted rating parameters. When they change, the weighted rating
+// of every movie is recomputed in the same transaction. Returns if the settings changed.
+func (r *RatingSettingsModel) UpdateRatingSettings(db *sql.DB, priorMean float64, minVotes int) (bool, error) {
+	log.Printf("Updating rating settings to prior mean %v and min votes %v in DB...\n", priorMean, minVotes)
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction to update rating settings: %v\n", err)
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE rating_settings
+		SET prior_mean = $1, min_votes = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE prior_mean <> $1 OR min_votes <> $2;`
+
+	result, err := tx.Exec(query, priorMean, minVotes)
+	if err != nil {
+		log.Printf("Error updating rating settings: %v\n", err)
+		return false, err
+	}
+
+	changed, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("Error reading updated rating settings: %v\n", err)
+		return false, err
+	}
+
+	if changed == 0 {
+		return false, nil
+	}
+
+	if _, err := tx.Exec(`SELECT refresh_movie_rating(id) FROM movies;`); err != nil {
+		log.Printf("Error recomputing movie ratings with the new settings: %v\n", err)
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing transaction while updating rating settings: %v\n", err)
+		return false, err
+	}
+
+	return true, nil
+}
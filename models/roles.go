@@ -0,0 +1,178 @@
+package models
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Permissions checked by the routes. Roles grant them, and the routes never check role names.
+const (
+	PermissionManageUsers      = "users:manage"
+	PermissionEditCatalog      = "catalog:edit"
+	PermissionModerateComments = "comments:moderate"
+)
+
+// Roles seeded by the migrations
+const (
+	RoleAdmin     = "admin"
+	RoleModerator = "moderator"
+	RoleCurator   = "curator"
+)
+
+type RoleModel struct {
+	Name        string    `json:"name"`
+	Permissions []string  `json:"permissions"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type RoleBody struct {
+	Role string `json:"role" validate:"required,oneof=admin moderator curator"`
+}
+
+// UserRoles are the roles of a user and the permissions they add up to, as carried in the token claims
+type UserRoles struct {
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"permissions"`
+}
+
+// Internal methods
+func (r *RoleModel) syncIsAdm(tx *sql.Tx, userId uuid.UUID) error {
+	// is_adm follows the admin role, since the users filter still reads it
+	query := `UPDATE users
+		SET is_adm = EXISTS (SELECT 1 FROM user_roles WHERE user_id = $1 AND role_name = $2)
+		WHERE id = $1;`
+
+	_, err := tx.Exec(query, userId, RoleAdmin)
+	return err
+}
+
+// Public methods
+func (r *RoleModel) GetUserRoles(db *sql.DB, userId uuid.UUID) (UserRoles, error) {
+	log.Printf("Getting roles of user %s in DB...\n", userId)
+
+	query := `SELECT COALESCE(ARRAY_AGG(DISTINCT ur.role_name), '{}'), COALESCE(ARRAY_AGG(DISTINCT p.permission) FILTER (WHERE p.permission IS NOT NULL), '{}')
+		FROM user_roles ur
+			JOIN roles r ON r.name = ur.role_name
+			LEFT JOIN LATERAL UNNEST(r.permissions) AS p(permission) ON TRUE
+				WHERE ur.user_id = $1;`
+
+	var userRoles UserRoles
+	if err := db.QueryRow(query, userId).Scan(pq.Array(&userRoles.Roles), pq.Array(&userRoles.Permissions)); err != nil {
+		log.Printf("Error getting roles of user: %v\n", err)
+		return UserRoles{}, err
+	}
+
+	return userRoles, nil
+}
+
+// UserHasPermission tells if any of the roles of the user grants the permission
+func (r *RoleModel) UserHasPermission(db *sql.DB, userId uuid.UUID, permission string) (bool, error) {
+	var found bool
+	query := `SELECT EXISTS (
+		SELECT 1 FROM user_roles ur
+			JOIN roles r ON r.name = ur.role_name
+				WHERE ur.user_id = $1 AND $2 = ANY(r.permissions)
+	);`
+	if err := db.QueryRow(query, userId, permission).Scan(&found); err != nil {
+		log.Printf("Error checking permission %v of user %v: %v\n", permission, userId, err)
+		return false, err
+	}
+
+	return found, nil
+}
+
+// GrantRoleToUser gives the role to the user and writes it to the audit log. Granting a role the user already has does nothing.
+func (r *RoleModel) GrantRoleToUser(db *sql.DB, actorId uuid.NullUUID, userId uuid.UUID, role string) error {
+	log.Printf("Granting role %s to user %s in DB...\n", role, userId)
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction to grant role: %v\n", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO user_roles (user_id, role_name) VALUES ($1, $2) ON CONFLICT DO NOTHING;`
+	result, err := tx.Exec(query, userId, role)
+	if err != nil {
+		log.Printf("Error granting role to user: %v\n", err)
+		return err
+	}
+
+	granted, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("Error counting rows while granting role: %v\n", err)
+		return err
+	}
+
+	// The user already had the role, so there is nothing to audit
+	if granted == 0 {
+		return nil
+	}
+
+	if err := insertAuditLog(tx, AuditLogModel{Action: AuditPromote, Role: role, ActorId: actorId, TargetId: userId.String()}); err != nil {
+		return err
+	}
+
+	if err := r.syncIsAdm(tx, userId); err != nil {
+		log.Printf("Error syncing is_adm of user: %v\n", err)
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing transaction while granting role: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
+// RevokeRoleFromUser takes the role from the user and writes it to the audit log
+func (r *RoleModel) RevokeRoleFromUser(db *sql.DB, actorId uuid.NullUUID, userId uuid.UUID, role string) error {
+	log.Printf("Revoking role %s of user %s in DB...\n", role, userId)
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction to revoke role: %v\n", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `DELETE FROM user_roles WHERE user_id = $1 AND role_name = $2;`
+	result, err := tx.Exec(query, userId, role)
+	if err != nil {
+		log.Printf("Error revoking role of user: %v\n", err)
+		return err
+	}
+
+	revoked, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("Error counting rows while revoking role: %v\n", err)
+		return err
+	}
+
+	// The user didn't have the role, so there is nothing to audit
+	if revoked == 0 {
+		return nil
+	}
+
+	if err := insertAuditLog(tx, AuditLogModel{Action: AuditDemote, Role: role, ActorId: actorId, TargetId: userId.String()}); err != nil {
+		return err
+	}
+
+	if err := r.syncIsAdm(tx, userId); err != nil {
+		log.Printf("Error syncing is_adm of user: %v\n", err)
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing transaction while revoking role: %v\n", err)
+		return err
+	}
+
+	return nil
+}
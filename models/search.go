@@ -0,0 +1,120 @@
+package models
+
+import (
+	"database/sql"
+	"log"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+type SearchModel struct{}
+
+type SearchResult struct {
+	Type     string    `json:"type"`
+	ID       uuid.UUID `json:"id"`
+	Title    string    `json:"title"`
+	Subtitle string    `json:"subtitle"`
+	Picture  string    `json:"picture"`
+	Rank     float64   `json:"rank"`
+}
+
+// Search result types
+const (
+	SearchTypeMovie = "movie"
+	SearchTypeActor = "actor"
+)
+
+// PrefixTsQuery turns free user input into a to_tsquery expression where every word must match
+// as a prefix ("star wa" -> "star:* & wa:*"). Everything that is not a letter or a number is
+// treated as a separator, so the output never contains tsquery operators typed by the user.
+// Returns an empty string when the input has no words.
+func PrefixTsQuery(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		terms = append(terms, word+":*")
+	}
+
+	return strings.Join(terms, " & ")
+}
+
+// Search results are only sorted by rank, the best match first
+const SearchOrderBy = "rank DESC"
+
+var searchSortColumns = []string{"rank"}
+
+// searchTable ranks movies (by title, director and synopsis) and actors (by name) against a to_tsquery expression,
+// which is bound to both "?"
+const searchTable = `(
+	SELECT
+		'movie' AS type, m.id, m.title, m.director AS subtitle, m.picture,
+		ts_rank(m.search_vector, q.query) AS rank
+		FROM movies m, (SELECT to_tsquery('simple', immutable_unaccent(?)) AS query) q
+			WHERE m.deleted_at IS NULL AND m.search_vector @@ q.query
+	UNION ALL
+	SELECT
+		'actor' AS type, a.id, TRIM(a.name || ' ' || COALESCE(a.surname, '')) AS title, '' AS subtitle, a.picture,
+		ts_rank(a.search_vector, q.query) AS rank
+		FROM actors a, (SELECT to_tsquery('simple', immutable_unaccent(?)) AS query) q
+			WHERE a.deleted_at IS NULL AND a.search_vector @@ q.query
+) AS results`
+
+// Search ranks movies and actors matching text.
+// searchType restricts results to SearchTypeMovie or SearchTypeActor, an empty string returns both.
+func (s *SearchModel) Search(db *sql.DB, text, searchType string, page PageRequest) (Page[SearchResult], error) {
+	log.Printf("Searching for %q with type %q and page %+v in DB...\n", text, searchType, page)
+
+	tsQuery := PrefixTsQuery(text)
+	queryBuilder := NewSelect("type, id, title, subtitle, picture, rank", searchTable, tsQuery, tsQuery)
+
+	if searchType != "" {
+		queryBuilder.Where("type = ?", searchType)
+	}
+
+	queryBuilder.OrderBy(SearchOrderBy, searchSortColumns)
+	total, err := countRows(db, queryBuilder)
+	if err != nil {
+		log.Println("Error counting search results in db:", err)
+		return Page[SearchResult]{}, err
+	}
+
+	query, args, err := queryBuilder.Page(page).Build()
+	if err != nil {
+		log.Println("Error building search query:", err)
+		return Page[SearchResult]{}, err
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		log.Println("Error searching movies and actors in db:", err)
+		return Page[SearchResult]{}, err
+	}
+	defer rows.Close()
+
+	var results []SearchResult
+	var keys []rowKey
+	for rows.Next() {
+		var result SearchResult
+		var key rowKey
+		if err := rows.Scan(&result.Type, &result.ID, &result.Title, &result.Subtitle, &result.Picture, &result.Rank, &key.value); err != nil {
+			log.Println("Error scanning search result from db:", err)
+			return Page[SearchResult]{}, err
+		}
+		key.id = result.ID
+
+		results = append(results, result)
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Println("Error reading search results from db:", err)
+		return Page[SearchResult]{}, err
+	}
+
+	return newPage(results, keys, page, SearchOrderBy, total), nil
+}
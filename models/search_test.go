@@ -0,0 +1,25 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PrefixTsQuery(t *testing.T) {
+	testCases := []struct {
+		Have string
+		Want string
+	}{
+		{"Star Wars", "star:* & wars:*"},
+		{"  ação   2 ", "ação:* & 2:*"},
+		{"o'brien & (nolan | !x)", "o:* & brien:* & nolan:* & x:*"},
+		{"spider-man:*", "spider:* & man:*"},
+		{"", ""},
+		{"!!! &&", ""},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.Want, PrefixTsQuery(testCase.Have), "Unexpected tsquery for %q", testCase.Have)
+	}
+}
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SessionModel struct {
+	ID               uuid.UUID    `json:"id"`
+	RefreshTokenHash string       `json:"-"`
+	CreatedAt        time.Time    `json:"createdAt"`
+	UpdatedAt        time.Time    `json:"updatedAt"`
+	ExpiresAt        time.Time    `json:"expiresAt"`
+	RevokedAt        sql.NullTime `json:"revokedAt"`
+
+	UserId uuid.UUID `json:"userId"`
+}
+
+// Active reports if the session can still be used to authenticate requests.
+func (s SessionModel) Active() bool {
+	return !s.RevokedAt.Valid && s.ExpiresAt.After(time.Now())
+}
+
+func (s *SessionModel) InsertSessionInDB(db *sql.DB, userId uuid.UUID, refreshTokenHash string, expiresAt time.Time) (SessionModel, error) {
+	log.Printf("Inserting session for user %s in DB...\n", userId)
+
+	query := `INSERT INTO sessions
+			(refresh_token_hash, expires_at, user_id)
+			VALUES ($1, $2, $3)
+				RETURNING id, refresh_token_hash, created_at, updated_at, expires_at, revoked_at, user_id;`
+
+	var session SessionModel
+	if err := db.QueryRow(query, refreshTokenHash, expiresAt, userId).Scan(&session.ID, &session.RefreshTokenHash, &session.CreatedAt, &session.UpdatedAt, &session.ExpiresAt, &session.RevokedAt, &session.UserId); err != nil {
+		log.Printf("Error inserting session into database: %v\n", err)
+		return SessionModel{}, err
+	}
+
+	return session, nil
+}
+
+func (s *SessionModel) GetSessionById(db *sql.DB, uuid uuid.UUID) (SessionModel, error) {
+	query := `SELECT 
+		id, refresh_token_hash, created_at, updated_at, expires_at, revoked_at, user_id
+		FROM sessions
+			WHERE id = $1;`
+
+	var session SessionModel
+	if err := db.QueryRow(query, uuid).Scan(&session.ID, &session.RefreshTokenHash, &session.CreatedAt, &session.UpdatedAt, &session.ExpiresAt, &session.RevokedAt, &session.UserId); err != nil {
+		log.Printf("Error getting session by id: %v\n", err)
+		return SessionModel{}, err
+	}
+
+	return session, nil
+}
+
+// RotateSessionRefreshToken swaps the refresh token of an active session in a single statement,
+// so the same refresh token can never be exchanged twice. Returns sql.ErrNoRows if the token
+// does not belong to an active session.
+func (s *SessionModel) RotateSessionRefreshToken(db *sql.DB, oldRefreshTokenHash, newRefreshTokenHash string, expiresAt time.Time) (SessionModel, error) {
+	log.Println("Rotating session refresh token in DB...")
+
+	query := `UPDATE sessions
+		SET refresh_token_hash = $1, expires_at = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE refresh_token_hash = $3 AND revoked_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+			RETURNING id, refresh_token_hash, created_at, updated_at, expires_at, revoked_at, user_id;`
+
+	var session SessionModel
+	if err := db.QueryRow(query, newRefreshTokenHash, expiresAt, oldRefreshTokenHash).Scan(&session.ID, &session.RefreshTokenHash, &session.CreatedAt, &session.UpdatedAt, &session.ExpiresAt, &session.RevokedAt, &session.UserId); err != nil {
+		log.Printf("Error rotating session refresh token: %v\n", err)
+		return SessionModel{}, err
+	}
+
+	return session, nil
+}
+
+func (s *SessionModel) RevokeSessionById(db *sql.DB, uuid uuid.UUID) error {
+	log.Printf("Revoking session with uuid %s in DB...\n", uuid)
+
+	query := `UPDATE sessions 
+		SET revoked_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP 
+		WHERE id = $1 AND revoked_at IS NULL;`
+
+	if _, err := db.Exec(query, uuid); err != nil {
+		log.Printf("Error revoking session by uuid: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *SessionModel) RevokeAllUserSessions(db *sql.DB, userId uuid.UUID) error {
+	log.Printf("Revoking all sessions of user %s in DB...\n", userId)
+
+	query := `UPDATE sessions 
+		SET revoked_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP 
+		WHERE user_id = $1 AND revoked_at IS NULL;`
+
+	if _, err := db.Exec(query, userId); err != nil {
+		log.Printf("Error revoking all sessions of user: %v\n", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package models
+
+import "regexp"
+
+// Text that takes the place of redacted spoilers
+const SpoilerPlaceholder = "[spoiler]"
+
+// Inline spoilers are marked as ||spoiler||, and can span lines
+var inlineSpoiler = regexp.MustCompile(`(?s)\|\|(.+?)\|\|`)
+
+// RedactSpoilers replaces the inline spoilers of the text with the placeholder
+func RedactSpoilers(text string) string {
+	return inlineSpoiler.ReplaceAllString(text, SpoilerPlaceholder)
+}
+
+// redactText takes the spoilers out of the text of a comment. Comments flagged as spoilers are redacted as a whole,
+// and the others only have their inline spoilers redacted.
+func redactText(text string, containsSpoilers bool) string {
+	if containsSpoilers {
+		return SpoilerPlaceholder
+	}
+
+	return RedactSpoilers(text)
+}
+
+// RedactComment takes the spoilers out of the comment
+func RedactComment(comment CommentResponse) CommentResponse {
+	if redacted := redactText(comment.Comment, comment.ContainsSpoilers); redacted != comment.Comment {
+		comment.Comment = redacted
+		comment.SpoilersRedacted = true
+	}
+
+	return comment
+}
+
+// RedactActivity takes the spoilers out of the comment of the activity, like RedactComment
+func RedactActivity(activity ActivityResponse) ActivityResponse {
+	if redacted := redactText(activity.Comment, activity.ContainsSpoilers); redacted != activity.Comment {
+		activity.Comment = redacted
+		activity.SpoilersRedacted = true
+	}
+
+	return activity
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RedactSpoilers(t *testing.T) {
+	testCases := []struct {
+		Have string
+		Want string
+	}{
+		{"No spoilers here", "No spoilers here"},
+		{"The ending: ||he was dead all along||", "The ending: [spoiler]"},
+		{"||One|| and ||two||", "[spoiler] and [spoiler]"},
+		{"Across ||two\nlines||!", "Across [spoiler]!"},
+		{"Empty |||| markup", "Empty |||| markup"},
+		{"Unclosed ||spoiler", "Unclosed ||spoiler"},
+		{"a || b", "a || b"},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.Want, RedactSpoilers(testCase.Have), "Unexpected redaction of %q", testCase.Have)
+	}
+}
+
+func Test_RedactComment(t *testing.T) {
+	flagged := RedactComment(CommentResponse{Comment: "Rosebud is a sled", ContainsSpoilers: true})
+	assert.Equal(t, SpoilerPlaceholder, flagged.Comment, "Flagged comment should be redacted as a whole")
+	assert.True(t, flagged.SpoilersRedacted, "Flagged comment should be marked as redacted")
+
+	inline := RedactComment(CommentResponse{Comment: "Great, and ||Rosebud is a sled||"})
+	assert.Equal(t, "Great, and [spoiler]", inline.Comment, "Inline spoiler should be redacted")
+	assert.True(t, inline.SpoilersRedacted, "Comment with inline spoilers should be marked as redacted")
+
+	clean := RedactComment(CommentResponse{Comment: "Great"})
+	assert.Equal(t, "Great", clean.Comment, "Comment without spoilers should not change")
+	assert.False(t, clean.SpoilersRedacted, "Comment without spoilers should not be marked as redacted")
+}
+
+func Test_RedactActivity(t *testing.T) {
+	flagged := RedactActivity(ActivityResponse{Comment: "Rosebud is a sled", ContainsSpoilers: true})
+	assert.Equal(t, SpoilerPlaceholder, flagged.Comment, "Comment of flagged activity should be redacted as a whole")
+	assert.True(t, flagged.SpoilersRedacted, "Flagged activity should be marked as redacted")
+
+	inline := RedactActivity(ActivityResponse{Comment: "Great, and ||Rosebud is a sled||"})
+	assert.Equal(t, "Great, and [spoiler]", inline.Comment, "Inline spoiler should be redacted")
+	assert.True(t, inline.SpoilersRedacted, "Activity with inline spoilers should be marked as redacted")
+
+	grade := RedactActivity(ActivityResponse{Type: ActivityGrade, Grade: 4})
+	assert.Equal(t, "", grade.Comment, "Activity without a comment should not change")
+	assert.False(t, grade.SpoilersRedacted, "Activity without a comment should not be marked as redacted")
+}
@@ -23,6 +23,21 @@ type UserModel struct {
 	CreatedAt time.Time    `json:"createdAt"`
 	UpdatedAt time.Time    `json:"updatedAt"`
 	DeletedAt sql.NullTime `json:"deletedAt"`
+	UserBan
+
+	PasswordResetPending bool `json:"-"`
+}
+
+// UserBan is the ban of a user, if any. Bans without BannedUntil never expire.
+type UserBan struct {
+	BannedAt    sql.NullTime `json:"bannedAt"`
+	BannedUntil sql.NullTime `json:"bannedUntil"`
+	BanReason   string       `json:"banReason"`
+}
+
+// Banned reports if the ban is still in effect
+func (b UserBan) Banned() bool {
+	return b.BannedAt.Valid && (!b.BannedUntil.Valid || b.BannedUntil.Time.After(time.Now()))
 }
 
 type UserBody struct {
@@ -53,6 +68,7 @@ type UserResponse struct {
 	CreatedAt time.Time    `json:"createdAt"`
 	UpdatedAt time.Time    `json:"updatedAt"`
 	DeletedAt sql.NullTime `json:"deletedAt"`
+	UserBan
 }
 
 type UserResponseWithComments struct {
@@ -60,16 +76,34 @@ type UserResponseWithComments struct {
 	Comments []CommentResponse
 }
 
+// UserFilters narrows the users list. Zero values leave the filter out of the query.
+type UserFilters struct {
+	Name  string
+	Email string
+	IsAdm *bool
+}
+
+// BanBody bans a user until the given time, or for good when Until is left out
+type BanBody struct {
+	Reason string     `json:"reason" validate:"required,max=500"`
+	Until  *time.Time `json:"until"`
+}
+
+// Columns the users list can be sorted by
+var userSortColumns = []string{"created_at", "updated_at", "name", "surname", "email"}
+
+const userBanColumns = "banned_at, banned_until, COALESCE(ban_reason, '')"
+
 func (u *UserModel) InsertUserInDB(db *sql.DB, userInfo UserBody) (UserResponse, error) {
 	log.Printf("Inserting user with email %s in DB...\n", userInfo.Email)
 
 	query := `INSERT INTO users
 			(name, surname, email, password, birthday, picture)
             VALUES ($1, $2, $3, $4, $5, $6) 
-			  	RETURNING id, name, surname, email, birthday, picture, created_at, updated_at, deleted_at;`
+			  	RETURNING id, name, surname, email, birthday, picture, created_at, updated_at, deleted_at, ` + userBanColumns + `;`
 
 	var user UserResponse
-	err := db.QueryRow(query, userInfo.Name, userInfo.Surname, userInfo.Email, userInfo.Password, userInfo.Birthday, userInfo.Picture).Scan(&user.ID, &user.Name, &user.Surname, &user.Email, &user.Birthday, &user.Picture, &user.CreatedAt, &user.UpdatedAt, &user.DeletedAt)
+	err := db.QueryRow(query, userInfo.Name, userInfo.Surname, userInfo.Email, userInfo.Password, userInfo.Birthday, userInfo.Picture).Scan(&user.ID, &user.Name, &user.Surname, &user.Email, &user.Birthday, &user.Picture, &user.CreatedAt, &user.UpdatedAt, &user.DeletedAt, &user.BannedAt, &user.BannedUntil, &user.BanReason)
 	if err != nil {
 		log.Printf("Error inserting user into database: %v\n", err)
 		return UserResponse{}, err
@@ -82,12 +116,12 @@ func (u *UserModel) GetUserByEmail(db *sql.DB, email string) (UserModel, error)
 	log.Printf("Getting user with email %s in DB... \n", email)
 
 	query := `SELECT 
-		id, name, surname, email, password, birthday, is_adm, picture, created_at, updated_at, deleted_at 
+		id, name, surname, email, password, birthday, is_adm, picture, created_at, updated_at, deleted_at, ` + userBanColumns + `, password_reset_token_hash IS NOT NULL 
 		FROM users 
 			WHERE email = $1;`
 
 	var user UserModel
-	err := db.QueryRow(query, email).Scan(&user.ID, &user.Name, &user.Surname, &user.Email, &user.Password, &user.Birthday, &user.IsAdm, &user.Picture, &user.CreatedAt, &user.UpdatedAt, &user.DeletedAt)
+	err := db.QueryRow(query, email).Scan(&user.ID, &user.Name, &user.Surname, &user.Email, &user.Password, &user.Birthday, &user.IsAdm, &user.Picture, &user.CreatedAt, &user.UpdatedAt, &user.DeletedAt, &user.BannedAt, &user.BannedUntil, &user.BanReason, &user.PasswordResetPending)
 	if err != nil {
 		log.Printf("Error getting user by email: %v\n", err)
 		return UserModel{}, err
@@ -96,50 +130,74 @@ func (u *UserModel) GetUserByEmail(db *sql.DB, email string) (UserModel, error)
 	return user, nil
 }
 
-func (u *UserModel) GetAllUsers(db *sql.DB, offset, limit int, orderBy string, deleted bool) ([]UserResponse, error) {
-	log.Printf("Getting all users in DB, with offset %v, limit %v, orderBy %v and deleted %v...\n", offset, limit, orderBy, deleted)
+func (u *UserModel) GetAllUsers(db *sql.DB, page PageRequest, orderBy string, deleted bool, filters UserFilters) (Page[UserResponse], error) {
+	log.Printf("Getting all users in DB, with page %+v, orderBy %v, deleted %v and filters %+v...\n", page, orderBy, deleted, filters)
 
-	var getUsersQueryBuilder strings.Builder
-	getUsersQueryBuilder.WriteString(`SELECT 
-		id, name, surname, email, birthday, is_adm, picture, created_at, updated_at, deleted_at 
-		FROM users`)
+	queryBuilder := NewSelect("id, name, surname, email, birthday, is_adm, picture, created_at, updated_at, deleted_at, "+userBanColumns, "users")
 
 	if !deleted {
-		getUsersQueryBuilder.WriteString(" WHERE deleted_at IS NULL")
+		queryBuilder.Where("deleted_at IS NULL")
+	}
+
+	if filters.Name != "" {
+		queryBuilder.Where("name || ' ' || surname ILIKE ?", containsPattern(filters.Name))
+	}
+
+	if filters.Email != "" {
+		queryBuilder.Where("email ILIKE ?", containsPattern(filters.Email))
+	}
+
+	if filters.IsAdm != nil {
+		queryBuilder.Where("is_adm = ?", *filters.IsAdm)
 	}
 
-	getUsersQueryBuilder.WriteString(" ORDER BY " + orderBy + " OFFSET $1 LIMIT $2;")
+	queryBuilder.OrderBy(orderBy, userSortColumns)
+	total, err := countRows(db, queryBuilder)
+	if err != nil {
+		log.Println("Error counting all users in db:", err)
+		return Page[UserResponse]{}, err
+	}
+
+	query, args, err := queryBuilder.Page(page).Build()
+	if err != nil {
+		log.Println("Error building all users query:", err)
+		return Page[UserResponse]{}, err
+	}
 
-	query := getUsersQueryBuilder.String()
-	rows, err := db.Query(query, offset, limit)
+	rows, err := db.Query(query, args...)
 	if err != nil {
 		log.Println("Error getting all users from db:", err)
-		return nil, err
+		return Page[UserResponse]{}, err
 	}
 	defer rows.Close()
 
 	var users []UserResponse
+	var keys []rowKey
 	for rows.Next() {
 		var user UserResponse
-		if err := rows.Scan(&user.ID, &user.Name, &user.Surname, &user.Email, &user.Birthday, &user.IsAdm, &user.Picture, &user.CreatedAt, &user.UpdatedAt, &user.DeletedAt); err != nil {
-			return nil, err
+		var key rowKey
+		if err := rows.Scan(&user.ID, &user.Name, &user.Surname, &user.Email, &user.Birthday, &user.IsAdm, &user.Picture, &user.CreatedAt, &user.UpdatedAt, &user.DeletedAt, &user.BannedAt, &user.BannedUntil, &user.BanReason, &key.value); err != nil {
+			return Page[UserResponse]{}, err
 		}
+		key.id = user.ID
+
 		users = append(users, user)
+		keys = append(keys, key)
 	}
 
-	return users, nil
+	return newPage(users, keys, page, orderBy, total), nil
 }
 
 func (u *UserModel) GetUserById(db *sql.DB, uuid uuid.UUID) (UserResponse, error) {
 	log.Printf("Getting user with uuid %s in DB... \n", uuid)
 
 	query := `SELECT 
-		id, name, surname, email, birthday, is_adm, picture, created_at, updated_at 
+		id, name, surname, email, birthday, is_adm, picture, created_at, updated_at, deleted_at, ` + userBanColumns + ` 
 		FROM users 
 			WHERE id = $1;`
 
 	var user UserResponse
-	err := db.QueryRow(query, uuid).Scan(&user.ID, &user.Name, &user.Surname, &user.Email, &user.Birthday, &user.IsAdm, &user.Picture, &user.CreatedAt, &user.UpdatedAt)
+	err := db.QueryRow(query, uuid).Scan(&user.ID, &user.Name, &user.Surname, &user.Email, &user.Birthday, &user.IsAdm, &user.Picture, &user.CreatedAt, &user.UpdatedAt, &user.DeletedAt, &user.BannedAt, &user.BannedUntil, &user.BanReason)
 	if err != nil {
 		log.Printf("Error getting user by uuid: %v\n", err)
 		return UserResponse{}, err
@@ -151,16 +209,39 @@ func (u *UserModel) GetUserById(db *sql.DB, uuid uuid.UUID) (UserResponse, error
 func (u *UserModel) DeleteUserById(db *sql.DB, uuid uuid.UUID) error {
 	log.Printf("Deleting user with uuid %s in DB... \n", uuid)
 
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error beginning transaction made while deleting user by id: %v\n", err)
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `UPDATE users 
 		SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP 
 		WHERE id = $1 AND deleted_at IS NULL;`
 
-	_, err := db.Exec(query, uuid)
+	_, err = tx.Exec(query, uuid)
 	if err != nil {
 		log.Printf("Error deleting user by uuid: %v\n", err)
 		return err
 	}
 
+	// A deleted user must lose access right away, so every session they still have is revoked
+	revokeSessionsQuery := `UPDATE sessions 
+		SET revoked_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP 
+		WHERE user_id = $1 AND revoked_at IS NULL;`
+
+	_, err = tx.Exec(revokeSessionsQuery, uuid)
+	if err != nil {
+		log.Printf("Error revoking sessions while deleting user by uuid: %v\n", err)
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing transaction made while deleting user by id: %v\n", err)
+		return err
+	}
+
 	return nil
 }
 
@@ -226,15 +307,160 @@ func (u *UserModel) UpdateUserById(db *sql.DB, uuid uuid.UUID, body UserEditBody
 	return user, nil
 }
 
-func (u *UserModel) UpdateUserToAdmById(db *sql.DB, uuid uuid.UUID) error {
-	log.Printf("Updating user with uuid %s to Admin in DB... \n", uuid)
+// UpdateUserToAdmById grants the admin role to the user
+func (u *UserModel) UpdateUserToAdmById(db *sql.DB, userId uuid.UUID) error {
+	log.Printf("Updating user with uuid %s to Admin in DB... \n", userId)
 
-	query := `UPDATE users SET is_adm = true WHERE id = $1;`
-	_, err := db.Exec(query, uuid)
-	if err != nil {
+	var roleModel RoleModel
+	if err := roleModel.GrantRoleToUser(db, uuid.NullUUID{}, userId, RoleAdmin); err != nil {
 		log.Printf("Error updating user to admin by uuid: %v\n", err)
 		return err
 	}
 
 	return nil
 }
+
+// BanUser bans the user for the reason of the body, replacing any ban they already had, and writes it to the audit log
+func (u *UserModel) BanUser(db *sql.DB, actorId uuid.UUID, userId uuid.UUID, body BanBody) (UserResponse, error) {
+	log.Printf("Banning user with uuid %s in DB...\n", userId)
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction to ban user: %v\n", err)
+		return UserResponse{}, err
+	}
+	defer tx.Rollback()
+
+	user, err := u.banUser(tx, actorId, userId, body)
+	if err != nil {
+		return UserResponse{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing transaction while banning user: %v\n", err)
+		return UserResponse{}, err
+	}
+
+	return user, nil
+}
+
+// banUser bans the user and audits it inside tx, so other actions can ban as part of their own transaction
+func (u *UserModel) banUser(tx *sql.Tx, actorId uuid.UUID, userId uuid.UUID, body BanBody) (UserResponse, error) {
+	var bannedUntil sql.NullTime
+	if body.Until != nil {
+		bannedUntil = sql.NullTime{Time: *body.Until, Valid: true}
+	}
+
+	query := `UPDATE users
+		SET banned_at = CURRENT_TIMESTAMP, banned_until = $1, ban_reason = $2
+		WHERE id = $3 AND deleted_at IS NULL
+			RETURNING id, name, surname, email, birthday, is_adm, picture, created_at, updated_at, deleted_at, ` + userBanColumns + `;`
+
+	var user UserResponse
+	if err := tx.QueryRow(query, bannedUntil, body.Reason, userId).Scan(&user.ID, &user.Name, &user.Surname, &user.Email, &user.Birthday, &user.IsAdm, &user.Picture, &user.CreatedAt, &user.UpdatedAt, &user.DeletedAt, &user.BannedAt, &user.BannedUntil, &user.BanReason); err != nil {
+		log.Printf("Error banning user: %v\n", err)
+		return UserResponse{}, err
+	}
+
+	entry := AuditLogModel{Action: AuditBan, Reason: body.Reason, ExpiresAt: bannedUntil, ActorId: uuid.NullUUID{UUID: actorId, Valid: true}, TargetId: userId.String()}
+	if err := insertAuditLog(tx, entry); err != nil {
+		return UserResponse{}, err
+	}
+
+	return user, nil
+}
+
+func (u *UserModel) UnbanUser(db *sql.DB, actorId uuid.UUID, userId uuid.UUID) (UserResponse, error) {
+	log.Printf("Unbanning user with uuid %s in DB...\n", userId)
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction to unban user: %v\n", err)
+		return UserResponse{}, err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE users
+		SET banned_at = NULL, banned_until = NULL, ban_reason = NULL
+		WHERE id = $1 AND deleted_at IS NULL
+			RETURNING id, name, surname, email, birthday, is_adm, picture, created_at, updated_at, deleted_at, ` + userBanColumns + `;`
+
+	var user UserResponse
+	if err := tx.QueryRow(query, userId).Scan(&user.ID, &user.Name, &user.Surname, &user.Email, &user.Birthday, &user.IsAdm, &user.Picture, &user.CreatedAt, &user.UpdatedAt, &user.DeletedAt, &user.BannedAt, &user.BannedUntil, &user.BanReason); err != nil {
+		log.Printf("Error unbanning user: %v\n", err)
+		return UserResponse{}, err
+	}
+
+	if err := insertAuditLog(tx, AuditLogModel{Action: AuditUnban, ActorId: uuid.NullUUID{UUID: actorId, Valid: true}, TargetId: userId.String()}); err != nil {
+		return UserResponse{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing transaction while unbanning user: %v\n", err)
+		return UserResponse{}, err
+	}
+
+	return user, nil
+}
+
+// RequirePasswordReset stores the hash of a reset token for the user and revokes their sessions,
+// so they can only get back in by setting a new password with the token
+func (u *UserModel) RequirePasswordReset(db *sql.DB, actorId uuid.UUID, userId uuid.UUID, resetTokenHash string, expiresAt time.Time) error {
+	log.Printf("Requiring password reset of user with uuid %s in DB...\n", userId)
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction to require password reset: %v\n", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE users
+		SET password_reset_token_hash = $1, password_reset_expires_at = $2
+		WHERE id = $3 AND deleted_at IS NULL;`
+
+	if _, err := tx.Exec(query, resetTokenHash, expiresAt, userId); err != nil {
+		log.Printf("Error requiring password reset of user: %v\n", err)
+		return err
+	}
+
+	revokeSessionsQuery := `UPDATE sessions 
+		SET revoked_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP 
+		WHERE user_id = $1 AND revoked_at IS NULL;`
+
+	if _, err := tx.Exec(revokeSessionsQuery, userId); err != nil {
+		log.Printf("Error revoking sessions while requiring password reset: %v\n", err)
+		return err
+	}
+
+	entry := AuditLogModel{Action: AuditPasswordReset, ExpiresAt: sql.NullTime{Time: expiresAt, Valid: true}, ActorId: uuid.NullUUID{UUID: actorId, Valid: true}, TargetId: userId.String()}
+	if err := insertAuditLog(tx, entry); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing transaction while requiring password reset: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
+// ResetPassword sets the already hashed password of the user with the reset token, consuming the token.
+// Returns sql.ErrNoRows if the token is unknown or expired.
+func (u *UserModel) ResetPassword(db *sql.DB, resetTokenHash string, hashedPassword string) (uuid.UUID, error) {
+	log.Println("Resetting user password in DB...")
+
+	query := `UPDATE users
+		SET password = $1, password_reset_token_hash = NULL, password_reset_expires_at = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE password_reset_token_hash = $2 AND password_reset_expires_at > CURRENT_TIMESTAMP AND deleted_at IS NULL
+			RETURNING id;`
+
+	var userId uuid.UUID
+	if err := db.QueryRow(query, hashedPassword, resetTokenHash).Scan(&userId); err != nil {
+		log.Printf("Error resetting user password: %v\n", err)
+		return uuid.Nil, err
+	}
+
+	return userId, nil
+}
@@ -0,0 +1,154 @@
+package models
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WatchlistModel struct {
+	ID        uuid.UUID `json:"id"`
+	Note      string    `json:"note"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	UserId  string `json:"userId"`
+	MovieId string `json:"movieId"`
+}
+
+type WatchlistBody struct {
+	MovieId string `json:"movieId" validate:"required,isvaliduuid"`
+	Note    string `json:"note" validate:"omitempty,max=500"`
+}
+
+// WatchlistItemResponse is a movie in the watchlist of a user, with when it was added
+type WatchlistItemResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Note      string    `json:"note"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	UserId string        `json:"userId"`
+	Movie  MovieResponse `json:"movie"`
+}
+
+// Columns the watchlist can be sorted by
+var watchlistSortColumns = []string{"created_at", "title", "release_date", "weighted_rating"}
+
+const watchlistColumns = `id, note, created_at, user_id,
+	movie_id, title, director, release_date, average_grade, picture, synopsis, movie_created_at, movie_updated_at, movie_deleted_at, creator_id, rating_count, grade_histogram, weighted_rating`
+
+// The items are joined with their movies in a subquery, so they can be sorted and paginated by the columns of both
+const watchlistTable = `(SELECT w.id, w.note, w.created_at, w.user_id,
+		m.id AS movie_id, m.title, m.director, m.release_date, m.average_grade, m.picture, m.synopsis, m.created_at AS movie_created_at,
+		m.updated_at AS movie_updated_at, m.deleted_at AS movie_deleted_at, m.creator_id, m.rating_count, m.grade_histogram, m.weighted_rating
+		FROM watchlist w
+			JOIN movies m ON m.id = w.movie_id) AS watchlist`
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows, so single and list reads share the same scan
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanWatchlistItem(row rowScanner, extra ...interface{}) (WatchlistItemResponse, error) {
+	var item WatchlistItemResponse
+	movie := &item.Movie
+	dest := []interface{}{&item.ID, &item.Note, &item.CreatedAt, &item.UserId, &movie.ID, &movie.Title, &movie.Director, &movie.ReleaseDate, &movie.AverageGrade, &movie.Picture, &movie.Synopsis, &movie.CreatedAt, &movie.UpdatedAt, &movie.DeletedAt, &movie.CreatorId, &movie.RatingCount, &movie.GradeHistogram, &movie.WeightedRating}
+	err := row.Scan(append(dest, extra...)...)
+
+	return item, err
+}
+
+// Public methods
+func (w *WatchlistModel) InsertWatchlistItemInDB(db *sql.DB, userId uuid.UUID, body WatchlistBody) (WatchlistItemResponse, error) {
+	log.Printf("Adding movie %s to the watchlist of user %s in DB...\n", body.MovieId, userId)
+
+	query := `INSERT INTO watchlist (note, user_id, movie_id) VALUES ($1, $2, $3);`
+	if _, err := db.Exec(query, body.Note, userId, body.MovieId); err != nil {
+		log.Printf("Error inserting watchlist item into database: %v\n", err)
+		return WatchlistItemResponse{}, err
+	}
+
+	movieId, err := uuid.Parse(body.MovieId)
+	if err != nil {
+		log.Printf("Error parsing movie id of watchlist item: %v\n", err)
+		return WatchlistItemResponse{}, err
+	}
+
+	return w.GetWatchlistItem(db, userId, movieId)
+}
+
+// GetWatchlistItem returns the movie in the watchlist of the user, or sql.ErrNoRows if it is not there
+func (w *WatchlistModel) GetWatchlistItem(db *sql.DB, userId uuid.UUID, movieId uuid.UUID) (WatchlistItemResponse, error) {
+	log.Printf("Getting movie %s of the watchlist of user %s in DB...\n", movieId, userId)
+
+	query := `SELECT ` + watchlistColumns + `
+		FROM ` + watchlistTable + `
+			WHERE user_id = $1 AND movie_id = $2;`
+
+	item, err := scanWatchlistItem(db.QueryRow(query, userId, movieId))
+	if err != nil {
+		log.Printf("Error getting watchlist item: %v\n", err)
+		return WatchlistItemResponse{}, err
+	}
+
+	return item, nil
+}
+
+func (w *WatchlistModel) GetUserWatchlist(db *sql.DB, userId uuid.UUID, page PageRequest, orderBy string) (Page[WatchlistItemResponse], error) {
+	log.Printf("Getting watchlist of user %s in DB, with page %+v and orderBy %v...\n", userId, page, orderBy)
+
+	// Deleted movies leave the watchlist without removing the rows, so they come back if the movie is restored
+	queryBuilder := NewSelect(watchlistColumns, watchlistTable).
+		Where("user_id = ?", userId).
+		Where("movie_deleted_at IS NULL").
+		OrderBy(orderBy, watchlistSortColumns)
+
+	total, err := countRows(db, queryBuilder)
+	if err != nil {
+		log.Printf("Error counting watchlist of user %v in db: %v \n", userId, err)
+		return Page[WatchlistItemResponse]{}, err
+	}
+
+	query, args, err := queryBuilder.Page(page).Build()
+	if err != nil {
+		log.Printf("Error building watchlist query of user %v: %v \n", userId, err)
+		return Page[WatchlistItemResponse]{}, err
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		log.Printf("Error getting watchlist of user %v from db: %v \n", userId, err)
+		return Page[WatchlistItemResponse]{}, err
+	}
+	defer rows.Close()
+
+	var items []WatchlistItemResponse
+	var keys []rowKey
+	for rows.Next() {
+		var key rowKey
+		item, err := scanWatchlistItem(rows, &key.value)
+		if err != nil {
+			log.Printf("Error scanning rows while getting watchlist of user %v from db: %v \n", userId, err)
+			return Page[WatchlistItemResponse]{}, err
+		}
+		key.id = item.ID
+
+		items = append(items, item)
+		keys = append(keys, key)
+	}
+
+	return newPage(items, keys, page, orderBy, total), nil
+}
+
+func (w *WatchlistModel) DeleteWatchlistItem(db *sql.DB, userId uuid.UUID, movieId uuid.UUID) error {
+	log.Printf("Removing movie %s from the watchlist of user %s in DB...\n", movieId, userId)
+
+	query := `DELETE FROM watchlist WHERE user_id = $1 AND movie_id = $2;`
+	if _, err := db.Exec(query, userId, movieId); err != nil {
+		log.Printf("Error deleting watchlist item: %v\n", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package moderation
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Blocklist holds the words and regular expressions that send a comment to the moderation queue.
+// The zero value and a nil Blocklist match nothing.
+type Blocklist struct {
+	patterns []*regexp.Regexp
+}
+
+// Letters, numbers and underscores are part of a word, so plain entries don't match inside other words
+const wordBoundaryStart = `(?:^|[^\p{L}\p{N}_])`
+const wordBoundaryEnd = `(?:$|[^\p{L}\p{N}_])`
+
+// NewBlocklist compiles the entries of the blocklist. Entries wrapped in slashes, like /sp[o0]iler/, are regular expressions,
+// and any other entry is a word or phrase matched as a whole. Both are case insensitive, and empty entries are skipped.
+func NewBlocklist(entries []string) (*Blocklist, error) {
+	blocklist := &Blocklist{}
+
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		var expression string
+		if len(entry) > 2 && strings.HasPrefix(entry, "/") && strings.HasSuffix(entry, "/") {
+			expression = "(?i)" + entry[1:len(entry)-1]
+		} else {
+			expression = "(?i)" + wordBoundaryStart + regexp.QuoteMeta(entry) + wordBoundaryEnd
+		}
+
+		pattern, err := regexp.Compile(expression)
+		if err != nil {
+			return nil, fmt.Errorf("invalid blocklist entry %q: %v", entry, err)
+		}
+
+		blocklist.patterns = append(blocklist.patterns, pattern)
+	}
+
+	return blocklist, nil
+}
+
+// LoadBlocklist reads a blocklist file with one entry per line. Lines starting with # are comments.
+func LoadBlocklist(path string) (*Blocklist, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening blocklist file: %v", err)
+	}
+	defer file.Close()
+
+	var entries []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		entries = append(entries, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading blocklist file: %v", err)
+	}
+
+	return NewBlocklist(entries)
+}
+
+// Matches tells if any entry of the blocklist is in the text
+func (b *Blocklist) Matches(text string) bool {
+	if b == nil {
+		return false
+	}
+
+	for _, pattern := range b.patterns {
+		if pattern.MatchString(text) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Len is the number of entries in the blocklist
+func (b *Blocklist) Len() int {
+	if b == nil {
+		return 0
+	}
+
+	return len(b.patterns)
+}
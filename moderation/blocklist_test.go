@@ -0,0 +1,70 @@
+package moderation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_BlocklistMatches(t *testing.T) {
+	blocklist, err := NewBlocklist([]string{"darn", "  ", "bad word", "/sp[o0]il(er|ed)/", "ação"})
+	if err != nil {
+		t.Fatalf("Error creating blocklist: %v", err)
+	}
+	assert.Equal(t, 4, blocklist.Len(), "Empty entries should be skipped")
+
+	testCases := []struct {
+		Have string
+		Want bool
+	}{
+		{"Darn, what a movie", true},
+		{"What a DARN movie", true},
+		{"darned good", false},
+		{"undarn", false},
+		{"A bad word here", true},
+		{"A bad wording here", false},
+		{"Sp0iled the ending", true},
+		// Regular expressions match anywhere, unlike words
+		{"No spoilers here", true},
+		{"Sp0il the ending", false},
+		{"spoiler", true},
+		{"Filme de ação!", true},
+		{"Reação", false},
+		{"A clean comment", false},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.Want, blocklist.Matches(testCase.Have), "Unexpected match for %q", testCase.Have)
+	}
+}
+
+func Test_NilBlocklist(t *testing.T) {
+	var blocklist *Blocklist
+	assert.False(t, blocklist.Matches("anything"), "Nil blocklist should match nothing")
+	assert.Equal(t, 0, blocklist.Len(), "Nil blocklist should be empty")
+}
+
+func Test_InvalidBlocklistEntry(t *testing.T) {
+	_, err := NewBlocklist([]string{"/sp[oiler/"})
+	assert.Error(t, err, "Expected error for invalid regular expression")
+}
+
+func Test_LoadBlocklist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+	if err := os.WriteFile(path, []byte("# words\ndarn\n\n/sp[o0]iler/\n"), 0o644); err != nil {
+		t.Fatalf("Error writing blocklist file: %v", err)
+	}
+
+	blocklist, err := LoadBlocklist(path)
+	if err != nil {
+		t.Fatalf("Error loading blocklist: %v", err)
+	}
+	assert.Equal(t, 2, blocklist.Len(), "Comments and empty lines should be skipped")
+	assert.True(t, blocklist.Matches("sp0iler alert"), "Expected match for regular expression entry")
+	assert.False(t, blocklist.Matches("# words"), "Comment lines should not be entries")
+
+	_, err = LoadBlocklist(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Error(t, err, "Expected error for missing file")
+}
@@ -0,0 +1,290 @@
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/VinOfSteel/cinemagrader/apierrors"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Version of the OpenAPI specification the document follows
+const Version = "3.1.0"
+
+// Name of the bearer token security scheme, which every authenticated operation uses
+const bearerScheme = "bearerAuth"
+
+// Operation describes a route of the API. Body and Response are zero values of the Go types sent and returned,
+// and are described from their fields and validate tags. A nil Response means the route answers without a body.
+type Operation struct {
+	Method      string
+	Path        string
+	Tag         string
+	Summary     string
+	Description string
+
+	// Auth requires a bearer token. OptionalAuth accepts requests with or without one.
+	Auth         bool
+	OptionalAuth bool
+	// Permissions the token needs to carry one of. Owner or self routes list what lets other users through.
+	Permissions []string
+
+	Query    []Parameter
+	Body     any
+	Status   int
+	Response any
+}
+
+// Key identifies the operation by method and fiber path, the same way the router does
+func (o Operation) Key() string {
+	return strings.ToUpper(o.Method) + " " + o.Path
+}
+
+// Parameter is a path or query parameter. Path params are read from the path by Build, query params are declared with the helpers below.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// Document is the OpenAPI document of the API
+type Document struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       Info                            `json:"info"`
+	Paths      map[string]map[string]*PathItem `json:"paths"`
+	Components Components                      `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem is an operation of the document, under its path and lowercased method
+type PathItem struct {
+	Tags        []string              `json:"tags,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	OperationID string                `json:"operationId"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// Fiber params, like :uuid, become OpenAPI templates, like {uuid}
+var pathParamRegex = regexp.MustCompile(`:([A-Za-z0-9_]+)`)
+
+// QueryParam declares an optional query param of the given JSON Schema type
+func QueryParam(name string, schemaType string, description string) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: &Schema{Type: schemaType}}
+}
+
+// EnumParam declares an optional query param that takes one of the values, the first one being the default
+func EnumParam(name string, description string, values ...string) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: &Schema{Type: "string", Enum: values, Default: values[0]}}
+}
+
+// FormatParam declares an optional string query param with a format, like uuid or date
+func FormatParam(name string, format string, description string) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: &Schema{Type: "string", Format: format}}
+}
+
+// Build generates the document with an entry for each operation, describing the Go types they use as components
+func Build(info Info, operations []Operation) (Document, error) {
+	registry := newSchemaRegistry()
+	errorSchema, err := problemSchema(registry)
+	if err != nil {
+		return Document{}, err
+	}
+
+	document := Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   map[string]map[string]*PathItem{},
+		Components: Components{
+			Schemas: registry.components,
+			SecuritySchemes: map[string]*SecurityScheme{
+				bearerScheme: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+
+	for _, operation := range operations {
+		path := pathParamRegex.ReplaceAllString(operation.Path, "{$1}")
+		method := strings.ToLower(operation.Method)
+
+		if _, found := document.Paths[path][method]; found {
+			return Document{}, fmt.Errorf("operation %v is declared twice", operation.Key())
+		}
+
+		item := &PathItem{
+			Tags:        []string{operation.Tag},
+			Summary:     operation.Summary,
+			Description: operationDescription(operation),
+			OperationID: operationID(method, path),
+			Responses: map[string]*Response{
+				"default": {
+					Description: "Error",
+					Content:     problemContent(errorSchema),
+				},
+			},
+		}
+
+		for _, match := range pathParamRegex.FindAllStringSubmatch(operation.Path, -1) {
+			item.Parameters = append(item.Parameters, pathParameter(match[1]))
+		}
+		item.Parameters = append(item.Parameters, operation.Query...)
+
+		if operation.Body != nil {
+			schema, err := registry.schemaFor(reflect.TypeOf(operation.Body))
+			if err != nil {
+				return Document{}, fmt.Errorf("body of %v: %w", operation.Key(), err)
+			}
+
+			item.RequestBody = &RequestBody{Required: true, Content: jsonContent(schema)}
+			item.Responses[strconv.Itoa(http.StatusBadRequest)] = &Response{
+				Description: "Validation failed, with the message of each invalid field in the details",
+				Content:     problemContent(errorSchema),
+			}
+		}
+
+		response := &Response{Description: http.StatusText(operation.Status)}
+		if operation.Response != nil {
+			schema, err := registry.schemaFor(reflect.TypeOf(operation.Response))
+			if err != nil {
+				return Document{}, fmt.Errorf("response of %v: %w", operation.Key(), err)
+			}
+
+			response.Content = jsonContent(schema)
+		}
+		item.Responses[strconv.Itoa(operation.Status)] = response
+
+		switch {
+		case operation.Auth:
+			item.Security = []map[string][]string{{bearerScheme: {}}}
+		case operation.OptionalAuth:
+			item.Security = []map[string][]string{{}, {bearerScheme: {}}}
+		}
+
+		if document.Paths[path] == nil {
+			document.Paths[path] = map[string]*PathItem{}
+		}
+		document.Paths[path][method] = item
+	}
+
+	return document, nil
+}
+
+func jsonContent(schema *Schema) map[string]*MediaType {
+	return map[string]*MediaType{fiber.MIMEApplicationJSON: {Schema: schema}}
+}
+
+// problemSchema describes the apierrors.Problem sent for every failed request, listing the codes of the catalogue
+func problemSchema(registry *schemaRegistry) (*Schema, error) {
+	schema, err := registry.schemaFor(reflect.TypeOf(apierrors.Problem{}))
+	if err != nil {
+		return nil, err
+	}
+
+	code := registry.components[componentName(reflect.TypeOf(apierrors.Problem{}))].Properties["code"]
+	for _, e := range apierrors.All() {
+		code.Enum = append(code.Enum, e.Code)
+	}
+
+	return schema, nil
+}
+
+func problemContent(schema *Schema) map[string]*MediaType {
+	return map[string]*MediaType{apierrors.ContentType: {Schema: schema}}
+}
+
+// pathParameter describes a param of the path. The params named like uuid take ids, and the others plain strings.
+func pathParameter(name string) Parameter {
+	schema := &Schema{Type: "string"}
+	if strings.HasSuffix(strings.ToLower(name), "uuid") {
+		schema.Format = "uuid"
+	}
+
+	return Parameter{Name: name, In: "path", Required: true, Schema: schema}
+}
+
+// operationDescription adds the permissions of the operation to its description
+func operationDescription(operation Operation) string {
+	if len(operation.Permissions) == 0 {
+		return operation.Description
+	}
+
+	permissions := "Requires one of the permissions: " + strings.Join(operation.Permissions, ", ") + "."
+	if operation.Description == "" {
+		return permissions
+	}
+
+	return operation.Description + "\n\n" + permissions
+}
+
+// operationID is the method followed by the segments of the path, like getUsersUuidComments
+func operationID(method string, path string) string {
+	id := method
+	for _, segment := range strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == '{' || r == '}' || r == '-' || r == '_' }) {
+		id += strings.ToUpper(segment[:1]) + segment[1:]
+	}
+
+	return id
+}
+
+//go:embed docs.html
+var docsPage []byte
+
+// Handler serves the document, marshalled once since it doesn't change while the server runs
+func Handler(document Document) (fiber.Handler, error) {
+	body, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		return c.Status(fiber.StatusOK).Send(body)
+	}, nil
+}
+
+// DocsHandler serves a Swagger UI page that reads the document from /openapi.json
+func DocsHandler(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	return c.Status(fiber.StatusOK).Send(docsPage)
+}
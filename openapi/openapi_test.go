@@ -0,0 +1,123 @@
+package openapi
+
+import (
+	"database/sql"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/VinOfSteel/cinemagrader/apierrors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type testBody struct {
+	Name     string   `json:"name" validate:"required,max=50"`
+	Email    string   `json:"email" validate:"required,email"`
+	Birthday string   `json:"birthday" validate:"required,datetime=2006-01-02"`
+	Role     string   `json:"role" validate:"omitempty,oneof=admin moderator"`
+	Grade    float64  `json:"grade" validate:"isvalidgrade"`
+	Ids      []string `json:"ids" validate:"required,min=1,dive,required,isvaliduuid"`
+	Skipped  string   `json:"-"`
+}
+
+type testEmbedded struct {
+	ID uuid.UUID `json:"id"`
+}
+
+type testResponse struct {
+	testEmbedded
+	DeletedAt sql.NullTime  `json:"deletedAt"`
+	Parent    *testResponse `json:"parent"`
+	Owner     uuid.NullUUID `json:"owner"`
+}
+
+type testPage[T any] struct {
+	Data []T `json:"data"`
+}
+
+func Test_StructSchema(t *testing.T) {
+	registry := newSchemaRegistry()
+	ref, err := registry.schemaFor(reflect.TypeOf(testBody{}))
+	if err != nil {
+		t.Fatalf("Error describing body: %v", err)
+	}
+	assert.Equal(t, "#/components/schemas/testBody", ref.Ref, "Structs should be referenced")
+
+	schema := registry.components["testBody"]
+	assert.Equal(t, []string{"name", "email", "birthday", "ids"}, schema.Required, "Required fields mismatch")
+	assert.NotContains(t, schema.Properties, "Skipped", "Fields ignored by json should be left out")
+	assert.Equal(t, 50, *schema.Properties["name"].MaxLength, "max should bound the length of strings")
+	assert.Equal(t, "email", schema.Properties["email"].Format, "email format mismatch")
+	assert.Equal(t, "date", schema.Properties["birthday"].Format, "datetime format mismatch")
+	assert.Equal(t, []string{"admin", "moderator"}, schema.Properties["role"].Enum, "oneof should become an enum")
+	assert.Equal(t, 5.0, *schema.Properties["grade"].Maximum, "Grade maximum mismatch")
+	assert.Equal(t, 1, *schema.Properties["ids"].MinItems, "min should bound the items of slices")
+	assert.Equal(t, "uuid", schema.Properties["ids"].Items.Format, "Rules after dive should apply to the items")
+}
+
+func Test_NullableSchema(t *testing.T) {
+	registry := newSchemaRegistry()
+	if _, err := registry.schemaFor(reflect.TypeOf(testResponse{})); err != nil {
+		t.Fatalf("Error describing response: %v", err)
+	}
+
+	schema := registry.components["testResponse"]
+	assert.Equal(t, "uuid", schema.Properties["id"].Format, "Embedded fields should be flattened")
+	assert.Equal(t, []string{"string", "null"}, schema.Properties["deletedAt"].Type, "sql.NullTime should be nullable")
+	assert.Equal(t, []string{"string", "null"}, schema.Properties["owner"].Type, "uuid.NullUUID should be nullable")
+	assert.Equal(t, "#/components/schemas/testResponse", schema.Properties["parent"].AnyOf[0].Ref, "Pointers to structs should reference them")
+	assert.Equal(t, "null", schema.Properties["parent"].AnyOf[1].Type, "Pointers should be nullable")
+}
+
+func Test_ComponentName(t *testing.T) {
+	assert.Equal(t, "testBody", componentName(reflect.TypeOf(testBody{})), "Name of plain types mismatch")
+	assert.Equal(t, "testResponsetestPage", componentName(reflect.TypeOf(testPage[testResponse]{})), "Name of generic types mismatch")
+}
+
+func Test_Build(t *testing.T) {
+	operations := []Operation{
+		{Method: http.MethodPost, Path: "/users/:uuid/items/:itemId", Tag: "Items", Auth: true, Permissions: []string{"items:edit"},
+			Body: testBody{}, Status: http.StatusCreated, Response: testResponse{}},
+		{Method: http.MethodGet, Path: "/items", Tag: "Items", OptionalAuth: true,
+			Query: []Parameter{EnumParam("sort", "Order", "name,asc", "name,desc")}, Status: http.StatusOK, Response: testPage[testResponse]{}},
+		{Method: http.MethodDelete, Path: "/items/:uuid", Tag: "Items", Status: http.StatusNoContent},
+	}
+
+	document, err := Build(Info{Title: "Test", Version: "1"}, operations)
+	if err != nil {
+		t.Fatalf("Error building document: %v", err)
+	}
+
+	create := document.Paths["/users/{uuid}/items/{itemId}"]["post"]
+	if assert.NotNil(t, create, "Path params should be turned into templates") {
+		assert.Equal(t, "postUsersUuidItemsItemId", create.OperationID, "Operation id mismatch")
+		assert.Equal(t, "uuid", create.Parameters[0].Schema.Format, "uuid params should have the uuid format")
+		assert.Empty(t, create.Parameters[1].Schema.Format, "Other params should be plain strings")
+		assert.Contains(t, create.Responses, "400", "Operations with a body should describe validation errors")
+		assert.Contains(t, create.Responses["default"].Content, apierrors.ContentType, "Errors should be problem documents")
+		assert.Contains(t, create.Responses, "201", "Operations should describe their status")
+		assert.Contains(t, create.Description, "items:edit", "Permissions should be described")
+		assert.Equal(t, []map[string][]string{{bearerScheme: {}}}, create.Security, "Security of authenticated operation mismatch")
+	}
+
+	list := document.Paths["/items"]["get"]
+	if assert.NotNil(t, list, "Operation without params should be kept") {
+		assert.Len(t, list.Security, 2, "Optional auth should accept requests with and without a token")
+		assert.Equal(t, "name,asc", list.Parameters[0].Schema.Default, "Enum params should default to the first value")
+	}
+
+	deleted := document.Paths["/items/{uuid}"]["delete"]
+	if assert.NotNil(t, deleted, "Delete operation should be kept") {
+		assert.Nil(t, deleted.Responses["204"].Content, "Operations without response should have no content")
+		assert.Empty(t, deleted.Security, "Public operations should have no security")
+	}
+
+	problem := document.Components.Schemas["Problem"]
+	if assert.NotNil(t, problem, "Problem should be a component") {
+		assert.Contains(t, problem.Properties["code"].Enum, apierrors.ValidationFailed.Code, "Codes of the catalogue should be listed")
+	}
+
+	_, err = Build(Info{}, append(operations, operations[2]))
+	assert.Error(t, err, "Operations declared twice should fail")
+}
@@ -0,0 +1,337 @@
+package openapi
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Schema is the subset of JSON Schema used by OpenAPI 3.1 that the API types need
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Type                 any                `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Default              any                `json:"default,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMinimum     *float64           `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     *float64           `json:"exclusiveMaximum,omitempty"`
+}
+
+// Types that marshal themselves to JSON, described by what they turn into instead of by their fields
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	nullTimeType = reflect.TypeOf(sql.NullTime{})
+	uuidType     = reflect.TypeOf(uuid.UUID{})
+	nullUUIDType = reflect.TypeOf(uuid.NullUUID{})
+)
+
+// Descriptions of the custom validations registered in initializers.NewValidator
+var customValidations = map[string]string{
+	"password":        "At least 8 characters, with a symbol, an uppercased letter and a number.",
+	"isadminuuid":     "Id of a user allowed to edit the catalog.",
+	"validactorslice": "Ids of existing actors.",
+	"validgenreslice": "Ids of existing genres.",
+	"ispersonuuid":    "Id of an existing person.",
+}
+
+// schemaRegistry generates the schemas of Go types, keeping every struct as a named component so they are only described once
+type schemaRegistry struct {
+	components map[string]*Schema
+	names      map[reflect.Type]string
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{
+		components: map[string]*Schema{},
+		names:      map[reflect.Type]string{},
+	}
+}
+
+// componentName is the name of the type without its package. Generic types get the name of their type argument in front,
+// so models.Page[models.UserResponse] becomes UserResponsePage.
+func componentName(t reflect.Type) string {
+	name := t.Name()
+	start := strings.Index(name, "[")
+	if start == -1 {
+		return name
+	}
+
+	argument := strings.TrimSuffix(name[start+1:], "]")
+	argument = argument[strings.LastIndex(argument, ".")+1:]
+
+	return argument + name[:start]
+}
+
+// schemaFor returns the schema of the type, which is a reference for structs and an inline schema for everything else
+func (r *schemaRegistry) schemaFor(t reflect.Type) (*Schema, error) {
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}, nil
+	case nullTimeType:
+		return &Schema{Type: []string{"string", "null"}, Format: "date-time"}, nil
+	case uuidType:
+		return &Schema{Type: "string", Format: "uuid"}, nil
+	case nullUUIDType:
+		return &Schema{Type: []string{"string", "null"}, Format: "uuid"}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema, err := r.schemaFor(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+
+		return nullable(schema), nil
+	case reflect.String:
+		return &Schema{Type: "string"}, nil
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}, nil
+	case reflect.Interface:
+		return &Schema{}, nil
+	case reflect.Slice, reflect.Array:
+		items, err := r.schemaFor(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+
+		schema := &Schema{Type: "array", Items: items}
+		if t.Kind() == reflect.Array {
+			length := t.Len()
+			schema.MinItems, schema.MaxItems = &length, &length
+		}
+
+		return schema, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("map %v needs string keys to be described", t)
+		}
+
+		values, err := r.schemaFor(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+
+		return &Schema{Type: "object", AdditionalProperties: values}, nil
+	case reflect.Struct:
+		return r.structRef(t)
+	}
+
+	return nil, fmt.Errorf("type %v can't be described in the spec", t)
+}
+
+// nullable lets the schema also be null
+func nullable(schema *Schema) *Schema {
+	switch schemaType := schema.Type.(type) {
+	case string:
+		schema.Type = []string{schemaType, "null"}
+		return schema
+	case nil:
+		if schema.Ref != "" {
+			return &Schema{AnyOf: []*Schema{schema, {Type: "null"}}}
+		}
+	}
+
+	return schema
+}
+
+// structRef registers the struct as a component, if it wasn't already, and references it
+func (r *schemaRegistry) structRef(t reflect.Type) (*Schema, error) {
+	name, found := r.names[t]
+	if !found {
+		name = componentName(t)
+		if _, taken := r.components[name]; taken {
+			return nil, fmt.Errorf("two types are named %v in the spec", name)
+		}
+
+		// Registered before the fields are described, so types that reference themselves end up as a reference
+		r.names[t] = name
+		r.components[name] = &Schema{}
+
+		schema, err := r.structSchema(t)
+		if err != nil {
+			return nil, err
+		}
+		r.components[name] = schema
+	}
+
+	return &Schema{Ref: "#/components/schemas/" + name}, nil
+}
+
+// structSchema describes the fields of the struct the way encoding/json sees them, with embedded structs flattened
+func (r *schemaRegistry) structSchema(t reflect.Type) (*Schema, error) {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if jsonName == "-" {
+			continue
+		}
+
+		// Like encoding/json, the exported fields of embedded structs are promoted even when the struct type is unexported
+		if field.Anonymous && jsonName == "" && field.Type.Kind() == reflect.Struct {
+			embedded, err := r.structSchema(field.Type)
+			if err != nil {
+				return nil, err
+			}
+
+			for name, property := range embedded.Properties {
+				schema.Properties[name] = property
+			}
+			schema.Required = append(schema.Required, embedded.Required...)
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if jsonName == "" {
+			jsonName = field.Name
+		}
+
+		property, err := r.schemaFor(field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("field %v of %v: %w", field.Name, t, err)
+		}
+
+		required := applyValidateTag(property, field.Tag.Get("validate"))
+		if required {
+			schema.Required = append(schema.Required, jsonName)
+		}
+
+		schema.Properties[jsonName] = property
+	}
+
+	return schema, nil
+}
+
+// applyValidateTag adds the constraints of the validate tag of a field to its schema and reports if the field is required.
+// Rules after "dive" apply to the items of slices.
+func applyValidateTag(schema *Schema, tag string) bool {
+	if tag == "" || tag == "-" {
+		return false
+	}
+
+	required := false
+	target := schema
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+
+		switch name {
+		case "required":
+			if target == schema {
+				required = true
+			}
+		case "dive":
+			if target.Items != nil {
+				target = target.Items
+			}
+		case "email":
+			target.Format = "email"
+		case "url":
+			target.Format = "uri"
+		case "uuid", "uuid4", "isvaliduuid":
+			target.Format = "uuid"
+		case "datetime":
+			if param == "2006-01-02" {
+				target.Format = "date"
+			} else {
+				target.Description = "Date in the Go layout " + param + "."
+			}
+		case "oneof":
+			target.Enum = strings.Fields(param)
+		case "isvalidgrade":
+			low, high := 1.0, 5.0
+			target.Minimum, target.Maximum = &low, &high
+		case "min", "max", "len", "gte", "lte", "gt", "lt":
+			applyBound(target, name, param)
+		default:
+			if description, found := customValidations[name]; found {
+				target.Description = description
+				if name != "password" {
+					applyUUIDFormat(target)
+				}
+			}
+		}
+	}
+
+	return required
+}
+
+// applyUUIDFormat marks the schema, or the items of an array schema, as uuids
+func applyUUIDFormat(schema *Schema) {
+	if schema.Type == "array" && schema.Items != nil {
+		schema = schema.Items
+	}
+
+	if schema.Type == "string" {
+		schema.Format = "uuid"
+	}
+}
+
+// applyBound turns a size rule of the validator into the matching keyword, which depends on the type the rule applies to
+func applyBound(schema *Schema, rule string, param string) {
+	number, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+	size := int(number)
+
+	switch schema.Type {
+	case "string":
+		switch rule {
+		case "min", "gte":
+			schema.MinLength = &size
+		case "max", "lte":
+			schema.MaxLength = &size
+		case "len":
+			schema.MinLength, schema.MaxLength = &size, &size
+		}
+	case "array":
+		switch rule {
+		case "min", "gte":
+			schema.MinItems = &size
+		case "max", "lte":
+			schema.MaxItems = &size
+		case "len":
+			schema.MinItems, schema.MaxItems = &size, &size
+		}
+	case "integer", "number":
+		switch rule {
+		case "min", "gte":
+			schema.Minimum = &number
+		case "max", "lte":
+			schema.Maximum = &number
+		case "len":
+			schema.Minimum, schema.Maximum = &number, &number
+		case "gt":
+			schema.ExclusiveMinimum = &number
+		case "lt":
+			schema.ExclusiveMaximum = &number
+		}
+	}
+}
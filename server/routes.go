@@ -0,0 +1,206 @@
+package server
+
+import (
+	"database/sql"
+
+	"github.com/VinOfSteel/cinemagrader/controllers"
+	"github.com/VinOfSteel/cinemagrader/middleware"
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/VinOfSteel/cinemagrader/moderation"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RegisterRoutes registers every route of the API. Each of them needs an entry in apiOperations, which the tests check.
+func RegisterRoutes(app *fiber.App, db *sql.DB, validate *validator.Validate, blocklist *moderation.Blocklist) {
+	// Middlewares
+	authMiddleware := middleware.Auth{
+		DB: db,
+	}
+
+	// Controllers
+	userController := controllers.User{
+		DB:       db,
+		Validate: validate,
+	}
+
+	sessionController := controllers.Session{
+		DB:       db,
+		Validate: validate,
+	}
+
+	actorController := controllers.Actor{
+		DB:       db,
+		Validate: validate,
+	}
+
+	movieController := controllers.Movie{
+		DB:       db,
+		Validate: validate,
+	}
+
+	commentController := controllers.Comment{
+		DB:        db,
+		Validate:  validate,
+		Blocklist: blocklist,
+	}
+
+	searchController := controllers.Search{
+		DB:       db,
+		Validate: validate,
+	}
+
+	genreController := controllers.Genre{
+		DB:       db,
+		Validate: validate,
+	}
+
+	personController := controllers.Person{
+		DB:       db,
+		Validate: validate,
+	}
+
+	watchlistController := controllers.Watchlist{
+		DB:       db,
+		Validate: validate,
+	}
+
+	diaryController := controllers.Diary{
+		DB:       db,
+		Validate: validate,
+	}
+
+	listController := controllers.List{
+		DB:       db,
+		Validate: validate,
+	}
+
+	followController := controllers.Follow{
+		DB:       db,
+		Validate: validate,
+	}
+
+	moderationController := controllers.Moderation{
+		DB:       db,
+		Validate: validate,
+	}
+
+	adminController := controllers.Admin{
+		DB:       db,
+		Validate: validate,
+	}
+
+	// Routes - Session
+	app.Post("/login", sessionController.HandleLogin)
+	app.Post("/refresh", sessionController.HandleRefresh)
+	app.Post("/logout", authMiddleware.VerifyUser, sessionController.HandleLogout)
+	app.Post("/logout-all", authMiddleware.VerifyUser, sessionController.HandleLogoutAll)
+	app.Post("/password-reset", sessionController.HandlePasswordReset)
+
+	// Routes - User
+	app.Post("/users", userController.CreateUser)
+	app.Get("/users", authMiddleware.Require(models.PermissionManageUsers), userController.ListAllUsersInDB)
+	app.Get("/users/:uuid", authMiddleware.RequireSelfOr(models.PermissionManageUsers), userController.GetUser)
+	app.Get("/users/:uuid/comments", authMiddleware.RequireSelfOr(models.PermissionManageUsers), userController.GetUserComments)
+	app.Delete("/users/:uuid", authMiddleware.RequireSelfOr(models.PermissionManageUsers), userController.DeleteUser)
+	app.Patch("/users/:uuid", authMiddleware.RequireSelfOr(models.PermissionManageUsers), userController.UpdateUser)
+
+	// Routes - Watchlist and diary
+	app.Post("/users/:uuid/watchlist", authMiddleware.RequireSelfOr(models.PermissionManageUsers), watchlistController.AddToWatchlist)
+	app.Get("/users/:uuid/watchlist", authMiddleware.RequireSelfOr(models.PermissionManageUsers), watchlistController.GetUserWatchlist)
+	app.Delete("/users/:uuid/watchlist/:movieUuid", authMiddleware.RequireSelfOr(models.PermissionManageUsers), watchlistController.RemoveFromWatchlist)
+	app.Post("/users/:uuid/diary", authMiddleware.RequireSelfOr(models.PermissionManageUsers), diaryController.CreateDiaryEntry)
+	app.Get("/users/:uuid/diary", authMiddleware.RequireSelfOr(models.PermissionManageUsers), diaryController.GetUserDiary)
+	app.Get("/users/:uuid/diary/stats", authMiddleware.RequireSelfOr(models.PermissionManageUsers), diaryController.GetUserDiaryStats)
+	app.Patch("/users/:uuid/diary/:entryUuid", authMiddleware.RequireSelfOr(models.PermissionManageUsers), diaryController.UpdateDiaryEntry)
+	app.Delete("/users/:uuid/diary/:entryUuid", authMiddleware.RequireSelfOr(models.PermissionManageUsers), diaryController.DeleteDiaryEntry)
+
+	// Routes - Lists
+	app.Post("/users/:uuid/lists", authMiddleware.RequireSelfOr(models.PermissionManageUsers), listController.CreateList)
+	app.Get("/users/:uuid/lists", authMiddleware.RequireSelfOr(models.PermissionManageUsers), listController.GetUserLists)
+	app.Get("/lists", listController.ListPublicLists)
+	app.Get("/lists/:uuid", authMiddleware.VerifyListVisible, listController.GetList)
+	app.Patch("/lists/:uuid", authMiddleware.RequireOwnerOr(middleware.ListResource, models.PermissionManageUsers), listController.UpdateList)
+	app.Delete("/lists/:uuid", authMiddleware.RequireOwnerOr(middleware.ListResource, models.PermissionManageUsers), listController.DeleteList)
+	app.Post("/lists/:uuid/movies", authMiddleware.RequireOwnerOr(middleware.ListResource, models.PermissionManageUsers), listController.AddMovieToList)
+	app.Delete("/lists/:uuid/movies/:movieUuid", authMiddleware.RequireOwnerOr(middleware.ListResource, models.PermissionManageUsers), listController.RemoveMovieFromList)
+	app.Put("/lists/:uuid/order", authMiddleware.RequireOwnerOr(middleware.ListResource, models.PermissionManageUsers), listController.ReorderList)
+	app.Post("/lists/:uuid/fork", authMiddleware.VerifyUser, listController.ForkList)
+
+	// Routes - Follows and feed
+	app.Post("/users/:uuid/follow", authMiddleware.VerifyUser, followController.FollowUser)
+	app.Delete("/users/:uuid/follow", authMiddleware.VerifyUser, followController.UnfollowUser)
+	app.Get("/users/:uuid/followers", followController.GetFollowers)
+	app.Get("/users/:uuid/following", followController.GetFollowing)
+	app.Get("/feed", authMiddleware.VerifyUser, followController.GetFeed)
+
+	// Routes - Actor
+	app.Post("/actors", authMiddleware.Require(models.PermissionEditCatalog), actorController.CreateActor)
+	app.Get("/actors", actorController.ListAllActorsInDB)
+	app.Get("/actors/:uuid", actorController.GetActor)
+	app.Get("/actors/:uuid/movies", actorController.GetActorMovies)
+	app.Delete("/actors/:uuid", authMiddleware.Require(models.PermissionEditCatalog), actorController.DeleteActor)
+	app.Patch("/actors/:uuid", authMiddleware.Require(models.PermissionEditCatalog), actorController.UpdateActor)
+
+	// Routes - Movie
+	app.Post("/movies", authMiddleware.Require(models.PermissionEditCatalog), movieController.CreateMovie)
+	app.Post("/movies/stats/recompute", authMiddleware.Require(models.PermissionEditCatalog), movieController.RecomputeMoviesStats)
+	app.Post("/movies/:uuid/actors", authMiddleware.Require(models.PermissionEditCatalog), movieController.CreateActorsRelationshipsWithMovie)
+	app.Get("/movies", movieController.ListAllMoviesInDB)
+	app.Get("/movies/top", movieController.ListTopMovies)
+	app.Get("/movies/trending", movieController.ListTrendingMovies)
+	app.Get("/movies/:uuid", movieController.GetMovie)
+	app.Get("/movies/:uuid/comments", authMiddleware.OptionalUser, movieController.GetMovieComments)
+	app.Delete("/movies/:uuid", authMiddleware.Require(models.PermissionEditCatalog), movieController.DeleteMovie)
+	app.Delete("/movies/:uuid/actors", authMiddleware.Require(models.PermissionEditCatalog), movieController.DeleteActorsRelationshipsWithMovie)
+	app.Patch("/movies/:uuid/actors/:actorUuid", authMiddleware.Require(models.PermissionEditCatalog), movieController.UpdateActorCastingInMovie)
+	app.Post("/movies/:uuid/genres", authMiddleware.Require(models.PermissionEditCatalog), movieController.CreateGenresRelationshipsWithMovie)
+	app.Delete("/movies/:uuid/genres", authMiddleware.Require(models.PermissionEditCatalog), movieController.DeleteGenresRelationshipsWithMovie)
+	app.Post("/movies/:uuid/crew", authMiddleware.Require(models.PermissionEditCatalog), movieController.CreateCrewRelationshipsWithMovie)
+	app.Delete("/movies/:uuid/crew", authMiddleware.Require(models.PermissionEditCatalog), movieController.DeleteCrewRelationshipsWithMovie)
+	app.Patch("/movies/:uuid", authMiddleware.Require(models.PermissionEditCatalog), movieController.UpdateMovie)
+
+	// Routes - Comments
+	app.Post("/comments/:uuid", authMiddleware.RequireSelfOr(models.PermissionManageUsers), commentController.CreateComment)
+	app.Get("/comments", authMiddleware.Require(models.PermissionModerateComments), commentController.ListAllCommentsInDb)
+	app.Get("/comments/:uuid", authMiddleware.OptionalUser, commentController.GetComment)
+	app.Delete("/comments/:uuid", authMiddleware.RequireOwnerOr(middleware.CommentResource, models.PermissionModerateComments), commentController.DeleteComment)
+	app.Patch("/comments/:uuid", authMiddleware.RequireOwnerOr(middleware.CommentResource, models.PermissionManageUsers), commentController.UpdateComment)
+	app.Post("/comments/:uuid/like", authMiddleware.VerifyUser, commentController.LikeComment)
+	app.Delete("/comments/:uuid/like", authMiddleware.VerifyUser, commentController.UnlikeComment)
+	app.Post("/comments/:uuid/report", authMiddleware.VerifyUser, moderationController.ReportComment)
+	app.Post("/comments/:uuid/spoiler", authMiddleware.Require(models.PermissionModerateComments), commentController.FlagSpoilers)
+	app.Delete("/comments/:uuid/spoiler", authMiddleware.Require(models.PermissionModerateComments), commentController.UnflagSpoilers)
+	app.Put("/movies/:uuid/review", authMiddleware.VerifyUser, commentController.UpsertReview)
+
+	// Routes - Moderation
+	app.Get("/moderation/queue", authMiddleware.Require(models.PermissionModerateComments), moderationController.GetModerationQueue)
+	app.Post("/moderation/comments/:uuid", authMiddleware.Require(models.PermissionModerateComments), moderationController.ModerateComment)
+
+	// Routes - Admin
+	app.Post("/admin/users/:uuid/roles", authMiddleware.Require(models.PermissionManageUsers), adminController.PromoteUser)
+	app.Delete("/admin/users/:uuid/roles/:role", authMiddleware.Require(models.PermissionManageUsers), adminController.DemoteUser)
+	app.Post("/admin/users/:uuid/ban", authMiddleware.Require(models.PermissionManageUsers), adminController.BanUser)
+	app.Delete("/admin/users/:uuid/ban", authMiddleware.Require(models.PermissionManageUsers), adminController.UnbanUser)
+	app.Post("/admin/users/:uuid/password-reset", authMiddleware.Require(models.PermissionManageUsers), adminController.ForcePasswordReset)
+	app.Get("/admin/audit-log", authMiddleware.Require(models.PermissionManageUsers), adminController.GetAuditLog)
+
+	// Routes - Search
+	app.Get("/search", searchController.SearchMoviesAndActors)
+
+	// Routes - Genre
+	app.Post("/genres", authMiddleware.Require(models.PermissionEditCatalog), genreController.CreateGenre)
+	app.Get("/genres", genreController.ListAllGenresInDB)
+	app.Get("/genres/:uuid", genreController.GetGenre)
+	app.Get("/genres/:uuid/movies", genreController.GetGenreMovies)
+	app.Delete("/genres/:uuid", authMiddleware.Require(models.PermissionEditCatalog), genreController.DeleteGenre)
+	app.Patch("/genres/:uuid", authMiddleware.Require(models.PermissionEditCatalog), genreController.UpdateGenre)
+
+	// Routes - People
+	app.Post("/people", authMiddleware.Require(models.PermissionEditCatalog), personController.CreatePerson)
+	app.Get("/people", personController.ListAllPeopleInDB)
+	app.Get("/people/:uuid", personController.GetPerson)
+	app.Get("/people/:uuid/filmography", personController.GetPersonFilmography)
+	app.Delete("/people/:uuid", authMiddleware.Require(models.PermissionEditCatalog), personController.DeletePerson)
+	app.Patch("/people/:uuid", authMiddleware.Require(models.PermissionEditCatalog), personController.UpdatePerson)
+}
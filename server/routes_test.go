@@ -0,0 +1,39 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/VinOfSteel/cinemagrader/openapi"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// Every route registered by the server needs an operation in the spec, and every operation needs a route
+func Test_SpecCoversRoutes(t *testing.T) {
+	app := fiber.New()
+	RegisterRoutes(app, nil, nil, nil)
+
+	operations := map[string]bool{}
+	for _, operation := range apiOperations {
+		operations[operation.Key()] = true
+	}
+
+	routes := map[string]bool{}
+	for _, route := range app.GetRoutes(true) {
+		// Fiber registers a HEAD route for every GET
+		if route.Method == fiber.MethodHead {
+			continue
+		}
+
+		key := route.Method + " " + route.Path
+		routes[key] = true
+		assert.True(t, operations[key], "Route %v has no operation in apiOperations", key)
+	}
+
+	for key := range operations {
+		assert.True(t, routes[key], "Operation %v has no registered route", key)
+	}
+
+	_, err := openapi.Build(apiInfo, apiOperations)
+	assert.NoError(t, err, "Error building the spec")
+}
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"time"
+
+	"github.com/VinOfSteel/cinemagrader/apierrors"
+	"github.com/VinOfSteel/cinemagrader/i18n"
+	"github.com/VinOfSteel/cinemagrader/openapi"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Config is the fiber configuration of the server, shared with the tests that run the real app
+func Config() fiber.Config {
+	return fiber.Config{
+		AppName:       "Cinema Grader",
+		Prefork:       false,
+		CaseSensitive: true,
+		ReadTimeout:   30 * time.Second,
+		WriteTimeout:  90 * time.Second,
+		IdleTimeout:   120 * time.Second,
+		ErrorHandler:  ErrorHandler,
+	}
+}
+
+// ErrorHandler sends every error as an apierrors.Problem, in the language of the Accept-Language header of the request and
+// with the id the requestid middleware gave to it
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	trans := i18n.FromRequest(c)
+	problem := apierrors.NewProblem(err, c.Path(), c.GetRespHeader(fiber.HeaderXRequestID), trans)
+
+	c.Set(fiber.HeaderContentLanguage, i18n.LanguageTag(trans))
+	c.Vary(fiber.HeaderAcceptLanguage)
+	return c.Status(problem.Status).JSON(problem, apierrors.ContentType)
+}
+
+// RegisterDocs serves the OpenAPI document of the routes at /openapi.json, and a Swagger UI page for it at /docs
+func RegisterDocs(app *fiber.App) error {
+	document, err := openapi.Build(apiInfo, apiOperations)
+	if err != nil {
+		return err
+	}
+
+	documentHandler, err := openapi.Handler(document)
+	if err != nil {
+		return err
+	}
+
+	app.Get("/openapi.json", documentHandler)
+	app.Get("/docs", openapi.DocsHandler)
+	return nil
+}
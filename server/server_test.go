@@ -0,0 +1,93 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/VinOfSteel/cinemagrader/apierrors"
+	"github.com/VinOfSteel/cinemagrader/validation"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ErrorHandler(t *testing.T) {
+	app := fiber.New(Config())
+	app.Use(requestid.New())
+	app.Post("/users", func(c *fiber.Ctx) error {
+		return validation.ValidateData(validator.New(), struct {
+			Email string `json:"email" validate:"required"`
+		}{})
+	})
+
+	testCases := []struct {
+		description      string
+		route            string
+		acceptLanguage   string
+		expectedStatus   int
+		expectedCode     string
+		expectedMessage  string
+		expectedLanguage string
+		expectedDetails  map[string]string
+	}{
+		{
+			description:      "Error of a handler",
+			route:            "/users",
+			expectedStatus:   fiber.StatusBadRequest,
+			expectedCode:     apierrors.ValidationFailed.Code,
+			expectedMessage:  "Validation failed",
+			expectedLanguage: "en",
+			expectedDetails:  map[string]string{"email": "The email field is required."},
+		},
+		{
+			description:      "Error of a handler in Portuguese",
+			route:            "/users",
+			acceptLanguage:   "pt-BR,pt;q=0.9",
+			expectedStatus:   fiber.StatusBadRequest,
+			expectedCode:     apierrors.ValidationFailed.Code,
+			expectedMessage:  "Falha na validação",
+			expectedLanguage: "pt-BR",
+			expectedDetails:  map[string]string{"email": "O campo email é obrigatório."},
+		},
+		{
+			description:      "Error of fiber",
+			route:            "/unknown",
+			acceptLanguage:   "pt-BR",
+			expectedStatus:   fiber.StatusNotFound,
+			expectedCode:     apierrors.RouteNotFound.Code,
+			expectedMessage:  "Cannot POST /unknown",
+			expectedLanguage: "pt-BR",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			request := httptest.NewRequest(fiber.MethodPost, testCase.route, nil)
+			request.Header.Set(fiber.HeaderAcceptLanguage, testCase.acceptLanguage)
+
+			response, err := app.Test(request)
+			if err != nil {
+				t.Fatalf("Error sending request: %v", err)
+			}
+			defer response.Body.Close()
+
+			var problem apierrors.Problem
+			if err := json.NewDecoder(response.Body).Decode(&problem); err != nil {
+				t.Fatalf("Error decoding problem: %v", err)
+			}
+
+			assert.Equal(t, testCase.expectedStatus, response.StatusCode, "Status mismatch")
+			assert.Equal(t, apierrors.ContentType, response.Header.Get(fiber.HeaderContentType), "Content type mismatch")
+			assert.Equal(t, testCase.expectedStatus, problem.Status, "Problem status mismatch")
+			assert.Equal(t, testCase.expectedLanguage, response.Header.Get(fiber.HeaderContentLanguage), "Content language mismatch")
+			assert.Equal(t, testCase.expectedCode, problem.Code, "Code mismatch")
+			assert.Equal(t, testCase.expectedMessage, problem.Message, "Message mismatch")
+			assert.Equal(t, testCase.expectedDetails, problem.Details, "Details mismatch")
+			assert.Equal(t, testCase.route, problem.Instance, "Instance mismatch")
+			assert.NotEmpty(t, problem.RequestID, "Problem should have the request id")
+			assert.Equal(t, response.Header.Get(fiber.HeaderXRequestID), problem.RequestID, "Request id should match the header")
+		})
+	}
+}
@@ -0,0 +1,418 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/VinOfSteel/cinemagrader/controllers"
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/VinOfSteel/cinemagrader/openapi"
+)
+
+// Descriptions of the routes that also let the user of the param, or the owner of the resource, through
+const (
+	selfDescription         = "Only accessible to the user of the param, or to users with the permissions below."
+	listOwnerDescription    = "Only accessible to the owner of the list, or to users with the permissions below."
+	commentOwnerDescription = "Only accessible to the author of the comment, or to users with the permissions below."
+)
+
+var apiInfo = openapi.Info{
+	Title:       "Cinema Grader",
+	Version:     "1.0.0",
+	Description: "API to grade and comment movies, keep track of what was watched and share lists of movies.",
+}
+
+// Query params shared by the routes
+var (
+	deletedParam  = openapi.QueryParam("deleted", "boolean", "Lists the soft deleted rows instead of the active ones.")
+	yearParam     = openapi.QueryParam("year", "integer", "Year the entries were watched in.")
+	spoilersParam = openapi.QueryParam("reveal_spoilers", "boolean", "Shows the spoilers of the comments.")
+)
+
+// paginated adds the pagination params to the params of a list route
+func paginated(params ...openapi.Parameter) []openapi.Parameter {
+	return append([]openapi.Parameter{
+		openapi.QueryParam("offset", "integer", "Rows to skip, 0 by default. Ignored when a cursor is sent."),
+		openapi.QueryParam("limit", "integer", "Rows in the page, 10 by default."),
+		openapi.QueryParam("cursor", "string", "Next or prev cursor of a previous page, sent back as received."),
+	}, params...)
+}
+
+// movieFilterParams are the filters of every movie list route
+func movieFilterParams(sort openapi.Parameter) []openapi.Parameter {
+	return paginated(
+		sort,
+		deletedParam,
+		openapi.QueryParam("with_actors", "boolean", "Sends the actors of each movie, as MovieResponseWithActors."),
+		openapi.FormatParam("released_from", "date", "Movies released on or after the date."),
+		openapi.FormatParam("released_to", "date", "Movies released on or before the date."),
+		openapi.QueryParam("min_grade", "number", "Lowest average grade."),
+		openapi.QueryParam("max_grade", "number", "Highest average grade."),
+		openapi.FormatParam("actor", "uuid", "Movies the actor is cast in."),
+		openapi.FormatParam("genre", "uuid", "Movies of the genre."),
+		openapi.QueryParam("director", "string", "Part of the name of the director."),
+		openapi.QueryParam("title", "string", "Part of the title."),
+	)
+}
+
+var movieSorts = []string{
+	"created,desc", "created,asc", "title,asc", "title,desc", "director,asc", "director,desc",
+	"release_date,asc", "release_date,desc", "average_grade,asc", "average_grade,desc",
+	"weighted_rating,asc", "weighted_rating,desc", "updated,asc",
+}
+
+// withDefault moves the default sort to the front, where EnumParam expects it
+func withDefault(sorts []string, fallback string) []string {
+	values := []string{fallback}
+	for _, sort := range sorts {
+		if sort != fallback {
+			values = append(values, sort)
+		}
+	}
+
+	return values
+}
+
+// apiOperations describes every route of RegisterRoutes, in the same order
+var apiOperations = []openapi.Operation{
+	// Session
+	{Method: http.MethodPost, Path: "/login", Tag: "Sessions", Summary: "Login with email and password",
+		Body: controllers.LoginBody{}, Status: http.StatusOK, Response: controllers.LoginResponse{}},
+	{Method: http.MethodPost, Path: "/refresh", Tag: "Sessions", Summary: "Trade a refresh token for a new session",
+		Body: controllers.RefreshBody{}, Status: http.StatusOK, Response: controllers.LoginResponse{}},
+	{Method: http.MethodPost, Path: "/logout", Tag: "Sessions", Summary: "Revoke the session of the token",
+		Auth: true, Status: http.StatusNoContent},
+	{Method: http.MethodPost, Path: "/logout-all", Tag: "Sessions", Summary: "Revoke every session of the user",
+		Auth: true, Status: http.StatusNoContent},
+	{Method: http.MethodPost, Path: "/password-reset", Tag: "Sessions", Summary: "Set a new password with a reset token given by an admin",
+		Body: controllers.PasswordResetBody{}, Status: http.StatusNoContent},
+
+	// User
+	{Method: http.MethodPost, Path: "/users", Tag: "Users", Summary: "Create a user",
+		Body: models.UserBody{}, Status: http.StatusCreated, Response: models.UserResponse{}},
+	{Method: http.MethodGet, Path: "/users", Tag: "Users", Summary: "List users",
+		Auth: true, Permissions: []string{models.PermissionManageUsers},
+		Query: paginated(
+			openapi.EnumParam("sort", "Order of the users.", "created,desc", "created,asc", "name,asc", "name,desc", "surname,asc", "surname,desc", "email,asc", "email,desc", "updated,asc"),
+			deletedParam,
+			openapi.QueryParam("is_adm", "boolean", "Only admins, or only users that aren't admins."),
+			openapi.QueryParam("name", "string", "Part of the name or surname."),
+			openapi.QueryParam("email", "string", "Part of the email."),
+		),
+		Status: http.StatusOK, Response: models.Page[models.UserResponse]{}},
+	{Method: http.MethodGet, Path: "/users/:uuid", Tag: "Users", Summary: "Get a user",
+		Description: selfDescription, Auth: true, Permissions: []string{models.PermissionManageUsers},
+		Status: http.StatusOK, Response: models.UserResponse{}},
+	{Method: http.MethodGet, Path: "/users/:uuid/comments", Tag: "Users", Summary: "Get a user with their comments",
+		Description: selfDescription, Auth: true, Permissions: []string{models.PermissionManageUsers},
+		Query: []openapi.Parameter{
+			openapi.EnumParam("sort", "Order of the comments.", "created,desc", "created,asc", "grade,asc", "grade,desc", "updated,asc"),
+			deletedParam,
+		},
+		Status: http.StatusOK, Response: models.UserResponseWithComments{}},
+	{Method: http.MethodDelete, Path: "/users/:uuid", Tag: "Users", Summary: "Delete a user",
+		Description: selfDescription, Auth: true, Permissions: []string{models.PermissionManageUsers},
+		Status: http.StatusNoContent},
+	{Method: http.MethodPatch, Path: "/users/:uuid", Tag: "Users", Summary: "Update a user",
+		Description: selfDescription, Auth: true, Permissions: []string{models.PermissionManageUsers},
+		Body: models.UserEditBody{}, Status: http.StatusOK, Response: models.UserResponse{}},
+
+	// Watchlist and diary
+	{Method: http.MethodPost, Path: "/users/:uuid/watchlist", Tag: "Watchlist", Summary: "Add a movie to the watchlist",
+		Description: selfDescription, Auth: true, Permissions: []string{models.PermissionManageUsers},
+		Body: models.WatchlistBody{}, Status: http.StatusCreated, Response: models.WatchlistItemResponse{}},
+	{Method: http.MethodGet, Path: "/users/:uuid/watchlist", Tag: "Watchlist", Summary: "List the watchlist of a user",
+		Description: selfDescription, Auth: true, Permissions: []string{models.PermissionManageUsers},
+		Query: paginated(
+			openapi.EnumParam("sort", "Order of the movies.", "added,desc", "added,asc", "title,asc", "title,desc", "release,asc", "release,desc", "rating,desc"),
+		),
+		Status: http.StatusOK, Response: models.Page[models.WatchlistItemResponse]{}},
+	{Method: http.MethodDelete, Path: "/users/:uuid/watchlist/:movieUuid", Tag: "Watchlist", Summary: "Remove a movie from the watchlist",
+		Description: selfDescription, Auth: true, Permissions: []string{models.PermissionManageUsers},
+		Status: http.StatusNoContent},
+	{Method: http.MethodPost, Path: "/users/:uuid/diary", Tag: "Diary", Summary: "Log a watched movie",
+		Description: selfDescription, Auth: true, Permissions: []string{models.PermissionManageUsers},
+		Body: models.DiaryBody{}, Status: http.StatusCreated, Response: models.DiaryEntryResponse{}},
+	{Method: http.MethodGet, Path: "/users/:uuid/diary", Tag: "Diary", Summary: "List the diary of a user",
+		Description: selfDescription, Auth: true, Permissions: []string{models.PermissionManageUsers},
+		Query: paginated(
+			openapi.EnumParam("sort", "Order of the entries.", "watched,desc", "watched,asc", "logged,asc", "logged,desc", "title,asc", "title,desc"),
+			yearParam,
+		),
+		Status: http.StatusOK, Response: models.Page[models.DiaryEntryResponse]{}},
+	{Method: http.MethodGet, Path: "/users/:uuid/diary/stats", Tag: "Diary", Summary: "Get the stats of the diary in a year",
+		Description: selfDescription + " The year is the current one unless sent.", Auth: true, Permissions: []string{models.PermissionManageUsers},
+		Query:  []openapi.Parameter{yearParam},
+		Status: http.StatusOK, Response: models.DiaryStatsResponse{}},
+	{Method: http.MethodPatch, Path: "/users/:uuid/diary/:entryUuid", Tag: "Diary", Summary: "Update a diary entry",
+		Description: selfDescription, Auth: true, Permissions: []string{models.PermissionManageUsers},
+		Body: models.DiaryEditBody{}, Status: http.StatusOK, Response: models.DiaryEntryResponse{}},
+	{Method: http.MethodDelete, Path: "/users/:uuid/diary/:entryUuid", Tag: "Diary", Summary: "Delete a diary entry",
+		Description: selfDescription, Auth: true, Permissions: []string{models.PermissionManageUsers},
+		Status: http.StatusNoContent},
+
+	// Lists
+	{Method: http.MethodPost, Path: "/users/:uuid/lists", Tag: "Lists", Summary: "Create a list",
+		Description: selfDescription, Auth: true, Permissions: []string{models.PermissionManageUsers},
+		Body: models.ListBody{}, Status: http.StatusCreated, Response: models.ListResponse{}},
+	{Method: http.MethodGet, Path: "/users/:uuid/lists", Tag: "Lists", Summary: "Get a user with their lists, private ones included",
+		Description: selfDescription, Auth: true, Permissions: []string{models.PermissionManageUsers},
+		Query: []openapi.Parameter{
+			openapi.EnumParam("sort", "Order of the lists.", "created,desc", "created,asc", "updated,asc", "updated,desc", "name,asc", "name,desc", "movies,asc", "movies,desc"),
+			deletedParam,
+		},
+		Status: http.StatusOK, Response: models.UserResponseWithLists{}},
+	{Method: http.MethodGet, Path: "/lists", Tag: "Lists", Summary: "List public lists",
+		Query: paginated(
+			openapi.EnumParam("sort", "Order of the lists.", "created,desc", "created,asc", "updated,asc", "updated,desc", "name,asc", "name,desc", "movies,asc", "movies,desc"),
+			openapi.FormatParam("owner", "uuid", "Lists of the user."),
+			openapi.QueryParam("name", "string", "Part of the name."),
+		),
+		Status: http.StatusOK, Response: models.Page[models.ListResponse]{}},
+	{Method: http.MethodGet, Path: "/lists/:uuid", Tag: "Lists", Summary: "Get a list with its movies",
+		Description: "Public lists are open to everyone, private ones only to their owner and to users that manage users.", OptionalAuth: true,
+		Status: http.StatusOK, Response: models.ListResponseWithItems{}},
+	{Method: http.MethodPatch, Path: "/lists/:uuid", Tag: "Lists", Summary: "Update a list",
+		Description: listOwnerDescription, Auth: true, Permissions: []string{models.PermissionManageUsers},
+		Body: models.ListEditBody{}, Status: http.StatusOK, Response: models.ListResponse{}},
+	{Method: http.MethodDelete, Path: "/lists/:uuid", Tag: "Lists", Summary: "Delete a list",
+		Description: listOwnerDescription, Auth: true, Permissions: []string{models.PermissionManageUsers},
+		Status: http.StatusNoContent},
+	{Method: http.MethodPost, Path: "/lists/:uuid/movies", Tag: "Lists", Summary: "Add a movie to a list",
+		Description: listOwnerDescription, Auth: true, Permissions: []string{models.PermissionManageUsers},
+		Body: models.ListItemBody{}, Status: http.StatusCreated, Response: models.ListResponseWithItems{}},
+	{Method: http.MethodDelete, Path: "/lists/:uuid/movies/:movieUuid", Tag: "Lists", Summary: "Remove a movie from a list",
+		Description: listOwnerDescription, Auth: true, Permissions: []string{models.PermissionManageUsers},
+		Status: http.StatusNoContent},
+	{Method: http.MethodPut, Path: "/lists/:uuid/order", Tag: "Lists", Summary: "Reorder the movies of a list",
+		Description: listOwnerDescription, Auth: true, Permissions: []string{models.PermissionManageUsers},
+		Body: models.ListOrderBody{}, Status: http.StatusOK, Response: models.ListResponseWithItems{}},
+	{Method: http.MethodPost, Path: "/lists/:uuid/fork", Tag: "Lists", Summary: "Copy a visible list to the logged user",
+		Auth: true, Status: http.StatusCreated, Response: models.ListResponseWithItems{}},
+
+	// Follows and feed
+	{Method: http.MethodPost, Path: "/users/:uuid/follow", Tag: "Follows", Summary: "Follow a user",
+		Auth: true, Status: http.StatusNoContent},
+	{Method: http.MethodDelete, Path: "/users/:uuid/follow", Tag: "Follows", Summary: "Unfollow a user",
+		Auth: true, Status: http.StatusNoContent},
+	{Method: http.MethodGet, Path: "/users/:uuid/followers", Tag: "Follows", Summary: "List the followers of a user",
+		Query:  paginated(openapi.EnumParam("sort", "Order of the users.", "followed,desc", "followed,asc", "name,asc", "name,desc")),
+		Status: http.StatusOK, Response: models.Page[models.FollowResponse]{}},
+	{Method: http.MethodGet, Path: "/users/:uuid/following", Tag: "Follows", Summary: "List the users a user follows",
+		Query:  paginated(openapi.EnumParam("sort", "Order of the users.", "followed,desc", "followed,asc", "name,asc", "name,desc")),
+		Status: http.StatusOK, Response: models.Page[models.FollowResponse]{}},
+	{Method: http.MethodGet, Path: "/feed", Tag: "Follows", Summary: "List the activity of the users the logged user follows",
+		Description: "Spoilers are redacted unless reveal_spoilers is sent or the logged user reviewed the movie of the activity.",
+		Auth:        true, Query: paginated(spoilersParam), Status: http.StatusOK, Response: models.Page[models.ActivityResponse]{}},
+
+	// Actor
+	{Method: http.MethodPost, Path: "/actors", Tag: "Actors", Summary: "Create an actor",
+		Auth: true, Permissions: []string{models.PermissionEditCatalog},
+		Body: models.ActorBody{}, Status: http.StatusCreated, Response: models.ActorResponse{}},
+	{Method: http.MethodGet, Path: "/actors", Tag: "Actors", Summary: "List actors",
+		Query: paginated(
+			openapi.EnumParam("sort", "Order of the actors.", "created,desc", "created,asc", "name,asc", "name,desc", "surname,asc", "surname,desc", "updated,asc"),
+			deletedParam,
+			openapi.FormatParam("movie", "uuid", "Actors cast in the movie."),
+			openapi.QueryParam("name", "string", "Part of the name or surname."),
+		),
+		Status: http.StatusOK, Response: models.Page[models.ActorResponse]{}},
+	{Method: http.MethodGet, Path: "/actors/:uuid", Tag: "Actors", Summary: "Get an actor",
+		Status: http.StatusOK, Response: models.ActorResponse{}},
+	{Method: http.MethodGet, Path: "/actors/:uuid/movies", Tag: "Actors", Summary: "Get an actor with their movies",
+		Status: http.StatusOK, Response: models.ActorResponseWithMovies{}},
+	{Method: http.MethodDelete, Path: "/actors/:uuid", Tag: "Actors", Summary: "Delete an actor",
+		Auth: true, Permissions: []string{models.PermissionEditCatalog}, Status: http.StatusNoContent},
+	{Method: http.MethodPatch, Path: "/actors/:uuid", Tag: "Actors", Summary: "Update an actor",
+		Auth: true, Permissions: []string{models.PermissionEditCatalog},
+		Body: models.ActorEditBody{}, Status: http.StatusOK, Response: models.ActorResponse{}},
+
+	// Movie
+	{Method: http.MethodPost, Path: "/movies", Tag: "Movies", Summary: "Create a movie",
+		Auth: true, Permissions: []string{models.PermissionEditCatalog},
+		Body: models.MovieBody{}, Status: http.StatusCreated, Response: models.MovieResponseWithActors{}},
+	{Method: http.MethodPost, Path: "/movies/stats/recompute", Tag: "Movies", Summary: "Recompute the rating stats of every movie",
+		Auth: true, Permissions: []string{models.PermissionEditCatalog},
+		Status: http.StatusOK, Response: controllers.RecomputeStatsResponse{}},
+	{Method: http.MethodPost, Path: "/movies/:uuid/actors", Tag: "Movies", Summary: "Cast actors in a movie",
+		Auth: true, Permissions: []string{models.PermissionEditCatalog},
+		Body: models.MovieActorsBody{}, Status: http.StatusNoContent},
+	{Method: http.MethodGet, Path: "/movies", Tag: "Movies", Summary: "List movies",
+		Query:  movieFilterParams(openapi.EnumParam("sort", "Order of the movies.", movieSorts...)),
+		Status: http.StatusOK, Response: models.Page[models.MovieResponse]{}},
+	{Method: http.MethodGet, Path: "/movies/top", Tag: "Movies", Summary: "List the reviewed movies, best rated first",
+		Query:  movieFilterParams(openapi.EnumParam("sort", "Order of the movies.", withDefault(movieSorts, "weighted_rating,desc")...)),
+		Status: http.StatusOK, Response: models.Page[models.MovieResponse]{}},
+	{Method: http.MethodGet, Path: "/movies/trending", Tag: "Movies", Summary: "List the movies with the most reviews per day in the window",
+		Query: append(
+			movieFilterParams(openapi.EnumParam("sort", "Order of the movies.", append([]string{"trending,desc", "trending,asc"}, movieSorts...)...)),
+			openapi.Parameter{Name: "window", In: "query", Description: "Days of reviews counted.", Schema: &openapi.Schema{Type: "integer", Default: 7, Minimum: floatPtr(1), Maximum: floatPtr(365)}},
+		),
+		Status: http.StatusOK, Response: models.Page[models.MovieResponse]{}},
+	{Method: http.MethodGet, Path: "/movies/:uuid", Tag: "Movies", Summary: "Get a movie with its actors",
+		Status: http.StatusOK, Response: models.MovieResponseWithActors{}},
+	{Method: http.MethodGet, Path: "/movies/:uuid/comments", Tag: "Movies", Summary: "Get a movie with its comments",
+		Description: "Spoilers are redacted unless reveal_spoilers is sent or the logged user reviewed the movie.", OptionalAuth: true,
+		Query: []openapi.Parameter{
+			openapi.EnumParam("sort", "Order of the comments.", "created,desc", "created,asc", "grade,asc", "grade,desc", "updated,asc", "top"),
+			deletedParam,
+			openapi.QueryParam("threaded", "boolean", "Nests the replies under the comment they answer."),
+			spoilersParam,
+		},
+		Status: http.StatusOK, Response: models.MovieResponseWithActorsWithComments{}},
+	{Method: http.MethodDelete, Path: "/movies/:uuid", Tag: "Movies", Summary: "Delete a movie",
+		Auth: true, Permissions: []string{models.PermissionEditCatalog}, Status: http.StatusNoContent},
+	{Method: http.MethodDelete, Path: "/movies/:uuid/actors", Tag: "Movies", Summary: "Remove actors from a movie",
+		Auth: true, Permissions: []string{models.PermissionEditCatalog},
+		Body: models.MovieActorsBody{}, Status: http.StatusNoContent},
+	{Method: http.MethodPatch, Path: "/movies/:uuid/actors/:actorUuid", Tag: "Movies", Summary: "Update the casting of an actor in a movie",
+		Auth: true, Permissions: []string{models.PermissionEditCatalog},
+		Body: models.CastingEditBody{}, Status: http.StatusOK, Response: models.CastingResponse{}},
+	{Method: http.MethodPost, Path: "/movies/:uuid/genres", Tag: "Movies", Summary: "Add genres to a movie",
+		Auth: true, Permissions: []string{models.PermissionEditCatalog},
+		Body: models.MovieGenresBody{}, Status: http.StatusNoContent},
+	{Method: http.MethodDelete, Path: "/movies/:uuid/genres", Tag: "Movies", Summary: "Remove genres from a movie",
+		Auth: true, Permissions: []string{models.PermissionEditCatalog},
+		Body: models.MovieGenresBody{}, Status: http.StatusNoContent},
+	{Method: http.MethodPost, Path: "/movies/:uuid/crew", Tag: "Movies", Summary: "Add people to the crew of a movie",
+		Auth: true, Permissions: []string{models.PermissionEditCatalog},
+		Body: models.MovieCrewBody{}, Status: http.StatusNoContent},
+	{Method: http.MethodDelete, Path: "/movies/:uuid/crew", Tag: "Movies", Summary: "Remove people from the crew of a movie",
+		Auth: true, Permissions: []string{models.PermissionEditCatalog},
+		Body: models.MovieCrewBody{}, Status: http.StatusNoContent},
+	{Method: http.MethodPatch, Path: "/movies/:uuid", Tag: "Movies", Summary: "Update a movie",
+		Auth: true, Permissions: []string{models.PermissionEditCatalog},
+		Body: models.MovieEditBody{}, Status: http.StatusOK, Response: models.MovieResponse{}},
+
+	// Comments
+	{Method: http.MethodPost, Path: "/comments/:uuid", Tag: "Comments", Summary: "Comment on a movie as the user of the param",
+		Description: selfDescription, Auth: true, Permissions: []string{models.PermissionManageUsers},
+		Body: models.CommentBody{}, Status: http.StatusCreated, Response: models.CommentResponse{}},
+	{Method: http.MethodGet, Path: "/comments", Tag: "Comments", Summary: "List comments",
+		Auth: true, Permissions: []string{models.PermissionModerateComments},
+		Query: paginated(
+			openapi.EnumParam("sort", "Order of the comments.", "created,desc", "created,asc", "grade,asc", "grade,desc", "updated,asc"),
+			deletedParam,
+			openapi.FormatParam("user", "uuid", "Comments of the user."),
+			openapi.FormatParam("movie", "uuid", "Comments on the movie."),
+			openapi.EnumParam("status", "Moderation status of the comments.", "visible", "pending", "hidden"),
+			openapi.QueryParam("min_grade", "number", "Lowest grade."),
+			openapi.QueryParam("max_grade", "number", "Highest grade."),
+		),
+		Status: http.StatusOK, Response: models.Page[models.CommentResponse]{}},
+	{Method: http.MethodGet, Path: "/comments/:uuid", Tag: "Comments", Summary: "Get a comment",
+		Description: "Spoilers are redacted unless reveal_spoilers is sent or the logged user reviewed the movie.", OptionalAuth: true,
+		Query: []openapi.Parameter{spoilersParam}, Status: http.StatusOK, Response: models.CommentResponse{}},
+	{Method: http.MethodDelete, Path: "/comments/:uuid", Tag: "Comments", Summary: "Delete a comment",
+		Description: commentOwnerDescription, Auth: true, Permissions: []string{models.PermissionModerateComments},
+		Status: http.StatusNoContent},
+	{Method: http.MethodPatch, Path: "/comments/:uuid", Tag: "Comments", Summary: "Update a comment",
+		Description: commentOwnerDescription, Auth: true, Permissions: []string{models.PermissionManageUsers},
+		Body: models.CommentEditBody{}, Status: http.StatusOK, Response: models.CommentResponse{}},
+	{Method: http.MethodPost, Path: "/comments/:uuid/like", Tag: "Comments", Summary: "Like a comment",
+		Auth: true, Status: http.StatusNoContent},
+	{Method: http.MethodDelete, Path: "/comments/:uuid/like", Tag: "Comments", Summary: "Remove the like of a comment",
+		Auth: true, Status: http.StatusNoContent},
+	{Method: http.MethodPost, Path: "/comments/:uuid/report", Tag: "Moderation", Summary: "Report a comment",
+		Auth: true, Body: models.ReportBody{}, Status: http.StatusCreated, Response: models.ReportResponse{}},
+	{Method: http.MethodPost, Path: "/comments/:uuid/spoiler", Tag: "Moderation", Summary: "Flag a comment as a spoiler",
+		Auth: true, Permissions: []string{models.PermissionModerateComments},
+		Status: http.StatusOK, Response: models.CommentResponse{}},
+	{Method: http.MethodDelete, Path: "/comments/:uuid/spoiler", Tag: "Moderation", Summary: "Remove the spoiler flag of a comment",
+		Auth: true, Permissions: []string{models.PermissionModerateComments},
+		Status: http.StatusOK, Response: models.CommentResponse{}},
+	{Method: http.MethodPut, Path: "/movies/:uuid/review", Tag: "Comments", Summary: "Create or update the review of the logged user",
+		Description: "Answers 201 when the review is created and 200 when an existing one is updated.",
+		Auth:        true, Body: models.ReviewBody{}, Status: http.StatusOK, Response: models.CommentResponse{}},
+
+	// Moderation
+	{Method: http.MethodGet, Path: "/moderation/queue", Tag: "Moderation", Summary: "List the comments waiting for moderation",
+		Auth: true, Permissions: []string{models.PermissionModerateComments},
+		Query:  paginated(openapi.EnumParam("sort", "Order of the comments.", "flagged,asc", "flagged,desc", "reports,desc", "reports,asc")),
+		Status: http.StatusOK, Response: models.Page[models.ModerationQueueItem]{}},
+	{Method: http.MethodPost, Path: "/moderation/comments/:uuid", Tag: "Moderation", Summary: "Approve or hide a comment",
+		Auth: true, Permissions: []string{models.PermissionModerateComments},
+		Body: models.ModerationActionBody{}, Status: http.StatusOK, Response: models.CommentResponse{}},
+
+	// Admin
+	{Method: http.MethodPost, Path: "/admin/users/:uuid/roles", Tag: "Admin", Summary: "Grant a role to a user",
+		Auth: true, Permissions: []string{models.PermissionManageUsers},
+		Body: models.RoleBody{}, Status: http.StatusOK, Response: models.UserRoles{}},
+	{Method: http.MethodDelete, Path: "/admin/users/:uuid/roles/:role", Tag: "Admin", Summary: "Revoke a role from a user",
+		Auth: true, Permissions: []string{models.PermissionManageUsers},
+		Status: http.StatusOK, Response: models.UserRoles{}},
+	{Method: http.MethodPost, Path: "/admin/users/:uuid/ban", Tag: "Admin", Summary: "Ban a user",
+		Auth: true, Permissions: []string{models.PermissionManageUsers},
+		Body: models.BanBody{}, Status: http.StatusOK, Response: models.UserResponse{}},
+	{Method: http.MethodDelete, Path: "/admin/users/:uuid/ban", Tag: "Admin", Summary: "Lift the ban of a user",
+		Auth: true, Permissions: []string{models.PermissionManageUsers},
+		Status: http.StatusOK, Response: models.UserResponse{}},
+	{Method: http.MethodPost, Path: "/admin/users/:uuid/password-reset", Tag: "Admin", Summary: "Log a user out and require a new password",
+		Auth: true, Permissions: []string{models.PermissionManageUsers},
+		Status: http.StatusCreated, Response: controllers.PasswordResetResponse{}},
+	{Method: http.MethodGet, Path: "/admin/audit-log", Tag: "Admin", Summary: "List what admins did to users",
+		Auth: true, Permissions: []string{models.PermissionManageUsers},
+		Query: paginated(
+			openapi.EnumParam("sort", "Order of the entries.", "created,desc", "created,asc"),
+			openapi.FormatParam("target_id", "uuid", "Entries about the user."),
+			openapi.FormatParam("actor_id", "uuid", "Entries of the admin."),
+			openapi.EnumParam("action", "Entries of the action.", "promote", "demote", "ban", "unban", "password_reset"),
+		),
+		Status: http.StatusOK, Response: models.Page[models.AuditLogModel]{}},
+
+	// Search
+	{Method: http.MethodGet, Path: "/search", Tag: "Search", Summary: "Search movies and actors",
+		Query: paginated(
+			openapi.Parameter{Name: "q", In: "query", Description: "Words to search for.", Required: true, Schema: &openapi.Schema{Type: "string"}},
+			openapi.Parameter{Name: "type", In: "query", Description: "Only results of the type.", Schema: &openapi.Schema{Type: "string", Enum: []string{models.SearchTypeMovie, models.SearchTypeActor}}},
+		),
+		Status: http.StatusOK, Response: models.Page[models.SearchResult]{}},
+
+	// Genre
+	{Method: http.MethodPost, Path: "/genres", Tag: "Genres", Summary: "Create a genre",
+		Auth: true, Permissions: []string{models.PermissionEditCatalog},
+		Body: models.GenreBody{}, Status: http.StatusCreated, Response: models.GenreResponse{}},
+	{Method: http.MethodGet, Path: "/genres", Tag: "Genres", Summary: "List genres",
+		Query: paginated(
+			openapi.EnumParam("sort", "Order of the genres.", "name,asc", "name,desc", "created,asc", "created,desc", "updated,asc", "updated,desc"),
+			deletedParam,
+			openapi.QueryParam("name", "string", "Part of the name."),
+		),
+		Status: http.StatusOK, Response: models.Page[models.GenreResponse]{}},
+	{Method: http.MethodGet, Path: "/genres/:uuid", Tag: "Genres", Summary: "Get a genre",
+		Status: http.StatusOK, Response: models.GenreResponse{}},
+	{Method: http.MethodGet, Path: "/genres/:uuid/movies", Tag: "Genres", Summary: "Get a genre with its movies",
+		Status: http.StatusOK, Response: models.GenreResponseWithMovies{}},
+	{Method: http.MethodDelete, Path: "/genres/:uuid", Tag: "Genres", Summary: "Delete a genre",
+		Auth: true, Permissions: []string{models.PermissionEditCatalog}, Status: http.StatusNoContent},
+	{Method: http.MethodPatch, Path: "/genres/:uuid", Tag: "Genres", Summary: "Update a genre",
+		Auth: true, Permissions: []string{models.PermissionEditCatalog},
+		Body: models.GenreEditBody{}, Status: http.StatusOK, Response: models.GenreResponse{}},
+
+	// People
+	{Method: http.MethodPost, Path: "/people", Tag: "People", Summary: "Create a person",
+		Auth: true, Permissions: []string{models.PermissionEditCatalog},
+		Body: models.PersonBody{}, Status: http.StatusCreated, Response: models.PersonResponse{}},
+	{Method: http.MethodGet, Path: "/people", Tag: "People", Summary: "List people",
+		Query: paginated(
+			openapi.EnumParam("sort", "Order of the people.", "name,asc", "name,desc", "created,asc", "created,desc", "updated,asc", "updated,desc"),
+			deletedParam,
+			openapi.QueryParam("name", "string", "Part of the name."),
+			openapi.Parameter{Name: "role", In: "query", Description: "People credited with the role in some movie.", Schema: &openapi.Schema{Type: "string", Enum: []string{
+				models.CrewRoleDirector, models.CrewRoleWriter, models.CrewRoleProducer, models.CrewRoleComposer, models.CrewRoleCinematographer,
+			}}},
+		),
+		Status: http.StatusOK, Response: models.Page[models.PersonResponse]{}},
+	{Method: http.MethodGet, Path: "/people/:uuid", Tag: "People", Summary: "Get a person",
+		Status: http.StatusOK, Response: models.PersonResponse{}},
+	{Method: http.MethodGet, Path: "/people/:uuid/filmography", Tag: "People", Summary: "Get a person with their filmography",
+		Status: http.StatusOK, Response: models.PersonResponseWithFilmography{}},
+	{Method: http.MethodDelete, Path: "/people/:uuid", Tag: "People", Summary: "Delete a person",
+		Auth: true, Permissions: []string{models.PermissionEditCatalog}, Status: http.StatusNoContent},
+	{Method: http.MethodPatch, Path: "/people/:uuid", Tag: "People", Summary: "Update a person",
+		Auth: true, Permissions: []string{models.PermissionEditCatalog},
+		Body: models.PersonEditBody{}, Status: http.StatusOK, Response: models.PersonResponse{}},
+}
+
+func floatPtr(number float64) *float64 {
+	return &number
+}
@@ -11,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/VinOfSteel/cinemagrader/apierrors"
 	"github.com/VinOfSteel/cinemagrader/initializers"
 	"github.com/VinOfSteel/cinemagrader/models"
 	"github.com/google/uuid"
@@ -18,10 +19,14 @@ import (
 )
 
 func Test_ActorRoutes(t *testing.T) {
+	// Creating, editing and deleting actors needs catalog:edit, which admins have
+	adminToken := loginForTest(t, "admin@admin.com", "Testando@Teste**").Token
+
 	testCases := []struct {
 		description      string
 		route            string
 		method           string
+		token            string
 		data             map[string]interface{}
 		expectedCode     int
 		expectedResponse interface{}
@@ -33,6 +38,7 @@ func Test_ActorRoutes(t *testing.T) {
 			description: "POST - Create a new actor route - Success Case", // Picture key not tested on purpose to make sure it is registered properly as an empty string
 			route:       "/actors",
 			method:      "POST",
+			token:       adminToken,
 			data: map[string]interface{}{
 				"name":      "Mark",
 				"surname":   "Whalberg",
@@ -78,27 +84,48 @@ func Test_ActorRoutes(t *testing.T) {
 			testType:     "success",
 		},
 		{
-			description:  "GET - Passing an offset that is not a number - Error Case",
-			route:        "/actors?offset=2.254",
-			method:       "GET",
-			expectedCode: 400,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "Offset needs to be a valid integer",
-			},
-			responseType: "slice",
-			testType:     "global-error",
+			description:      "GET - Passing an offset that is not a number - Error Case",
+			route:            "/actors?offset=2.254",
+			method:           "GET",
+			expectedCode:     400,
+			expectedResponse: apierrors.InvalidOffset,
+			responseType:     "slice",
+			testType:         "global-error",
 		},
 		{
-			description:  "GET - Passing a limit that is not a number - Error Case",
-			route:        "/actors?limit=aushaushaush",
+			description:      "GET - Passing a limit that is not a number - Error Case",
+			route:            "/actors?limit=aushaushaush",
+			method:           "GET",
+			expectedCode:     400,
+			expectedResponse: apierrors.InvalidLimit,
+			responseType:     "slice",
+			testType:         "global-error",
+		}, // Since sort casts every non-valid value to a default valid one, it does not need to be tested, as any error case will fall into the updated_at DESC clause.
+		{
+			description:  "GET - Filtering actors by full name substring - Success Case",
+			route:        "/actors?name=mark%20whal",
 			method:       "GET",
-			expectedCode: 400,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "Limit needs to be a valid integer",
+			expectedCode: 200,
+			expectedResponse: []models.ActorResponse{
+				{
+					Name:      "Mark",
+					Surname:   "Whalberg",
+					Birthday:  "1971-06-05T00:00:00Z",
+					CreatorId: adminId,
+				},
 			},
 			responseType: "slice",
-			testType:     "global-error",
-		}, // Since sort casts every non-valid value to a default valid one, it does not need to be tested, as any error case will fall into the updated_at DESC clause.
+			testType:     "success",
+		},
+		{
+			description:      "GET - Passing a movie filter that is not an uuid - Error Case",
+			route:            "/actors?movie=testestetsts",
+			method:           "GET",
+			expectedCode:     400,
+			expectedResponse: apierrors.InvalidQueryParam("movie", apierrors.ValidUUID),
+			responseType:     "slice",
+			testType:         "global-error",
+		},
 		{
 			description:      "GET BY ID - Passing an uuid that exists in DB - Success Case",
 			route:            fmt.Sprintf("/actors/%v", actorResponses[1].ID),
@@ -109,26 +136,22 @@ func Test_ActorRoutes(t *testing.T) {
 			testType:         "success",
 		},
 		{
-			description:  "GET BY ID - Passing an uuid that does not exist in DB - Error Case",
-			route:        fmt.Sprintf("/actors/%v", uuid.New()),
-			method:       "GET",
-			expectedCode: 404,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "Actor id not found in database",
-			},
-			responseType: "struct",
-			testType:     "global-error",
+			description:      "GET BY ID - Passing an uuid that does not exist in DB - Error Case",
+			route:            fmt.Sprintf("/actors/%v", uuid.New()),
+			method:           "GET",
+			expectedCode:     404,
+			expectedResponse: apierrors.ActorNotFound,
+			responseType:     "struct",
+			testType:         "global-error",
 		},
 		{
-			description:  "GET BY ID - Passing an invalid uuid - Error Case",
-			route:        "/actors/testestetsts",
-			method:       "GET",
-			expectedCode: 400,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "Invalid uuid parameter",
-			},
-			responseType: "struct",
-			testType:     "global-error",
+			description:      "GET BY ID - Passing an invalid uuid - Error Case",
+			route:            "/actors/testestetsts",
+			method:           "GET",
+			expectedCode:     400,
+			expectedResponse: apierrors.InvalidUUID,
+			responseType:     "struct",
+			testType:         "global-error",
 		},
 		{
 			description:  "GET BY ID WITH MOVIES - Passing an uuid that does not exist in DB - Success Case",
@@ -153,61 +176,57 @@ func Test_ActorRoutes(t *testing.T) {
 			testType:     "success-with-movies",
 		},
 		{
-			description:  "GET BY ID WITH MOVIES - Passing an uuid that does not exist in DB - Error Case",
-			route:        fmt.Sprintf("/actors/%v/movies", uuid.New()),
-			method:       "GET",
-			expectedCode: 404,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "Actor id not found in database",
-			},
-			responseType: "struct",
-			testType:     "global-error",
+			description:      "GET BY ID WITH MOVIES - Passing an uuid that does not exist in DB - Error Case",
+			route:            fmt.Sprintf("/actors/%v/movies", uuid.New()),
+			method:           "GET",
+			expectedCode:     404,
+			expectedResponse: apierrors.ActorNotFound,
+			responseType:     "struct",
+			testType:         "global-error",
 		},
 		{
-			description:  "GET BY ID WITH MOVIES - Passing an invalid uuid - Error Case",
-			route:        "/actors/randomshit/movies",
-			method:       "GET",
-			expectedCode: 400,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "Invalid uuid parameter",
-			},
-			responseType: "struct",
-			testType:     "global-error",
+			description:      "GET BY ID WITH MOVIES - Passing an invalid uuid - Error Case",
+			route:            "/actors/randomshit/movies",
+			method:           "GET",
+			expectedCode:     400,
+			expectedResponse: apierrors.InvalidUUID,
+			responseType:     "struct",
+			testType:         "global-error",
 		},
 		// Delete requests
 		{
 			description:      "DELETE BY ID - Passing an uuid that exists in DB - Success Case",
 			route:            fmt.Sprintf("/actors/%v", actorResponses[2].ID),
 			method:           "DELETE",
+			token:            adminToken,
 			expectedCode:     204,
 			expectedResponse: actorResponses[2],
 			testType:         "delete",
 		},
 		{
-			description:  "DELETE BY ID - Passing an uuid that does not exist in DB - Error Case",
-			route:        fmt.Sprintf("/actors/%v", uuid.New()),
-			method:       "DELETE",
-			expectedCode: 404,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "Actor id not found in database",
-			},
-			testType: "global-error",
+			description:      "DELETE BY ID - Passing an uuid that does not exist in DB - Error Case",
+			route:            fmt.Sprintf("/actors/%v", uuid.New()),
+			method:           "DELETE",
+			token:            adminToken,
+			expectedCode:     404,
+			expectedResponse: apierrors.ActorNotFound,
+			testType:         "global-error",
 		},
 		{
-			description:  "DELETE BY ID - Passing an invalid uuid - Error Case",
-			route:        fmt.Sprintf("/actors/%v", "testeasdasd"),
-			method:       "DELETE",
-			expectedCode: 400,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "Invalid uuid parameter",
-			},
-			testType: "global-error",
+			description:      "DELETE BY ID - Passing an invalid uuid - Error Case",
+			route:            fmt.Sprintf("/actors/%v", "testeasdasd"),
+			method:           "DELETE",
+			token:            adminToken,
+			expectedCode:     400,
+			expectedResponse: apierrors.InvalidUUID,
+			testType:         "global-error",
 		},
 		// Update requests
 		{
 			description: "UPDATE - Update actor info (all keys) - Success Case",
 			route:       fmt.Sprintf("/actors/%v", actorResponses[3].ID),
 			method:      "PATCH",
+			token:       adminToken,
 			data: map[string]interface{}{
 				"name":     "New name",
 				"surname":  "New surname",
@@ -224,15 +243,14 @@ func Test_ActorRoutes(t *testing.T) {
 			testType: "update",
 		},
 		{
-			description:  "UPDATE - Passing an invalid uuid - Error Case",
-			route:        "/actors/12345677",
-			method:       "PATCH",
-			expectedCode: 400,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "Invalid uuid parameter",
-			},
-			responseType: "struct",
-			testType:     "global-error",
+			description:      "UPDATE - Passing an invalid uuid - Error Case",
+			route:            "/actors/12345677",
+			method:           "PATCH",
+			token:            adminToken,
+			expectedCode:     400,
+			expectedResponse: apierrors.InvalidUUID,
+			responseType:     "struct",
+			testType:         "global-error",
 		},
 	}
 
@@ -257,6 +275,9 @@ func Test_ActorRoutes(t *testing.T) {
 		}
 
 		req.Header.Set("Content-Type", "application/json")
+		if testCase.token != "" {
+			req.Header.Set("Authorization", "Bearer "+testCase.token)
+		}
 
 		resp, err := App.Test(req, -1)
 		if err != nil {
@@ -278,9 +299,12 @@ func Test_ActorRoutes(t *testing.T) {
 			var respSlice []models.ActorResponse
 
 			if testCase.responseType == "slice" {
-				if err := json.Unmarshal(responseBody, &respSlice); err != nil {
+				// List routes answer with the pagination envelope
+				var respPage models.Page[models.ActorResponse]
+				if err := json.Unmarshal(responseBody, &respPage); err != nil {
 					t.Fatalf("Error unmarshalling response body: %v", err)
 				}
+				respSlice = respPage.Data
 			} else {
 				if err := json.Unmarshal(responseBody, &respStruct); err != nil {
 					t.Fatalf("Error unmarshalling response body: %v", err)
@@ -435,7 +459,7 @@ func Test_ActorRoutes(t *testing.T) {
 		}
 
 		if testCase.testType == "global-error" {
-			assert.Equal(t, testCase.expectedResponse.(GlobalErrorHandlerResp).Message, string(responseBody))
+			assertProblem(t, testCase.expectedResponse.(*apierrors.Error), responseBody)
 		}
 
 		if testCase.testType == "delete" {
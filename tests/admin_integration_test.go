@@ -0,0 +1,182 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/VinOfSteel/cinemagrader/apierrors"
+	"github.com/VinOfSteel/cinemagrader/controllers"
+	"github.com/VinOfSteel/cinemagrader/initializers"
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func getAuditLog(t *testing.T, route, token string) []models.AuditLogModel {
+	statusCode, responseBody := sendSessionRequest(t, "GET", route, token, nil)
+	if statusCode != 200 {
+		t.Fatalf("Unexpected status code %v getting audit log: %s", statusCode, responseBody)
+	}
+
+	var auditLog models.Page[models.AuditLogModel]
+	if err := json.Unmarshal(responseBody, &auditLog); err != nil {
+		t.Fatalf("Error unmarshalling response body: %v", err)
+	}
+
+	return auditLog.Data
+}
+
+func Test_UserAdministration(t *testing.T) {
+	db := initializers.NewDatabaseConn()
+	defer db.Close()
+
+	users := map[string]models.UserResponse{}
+	for _, user := range InsertMockedUsersInDB(db, []models.UserBody{
+		{Name: "Administration", Surname: "Target", Email: "administrationtarget@teste.com", Password: "testando123@Teste", Birthday: "1990-01-01"},
+		{Name: "Administration", Surname: "Bystander", Email: "administrationbystander@teste.com", Password: "testando123@Teste", Birthday: "1990-01-01"},
+	}) {
+		users[user.Surname] = user
+	}
+	target, bystander := users["Target"], users["Bystander"]
+	adminLogin := loginForTest(t, "admin@admin.com", "Testando@Teste**")
+	targetToken := loginForTest(t, target.Email, "testando123@Teste").Token
+	bystanderToken := loginForTest(t, bystander.Email, "testando123@Teste").Token
+	adminRoute := fmt.Sprintf("/admin/users/%v", target.ID)
+
+	// Only users that manage users can use the admin routes
+	statusCode, responseBody := sendSessionRequest(t, "POST", adminRoute+"/roles", bystanderToken, map[string]interface{}{"role": models.RoleModerator})
+	assert.Equal(t, 401, statusCode, "status code of promotion by a regular user")
+	assertProblem(t, apierrors.PermissionDenied, responseBody, "response of promotion by a regular user")
+
+	// Promote and demote
+	statusCode, responseBody = sendSessionRequest(t, "POST", adminRoute+"/roles", adminLogin.Token, map[string]interface{}{"role": models.RoleModerator})
+	assert.Equal(t, 200, statusCode, "status code of promotion: %s", responseBody)
+
+	var userRoles models.UserRoles
+	if err := json.Unmarshal(responseBody, &userRoles); err != nil {
+		t.Fatalf("Error unmarshalling response body: %v", err)
+	}
+	assert.Equal(t, []string{models.RoleModerator}, userRoles.Roles, "Roles after promotion mismatch")
+
+	statusCode, responseBody = sendSessionRequest(t, "POST", adminRoute+"/roles", adminLogin.Token, map[string]interface{}{"role": models.RoleModerator})
+	assert.Equal(t, 400, statusCode, "status code of repeated promotion")
+	assertProblem(t, apierrors.RoleAlreadyGranted, responseBody, "response of repeated promotion")
+
+	statusCode, responseBody = sendSessionRequest(t, "DELETE", adminRoute+"/roles/"+models.RoleModerator, adminLogin.Token, nil)
+	assert.Equal(t, 200, statusCode, "status code of demotion: %s", responseBody)
+
+	statusCode, responseBody = sendSessionRequest(t, "DELETE", adminRoute+"/roles/"+models.RoleModerator, adminLogin.Token, nil)
+	assert.Equal(t, 404, statusCode, "status code of repeated demotion")
+	assertProblem(t, apierrors.RoleNotGranted, responseBody, "response of repeated demotion")
+
+	statusCode, responseBody = sendSessionRequest(t, "DELETE", fmt.Sprintf("/admin/users/%v/roles/%v", adminLogin.UserID, models.RoleAdmin), adminLogin.Token, nil)
+	assert.Equal(t, 400, statusCode, "status code of self demotion")
+	assertProblem(t, apierrors.SelfDemotion, responseBody, "response of self demotion")
+
+	// Demoted admins lose the permissions of the role on the tokens issued while they were admins
+	statusCode, responseBody = sendSessionRequest(t, "POST", adminRoute+"/roles", adminLogin.Token, map[string]interface{}{"role": models.RoleAdmin})
+	assert.Equal(t, 200, statusCode, "status code of promotion to admin: %s", responseBody)
+
+	statusCode, responseBody = sendSessionRequest(t, "GET", "/admin/audit-log", targetToken, nil)
+	assert.Equal(t, 200, statusCode, "status code of admin route for the promoted user: %s", responseBody)
+
+	statusCode, responseBody = sendSessionRequest(t, "DELETE", adminRoute+"/roles/"+models.RoleAdmin, adminLogin.Token, nil)
+	assert.Equal(t, 200, statusCode, "status code of demotion from admin: %s", responseBody)
+
+	statusCode, responseBody = sendSessionRequest(t, "GET", "/admin/audit-log", targetToken, nil)
+	assert.Equal(t, 401, statusCode, "status code of admin route with the token of a demoted admin")
+	assertProblem(t, apierrors.PermissionDenied, responseBody, "response of admin route with the token of a demoted admin")
+
+	// Bans need a reason and an expiry in the future
+	statusCode, _ = sendSessionRequest(t, "POST", adminRoute+"/ban", adminLogin.Token, map[string]interface{}{})
+	assert.Equal(t, 400, statusCode, "status code of ban without a reason")
+
+	statusCode, responseBody = sendSessionRequest(t, "POST", adminRoute+"/ban", adminLogin.Token, map[string]interface{}{"reason": "Spam", "until": time.Now().Add(-time.Hour)})
+	assert.Equal(t, 400, statusCode, "status code of ban expiring in the past")
+	assertProblem(t, apierrors.BanExpiryInPast, responseBody, "response of ban expiring in the past")
+
+	statusCode, responseBody = sendSessionRequest(t, "POST", fmt.Sprintf("/admin/users/%v/ban", adminLogin.UserID), adminLogin.Token, map[string]interface{}{"reason": "Oops"})
+	assert.Equal(t, 400, statusCode, "status code of self ban")
+	assertProblem(t, apierrors.SelfBan, responseBody, "response of self ban")
+
+	banUntil := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	statusCode, responseBody = sendSessionRequest(t, "POST", adminRoute+"/ban", adminLogin.Token, map[string]interface{}{"reason": "Spam", "until": banUntil})
+	assert.Equal(t, 200, statusCode, "status code of ban: %s", responseBody)
+
+	var bannedUser models.UserResponse
+	if err := json.Unmarshal(responseBody, &bannedUser); err != nil {
+		t.Fatalf("Error unmarshalling response body: %v", err)
+	}
+	assert.True(t, bannedUser.Banned(), "User should be banned")
+	assert.Equal(t, "Spam", bannedUser.BanReason, "Ban reason mismatch")
+	assert.True(t, banUntil.Equal(bannedUser.BannedUntil.Time), "Ban expiry mismatch")
+
+	// Banned users are rejected at login and by the auth middleware, even with tokens issued before the ban
+	bannedError := apierrors.UserBannedUntil(banUntil)
+	statusCode, responseBody = sendSessionRequest(t, "POST", "/login", "", map[string]interface{}{"email": target.Email, "password": "testando123@Teste"})
+	assert.Equal(t, 403, statusCode, "status code of login of banned user")
+	assertProblem(t, bannedError, responseBody, "response of login of banned user")
+
+	statusCode, responseBody = sendSessionRequest(t, "GET", fmt.Sprintf("/users/%v", target.ID), targetToken, nil)
+	assert.Equal(t, 403, statusCode, "status code of request of banned user")
+	assertProblem(t, bannedError, responseBody, "response of request of banned user")
+
+	// Unbanning restores access to the existing sessions
+	statusCode, responseBody = sendSessionRequest(t, "DELETE", adminRoute+"/ban", adminLogin.Token, nil)
+	assert.Equal(t, 200, statusCode, "status code of unban: %s", responseBody)
+
+	statusCode, responseBody = sendSessionRequest(t, "DELETE", adminRoute+"/ban", adminLogin.Token, nil)
+	assert.Equal(t, 400, statusCode, "status code of repeated unban")
+	assertProblem(t, apierrors.UserNotBanned, responseBody, "response of repeated unban")
+
+	statusCode, responseBody = sendSessionRequest(t, "GET", fmt.Sprintf("/users/%v", target.ID), targetToken, nil)
+	assert.Equal(t, 200, statusCode, "status code of request of unbanned user: %s", responseBody)
+
+	// Forcing a password reset logs the user out until a new password is set with the token
+	statusCode, responseBody = sendSessionRequest(t, "POST", adminRoute+"/password-reset", adminLogin.Token, nil)
+	assert.Equal(t, 201, statusCode, "status code of forced password reset: %s", responseBody)
+
+	var passwordReset controllers.PasswordResetResponse
+	if err := json.Unmarshal(responseBody, &passwordReset); err != nil {
+		t.Fatalf("Error unmarshalling response body: %v", err)
+	}
+	assert.NotEmpty(t, passwordReset.ResetToken, "Reset token should be returned")
+
+	statusCode, _ = sendSessionRequest(t, "GET", fmt.Sprintf("/users/%v", target.ID), targetToken, nil)
+	assert.Equal(t, 401, statusCode, "status code of request with session revoked by the reset")
+
+	statusCode, responseBody = sendSessionRequest(t, "POST", "/login", "", map[string]interface{}{"email": target.Email, "password": "testando123@Teste"})
+	assert.Equal(t, 403, statusCode, "status code of login with pending reset")
+	assertProblem(t, apierrors.PasswordResetRequired, responseBody, "response of login with pending reset")
+
+	statusCode, responseBody = sendSessionRequest(t, "POST", "/password-reset", "", map[string]interface{}{"resetToken": "wrong", "password": "novaSenha123@Teste"})
+	assert.Equal(t, 400, statusCode, "status code of reset with invalid token")
+	assertProblem(t, apierrors.InvalidResetToken, responseBody, "response of reset with invalid token")
+
+	statusCode, responseBody = sendSessionRequest(t, "POST", "/password-reset", "", map[string]interface{}{"resetToken": passwordReset.ResetToken, "password": "novaSenha123@Teste"})
+	assert.Equal(t, 204, statusCode, "status code of password reset: %s", responseBody)
+
+	statusCode, _ = sendSessionRequest(t, "POST", "/password-reset", "", map[string]interface{}{"resetToken": passwordReset.ResetToken, "password": "outraSenha123@Teste"})
+	assert.Equal(t, 400, statusCode, "status code of reusing reset token")
+
+	loginForTest(t, target.Email, "novaSenha123@Teste")
+
+	// Every action lands in the audit log with the admin who did it
+	auditLog := getAuditLog(t, fmt.Sprintf("/admin/audit-log?target_id=%v&sort=created,asc", target.ID), adminLogin.Token)
+	actions := []string{}
+	for _, entry := range auditLog {
+		actions = append(actions, entry.Action)
+		assert.Equal(t, adminLogin.UserID, entry.ActorId.UUID, "Audit log actor mismatch")
+	}
+	assert.Equal(t, []string{models.AuditPromote, models.AuditDemote, models.AuditPromote, models.AuditDemote, models.AuditBan, models.AuditUnban, models.AuditPasswordReset}, actions, "Audit log actions mismatch")
+	assert.Equal(t, models.RoleModerator, auditLog[0].Role, "Audit log role mismatch")
+	assert.Equal(t, models.RoleAdmin, auditLog[2].Role, "Audit log role mismatch")
+	assert.Equal(t, "Spam", auditLog[4].Reason, "Audit log reason mismatch")
+
+	bans := getAuditLog(t, fmt.Sprintf("/admin/audit-log?target_id=%v&action=ban", target.ID), adminLogin.Token)
+	assert.Len(t, bans, 1, "Audit log filtered by action length mismatch")
+
+	statusCode, _ = sendSessionRequest(t, "GET", "/admin/audit-log?action=delete", adminLogin.Token, nil)
+	assert.Equal(t, 400, statusCode, "status code of audit log with invalid action")
+}
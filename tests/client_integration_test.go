@@ -0,0 +1,123 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/VinOfSteel/cinemagrader/apierrors"
+	"github.com/VinOfSteel/cinemagrader/client"
+	"github.com/VinOfSteel/cinemagrader/initializers"
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/VinOfSteel/cinemagrader/moderation"
+	"github.com/VinOfSteel/cinemagrader/server"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestServer serves the real app, with the routes and error handler of the server, to test the client against it
+func newTestServer(t *testing.T) *httptest.Server {
+	db := initializers.NewDatabaseConn()
+	t.Cleanup(func() { db.Close() })
+
+	app := fiber.New(server.Config())
+	app.Use(requestid.New())
+	server.RegisterRoutes(app, db, initializers.NewValidator(db), &moderation.Blocklist{})
+
+	testServer := httptest.NewServer(adaptor.FiberApp(app))
+	t.Cleanup(testServer.Close)
+
+	return testServer
+}
+
+func Test_Client(t *testing.T) {
+	testServer := newTestServer(t)
+	ctx := context.Background()
+
+	userClient := client.New(testServer.URL)
+	userBody := models.UserBody{Name: "Client", Surname: "User", Email: "clientuser@teste.com", Password: "testando123@Teste", Birthday: "1990-01-01"}
+
+	user, err := userClient.Users.Create(ctx, userBody)
+	if err != nil {
+		t.Fatalf("Error creating user with the client: %v", err)
+	}
+	assert.Equal(t, userBody.Email, user.Email, "Created user email mismatch")
+
+	// Errors of the handlers and of validation come back typed
+	_, err = userClient.Users.Create(ctx, userBody)
+	var apiError *client.APIError
+	if assert.ErrorAs(t, err, &apiError, "Repeated user should fail with an APIError") {
+		assert.Equal(t, http.StatusBadRequest, apiError.StatusCode, "status code of repeated user")
+		assert.Equal(t, "User with this email already exists", apiError.Message, "message of repeated user")
+		assert.NotEmpty(t, apiError.RequestID, "request id of repeated user")
+	}
+	assert.ErrorIs(t, err, apierrors.UserEmailTaken, "code of repeated user")
+
+	_, err = userClient.Users.Create(ctx, models.UserBody{Name: "Client", Surname: "User", Email: "invalid", Password: "testando123@Teste", Birthday: "1990-01-01"})
+	var validationError *client.ValidationError
+	if assert.ErrorAs(t, err, &validationError, "Invalid user should fail with a ValidationError") {
+		assert.Contains(t, validationError.Errors, "email", "Invalid fields should have the email")
+	}
+
+	// The session is kept by the client and refreshed when asked to
+	if _, err := userClient.Sessions.Login(ctx, userBody.Email, userBody.Password); err != nil {
+		t.Fatalf("Error logging in with the client: %v", err)
+	}
+
+	gotUser, err := userClient.Users.Get(ctx, user.ID)
+	assert.NoError(t, err, "Error getting logged user")
+	assert.Equal(t, user.ID, gotUser.ID, "Logged user id mismatch")
+
+	_, err = userClient.Users.Get(ctx, userResponses[0].ID)
+	assert.Equal(t, http.StatusUnauthorized, client.StatusCode(err), "status code of getting another user")
+
+	oldToken, _ := userClient.Tokens()
+	session, err := userClient.Sessions.Refresh(ctx)
+	assert.NoError(t, err, "Error refreshing session")
+	assert.Equal(t, user.ID, session.UserID, "Refreshed session user mismatch")
+	assert.NotEqual(t, oldToken, session.Token, "Refreshed token should be new")
+
+	_, err = userClient.Users.Get(ctx, user.ID)
+	assert.NoError(t, err, "Error getting logged user after refresh")
+
+	// Catalog routes with the admin
+	adminClient := client.New(testServer.URL)
+	adminSession, err := adminClient.Sessions.Login(ctx, "admin@admin.com", "Testando@Teste**")
+	if err != nil {
+		t.Fatalf("Error logging in as admin with the client: %v", err)
+	}
+
+	genre, err := adminClient.Genres.Create(ctx, models.GenreBody{Name: "Client Genre", CreatorId: adminSession.UserID.String()})
+	if err != nil {
+		t.Fatalf("Error creating genre with the client: %v", err)
+	}
+
+	gotGenre, err := userClient.Genres.Get(ctx, genre.ID)
+	assert.NoError(t, err, "Error getting genre")
+	assert.Equal(t, "Client Genre", gotGenre.Name, "Genre name mismatch")
+
+	genres, err := userClient.Genres.List(ctx, &client.GenreListOptions{Name: "Client Genre"})
+	assert.NoError(t, err, "Error listing genres")
+	assert.Len(t, genres.Data, 1, "Genres filtered by name length mismatch")
+
+	movies, err := userClient.Movies.List(ctx, &client.MovieListOptions{ListOptions: client.ListOptions{Limit: 1}})
+	assert.NoError(t, err, "Error listing movies")
+	assert.LessOrEqual(t, len(movies.Data), 1, "Movies page should respect the limit")
+
+	_, err = userClient.Genres.Get(ctx, uuid.New())
+	assert.True(t, client.IsNotFound(err), "Missing genre should be not found: %v", err)
+	assert.ErrorIs(t, err, apierrors.GenreNotFound, "Missing genre should have the genre.not_found code")
+
+	// Logging out forgets the session
+	assert.NoError(t, userClient.Sessions.Logout(ctx), "Error logging out")
+	token, refreshToken := userClient.Tokens()
+	assert.Empty(t, token+refreshToken, "Tokens should be cleared by logout")
+
+	_, err = userClient.Users.Get(ctx, user.ID)
+	assert.True(t, errors.As(err, &apiError) && apiError.StatusCode == http.StatusUnauthorized, "Logged out request should be unauthorized: %v", err)
+}
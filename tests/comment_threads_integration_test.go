@@ -0,0 +1,176 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/VinOfSteel/cinemagrader/apierrors"
+	"github.com/VinOfSteel/cinemagrader/initializers"
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func getMovieComments(t *testing.T, route string, token string) []models.CommentResponse {
+	statusCode, responseBody := sendSessionRequest(t, "GET", route, token, nil)
+	if statusCode != 200 {
+		t.Fatalf("Unexpected status code %v getting movie comments: %s", statusCode, responseBody)
+	}
+
+	var movie models.MovieResponseWithActorsWithComments
+	if err := json.Unmarshal(responseBody, &movie); err != nil {
+		t.Fatalf("Error unmarshalling response body: %v", err)
+	}
+
+	return movie.Comments
+}
+
+func Test_CommentThreadsAndLikes(t *testing.T) {
+	db := initializers.NewDatabaseConn()
+	defer db.Close()
+
+	users := map[string]models.UserResponse{}
+	for _, user := range InsertMockedUsersInDB(db, []models.UserBody{
+		{Name: "Thread", Surname: "Starter", Email: "threadstarter@teste.com", Password: "testando123@Teste", Birthday: "1990-01-01"},
+		{Name: "Thread", Surname: "Replier", Email: "threadreplier@teste.com", Password: "testando123@Teste", Birthday: "1990-01-01"},
+	}) {
+		users[user.Surname] = user
+	}
+	starter, replier := users["Starter"], users["Replier"]
+	replierToken := loginForTest(t, replier.Email, "testando123@Teste").Token
+
+	movies := []models.MovieResponseWithActors{}
+	for _, title := range []string{"Thread Movie", "Other Thread Movie"} {
+		movie, err := MovieModel.InsertMovieInDB(db, models.MovieBody{
+			Title:       title,
+			Director:    "Thread Director",
+			ReleaseDate: "2012-12-12",
+			CreatorId:   adminId,
+			Actors:      []models.CastingBody{{ActorId: actorResponses[0].ID.String()}},
+		})
+		if err != nil {
+			t.Fatalf("Error inserting movie: %v", err)
+		}
+		movies = append(movies, movie)
+	}
+	movieId := movies[0].ID.String()
+
+	review, err := CommentModel.InsertCommentInDB(db, starter.ID, models.CommentBody{Comment: "Loved it", Grade: 4, MovieId: movieId})
+	if err != nil {
+		t.Fatalf("Error inserting review: %v", err)
+	}
+	other, err := CommentModel.InsertCommentInDB(db, starter.ID, models.CommentBody{Comment: "Second thoughts", MovieId: movieId})
+	if err != nil {
+		t.Fatalf("Error inserting comment: %v", err)
+	}
+
+	// Replying down to the depth limit
+	replyRoute := fmt.Sprintf("/comments/%v", replier.ID)
+	parentId := review.ID.String()
+	replies := []models.CommentResponse{}
+	for depth := 1; depth <= models.MaxCommentDepth; depth++ {
+		statusCode, responseBody := sendSessionRequest(t, "POST", replyRoute, replierToken, map[string]interface{}{
+			"comment":  fmt.Sprintf("Reply at depth %d", depth),
+			"movieId":  movieId,
+			"parentId": parentId,
+		})
+		if !assert.Equal(t, 201, statusCode, "status code of reply creation: %s", responseBody) {
+			return
+		}
+
+		var reply models.CommentResponse
+		if err := json.Unmarshal(responseBody, &reply); err != nil {
+			t.Fatalf("Error unmarshalling response body: %v", err)
+		}
+		assert.Equal(t, depth, reply.Depth, "Depth of reply mismatch")
+		assert.Equal(t, parentId, reply.ParentId.UUID.String(), "Parent of reply mismatch")
+
+		replies = append(replies, reply)
+		parentId = reply.ID.String()
+	}
+
+	statusCode, responseBody := sendSessionRequest(t, "POST", replyRoute, replierToken, map[string]interface{}{
+		"comment": "Too deep", "movieId": movieId, "parentId": parentId,
+	})
+	assert.Equal(t, 400, statusCode, "status code of reply over the depth limit")
+	assertProblem(t, apierrors.ReplyTooDeep(3), responseBody, "response of reply over the depth limit")
+
+	statusCode, responseBody = sendSessionRequest(t, "POST", replyRoute, replierToken, map[string]interface{}{
+		"comment": "Graded reply", "grade": 3, "movieId": movieId, "parentId": review.ID.String(),
+	})
+	assert.Equal(t, 400, statusCode, "status code of graded reply")
+	assertProblem(t, apierrors.ReplyWithGrade, responseBody, "response of graded reply")
+
+	statusCode, responseBody = sendSessionRequest(t, "POST", replyRoute, replierToken, map[string]interface{}{
+		"comment": "Wrong movie", "movieId": movies[1].ID.String(), "parentId": review.ID.String(),
+	})
+	assert.Equal(t, 400, statusCode, "status code of reply on another movie")
+	assertProblem(t, apierrors.ReplyOnOtherMovie, responseBody, "response of reply on another movie")
+
+	statusCode, responseBody = sendSessionRequest(t, "PATCH", fmt.Sprintf("/comments/%v", replies[0].ID), replierToken, map[string]interface{}{"grade": 3})
+	assert.Equal(t, 400, statusCode, "status code of grading a reply")
+	assertProblem(t, apierrors.ReplyWithGrade, responseBody, "response of grading a reply")
+
+	// Likes
+	likeRoute := fmt.Sprintf("/comments/%v/like", other.ID)
+	statusCode, _ = sendSessionRequest(t, "POST", likeRoute, replierToken, nil)
+	assert.Equal(t, 204, statusCode, "status code of like")
+
+	statusCode, responseBody = sendSessionRequest(t, "POST", likeRoute, replierToken, nil)
+	assert.Equal(t, 400, statusCode, "status code of repeated like")
+	assertProblem(t, apierrors.CommentAlreadyLiked, responseBody, "response of repeated like")
+
+	liked, err := CommentModel.GetCommentById(db, other.ID)
+	if err != nil {
+		t.Fatalf("Error getting comment by id: %v", err)
+	}
+	assert.Equal(t, 1, liked.LikeCount, "Like count mismatch")
+
+	// The liked comment ranks first, even though the review is older and has replies
+	topComments := getMovieComments(t, fmt.Sprintf("/movies/%v/comments?sort=top", movieId), "")
+	if assert.NotEmpty(t, topComments, "Top comments should not be empty") {
+		assert.Equal(t, other.ID, topComments[0].ID, "Most liked comment should be first")
+	}
+
+	// Threads
+	threads := getMovieComments(t, fmt.Sprintf("/movies/%v/comments?threaded=true&sort=created,asc", movieId), "")
+	if assert.Len(t, threads, 2, "Only comments on the movie should be at the top of the threads") {
+		assert.Equal(t, review.ID, threads[0].ID, "First thread mismatch")
+		assert.Equal(t, 1, threads[0].ReplyCount, "Reply count mismatch")
+
+		reply := threads[0]
+		for depth := 1; depth <= models.MaxCommentDepth; depth++ {
+			if !assert.Len(t, reply.Replies, 1, "Replies at depth %d mismatch", depth) {
+				break
+			}
+			reply = reply.Replies[0]
+			assert.Equal(t, replies[depth-1].ID, reply.ID, "Reply at depth %d mismatch", depth)
+		}
+	}
+
+	// Deleting a reply updates the reply count of its parent
+	statusCode, _ = sendSessionRequest(t, "DELETE", fmt.Sprintf("/comments/%v", replies[0].ID), replierToken, nil)
+	assert.Equal(t, 204, statusCode, "status code of reply deletion")
+
+	parent, err := CommentModel.GetCommentById(db, review.ID)
+	if err != nil {
+		t.Fatalf("Error getting comment by id: %v", err)
+	}
+	assert.Equal(t, 0, parent.ReplyCount, "Deleted replies should not be counted")
+
+	// Unliking
+	statusCode, _ = sendSessionRequest(t, "DELETE", likeRoute, replierToken, nil)
+	assert.Equal(t, 204, statusCode, "status code of unlike")
+
+	statusCode, responseBody = sendSessionRequest(t, "DELETE", likeRoute, replierToken, nil)
+	assert.Equal(t, 404, statusCode, "status code of repeated unlike")
+	assertProblem(t, apierrors.CommentNotLiked, responseBody, "response of repeated unlike")
+
+	statusCode, responseBody = sendSessionRequest(t, "POST", fmt.Sprintf("/comments/%v/like", replies[0].ID), replierToken, nil)
+	assert.Equal(t, 404, statusCode, "status code of liking a deleted comment")
+	assertProblem(t, apierrors.CommentNotFound, responseBody, "response of liking a deleted comment")
+
+	statusCode, responseBody = sendSessionRequest(t, "GET", fmt.Sprintf("/movies/%v/comments?threaded=maybe", movieId), "", nil)
+	assert.Equal(t, 400, statusCode, "status code of invalid threaded param")
+	assertProblem(t, apierrors.InvalidQueryParam("threaded", apierrors.ValidBool), responseBody, "response of invalid threaded param")
+}
@@ -11,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/VinOfSteel/cinemagrader/apierrors"
 	"github.com/VinOfSteel/cinemagrader/initializers"
 	"github.com/VinOfSteel/cinemagrader/models"
 	"github.com/google/uuid"
@@ -18,10 +19,14 @@ import (
 )
 
 func Test_CommentsRoutes(t *testing.T) {
+	// Creating, listing, editing and deleting comments are guarded routes, and admins are let through all of them
+	adminToken := loginForTest(t, "admin@admin.com", "Testando@Teste**").Token
+
 	testCases := []struct {
 		description      string
 		route            string
 		method           string
+		token            string
 		data             map[string]interface{}
 		expectedCode     int
 		expectedResponse interface{}
@@ -33,6 +38,7 @@ func Test_CommentsRoutes(t *testing.T) {
 			description: "POST BY ID - Create a new actor route - Success Case",
 			route:       fmt.Sprintf("/comments/%v", userResponses[1].ID.String()),
 			method:      "POST",
+			token:       adminToken,
 			data: map[string]interface{}{
 				"comment": "i8fhdas8ifdhas0i fhasoif hasoif hasiof hasipodf hpaisd hpas dpoa",
 				"grade":   4,
@@ -48,78 +54,108 @@ func Test_CommentsRoutes(t *testing.T) {
 			testType: "success",
 		},
 		{
-			description:  "POST WITH ID - Passing an user uuid that does not exist in DB - Error Case",
-			route:        fmt.Sprintf("/comments/%v", uuid.New()),
-			method:       "POST",
-			expectedCode: 404,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "User id not found in database",
-			},
-			responseType: "struct",
-			testType:     "global-error",
+			description:      "POST WITH ID - Passing an user uuid that does not exist in DB - Error Case",
+			route:            fmt.Sprintf("/comments/%v", uuid.New()),
+			method:           "POST",
+			token:            adminToken,
+			expectedCode:     404,
+			expectedResponse: apierrors.UserNotFound,
+			responseType:     "struct",
+			testType:         "global-error",
 		},
 		{
-			description:  "POST WITH ID - Passing an invalid user uuid - Error Case",
-			route:        "/comments/testestetsts",
-			method:       "POST",
-			expectedCode: 400,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "Invalid uuid parameter",
-			},
-			responseType: "struct",
-			testType:     "global-error",
+			description:      "POST WITH ID - Passing an invalid user uuid - Error Case",
+			route:            "/comments/testestetsts",
+			method:           "POST",
+			token:            adminToken,
+			expectedCode:     400,
+			expectedResponse: apierrors.InvalidUUID,
+			responseType:     "struct",
+			testType:         "global-error",
 		},
 		// Get requests
 		{
 			description:  "GET - All comments with basic query params - Success Case", // We don't do gigantic offsets and limits to not need to mock 10 things
 			route:        "/comments?offset=1&limit=3&sort=grade,asc",
 			method:       "GET",
+			token:        adminToken,
 			expectedCode: 200,
 			expectedResponse: []models.CommentResponse{
 				{
 					Comment: "Comment 4",
 					Grade:   2,
 					MovieId: movieResponses[0].ID.String(),
-					UserId:  adminId,
+					UserId:  userResponses[2].ID.String(),
 				},
 				{
 					Comment: "Comment 3",
 					Grade:   3,
 					MovieId: movieResponses[0].ID.String(),
-					UserId:  adminId,
+					UserId:  userResponses[1].ID.String(),
 				},
 				{
 					Comment: "Comment 2", // Since we have the comment created in the POST request, commenting the other tests will net this one a failure. Too bad!
 					Grade:   4,
 					MovieId: movieResponses[0].ID.String(),
-					UserId:  adminId,
+					UserId:  userResponses[0].ID.String(),
 				},
 			},
 			responseType: "slice",
 			testType:     "success",
 		},
 		{
-			description:  "GET - Passing an offset that is not a number - Error Case",
-			route:        "/comments?offset=2.254",
-			method:       "GET",
-			expectedCode: 400,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "Offset needs to be a valid integer",
-			},
-			responseType: "slice",
-			testType:     "global-error",
+			description:      "GET - Passing an offset that is not a number - Error Case",
+			route:            "/comments?offset=2.254",
+			method:           "GET",
+			token:            adminToken,
+			expectedCode:     400,
+			expectedResponse: apierrors.InvalidOffset,
+			responseType:     "slice",
+			testType:         "global-error",
 		},
 		{
-			description:  "GET - Passing a limit that is not a number - Error Case",
-			route:        "/comments?limit=aushaushaush",
+			description:      "GET - Passing a limit that is not a number - Error Case",
+			route:            "/comments?limit=aushaushaush",
+			method:           "GET",
+			token:            adminToken,
+			expectedCode:     400,
+			expectedResponse: apierrors.InvalidLimit,
+			responseType:     "slice",
+			testType:         "global-error",
+		}, // Since sort casts every non-valid value to a default valid one, it does not need to be tested, as any error case will fall into the updated_at DESC clause.
+		{
+			description:  "GET - Filtering comments by movie and grade range - Success Case",
+			route:        fmt.Sprintf("/comments?movie=%v&min_grade=2&max_grade=3&sort=grade,desc", movieResponses[0].ID),
 			method:       "GET",
-			expectedCode: 400,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "Limit needs to be a valid integer",
+			token:        adminToken,
+			expectedCode: 200,
+			expectedResponse: []models.CommentResponse{
+				{
+					Comment: "Comment 3",
+					Grade:   3,
+					MovieId: movieResponses[0].ID.String(),
+					UserId:  userResponses[1].ID.String(),
+				},
+				{
+					Comment: "Comment 4",
+					Grade:   2,
+					MovieId: movieResponses[0].ID.String(),
+					UserId:  userResponses[2].ID.String(),
+				},
 			},
 			responseType: "slice",
-			testType:     "global-error",
-		}, // Since sort casts every non-valid value to a default valid one, it does not need to be tested, as any error case will fall into the updated_at DESC clause.
+			testType:     "success",
+		},
+		{
+			description:      "GET - Passing a user filter that is not an uuid - Error Case",
+			route:            "/comments?user=testestetsts",
+			method:           "GET",
+			token:            adminToken,
+			expectedCode:     400,
+			expectedResponse: apierrors.InvalidQueryParam("user", apierrors.ValidUUID),
+			responseType:     "slice",
+			testType:         "global-error",
+		},
 		{
 			description:      "GET BY ID - Passing an uuid that exists in DB - Success Case",
 			route:            fmt.Sprintf("/comments/%v", commentResponses[1].ID),
@@ -130,61 +166,57 @@ func Test_CommentsRoutes(t *testing.T) {
 			testType:         "success",
 		},
 		{
-			description:  "GET BY ID - Passing an uuid that does not exist in DB - Error Case",
-			route:        fmt.Sprintf("/comments/%v", uuid.New()),
-			method:       "GET",
-			expectedCode: 404,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "Comment id not found in database",
-			},
-			responseType: "struct",
-			testType:     "global-error",
+			description:      "GET BY ID - Passing an uuid that does not exist in DB - Error Case",
+			route:            fmt.Sprintf("/comments/%v", uuid.New()),
+			method:           "GET",
+			expectedCode:     404,
+			expectedResponse: apierrors.CommentNotFound,
+			responseType:     "struct",
+			testType:         "global-error",
 		},
 		{
-			description:  "GET BY ID - Passing an invalid uuid - Error Case",
-			route:        "/comments/testestetsts",
-			method:       "GET",
-			expectedCode: 400,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "Invalid uuid parameter",
-			},
-			responseType: "struct",
-			testType:     "global-error",
+			description:      "GET BY ID - Passing an invalid uuid - Error Case",
+			route:            "/comments/testestetsts",
+			method:           "GET",
+			expectedCode:     400,
+			expectedResponse: apierrors.InvalidUUID,
+			responseType:     "struct",
+			testType:         "global-error",
 		},
 		// Delete requests
 		{
 			description:      "DELETE BY ID - Passing an uuid that exists in DB - Success Case",
 			route:            fmt.Sprintf("/comments/%v", commentResponses[2].ID),
 			method:           "DELETE",
+			token:            adminToken,
 			expectedCode:     204,
 			expectedResponse: commentResponses[2],
 			testType:         "delete",
 		},
 		{
-			description:  "DELETE BY ID - Passing an uuid that does not exist in DB - Error Case",
-			route:        fmt.Sprintf("/comments/%v", uuid.New()),
-			method:       "DELETE",
-			expectedCode: 404,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "Comment id not found in database",
-			},
-			testType: "global-error",
+			description:      "DELETE BY ID - Passing an uuid that does not exist in DB - Error Case",
+			route:            fmt.Sprintf("/comments/%v", uuid.New()),
+			method:           "DELETE",
+			token:            adminToken,
+			expectedCode:     404,
+			expectedResponse: apierrors.CommentNotFound,
+			testType:         "global-error",
 		},
 		{
-			description:  "DELETE BY ID - Passing an invalid uuid - Error Case",
-			route:        fmt.Sprintf("/comments/%v", "testeasdasd"),
-			method:       "DELETE",
-			expectedCode: 400,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "Invalid uuid parameter",
-			},
-			testType: "global-error",
+			description:      "DELETE BY ID - Passing an invalid uuid - Error Case",
+			route:            fmt.Sprintf("/comments/%v", "testeasdasd"),
+			method:           "DELETE",
+			token:            adminToken,
+			expectedCode:     400,
+			expectedResponse: apierrors.InvalidUUID,
+			testType:         "global-error",
 		},
 		// Update requests
 		{
 			description: "UPDATE - Update comment info (all keys) - Success Case",
 			route:       fmt.Sprintf("/comments/%v", commentResponses[3].ID),
 			method:      "PATCH",
+			token:       adminToken,
 			data: map[string]interface{}{
 				"comment": "New comment",
 				"grade":   3.4,
@@ -197,26 +229,24 @@ func Test_CommentsRoutes(t *testing.T) {
 			testType: "update",
 		},
 		{
-			description:  "UPDATE - Passing an uuid that does not exist in DB - Error Case",
-			route:        fmt.Sprintf("/comments/%v", uuid.New()),
-			method:       "PATCH",
-			expectedCode: 404,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "Comment id not found in database",
-			},
-			responseType: "struct",
-			testType:     "global-error",
+			description:      "UPDATE - Passing an uuid that does not exist in DB - Error Case",
+			route:            fmt.Sprintf("/comments/%v", uuid.New()),
+			method:           "PATCH",
+			token:            adminToken,
+			expectedCode:     404,
+			expectedResponse: apierrors.CommentNotFound,
+			responseType:     "struct",
+			testType:         "global-error",
 		},
 		{
-			description:  "UPDATE - Passing an invalid uuid - Error Case",
-			route:        fmt.Sprintf("/comments/%v", "saudhaushdu"),
-			method:       "PATCH",
-			expectedCode: 400,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "Invalid uuid parameter",
-			},
-			responseType: "struct",
-			testType:     "global-error",
+			description:      "UPDATE - Passing an invalid uuid - Error Case",
+			route:            fmt.Sprintf("/comments/%v", "saudhaushdu"),
+			method:           "PATCH",
+			token:            adminToken,
+			expectedCode:     400,
+			expectedResponse: apierrors.InvalidUUID,
+			responseType:     "struct",
+			testType:         "global-error",
 		},
 	}
 
@@ -241,6 +271,9 @@ func Test_CommentsRoutes(t *testing.T) {
 		}
 
 		req.Header.Set("Content-Type", "application/json")
+		if testCase.token != "" {
+			req.Header.Set("Authorization", "Bearer "+testCase.token)
+		}
 
 		resp, err := App.Test(req, -1)
 		if err != nil {
@@ -261,9 +294,12 @@ func Test_CommentsRoutes(t *testing.T) {
 			var respSlice []models.CommentResponse
 
 			if testCase.responseType == "slice" {
-				if err := json.Unmarshal(responseBody, &respSlice); err != nil {
+				// List routes answer with the pagination envelope
+				var respPage models.Page[models.CommentResponse]
+				if err := json.Unmarshal(responseBody, &respPage); err != nil {
 					t.Fatalf("Error unmarshalling response body: %v", err)
 				}
+				respSlice = respPage.Data
 			} else {
 				if err := json.Unmarshal(responseBody, &respStruct); err != nil {
 					t.Fatalf("Error unmarshalling response body: %v", err)
@@ -330,7 +366,7 @@ func Test_CommentsRoutes(t *testing.T) {
 		}
 
 		if testCase.testType == "global-error" {
-			assert.Equal(t, testCase.expectedResponse.(GlobalErrorHandlerResp).Message, string(responseBody))
+			assertProblem(t, testCase.expectedResponse.(*apierrors.Error), responseBody)
 		}
 
 		if testCase.testType == "delete" {
@@ -373,3 +409,199 @@ func Test_CommentsRoutes(t *testing.T) {
 		}
 	}
 }
+
+func Test_MovieReviewRoutes(t *testing.T) {
+	db := initializers.NewDatabaseConn()
+	defer db.Close()
+
+	reviewer := userResponses[3]
+	token := loginForTest(t, reviewer.Email, "testando123@Teste").Token
+	reviewRoute := fmt.Sprintf("/movies/%v/review", movieResponses[4].ID)
+
+	decodeComment := func(t *testing.T, responseBody []byte) models.CommentResponse {
+		var comment models.CommentResponse
+		if err := json.Unmarshal(responseBody, &comment); err != nil {
+			t.Fatalf("Error unmarshalling response body: %v", err)
+		}
+
+		return comment
+	}
+
+	statusCode, _ := sendSessionRequest(t, "PUT", reviewRoute, "", map[string]interface{}{"comment": "No token", "grade": 3})
+	assert.Equal(t, 401, statusCode, "status code of review without token")
+
+	// The first PUT creates the review and the next ones replace it
+	statusCode, responseBody := sendSessionRequest(t, "PUT", reviewRoute, token, map[string]interface{}{"comment": "Pretty good", "grade": 4})
+	assert.Equal(t, 201, statusCode, "status code of review creation")
+
+	review := decodeComment(t, responseBody)
+	assert.Equal(t, "Pretty good", review.Comment, "Comment mismatch")
+	assert.Equal(t, 4.0, review.Grade, "Grade mismatch")
+	assert.Equal(t, reviewer.ID.String(), review.UserId, "UserId mismatch")
+	assert.Equal(t, movieResponses[4].ID.String(), review.MovieId, "MovieId mismatch")
+
+	statusCode, responseBody = sendSessionRequest(t, "PUT", reviewRoute, token, map[string]interface{}{"comment": "Not that good on a rewatch", "grade": 2})
+	assert.Equal(t, 200, statusCode, "status code of review update")
+
+	updatedReview := decodeComment(t, responseBody)
+	assert.Equal(t, review.ID, updatedReview.ID, "The review should be updated in place")
+	assert.Equal(t, "Not that good on a rewatch", updatedReview.Comment, "Comment should be updated")
+	assert.Equal(t, 2.0, updatedReview.Grade, "Grade should be updated")
+
+	// Another graded comment would be a second review
+	statusCode, responseBody = sendSessionRequest(t, "POST", fmt.Sprintf("/comments/%v", reviewer.ID), token, map[string]interface{}{
+		"comment": "Second review",
+		"grade":   5,
+		"movieId": movieResponses[4].ID.String(),
+	})
+	assert.Equal(t, 409, statusCode, "status code of second graded comment")
+	assertProblem(t, apierrors.MovieAlreadyReviewed, responseBody, "response of second graded comment")
+
+	// Concurrent requests can get past that check, so the unique index has to be reported the same way
+	_, err := CommentModel.InsertCommentInDB(db, reviewer.ID, models.CommentBody{Comment: "Racing review", Grade: 5, MovieId: movieResponses[4].ID.String()})
+	assert.True(t, models.IsDuplicateReview(err), "Second review should be stopped by the unique index: %v", err)
+
+	// Ungraded comments are not reviews, so there can be any number of them
+	var discussion models.CommentResponse
+	for _, text := range []string{"What about the ending?", "Still thinking about the ending"} {
+		statusCode, responseBody = sendSessionRequest(t, "POST", fmt.Sprintf("/comments/%v", reviewer.ID), token, map[string]interface{}{
+			"comment": text,
+			"movieId": movieResponses[4].ID.String(),
+		})
+		assert.Equal(t, 201, statusCode, "status code of ungraded comment")
+
+		discussion = decodeComment(t, responseBody)
+		assert.Equal(t, 0.0, discussion.Grade, "Ungraded comment should have a 0 grade")
+	}
+
+	statusCode, responseBody = sendSessionRequest(t, "PATCH", fmt.Sprintf("/comments/%v", discussion.ID), token, map[string]interface{}{"grade": 5})
+	assert.Equal(t, 409, statusCode, "status code of grading a discussion comment")
+	assertProblem(t, apierrors.MovieAlreadyReviewed, responseBody, "response of grading a discussion comment")
+
+	statusCode, _ = sendSessionRequest(t, "PATCH", fmt.Sprintf("/comments/%v", review.ID), token, map[string]interface{}{"grade": 7})
+	assert.Equal(t, 400, statusCode, "status code of editing a review with a grade out of range")
+
+	movie, err := MovieModel.GetMovieByIdWithActors(db, movieResponses[4].ID)
+	if err != nil {
+		t.Fatalf("Error getting movie by id: %v", err)
+	}
+	assert.Equal(t, 2.0, movie.AverageGrade, "Only the review should count in the average grade")
+
+	// Error cases
+	statusCode, _ = sendSessionRequest(t, "PUT", reviewRoute, token, map[string]interface{}{"comment": "No grade"})
+	assert.Equal(t, 400, statusCode, "status code of review without grade")
+
+	statusCode, responseBody = sendSessionRequest(t, "PUT", fmt.Sprintf("/movies/%v/review", uuid.New()), token, map[string]interface{}{"comment": "Missing movie", "grade": 3})
+	assert.Equal(t, 404, statusCode, "status code of review of a movie that does not exist")
+	assertProblem(t, apierrors.MovieNotFound, responseBody, "response of review of a movie that does not exist")
+
+	statusCode, responseBody = sendSessionRequest(t, "PUT", "/movies/testestetsts/review", token, map[string]interface{}{"comment": "Invalid movie", "grade": 3})
+	assert.Equal(t, 400, statusCode, "status code of invalid uuid")
+	assertProblem(t, apierrors.InvalidUUID, responseBody, "response of invalid uuid")
+}
+
+func Test_CommentOwnership(t *testing.T) {
+	db := initializers.NewDatabaseConn()
+	defer db.Close()
+
+	users := map[string]models.UserResponse{}
+	for _, user := range InsertMockedUsersInDB(db, []models.UserBody{
+		{Name: "Ownership", Surname: "Author", Email: "ownershipauthor@teste.com", Password: "testando123@Teste", Birthday: "1990-01-01"},
+		{Name: "Ownership", Surname: "Other", Email: "ownershipother@teste.com", Password: "testando123@Teste", Birthday: "1990-01-01"},
+		{Name: "Ownership", Surname: "Moderator", Email: "ownershipmoderator@teste.com", Password: "testando123@Teste", Birthday: "1990-01-01"},
+	}) {
+		users[user.Surname] = user
+	}
+	author, other, moderator := users["Author"], users["Other"], users["Moderator"]
+
+	if err := RoleModel.GrantRoleToUser(db, uuid.NullUUID{}, moderator.ID, models.RoleModerator); err != nil {
+		t.Fatalf("Error granting role: %v", err)
+	}
+
+	authorToken := loginForTest(t, author.Email, "testando123@Teste").Token
+	otherToken := loginForTest(t, other.Email, "testando123@Teste").Token
+	moderatorToken := loginForTest(t, moderator.Email, "testando123@Teste").Token
+	adminToken := loginForTest(t, "admin@admin.com", "Testando@Teste**").Token
+
+	movie, err := MovieModel.InsertMovieInDB(db, models.MovieBody{
+		Title:       "Ownership Movie",
+		Director:    "Ownership Director",
+		ReleaseDate: "2012-12-12",
+		CreatorId:   adminId,
+		Actors:      []models.CastingBody{{ActorId: actorResponses[0].ID.String()}},
+	})
+	if err != nil {
+		t.Fatalf("Error inserting movie: %v", err)
+	}
+
+	insertComment := func(body models.CommentBody) string {
+		body.MovieId = movie.ID.String()
+		comment, err := CommentModel.InsertCommentInDB(db, author.ID, body)
+		if err != nil {
+			t.Fatalf("Error inserting comment: %v", err)
+		}
+
+		return fmt.Sprintf("/comments/%v", comment.ID)
+	}
+
+	commentRoute := insertComment(models.CommentBody{Comment: "My own words"})
+	reviewRoute := insertComment(models.CommentBody{Comment: "My own review", Grade: 3})
+
+	// Other users can't touch the comment, even though the param is not their user id
+	statusCode, responseBody := sendSessionRequest(t, "PATCH", commentRoute, otherToken, map[string]interface{}{"comment": "Not mine"})
+	assert.Equal(t, 401, statusCode, "status code of edit by another user")
+	assertProblem(t, apierrors.NotOwner("comment"), responseBody, "response of edit by another user")
+
+	statusCode, responseBody = sendSessionRequest(t, "DELETE", reviewRoute, otherToken, nil)
+	assert.Equal(t, 401, statusCode, "status code of deletion by another user")
+	assertProblem(t, apierrors.NotOwner("comment"), responseBody, "response of deletion by another user")
+
+	statusCode, _ = sendSessionRequest(t, "PATCH", commentRoute, "", map[string]interface{}{"comment": "No token"})
+	assert.Equal(t, 401, statusCode, "status code of edit without token")
+
+	// Authors edit their own comments and reviews
+	statusCode, responseBody = sendSessionRequest(t, "PATCH", commentRoute, authorToken, map[string]interface{}{"comment": "My edited words"})
+	assert.Equal(t, 200, statusCode, "status code of edit by the author: %s", responseBody)
+
+	statusCode, responseBody = sendSessionRequest(t, "PATCH", reviewRoute, authorToken, map[string]interface{}{"comment": "My edited review", "grade": 4})
+	assert.Equal(t, 200, statusCode, "status code of review edit by the author: %s", responseBody)
+
+	var review models.CommentResponse
+	if err := json.Unmarshal(responseBody, &review); err != nil {
+		t.Fatalf("Error unmarshalling response body: %v", err)
+	}
+	assert.Equal(t, 4.0, review.Grade, "Grade should be updated by the author")
+
+	// Moderators can't rewrite comments of others
+	statusCode, responseBody = sendSessionRequest(t, "PATCH", commentRoute, moderatorToken, map[string]interface{}{"comment": "Moderated words"})
+	assert.Equal(t, 401, statusCode, "status code of edit by a moderator")
+	assertProblem(t, apierrors.NotOwner("comment"), responseBody, "response of edit by a moderator")
+
+	// Admins edit and delete any comment
+	statusCode, responseBody = sendSessionRequest(t, "PATCH", commentRoute, adminToken, map[string]interface{}{"comment": "Edited by an admin"})
+	assert.Equal(t, 200, statusCode, "status code of edit by an admin: %s", responseBody)
+
+	var adminEdited models.CommentResponse
+	if err := json.Unmarshal(responseBody, &adminEdited); err != nil {
+		t.Fatalf("Error unmarshalling response body: %v", err)
+	}
+
+	// Only the edit of the author shows up in the activity of the author
+	var edits int
+	if err := db.QueryRow("SELECT COUNT(*) FROM activity WHERE comment_id = $1 AND type = $2;", adminEdited.ID, models.ActivityCommentEdit).Scan(&edits); err != nil {
+		t.Fatalf("Error counting edit activity: %v", err)
+	}
+	assert.Equal(t, 1, edits, "Edit by an admin should not be in the activity of the author")
+
+	statusCode, responseBody = sendSessionRequest(t, "DELETE", commentRoute, adminToken, nil)
+	assert.Equal(t, 204, statusCode, "status code of deletion by an admin: %s", responseBody)
+
+	// Moderators can delete comments of others, though
+	statusCode, responseBody = sendSessionRequest(t, "DELETE", reviewRoute, moderatorToken, nil)
+	assert.Equal(t, 204, statusCode, "status code of deletion by a moderator: %s", responseBody)
+
+	// Deleted comments are gone for their authors too
+	statusCode, responseBody = sendSessionRequest(t, "DELETE", commentRoute, authorToken, nil)
+	assert.Equal(t, 404, statusCode, "status code of deletion of a deleted comment")
+	assertProblem(t, apierrors.CommentNotFound, responseBody, "response of deletion of a deleted comment")
+}
@@ -0,0 +1,91 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/VinOfSteel/cinemagrader/apierrors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// decodeProblem reads the body of an error response, which the error handler of the server sends as an apierrors.Problem
+func decodeProblem(t *testing.T, responseBody []byte) apierrors.Problem {
+	t.Helper()
+
+	var problem apierrors.Problem
+	if err := json.Unmarshal(responseBody, &problem); err != nil {
+		t.Fatalf("Error unmarshalling problem %s: %v", responseBody, err)
+	}
+
+	return problem
+}
+
+// assertProblem checks the error response is the expected error of the catalogue by its code, and by its details when it has any
+func assertProblem(t *testing.T, expected *apierrors.Error, responseBody []byte, msgAndArgs ...interface{}) {
+	t.Helper()
+
+	problem := decodeProblem(t, responseBody)
+	assert.Equal(t, expected.Code, problem.Code, msgAndArgs...)
+	if expected.Details != nil {
+		assert.Equal(t, expected.Details, problem.Details, msgAndArgs...)
+	}
+}
+
+func Test_ErrorResponses(t *testing.T) {
+	createUser := func(language string, body map[string]interface{}) (*http.Response, apierrors.Problem) {
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("Error marshalling body: %v", err)
+		}
+
+		req := httptest.NewRequest("POST", "/users", bytes.NewReader(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		if language != "" {
+			req.Header.Set("Accept-Language", language)
+		}
+
+		resp, err := App.Test(req, -1)
+		if err != nil {
+			t.Fatalf("Error testing app requisition: %v", err)
+		}
+
+		responseBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("Error reading response body: %v", err)
+		}
+
+		return resp, decodeProblem(t, responseBody)
+	}
+	invalidUser := map[string]interface{}{"email": "not an email", "password": "testando123@Teste", "birthday": "1990-01-01"}
+
+	// Errors are problems with the code, the message of each invalid field in the details and the id of the request
+	resp, problem := createUser("", invalidUser)
+	assert.Equal(t, 400, resp.StatusCode, "status code of invalid user")
+	assert.Equal(t, apierrors.ContentType, resp.Header.Get("Content-Type"), "content type of invalid user")
+	assert.Equal(t, "en", resp.Header.Get("Content-Language"), "language of invalid user")
+	assert.Equal(t, 400, problem.Status, "status of invalid user")
+	assert.Equal(t, apierrors.ValidationFailed.Code, problem.Code, "code of invalid user")
+	assert.Equal(t, "/users", problem.Instance, "instance of invalid user")
+	assert.Equal(t, resp.Header.Get(fiber.HeaderXRequestID), problem.RequestID, "request id of invalid user")
+	assert.NotEmpty(t, problem.RequestID, "request id of invalid user")
+	assert.Equal(t, map[string]string{
+		"name":  "The name field is required.",
+		"email": "The email field needs to be a valid email.",
+	}, problem.Details, "details of invalid user")
+
+	// The message and the details follow the Accept-Language header, the code stays the same
+	resp, translated := createUser("pt-BR,pt;q=0.9", invalidUser)
+	assert.Equal(t, "pt-BR", resp.Header.Get("Content-Language"), "language of invalid user in Portuguese")
+	assert.Equal(t, apierrors.ValidationFailed.Code, translated.Code, "code of invalid user in Portuguese")
+	assert.Equal(t, "Falha na validação", translated.Message, "message of invalid user in Portuguese")
+	assert.Equal(t, map[string]string{
+		"name":  "O campo name é obrigatório.",
+		"email": "O campo email precisa ser um email válido.",
+	}, translated.Details, "details of invalid user in Portuguese")
+	assert.NotEqual(t, problem.RequestID, translated.RequestID, "request id of invalid user in Portuguese")
+}
@@ -0,0 +1,140 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/VinOfSteel/cinemagrader/apierrors"
+	"github.com/VinOfSteel/cinemagrader/initializers"
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FollowsAndFeed(t *testing.T) {
+	db := initializers.NewDatabaseConn()
+	defer db.Close()
+
+	users := map[string]models.UserResponse{}
+	for _, user := range InsertMockedUsersInDB(db, []models.UserBody{
+		{Name: "Feed", Surname: "Reader", Email: "feedreader@teste.com", Password: "testando123@Teste", Birthday: "1990-01-01"},
+		{Name: "Feed", Surname: "Critic", Email: "feedcritic@teste.com", Password: "testando123@Teste", Birthday: "1990-01-01"},
+		{Name: "Feed", Surname: "Stranger", Email: "feedstranger@teste.com", Password: "testando123@Teste", Birthday: "1990-01-01"},
+	}) {
+		users[user.Surname] = user
+	}
+	reader, critic, stranger := users["Reader"], users["Critic"], users["Stranger"]
+	readerToken := loginForTest(t, reader.Email, "testando123@Teste").Token
+
+	movie, err := MovieModel.InsertMovieInDB(db, models.MovieBody{
+		Title:       "Feed Movie",
+		Director:    "Feed Director",
+		ReleaseDate: "2012-12-12",
+		CreatorId:   adminId,
+		Actors:      []models.CastingBody{{ActorId: actorResponses[0].ID.String()}},
+	})
+	if err != nil {
+		t.Fatalf("Error inserting movie: %v", err)
+	}
+
+	followRoute := fmt.Sprintf("/users/%v/follow", critic.ID)
+
+	// Following
+	statusCode, _ := sendSessionRequest(t, "POST", followRoute, readerToken, nil)
+	assert.Equal(t, 204, statusCode, "status code of follow")
+
+	statusCode, responseBody := sendSessionRequest(t, "POST", followRoute, readerToken, nil)
+	assert.Equal(t, 400, statusCode, "status code of repeated follow")
+	assertProblem(t, apierrors.UserAlreadyFollowed, responseBody, "response of repeated follow")
+
+	statusCode, responseBody = sendSessionRequest(t, "POST", fmt.Sprintf("/users/%v/follow", reader.ID), readerToken, nil)
+	assert.Equal(t, 400, statusCode, "status code of self follow")
+	assertProblem(t, apierrors.SelfFollow, responseBody, "response of self follow")
+
+	statusCode, responseBody = sendSessionRequest(t, "GET", fmt.Sprintf("/users/%v/followers", critic.ID), "", nil)
+	assert.Equal(t, 200, statusCode, "status code of followers")
+
+	var followers models.Page[models.FollowResponse]
+	if err := json.Unmarshal(responseBody, &followers); err != nil {
+		t.Fatalf("Error unmarshalling response body: %v", err)
+	}
+	if assert.Len(t, followers.Data, 1, "Critic should have one follower") {
+		assert.Equal(t, reader.ID, followers.Data[0].ID, "Follower mismatch")
+	}
+
+	statusCode, responseBody = sendSessionRequest(t, "GET", fmt.Sprintf("/users/%v/following", reader.ID), "", nil)
+	assert.Equal(t, 200, statusCode, "status code of following")
+
+	var following models.Page[models.FollowResponse]
+	if err := json.Unmarshal(responseBody, &following); err != nil {
+		t.Fatalf("Error unmarshalling response body: %v", err)
+	}
+	if assert.Len(t, following.Data, 1, "Reader should follow one user") {
+		assert.Equal(t, critic.ID, following.Data[0].ID, "Followed user mismatch")
+	}
+
+	// Activity of the followed user, and of someone else that should stay out of the feed
+	review, err := CommentModel.InsertCommentInDB(db, critic.ID, models.CommentBody{Comment: "Great", Grade: 4, MovieId: movie.ID.String()})
+	if err != nil {
+		t.Fatalf("Error inserting review: %v", err)
+	}
+	if _, err := CommentModel.UpdateCommentsById(db, review.ID, models.CommentEditBody{Comment: "Great, on second thought"}); err != nil {
+		t.Fatalf("Error editing review: %v", err)
+	}
+	if _, err := CommentModel.UpdateCommentsById(db, review.ID, models.CommentEditBody{Grade: 5}); err != nil {
+		t.Fatalf("Error grading review: %v", err)
+	}
+	if _, err := CommentModel.InsertCommentInDB(db, stranger.ID, models.CommentBody{Comment: "Not followed", MovieId: movie.ID.String()}); err != nil {
+		t.Fatalf("Error inserting comment: %v", err)
+	}
+
+	getFeed := func(t *testing.T, route string) models.Page[models.ActivityResponse] {
+		statusCode, responseBody := sendSessionRequest(t, "GET", route, readerToken, nil)
+		if statusCode != 200 {
+			t.Fatalf("Unexpected status code %v getting feed: %s", statusCode, responseBody)
+		}
+
+		var feed models.Page[models.ActivityResponse]
+		if err := json.Unmarshal(responseBody, &feed); err != nil {
+			t.Fatalf("Error unmarshalling response body: %v", err)
+		}
+
+		return feed
+	}
+
+	feed := getFeed(t, "/feed?limit=2")
+	assert.Equal(t, 3, feed.Pagination.Total, "Feed should only have the activity of followed users")
+	if assert.Len(t, feed.Data, 2, "Feed page size mismatch") {
+		assert.Equal(t, models.ActivityGrade, feed.Data[0].Type, "Newest activity should come first")
+		assert.Equal(t, 5.0, feed.Data[0].Grade, "Grade of the activity mismatch")
+		assert.Equal(t, models.ActivityCommentEdit, feed.Data[1].Type, "Activity type mismatch")
+		assert.Equal(t, "Great, on second thought", feed.Data[1].Comment, "Comment of the activity mismatch")
+		assert.Equal(t, movie.Title, feed.Data[1].MovieTitle, "Movie of the activity mismatch")
+	}
+
+	if assert.NotEmpty(t, feed.Pagination.Next, "Feed should have a next page") {
+		nextPage := getFeed(t, "/feed?limit=2&cursor="+url.QueryEscape(feed.Pagination.Next))
+		if assert.Len(t, nextPage.Data, 1, "Next page size mismatch") {
+			assert.Equal(t, models.ActivityReview, nextPage.Data[0].Type, "Oldest activity should be the review")
+			assert.Equal(t, 4.0, nextPage.Data[0].Grade, "Grade of the review activity mismatch")
+		}
+	}
+
+	// The activity table can't be changed
+	_, err = db.Exec("DELETE FROM activity WHERE user_id = $1;", critic.ID)
+	assert.Error(t, err, "Activity should be append only")
+
+	// Unfollowing
+	statusCode, _ = sendSessionRequest(t, "DELETE", followRoute, readerToken, nil)
+	assert.Equal(t, 204, statusCode, "status code of unfollow")
+
+	statusCode, responseBody = sendSessionRequest(t, "DELETE", followRoute, readerToken, nil)
+	assert.Equal(t, 404, statusCode, "status code of unfollowing a user that is not followed")
+	assertProblem(t, apierrors.UserNotFollowed, responseBody, "response of unfollowing a user that is not followed")
+
+	assert.Equal(t, 0, getFeed(t, "/feed").Pagination.Total, "Feed should be empty after unfollowing")
+
+	statusCode, _ = sendSessionRequest(t, "GET", "/feed", "", nil)
+	assert.Equal(t, 401, statusCode, "status code of feed without token")
+}
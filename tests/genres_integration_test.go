@@ -0,0 +1,379 @@
+package tests
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/VinOfSteel/cinemagrader/apierrors"
+	"github.com/VinOfSteel/cinemagrader/initializers"
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GenreRoutes(t *testing.T) {
+	// Changing genres and the genres of movies needs catalog:edit, which admins have
+	adminToken := loginForTest(t, "admin@admin.com", "Testando@Teste**").Token
+
+	testCases := []struct {
+		description      string
+		route            string
+		method           string
+		token            string
+		data             map[string]interface{}
+		expectedCode     int
+		expectedResponse interface{}
+		responseType     string
+		testType         string
+	}{
+		// Post requests
+		{
+			description: "POST - Create a new genre route - Success Case",
+			route:       "/genres",
+			method:      "POST",
+			token:       adminToken,
+			data: map[string]interface{}{
+				"name":        "Drama",
+				"description": "Movies that take themselves seriously",
+				"creatorId":   adminId,
+			},
+			expectedCode: 201,
+			expectedResponse: models.GenreResponse{
+				Name:        "Drama",
+				Description: "Movies that take themselves seriously",
+				CreatorId:   adminId,
+			},
+			testType: "success",
+		},
+		{
+			description: "POST - Create a genre with a name that already exists, ignoring case - Error Case",
+			route:       "/genres",
+			method:      "POST",
+			token:       adminToken,
+			data: map[string]interface{}{
+				"name":      "inserted genre 1",
+				"creatorId": adminId,
+			},
+			expectedCode:     400,
+			expectedResponse: apierrors.GenreNameTaken,
+			testType:         "global-error",
+		},
+		{
+			description: "POST RELATIONSHIP - Tag a movie with genres - Success Case",
+			route:       fmt.Sprintf("/movies/%v/genres", movieResponses[4].ID),
+			method:      "POST",
+			token:       adminToken,
+			data: map[string]interface{}{
+				"genres": []string{genreResponses[0].ID.String(), genreResponses[1].ID.String()},
+			},
+			expectedCode: 204,
+			expectedResponse: []models.GenreResponse{
+				genreResponses[0],
+				genreResponses[1],
+			},
+			testType: "relationship",
+		},
+		{
+			description: "POST RELATIONSHIP - Tag a movie with a genre it already has - Error Case",
+			route:       fmt.Sprintf("/movies/%v/genres", movieResponses[4].ID),
+			method:      "POST",
+			token:       adminToken,
+			data: map[string]interface{}{
+				"genres": []string{genreResponses[0].ID.String()},
+			},
+			expectedCode:     400,
+			expectedResponse: apierrors.GenreAlreadyInMovie,
+			testType:         "global-error",
+		},
+		{
+			description: "POST RELATIONSHIP - Passing a movie uuid that does not exist in DB - Error Case",
+			route:       fmt.Sprintf("/movies/%v/genres", uuid.New()),
+			method:      "POST",
+			token:       adminToken,
+			data: map[string]interface{}{
+				"genres": []string{genreResponses[0].ID.String()},
+			},
+			expectedCode:     404,
+			expectedResponse: apierrors.MovieNotFound,
+			testType:         "global-error",
+		},
+		// Get requests
+		{
+			description:  "GET - All genres filtered by name - Success Case",
+			route:        "/genres?name=inserted&sort=name,asc",
+			method:       "GET",
+			expectedCode: 200,
+			expectedResponse: []models.GenreResponse{
+				genreResponses[0],
+				genreResponses[1],
+				genreResponses[2],
+			},
+			responseType: "slice",
+			testType:     "success",
+		},
+		{
+			description:      "GET - Passing an offset that is not a number - Error Case",
+			route:            "/genres?offset=2.254",
+			method:           "GET",
+			expectedCode:     400,
+			expectedResponse: apierrors.InvalidOffset,
+			testType:         "global-error",
+		},
+		{
+			description:  "GET - Movies filtered by genre - Success Case",
+			route:        fmt.Sprintf("/movies?genre=%v", genreResponses[0].ID),
+			method:       "GET",
+			expectedCode: 200,
+			expectedResponse: []models.MovieResponseWithActors{
+				movieResponses[4],
+			},
+			testType: "movies-by-genre",
+		},
+		{
+			description:      "GET - Passing a genre filter that is not an uuid - Error Case",
+			route:            "/movies?genre=testestetsts",
+			method:           "GET",
+			expectedCode:     400,
+			expectedResponse: apierrors.InvalidQueryParam("genre", apierrors.ValidUUID),
+			testType:         "global-error",
+		},
+		{
+			description:      "GET BY ID - Passing an uuid that exists in DB - Success Case",
+			route:            fmt.Sprintf("/genres/%v", genreResponses[0].ID),
+			method:           "GET",
+			expectedCode:     200,
+			expectedResponse: genreResponses[0],
+			testType:         "success",
+		},
+		{
+			description:      "GET BY ID - Passing an uuid that does not exist in DB - Error Case",
+			route:            fmt.Sprintf("/genres/%v", uuid.New()),
+			method:           "GET",
+			expectedCode:     404,
+			expectedResponse: apierrors.GenreNotFound,
+			testType:         "global-error",
+		},
+		{
+			description:      "GET BY ID WITH MOVIES - Passing an invalid uuid - Error Case",
+			route:            "/genres/testestetsts/movies",
+			method:           "GET",
+			expectedCode:     400,
+			expectedResponse: apierrors.InvalidUUID,
+			testType:         "global-error",
+		},
+		// Delete requests
+		{
+			description: "DELETE RELATIONSHIP - Untag a genre that is not on the movie - Error Case",
+			route:       fmt.Sprintf("/movies/%v/genres", movieResponses[4].ID),
+			method:      "DELETE",
+			token:       adminToken,
+			data: map[string]interface{}{
+				"genres": []string{genreResponses[2].ID.String()},
+			},
+			expectedCode:     400,
+			expectedResponse: apierrors.GenreNotInMovie,
+			testType:         "global-error",
+		},
+		{
+			description: "DELETE RELATIONSHIP - Untag a genre from a movie - Success Case",
+			route:       fmt.Sprintf("/movies/%v/genres", movieResponses[4].ID),
+			method:      "DELETE",
+			token:       adminToken,
+			data: map[string]interface{}{
+				"genres": []string{genreResponses[1].ID.String()},
+			},
+			expectedCode: 204,
+			expectedResponse: []models.GenreResponse{
+				genreResponses[0],
+			},
+			testType: "relationship",
+		},
+		{
+			description:      "DELETE BY ID - Passing an uuid that exists in DB - Success Case",
+			route:            fmt.Sprintf("/genres/%v", genreResponses[2].ID),
+			method:           "DELETE",
+			token:            adminToken,
+			expectedCode:     204,
+			expectedResponse: genreResponses[2],
+			testType:         "delete",
+		},
+		{
+			description:      "DELETE BY ID - Passing an invalid uuid - Error Case",
+			route:            "/genres/testeasdasd",
+			method:           "DELETE",
+			token:            adminToken,
+			expectedCode:     400,
+			expectedResponse: apierrors.InvalidUUID,
+			testType:         "global-error",
+		},
+		// Update requests
+		{
+			description: "UPDATE - Update genre info (all keys) - Success Case",
+			route:       fmt.Sprintf("/genres/%v", genreResponses[0].ID),
+			method:      "PATCH",
+			token:       adminToken,
+			data: map[string]interface{}{
+				"name":        "Updated Genre",
+				"description": "Updated description",
+			},
+			expectedCode: 200,
+			expectedResponse: models.GenreResponse{
+				Name:        "Updated Genre",
+				Description: "Updated description",
+				CreatorId:   adminId,
+			},
+			testType: "success",
+		},
+		{
+			description: "UPDATE - Update genre with the name of another genre - Error Case",
+			route:       fmt.Sprintf("/genres/%v", genreResponses[0].ID),
+			method:      "PATCH",
+			token:       adminToken,
+			data: map[string]interface{}{
+				"name": "Drama",
+			},
+			expectedCode:     400,
+			expectedResponse: apierrors.GenreNameTaken,
+			testType:         "global-error",
+		},
+	}
+
+	for _, testCase := range testCases {
+		db := initializers.NewDatabaseConn()
+		defer db.Close()
+
+		statusCode, responseBody := sendSessionRequest(t, testCase.method, testCase.route, testCase.token, testCase.data)
+
+		// Verifying status code
+		assert.Equal(t, testCase.expectedCode, statusCode, testCase.description)
+
+		compareGenreResponses := func(t *testing.T, expected, actual models.GenreResponse) {
+			assert.Equal(t, expected.Name, actual.Name, "Name mismatch")
+			assert.Equal(t, expected.Description, actual.Description, "Description mismatch")
+			assert.Equal(t, expected.CreatorId, actual.CreatorId, "CreatorId mismatch")
+			assert.Equal(t, sql.NullTime{}, actual.DeletedAt, "DeletedAt should be nil")
+
+			assert.NotEqual(t, uuid.Nil, actual.ID, "ID should not be nil")
+			assert.NotEqual(t, time.Time{}, actual.CreatedAt, "CreatedAt should not be nil")
+			assert.NotEqual(t, time.Time{}, actual.UpdatedAt, "UpdatedAt should not be nil")
+		}
+
+		if testCase.testType == "success" {
+			if testCase.responseType == "slice" {
+				// List routes answer with the pagination envelope
+				var respPage models.Page[models.GenreResponse]
+				if err := json.Unmarshal(responseBody, &respPage); err != nil {
+					t.Fatalf("Error unmarshalling response body: %v", err)
+				}
+
+				expected := testCase.expectedResponse.([]models.GenreResponse)
+				assert.Equal(t, len(expected), len(respPage.Data), testCase.description)
+				assert.Equal(t, len(expected), respPage.Pagination.Total, "Total mismatch")
+				for i, actual := range respPage.Data {
+					compareGenreResponses(t, expected[i], actual)
+					assert.Equal(t, expected[i].ID, actual.ID, "ID mismatch")
+				}
+			} else {
+				var respStruct models.GenreResponse
+				if err := json.Unmarshal(responseBody, &respStruct); err != nil {
+					t.Fatalf("Error unmarshalling response body: %v", err)
+				}
+
+				compareGenreResponses(t, testCase.expectedResponse.(models.GenreResponse), respStruct)
+			}
+		}
+
+		if testCase.testType == "movies-by-genre" {
+			var respPage models.Page[models.MovieResponse]
+			if err := json.Unmarshal(responseBody, &respPage); err != nil {
+				t.Fatalf("Error unmarshalling response body: %v", err)
+			}
+
+			expected := testCase.expectedResponse.([]models.MovieResponseWithActors)
+			assert.Equal(t, len(expected), len(respPage.Data), testCase.description)
+			for i, movie := range respPage.Data {
+				assert.Equal(t, expected[i].ID, movie.ID, "Movie ID mismatch")
+				assert.Equal(t, expected[i].Title, movie.Title, "Movie Title mismatch")
+			}
+		}
+
+		if testCase.testType == "relationship" {
+			// The movie answers with its genres sorted by name
+			statusCode, responseBody := sendSessionRequest(t, "GET", fmt.Sprintf("/movies/%v", movieResponses[4].ID), "", nil)
+			assert.Equal(t, 200, statusCode, "status code getting movie with genres")
+
+			var movie models.MovieResponseWithActors
+			if err := json.Unmarshal(responseBody, &movie); err != nil {
+				t.Fatalf("Error unmarshalling response body: %v", err)
+			}
+
+			expected := testCase.expectedResponse.([]models.GenreResponse)
+			assert.Equal(t, len(expected), len(movie.Genres), testCase.description)
+			for i, genre := range movie.Genres {
+				assert.Equal(t, expected[i].ID, genre.ID, "Genre ID mismatch")
+				assert.Equal(t, expected[i].Name, genre.Name, "Genre Name mismatch")
+			}
+		}
+
+		if testCase.testType == "global-error" {
+			assertProblem(t, testCase.expectedResponse.(*apierrors.Error), responseBody, testCase.description)
+		}
+
+		if testCase.testType == "delete" {
+			genreResp, err := GenreModel.GetGenreById(db, testCase.expectedResponse.(models.GenreResponse).ID)
+			if err != nil {
+				assert.Fail(t, "Error when getting genre by id", err)
+			}
+
+			assert.Equal(t, true, genreResp.DeletedAt.Valid, "deletedAt date is not valid after executing delete request on genre")
+			assert.NotEqual(t, time.Time{}, genreResp.DeletedAt.Time, "deletedAt time should be the time of deletion, not a 0 value")
+		}
+	}
+}
+
+func Test_GenreMoviesAverageGrade(t *testing.T) {
+	db := initializers.NewDatabaseConn()
+	defer db.Close()
+
+	// Two movies in the same genre, one with two comments and the other with one
+	for _, movie := range []models.MovieResponseWithActors{movieResponses[2], movieResponses[3]} {
+		if err := MovieModel.InsertGenresRelationshipsWithMovie(db, movie.ID, models.MovieGenresBody{Genres: []string{genreResponses[1].ID.String()}}); err != nil {
+			t.Fatalf("Error tagging movie %v with genre: %v", movie.Title, err)
+		}
+	}
+
+	comments := []models.CommentBody{
+		{Comment: "Genre comment 1", Grade: 5, MovieId: movieResponses[2].ID.String()},
+		{Comment: "Genre comment 2", Grade: 4, MovieId: movieResponses[2].ID.String()},
+		{Comment: "Genre comment 3", Grade: 1, MovieId: movieResponses[3].ID.String()},
+	}
+	// A user can only review a movie once, so every comment comes from a different user
+	for i, comment := range comments {
+		if _, err := CommentModel.InsertCommentInDB(db, userResponses[i].ID, comment); err != nil {
+			t.Fatalf("Error inserting comment on genre movie: %v", err)
+		}
+	}
+
+	statusCode, responseBody := sendSessionRequest(t, "GET", fmt.Sprintf("/genres/%v/movies", genreResponses[1].ID), "", nil)
+	assert.Equal(t, 200, statusCode, "status code")
+
+	var genre models.GenreResponseWithMovies
+	if err := json.Unmarshal(responseBody, &genre); err != nil {
+		t.Fatalf("Error unmarshalling response body: %v", err)
+	}
+
+	// Every comment weighs the same, so this is 10/3 instead of the 2.75 of averaging the movies
+	assert.Equal(t, 3.3, genre.AverageGrade, "Genre average grade mismatch")
+	assert.Equal(t, 2, genre.MovieCount, "Genre movie count mismatch")
+	if assert.Len(t, genre.Movies, 2, "Genre movies length mismatch") {
+		assert.Equal(t, movieResponses[2].ID, genre.Movies[0].ID, "First movie of the genre mismatch")
+		assert.Equal(t, movieResponses[3].ID, genre.Movies[1].ID, "Second movie of the genre mismatch")
+	}
+
+	statusCode, responseBody = sendSessionRequest(t, "GET", fmt.Sprintf("/genres/%v/movies", uuid.New()), "", nil)
+	assert.Equal(t, 404, statusCode, "status code of genre that does not exist")
+	assertProblem(t, apierrors.GenreNotFound, responseBody, "response of genre that does not exist")
+}
@@ -0,0 +1,181 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/VinOfSteel/cinemagrader/apierrors"
+	"github.com/VinOfSteel/cinemagrader/initializers"
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ListsRoutes(t *testing.T) {
+	db := initializers.NewDatabaseConn()
+	defer db.Close()
+
+	users := InsertMockedUsersInDB(db, []models.UserBody{
+		{Name: "List", Surname: "Owner", Email: "listowner@teste.com", Password: "testando123@Teste", Birthday: "1990-01-01"},
+		{Name: "List", Surname: "Forker", Email: "listforker@teste.com", Password: "testando123@Teste", Birthday: "1990-01-01"},
+	})
+	owner, forker := users[0], users[1]
+	if owner.Email != "listowner@teste.com" {
+		owner, forker = forker, owner
+	}
+	ownerToken := loginForTest(t, owner.Email, "testando123@Teste").Token
+	forkerToken := loginForTest(t, forker.Email, "testando123@Teste").Token
+
+	var movieIds []string
+	for _, title := range []string{"First Listed Movie", "Second Listed Movie", "Third Listed Movie"} {
+		movie, err := MovieModel.InsertMovieInDB(db, models.MovieBody{
+			Title:       title,
+			Director:    "List Director",
+			ReleaseDate: "2001-01-01",
+			CreatorId:   adminId,
+			Actors:      []models.CastingBody{{ActorId: actorResponses[0].ID.String()}},
+		})
+		if err != nil {
+			t.Fatalf("Error inserting movie %v: %v", title, err)
+		}
+		movieIds = append(movieIds, movie.ID.String())
+	}
+
+	decodeList := func(t *testing.T, responseBody []byte) models.ListResponseWithItems {
+		var list models.ListResponseWithItems
+		if err := json.Unmarshal(responseBody, &list); err != nil {
+			t.Fatalf("Error unmarshalling response body: %v", err)
+		}
+
+		return list
+	}
+
+	itemOrder := func(list models.ListResponseWithItems) []string {
+		order := []string{}
+		for i, item := range list.Items {
+			assert.Equal(t, i+1, item.Position, "Positions should start at 1 and have no gaps")
+			order = append(order, item.Movie.ID.String())
+		}
+
+		return order
+	}
+
+	// Creating a private list
+	statusCode, responseBody := sendSessionRequest(t, "POST", fmt.Sprintf("/users/%v/lists", owner.ID), ownerToken, map[string]interface{}{
+		"name":        "Favorites",
+		"description": "The best ones",
+	})
+	assert.Equal(t, 201, statusCode, "status code of list creation")
+
+	list := decodeList(t, responseBody)
+	assert.Equal(t, "Favorites", list.Name, "Name mismatch")
+	assert.False(t, list.IsPublic, "Lists should be private by default")
+	assert.Equal(t, owner.ID.String(), list.OwnerId, "Owner mismatch")
+
+	listRoute := fmt.Sprintf("/lists/%v", list.ID)
+
+	// Adding movies, the last one at the top of the list
+	for _, body := range []map[string]interface{}{
+		{"movieId": movieIds[1], "note": "Second"},
+		{"movieId": movieIds[2]},
+		{"movieId": movieIds[0], "position": 1},
+	} {
+		statusCode, responseBody = sendSessionRequest(t, "POST", listRoute+"/movies", ownerToken, body)
+		assert.Equal(t, 201, statusCode, "status code of movie added to list: %s", responseBody)
+	}
+	assert.Equal(t, movieIds, itemOrder(decodeList(t, responseBody)), "Movie inserted at a position should move the others down")
+
+	statusCode, responseBody = sendSessionRequest(t, "POST", listRoute+"/movies", ownerToken, map[string]interface{}{"movieId": movieIds[0]})
+	assert.Equal(t, 400, statusCode, "status code of repeated movie in list")
+	assertProblem(t, apierrors.MovieAlreadyInList, responseBody, "response of repeated movie in list")
+
+	// Private lists are only visible to their owner
+	statusCode, _ = sendSessionRequest(t, "GET", listRoute, "", nil)
+	assert.Equal(t, 401, statusCode, "status code of private list without token")
+
+	statusCode, responseBody = sendSessionRequest(t, "GET", listRoute, forkerToken, nil)
+	assert.Equal(t, 401, statusCode, "status code of private list of another user")
+	assertProblem(t, apierrors.NotOwner("list"), responseBody, "response of private list of another user")
+
+	statusCode, responseBody = sendSessionRequest(t, "GET", listRoute, ownerToken, nil)
+	assert.Equal(t, 200, statusCode, "status code of private list of the owner")
+	assert.Equal(t, 3, decodeList(t, responseBody).MovieCount, "Movie count mismatch")
+
+	statusCode, responseBody = sendSessionRequest(t, "POST", listRoute+"/fork", forkerToken, nil)
+	assert.Equal(t, 404, statusCode, "status code of fork of a private list")
+	assertProblem(t, apierrors.ListNotFound, responseBody, "response of fork of a private list")
+
+	// Reordering
+	statusCode, responseBody = sendSessionRequest(t, "PUT", listRoute+"/order", ownerToken, map[string]interface{}{"movieIds": []string{movieIds[2], movieIds[0]}})
+	assert.Equal(t, 400, statusCode, "status code of order missing a movie")
+	assertProblem(t, apierrors.InvalidListOrder, responseBody, "response of order missing a movie")
+
+	statusCode, _ = sendSessionRequest(t, "PUT", listRoute+"/order", ownerToken, map[string]interface{}{"movieIds": []string{movieIds[2], movieIds[0], movieIds[0]}})
+	assert.Equal(t, 400, statusCode, "status code of order with a repeated movie")
+
+	newOrder := []string{movieIds[2], movieIds[0], movieIds[1]}
+	statusCode, responseBody = sendSessionRequest(t, "PUT", listRoute+"/order", ownerToken, map[string]interface{}{"movieIds": newOrder})
+	assert.Equal(t, 200, statusCode, "status code of list reorder")
+	assert.Equal(t, newOrder, itemOrder(decodeList(t, responseBody)), "List should follow the new order")
+
+	// Removing a movie closes the gap
+	statusCode, _ = sendSessionRequest(t, "DELETE", fmt.Sprintf("%v/movies/%v", listRoute, movieIds[0]), ownerToken, nil)
+	assert.Equal(t, 204, statusCode, "status code of movie removed from list")
+
+	statusCode, responseBody = sendSessionRequest(t, "DELETE", fmt.Sprintf("%v/movies/%v", listRoute, movieIds[0]), ownerToken, nil)
+	assert.Equal(t, 404, statusCode, "status code of movie not in the list")
+	assertProblem(t, apierrors.MovieNotInList, responseBody, "response of movie not in the list")
+
+	// Public lists can be seen by anyone and forked
+	statusCode, _ = sendSessionRequest(t, "PATCH", listRoute, ownerToken, map[string]interface{}{"isPublic": true})
+	assert.Equal(t, 200, statusCode, "status code of list update")
+
+	statusCode, responseBody = sendSessionRequest(t, "GET", listRoute, "", nil)
+	assert.Equal(t, 200, statusCode, "status code of public list without token")
+	assert.Equal(t, []string{movieIds[2], movieIds[1]}, itemOrder(decodeList(t, responseBody)), "Removed movie should leave the list")
+
+	statusCode, responseBody = sendSessionRequest(t, "GET", fmt.Sprintf("/lists?owner=%v", owner.ID), "", nil)
+	assert.Equal(t, 200, statusCode, "status code of public lists")
+
+	var publicLists models.Page[models.ListResponse]
+	if err := json.Unmarshal(responseBody, &publicLists); err != nil {
+		t.Fatalf("Error unmarshalling response body: %v", err)
+	}
+	if assert.Len(t, publicLists.Data, 1, "Public list of the owner should be listed") {
+		assert.Equal(t, list.ID, publicLists.Data[0].ID, "Public list mismatch")
+	}
+
+	statusCode, responseBody = sendSessionRequest(t, "POST", listRoute+"/fork", forkerToken, nil)
+	assert.Equal(t, 201, statusCode, "status code of list fork")
+
+	fork := decodeList(t, responseBody)
+	assert.Equal(t, forker.ID.String(), fork.OwnerId, "Fork should belong to the user who forked it")
+	assert.Equal(t, uuid.NullUUID{UUID: list.ID, Valid: true}, fork.ForkedFromId, "Fork should reference the original list")
+	assert.False(t, fork.IsPublic, "Forks should start private")
+	assert.Equal(t, []string{movieIds[2], movieIds[1]}, itemOrder(fork), "Fork should have the movies of the original list")
+	assert.Equal(t, "Second", fork.Items[1].Note, "Fork should keep the notes of the original list")
+
+	statusCode, responseBody = sendSessionRequest(t, "GET", fmt.Sprintf("/users/%v/lists", forker.ID), forkerToken, nil)
+	assert.Equal(t, 200, statusCode, "status code of lists of user")
+
+	var forkerLists models.UserResponseWithLists
+	if err := json.Unmarshal(responseBody, &forkerLists); err != nil {
+		t.Fatalf("Error unmarshalling response body: %v", err)
+	}
+	if assert.Len(t, forkerLists.Lists, 1, "Private fork should be in the lists of the user") {
+		assert.Equal(t, fork.ID, forkerLists.Lists[0].ID, "Fork mismatch")
+	}
+
+	// Deleting
+	statusCode, _ = sendSessionRequest(t, "DELETE", listRoute, ownerToken, nil)
+	assert.Equal(t, 204, statusCode, "status code of list deletion")
+
+	statusCode, responseBody = sendSessionRequest(t, "GET", listRoute, "", nil)
+	assert.Equal(t, 404, statusCode, "status code of deleted list")
+	assertProblem(t, apierrors.ListNotFound, responseBody, "response of deleted list")
+
+	statusCode, responseBody = sendSessionRequest(t, "GET", "/lists/testestetsts", "", nil)
+	assert.Equal(t, 400, statusCode, "status code of invalid uuid")
+	assertProblem(t, apierrors.InvalidUUID, responseBody, "response of invalid uuid")
+}
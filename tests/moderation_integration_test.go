@@ -0,0 +1,208 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/VinOfSteel/cinemagrader/apierrors"
+	"github.com/VinOfSteel/cinemagrader/initializers"
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func getModerationQueueItem(t *testing.T, token string, commentId uuid.UUID) (models.ModerationQueueItem, bool) {
+	statusCode, responseBody := sendSessionRequest(t, "GET", "/moderation/queue?limit=100", token, nil)
+	if statusCode != 200 {
+		t.Fatalf("Unexpected status code %v getting moderation queue: %s", statusCode, responseBody)
+	}
+
+	var queue models.Page[models.ModerationQueueItem]
+	if err := json.Unmarshal(responseBody, &queue); err != nil {
+		t.Fatalf("Error unmarshalling response body: %v", err)
+	}
+
+	for _, item := range queue.Data {
+		if item.Comment.ID == commentId {
+			return item, true
+		}
+	}
+
+	return models.ModerationQueueItem{}, false
+}
+
+func Test_CommentModeration(t *testing.T) {
+	db := initializers.NewDatabaseConn()
+	defer db.Close()
+
+	users := map[string]models.UserResponse{}
+	for _, user := range InsertMockedUsersInDB(db, []models.UserBody{
+		{Name: "Moderation", Surname: "Author", Email: "moderationauthor@teste.com", Password: "testando123@Teste", Birthday: "1990-01-01"},
+		{Name: "Moderation", Surname: "Reporter", Email: "moderationreporter@teste.com", Password: "testando123@Teste", Birthday: "1990-01-01"},
+	}) {
+		users[user.Surname] = user
+	}
+	author, reporter := users["Author"], users["Reporter"]
+	authorToken := loginForTest(t, author.Email, "testando123@Teste").Token
+	reporterToken := loginForTest(t, reporter.Email, "testando123@Teste").Token
+	adminToken := loginForTest(t, "admin@admin.com", "Testando@Teste**").Token
+
+	movie, err := MovieModel.InsertMovieInDB(db, models.MovieBody{
+		Title:       "Moderation Movie",
+		Director:    "Moderation Director",
+		ReleaseDate: "2012-12-12",
+		CreatorId:   adminId,
+		Actors:      []models.CastingBody{{ActorId: actorResponses[0].ID.String()}},
+	})
+	if err != nil {
+		t.Fatalf("Error inserting movie: %v", err)
+	}
+
+	createComment := func(body map[string]interface{}) models.CommentResponse {
+		body["movieId"] = movie.ID.String()
+		statusCode, responseBody := sendSessionRequest(t, "POST", fmt.Sprintf("/comments/%v", author.ID), authorToken, body)
+		if statusCode != 201 {
+			t.Fatalf("Unexpected status code %v creating comment: %s", statusCode, responseBody)
+		}
+
+		var comment models.CommentResponse
+		if err := json.Unmarshal(responseBody, &comment); err != nil {
+			t.Fatalf("Error unmarshalling response body: %v", err)
+		}
+
+		return comment
+	}
+
+	review := createComment(map[string]interface{}{"comment": "A fine movie", "grade": 4})
+	assert.Equal(t, models.CommentStatusVisible, review.Status, "Clean comment should be visible")
+
+	// Comments that hit the blocklist wait in the queue and aren't public
+	held := createComment(map[string]interface{}{"comment": "Big SP0ILER: everyone dies"})
+	assert.Equal(t, models.CommentStatusPending, held.Status, "Comment hitting the blocklist should be pending")
+
+	statusCode, _ := sendSessionRequest(t, "GET", fmt.Sprintf("/comments/%v", held.ID), "", nil)
+	assert.Equal(t, 404, statusCode, "status code of pending comment")
+
+	for _, comment := range getMovieComments(t, fmt.Sprintf("/movies/%v/comments", movie.ID), "") {
+		assert.NotEqual(t, held.ID, comment.ID, "Pending comment should not be in the movie comments")
+	}
+
+	item, found := getModerationQueueItem(t, adminToken, held.ID)
+	if assert.True(t, found, "Pending comment should be in the queue") {
+		assert.Equal(t, 0, item.ReportCount, "Held comment should have no reports")
+	}
+
+	// Reports
+	reportRoute := fmt.Sprintf("/comments/%v/report", review.ID)
+	statusCode, responseBody := sendSessionRequest(t, "POST", reportRoute, reporterToken, map[string]interface{}{"reason": "Offensive"})
+	assert.Equal(t, 201, statusCode, "status code of report")
+
+	var report models.ReportResponse
+	if err := json.Unmarshal(responseBody, &report); err != nil {
+		t.Fatalf("Error unmarshalling response body: %v", err)
+	}
+	assert.Equal(t, reporter.ID.String(), report.UserId, "Reporter mismatch")
+	assert.False(t, report.ResolvedAt.Valid, "New report should be open")
+
+	statusCode, responseBody = sendSessionRequest(t, "POST", reportRoute, reporterToken, map[string]interface{}{"reason": "Still offensive"})
+	assert.Equal(t, 400, statusCode, "status code of repeated report")
+	assertProblem(t, apierrors.CommentAlreadyReported, responseBody, "response of repeated report")
+
+	statusCode, responseBody = sendSessionRequest(t, "POST", reportRoute, authorToken, map[string]interface{}{"reason": "Mine"})
+	assert.Equal(t, 400, statusCode, "status code of report on own comment")
+	assertProblem(t, apierrors.SelfReport, responseBody, "response of report on own comment")
+
+	statusCode, _ = sendSessionRequest(t, "POST", fmt.Sprintf("/comments/%v/report", held.ID), reporterToken, map[string]interface{}{})
+	assert.Equal(t, 400, statusCode, "status code of report without reason")
+
+	item, found = getModerationQueueItem(t, adminToken, review.ID)
+	if assert.True(t, found, "Reported comment should be in the queue") {
+		assert.Equal(t, 1, item.ReportCount, "Report count mismatch")
+		assert.Equal(t, []string{"Offensive"}, item.Reasons, "Report reasons mismatch")
+	}
+
+	// Approving makes the held comment public
+	statusCode, responseBody = sendSessionRequest(t, "POST", fmt.Sprintf("/moderation/comments/%v", held.ID), adminToken, map[string]interface{}{"action": models.ModerationApprove})
+	assert.Equal(t, 200, statusCode, "status code of approval: %s", responseBody)
+
+	statusCode, _ = sendSessionRequest(t, "GET", fmt.Sprintf("/comments/%v", held.ID), "", nil)
+	assert.Equal(t, 200, statusCode, "status code of approved comment")
+
+	_, found = getModerationQueueItem(t, adminToken, held.ID)
+	assert.False(t, found, "Approved comment should leave the queue")
+
+	// Hiding removes the review from the public reads and from the rating of the movie
+	movieResponse, err := MovieModel.GetMovieByIdWithActors(db, movie.ID)
+	if err != nil {
+		t.Fatalf("Error getting movie by id: %v", err)
+	}
+	assert.Equal(t, 4.0, movieResponse.AverageGrade, "Average grade before hiding mismatch")
+
+	statusCode, responseBody = sendSessionRequest(t, "POST", fmt.Sprintf("/moderation/comments/%v", review.ID), adminToken, map[string]interface{}{"action": models.ModerationHide})
+	assert.Equal(t, 200, statusCode, "status code of hiding: %s", responseBody)
+
+	movieResponse, err = MovieModel.GetMovieByIdWithActors(db, movie.ID)
+	if err != nil {
+		t.Fatalf("Error getting movie by id: %v", err)
+	}
+	assert.Equal(t, 0.0, movieResponse.AverageGrade, "Hidden review should not count in the average grade")
+	assert.Equal(t, 0, movieResponse.RatingCount, "Hidden review should not count in the rating count")
+
+	statusCode, _ = sendSessionRequest(t, "GET", fmt.Sprintf("/comments/%v", review.ID), "", nil)
+	assert.Equal(t, 404, statusCode, "status code of hidden comment")
+
+	_, found = getModerationQueueItem(t, adminToken, review.ID)
+	assert.False(t, found, "Hidden comment should leave the queue")
+
+	// Banning hides the comment and stops its author from commenting
+	statusCode, _ = sendSessionRequest(t, "POST", fmt.Sprintf("/comments/%v/report", held.ID), reporterToken, map[string]interface{}{"reason": "Spoilers"})
+	assert.Equal(t, 201, statusCode, "status code of report")
+
+	statusCode, responseBody = sendSessionRequest(t, "POST", fmt.Sprintf("/moderation/comments/%v", held.ID), adminToken, map[string]interface{}{"action": models.ModerationBan})
+	assert.Equal(t, 200, statusCode, "status code of ban: %s", responseBody)
+
+	var banned models.CommentResponse
+	if err := json.Unmarshal(responseBody, &banned); err != nil {
+		t.Fatalf("Error unmarshalling response body: %v", err)
+	}
+	assert.Equal(t, models.CommentStatusHidden, banned.Status, "Banning should hide the comment")
+
+	user, err := UserModel.GetUserById(db, author.ID)
+	if err != nil {
+		t.Fatalf("Error getting user by id: %v", err)
+	}
+	assert.True(t, user.BannedAt.Valid, "Author should be banned")
+
+	statusCode, responseBody = sendSessionRequest(t, "POST", fmt.Sprintf("/comments/%v", author.ID), authorToken, map[string]interface{}{"comment": "Let me back", "movieId": movie.ID.String()})
+	assert.Equal(t, 403, statusCode, "status code of comment by banned user")
+	assertProblem(t, apierrors.UserBanned, responseBody, "response of comment by banned user")
+
+	statusCode, responseBody = sendSessionRequest(t, "POST", fmt.Sprintf("/comments/%v", author.ID), adminToken, map[string]interface{}{"comment": "Let me back", "movieId": movie.ID.String()})
+	assert.Equal(t, 403, statusCode, "status code of comment on behalf of a banned user")
+	assertProblem(t, apierrors.BannedFromCommenting, responseBody, "response of comment on behalf of a banned user")
+
+	// Error cases
+	ownComment, err := CommentModel.InsertCommentInDB(db, uuid.MustParse(adminId), models.CommentBody{Comment: "My own words", MovieId: movie.ID.String()})
+	if err != nil {
+		t.Fatalf("Error inserting comment: %v", err)
+	}
+
+	statusCode, responseBody = sendSessionRequest(t, "POST", fmt.Sprintf("/moderation/comments/%v", ownComment.ID), adminToken, map[string]interface{}{"action": models.ModerationBan})
+	assert.Equal(t, 400, statusCode, "status code of ban of the own comment")
+	assertProblem(t, apierrors.SelfBan, responseBody, "response of ban of the own comment")
+
+	statusCode, _ = sendSessionRequest(t, "POST", fmt.Sprintf("/moderation/comments/%v", review.ID), adminToken, map[string]interface{}{"action": "delete"})
+	assert.Equal(t, 400, statusCode, "status code of invalid action")
+
+	statusCode, responseBody = sendSessionRequest(t, "POST", fmt.Sprintf("/moderation/comments/%v", uuid.New()), adminToken, map[string]interface{}{"action": models.ModerationHide})
+	assert.Equal(t, 404, statusCode, "status code of moderating a comment that does not exist")
+	assertProblem(t, apierrors.CommentNotFound, responseBody, "response of moderating a comment that does not exist")
+
+	statusCode, _ = sendSessionRequest(t, "POST", fmt.Sprintf("/moderation/comments/%v", review.ID), reporterToken, map[string]interface{}{"action": models.ModerationApprove})
+	assert.Equal(t, 401, statusCode, "status code of moderation by a user that is not an admin")
+
+	statusCode, responseBody = sendSessionRequest(t, "GET", "/comments?status=archived", adminToken, nil)
+	assert.Equal(t, 400, statusCode, "status code of invalid status filter")
+	assertProblem(t, apierrors.InvalidQueryParam("status", apierrors.OneOf, "visible, pending, hidden"), responseBody, "response of invalid status filter")
+}
@@ -11,6 +11,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/VinOfSteel/cinemagrader/apierrors"
+	"github.com/VinOfSteel/cinemagrader/controllers"
 	"github.com/VinOfSteel/cinemagrader/initializers"
 	"github.com/VinOfSteel/cinemagrader/models"
 	"github.com/google/uuid"
@@ -18,10 +20,14 @@ import (
 )
 
 func Test_MoviesRoutes(t *testing.T) {
+	// Changing movies and their casting needs catalog:edit, which admins have
+	adminToken := loginForTest(t, "admin@admin.com", "Testando@Teste**").Token
+
 	testCases := []struct {
 		description      string
 		route            string
 		method           string
+		token            string
 		data             map[string]interface{}
 		expectedCode     int
 		expectedResponse interface{}
@@ -33,12 +39,16 @@ func Test_MoviesRoutes(t *testing.T) {
 			description: "POST - Create a new movie route - Success Case", // Picture key not tested on purpose to make sure it is registered properly as an empty string
 			route:       "/movies",
 			method:      "POST",
+			token:       adminToken,
 			data: map[string]interface{}{
 				"title":       "Movie 1",
 				"director":    "Director 1",
 				"releaseDate": "1990-01-01",
 				"creatorId":   adminId,
-				"actors":      []string{actorResponses[0].ID.String(), actorResponses[1].ID.String()},
+				"actors": []map[string]interface{}{
+					{"actorId": actorResponses[0].ID.String(), "characterName": "Protagonist"},
+					{"actorId": actorResponses[1].ID.String()},
+				},
 			},
 			expectedCode: 201,
 			expectedResponse: models.MovieResponseWithActors{
@@ -46,7 +56,10 @@ func Test_MoviesRoutes(t *testing.T) {
 				Director:    "Director 1",
 				ReleaseDate: "1990-01-01T00:00:00Z",
 				CreatorId:   adminId,
-				Actors:      []models.ActorResponse{actorResponses[0], actorResponses[1]},
+				Actors: []models.CastingResponse{
+					{ActorResponse: actorResponses[0], CharacterName: "Protagonist", BillingOrder: 1},
+					{ActorResponse: actorResponses[1], BillingOrder: 2},
+				},
 			},
 			testType: "success",
 		},
@@ -54,25 +67,25 @@ func Test_MoviesRoutes(t *testing.T) {
 			description: "POST - Movie already exists in DB - Error Case",
 			route:       "/movies",
 			method:      "POST",
+			token:       adminToken,
 			data: map[string]interface{}{
 				"title":       "Movie 1",
 				"director":    "Director 1",
 				"releaseDate": "1990-01-01",
 				"creatorId":   adminId,
-				"actors":      []string{actorResponses[0].ID.String(), actorResponses[1].ID.String()},
+				"actors":      []map[string]interface{}{{"actorId": actorResponses[0].ID.String()}, {"actorId": actorResponses[1].ID.String()}},
 			},
-			expectedCode: 400,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "Movie with this title already exists",
-			},
-			testType: "global-error",
+			expectedCode:     400,
+			expectedResponse: apierrors.MovieTitleTaken,
+			testType:         "global-error",
 		},
 		{
 			description: "POST WITH ID - Add new actors to a movie - Success Case",
 			route:       fmt.Sprintf("/movies/%v/actors", movieResponses[4].ID),
 			method:      "POST",
+			token:       adminToken,
 			data: map[string]interface{}{
-				"actors": []string{actorResponses[0].ID.String()},
+				"actors": []map[string]interface{}{{"actorId": actorResponses[0].ID.String()}},
 			},
 			expectedCode:     204,
 			expectedResponse: movieResponses[4],
@@ -82,34 +95,33 @@ func Test_MoviesRoutes(t *testing.T) {
 			description: "POST WITH ID - Add repeat actors to a movie - Error Case",
 			route:       fmt.Sprintf("/movies/%v/actors", movieResponses[3].ID),
 			method:      "POST",
+			token:       adminToken,
 			data: map[string]interface{}{
-				"actors": []string{actorResponses[3].ID.String()},
+				"actors": []map[string]interface{}{{"actorId": actorResponses[3].ID.String()}},
 			},
 			expectedCode:     400,
 			expectedResponse: movieResponses[3],
 			testType:         "success-movies-actors", // This still passes because the test only checks if the relationship exists and, well, it already does.
 		},
 		{
-			description:  "POST WITH ID - Passing an uuid that does not exist in DB - Error Case",
-			route:        fmt.Sprintf("/movies/%v/actors", uuid.New()),
-			method:       "POST",
-			expectedCode: 404,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "Movie id not found in database",
-			},
-			responseType: "struct",
-			testType:     "global-error",
+			description:      "POST WITH ID - Passing an uuid that does not exist in DB - Error Case",
+			route:            fmt.Sprintf("/movies/%v/actors", uuid.New()),
+			method:           "POST",
+			token:            adminToken,
+			expectedCode:     404,
+			expectedResponse: apierrors.MovieNotFound,
+			responseType:     "struct",
+			testType:         "global-error",
 		},
 		{
-			description:  "POST WITH ID - Passing an invalid uuid - Error Case",
-			route:        "/movies/testestetsts/actors",
-			method:       "POST",
-			expectedCode: 400,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "Invalid uuid parameter",
-			},
-			responseType: "struct",
-			testType:     "global-error",
+			description:      "POST WITH ID - Passing an invalid uuid - Error Case",
+			route:            "/movies/testestetsts/actors",
+			method:           "POST",
+			token:            adminToken,
+			expectedCode:     400,
+			expectedResponse: apierrors.InvalidUUID,
+			responseType:     "struct",
+			testType:         "global-error",
 		},
 		// Get requests
 		{
@@ -139,27 +151,104 @@ func Test_MoviesRoutes(t *testing.T) {
 			testType:     "success",
 		}, // Not gonna test the movies without actors because it would be a huge hassle for what's basically the same test but without the actors key (which is arguably the hardest part of this)
 		{
-			description:  "GET - Passing an offset that is not a number - Error Case",
-			route:        "/movies?offset=2.254",
+			description:      "GET - Passing an offset that is not a number - Error Case",
+			route:            "/movies?offset=2.254",
+			method:           "GET",
+			expectedCode:     400,
+			expectedResponse: apierrors.InvalidOffset,
+			responseType:     "slice",
+			testType:         "global-error",
+		},
+		{
+			description:      "GET - Passing a limit that is not a number - Error Case",
+			route:            "/movies?limit=aushaushaush",
+			method:           "GET",
+			expectedCode:     400,
+			expectedResponse: apierrors.InvalidLimit,
+			responseType:     "slice",
+			testType:         "global-error",
+		}, // Since sort and with_actors casts every non-valid value to a default valid one, it does not need to be tested, as any error case will fall into the updated_at DESC clause.
+		{
+			description:  "GET - Filtering movies by title and director substrings - Success Case",
+			route:        "/movies?title=movie%202&director=DIRECTOR%202",
+			method:       "GET",
+			expectedCode: 200,
+			expectedResponse: []models.MovieResponseWithActors{
+				{
+					Title:       movieResponses[1].Title,
+					Director:    movieResponses[1].Director,
+					ReleaseDate: movieResponses[1].ReleaseDate,
+					CreatorId:   adminId,
+				},
+			},
+			responseType: "slice",
+			testType:     "success",
+		},
+		{
+			description:  "GET - Filtering movies by actor - Success Case",
+			route:        fmt.Sprintf("/movies?actor=%v&sort=title,asc", actorResponses[1].ID),
 			method:       "GET",
-			expectedCode: 400,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "Offset needs to be a valid integer",
+			expectedCode: 200,
+			expectedResponse: []models.MovieResponseWithActors{
+				{
+					Title:       movieResponses[0].Title,
+					Director:    movieResponses[0].Director,
+					ReleaseDate: movieResponses[0].ReleaseDate,
+					CreatorId:   adminId,
+				},
+				{
+					Title:       "Movie 1",
+					Director:    "Director 1",
+					ReleaseDate: "1990-01-01T00:00:00Z",
+					CreatorId:   adminId,
+				},
 			},
 			responseType: "slice",
-			testType:     "global-error",
+			testType:     "success",
 		},
 		{
-			description:  "GET - Passing a limit that is not a number - Error Case",
-			route:        "/movies?limit=aushaushaush",
+			description:  "GET - Filtering movies by release date range - Success Case",
+			route:        "/movies?released_from=1990-01-01&released_to=1990-12-31",
 			method:       "GET",
-			expectedCode: 400,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "Limit needs to be a valid integer",
+			expectedCode: 200,
+			expectedResponse: []models.MovieResponseWithActors{
+				{
+					Title:       "Movie 1",
+					Director:    "Director 1",
+					ReleaseDate: "1990-01-01T00:00:00Z",
+					CreatorId:   adminId,
+				},
 			},
 			responseType: "slice",
-			testType:     "global-error",
-		}, // Since sort and with_actors casts every non-valid value to a default valid one, it does not need to be tested, as any error case will fall into the updated_at DESC clause.
+			testType:     "success",
+		},
+		{
+			description:      "GET - Passing a release date filter that is not a date - Error Case",
+			route:            "/movies?released_from=01/01/1990",
+			method:           "GET",
+			expectedCode:     400,
+			expectedResponse: apierrors.InvalidQueryParam("released_from", apierrors.ValidDate),
+			responseType:     "slice",
+			testType:         "global-error",
+		},
+		{
+			description:      "GET - Passing a grade filter that is not a number - Error Case",
+			route:            "/movies?min_grade=five",
+			method:           "GET",
+			expectedCode:     400,
+			expectedResponse: apierrors.InvalidQueryParam("min_grade", apierrors.ValidNumber),
+			responseType:     "slice",
+			testType:         "global-error",
+		},
+		{
+			description:      "GET - Passing an actor filter that is not an uuid - Error Case",
+			route:            "/movies?actor=not-an-uuid",
+			method:           "GET",
+			expectedCode:     400,
+			expectedResponse: apierrors.InvalidQueryParam("actor", apierrors.ValidUUID),
+			responseType:     "slice",
+			testType:         "global-error",
+		},
 		{
 			description:      "GET BY ID - Passing an uuid that exists in DB - Success Case",
 			route:            fmt.Sprintf("/movies/%v", movieResponses[1].ID),
@@ -170,52 +259,49 @@ func Test_MoviesRoutes(t *testing.T) {
 			testType:         "success",
 		},
 		{
-			description:  "GET BY ID - Passing an uuid that does not exist in DB - Error Case",
-			route:        fmt.Sprintf("/movies/%v", uuid.New()),
-			method:       "GET",
-			expectedCode: 404,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "Movie id not found in database",
-			},
-			responseType: "struct",
-			testType:     "global-error",
+			description:      "GET BY ID - Passing an uuid that does not exist in DB - Error Case",
+			route:            fmt.Sprintf("/movies/%v", uuid.New()),
+			method:           "GET",
+			expectedCode:     404,
+			expectedResponse: apierrors.MovieNotFound,
+			responseType:     "struct",
+			testType:         "global-error",
 		},
 		{
-			description:  "GET BY ID - Passing an invalid uuid - Error Case",
-			route:        "/movies/testestetsts",
-			method:       "GET",
-			expectedCode: 400,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "Invalid uuid parameter",
-			},
-			responseType: "struct",
-			testType:     "global-error",
+			description:      "GET BY ID - Passing an invalid uuid - Error Case",
+			route:            "/movies/testestetsts",
+			method:           "GET",
+			expectedCode:     400,
+			expectedResponse: apierrors.InvalidUUID,
+			responseType:     "struct",
+			testType:         "global-error",
 		},
 		// Delete requests
 		{
 			description:      "DELETE BY ID - Passing an uuid that exists in DB - Success Case",
 			route:            fmt.Sprintf("/movies/%v", movieResponses[2].ID),
 			method:           "DELETE",
+			token:            adminToken,
 			expectedCode:     204,
 			expectedResponse: movieResponses[2],
 			testType:         "delete",
 		},
 		{
-			description:  "DELETE BY ID - Passing an uuid that does not exist in DB - Error Case",
-			route:        fmt.Sprintf("/movies/%v", "testeasdasd"),
-			method:       "DELETE",
-			expectedCode: 400,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "Invalid uuid parameter",
-			},
-			testType: "global-error",
+			description:      "DELETE BY ID - Passing an uuid that does not exist in DB - Error Case",
+			route:            fmt.Sprintf("/movies/%v", "testeasdasd"),
+			method:           "DELETE",
+			token:            adminToken,
+			expectedCode:     400,
+			expectedResponse: apierrors.InvalidUUID,
+			testType:         "global-error",
 		},
 		{
 			description: "DELETE WITH ID - Delete existing actors to a movie - Success Case",
 			route:       fmt.Sprintf("/movies/%v/actors", movieResponses[4].ID),
 			method:      "DELETE",
+			token:       adminToken,
 			data: map[string]interface{}{
-				"actors": []string{actorResponses[0].ID.String()},
+				"actors": []map[string]interface{}{{"actorId": actorResponses[0].ID.String()}},
 			},
 			expectedCode:     204,
 			expectedResponse: movieResponses[4],
@@ -225,40 +311,40 @@ func Test_MoviesRoutes(t *testing.T) {
 			description: "DELETE WITH ID - Deleting actors that already don't exist on a movie - Error Case",
 			route:       fmt.Sprintf("/movies/%v/actors", movieResponses[3].ID),
 			method:      "DELETE",
+			token:       adminToken,
 			data: map[string]interface{}{
-				"actors": []string{actorResponses[2].ID.String()},
+				"actors": []map[string]interface{}{{"actorId": actorResponses[2].ID.String()}},
 			},
 			expectedCode:     400,
 			expectedResponse: movieResponses[3],
 			testType:         "success-delete-movies-actors", // This still passes because the test only checks if the relationship exists and, well, it already does.
 		},
 		{
-			description:  "DELETE WITH ID - Passing an uuid that does not exist in DB - Error Case",
-			route:        fmt.Sprintf("/movies/%v/actors", uuid.New()),
-			method:       "DELETE",
-			expectedCode: 404,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "Movie id not found in database",
-			},
-			responseType: "struct",
-			testType:     "global-error",
+			description:      "DELETE WITH ID - Passing an uuid that does not exist in DB - Error Case",
+			route:            fmt.Sprintf("/movies/%v/actors", uuid.New()),
+			method:           "DELETE",
+			token:            adminToken,
+			expectedCode:     404,
+			expectedResponse: apierrors.MovieNotFound,
+			responseType:     "struct",
+			testType:         "global-error",
 		},
 		{
-			description:  "DELETE WITH ID - Passing an invalid uuid - Error Case",
-			route:        "/movies/testestetsts/actors",
-			method:       "DELETE",
-			expectedCode: 400,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "Invalid uuid parameter",
-			},
-			responseType: "struct",
-			testType:     "global-error",
+			description:      "DELETE WITH ID - Passing an invalid uuid - Error Case",
+			route:            "/movies/testestetsts/actors",
+			method:           "DELETE",
+			token:            adminToken,
+			expectedCode:     400,
+			expectedResponse: apierrors.InvalidUUID,
+			responseType:     "struct",
+			testType:         "global-error",
 		},
 		// Update requests
 		{
 			description: "UPDATE - Update movie info (all keys) - Success Case",
 			route:       fmt.Sprintf("/movies/%v", movieResponses[3].ID),
 			method:      "PATCH",
+			token:       adminToken,
 			data: map[string]interface{}{
 				"title":       "New title",
 				"director":    "New director",
@@ -275,15 +361,53 @@ func Test_MoviesRoutes(t *testing.T) {
 			testType: "update",
 		},
 		{
-			description:  "UPDATE - Passing an invalid uuid - Error Case",
-			route:        "/movies/09ehrgf",
-			method:       "PATCH",
-			expectedCode: 400,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "Invalid uuid parameter",
+			description: "UPDATE CASTING - Update character and billing order of an actor in a movie - Success Case",
+			route:       fmt.Sprintf("/movies/%v/actors/%v", movieResponses[0].ID, actorResponses[1].ID),
+			method:      "PATCH",
+			token:       adminToken,
+			data: map[string]interface{}{
+				"characterName": "Sidekick",
+				"billingOrder":  5,
+			},
+			expectedCode: 200,
+			expectedResponse: models.CastingResponse{
+				ActorResponse: actorResponses[1],
+				CharacterName: "Sidekick",
+				BillingOrder:  5,
+			},
+			testType: "update-casting",
+		},
+		{
+			description: "UPDATE CASTING - Passing an actor that is not in the cast of the movie - Error Case",
+			route:       fmt.Sprintf("/movies/%v/actors/%v", movieResponses[0].ID, actorResponses[0].ID),
+			method:      "PATCH",
+			token:       adminToken,
+			data: map[string]interface{}{
+				"characterName": "Nobody",
 			},
-			responseType: "struct",
-			testType:     "global-error",
+			expectedCode:     404,
+			expectedResponse: apierrors.CastingNotFound,
+			testType:         "global-error",
+		},
+		{
+			description:      "UPDATE CASTING - Passing an empty body - Error Case",
+			route:            fmt.Sprintf("/movies/%v/actors/%v", movieResponses[0].ID, actorResponses[1].ID),
+			method:           "PATCH",
+			token:            adminToken,
+			data:             map[string]interface{}{},
+			expectedCode:     400,
+			expectedResponse: apierrors.EmptyCastingUpdate,
+			testType:         "global-error",
+		},
+		{
+			description:      "UPDATE - Passing an invalid uuid - Error Case",
+			route:            "/movies/09ehrgf",
+			method:           "PATCH",
+			token:            adminToken,
+			expectedCode:     400,
+			expectedResponse: apierrors.InvalidUUID,
+			responseType:     "struct",
+			testType:         "global-error",
 		},
 	}
 
@@ -308,6 +432,9 @@ func Test_MoviesRoutes(t *testing.T) {
 		}
 
 		req.Header.Set("Content-Type", "application/json")
+		if testCase.token != "" {
+			req.Header.Set("Authorization", "Bearer "+testCase.token)
+		}
 
 		resp, err := App.Test(req, -1)
 		if err != nil {
@@ -329,9 +456,12 @@ func Test_MoviesRoutes(t *testing.T) {
 			var respSlice []models.MovieResponseWithActors
 
 			if testCase.responseType == "slice" {
-				if err := json.Unmarshal(responseBody, &respSlice); err != nil {
+				// List routes answer with the pagination envelope
+				var respPage models.Page[models.MovieResponseWithActors]
+				if err := json.Unmarshal(responseBody, &respPage); err != nil {
 					t.Fatalf("Error unmarshalling response body: %v", err)
 				}
+				respSlice = respPage.Data
 			} else {
 				if err := json.Unmarshal(responseBody, &respStruct); err != nil {
 					t.Fatalf("Error unmarshalling response body: %v", err)
@@ -392,6 +522,8 @@ func Test_MoviesRoutes(t *testing.T) {
 						assert.Equal(t, expected.Actors[i].Surname, actor.Surname, "Actor Surname mismatch")
 						assert.Equal(t, expected.Actors[i].Birthday, actor.Birthday, "Actor Birthday mismatch")
 						assert.Equal(t, expected.Actors[i].CreatorId, actor.CreatorId, "Actor CreatorId mismatch")
+						assert.Equal(t, expected.Actors[i].CharacterName, actor.CharacterName, "Actor CharacterName mismatch")
+						assert.Equal(t, expected.Actors[i].BillingOrder, actor.BillingOrder, "Actor BillingOrder mismatch")
 					}
 
 					for _, movie := range movieResponses {
@@ -411,7 +543,7 @@ func Test_MoviesRoutes(t *testing.T) {
 		}
 
 		if testCase.testType == "global-error" {
-			assert.Equal(t, testCase.expectedResponse.(GlobalErrorHandlerResp).Message, string(responseBody))
+			assertProblem(t, testCase.expectedResponse.(*apierrors.Error), responseBody)
 		}
 
 		if testCase.testType == "delete" {
@@ -455,6 +587,28 @@ func Test_MoviesRoutes(t *testing.T) {
 			compareMovieResponses(t, testCase.expectedResponse.(models.MovieResponse), respStruct)
 		}
 
+		if testCase.testType == "update-casting" {
+			var respStruct models.CastingResponse
+			if err := json.Unmarshal(responseBody, &respStruct); err != nil {
+				t.Fatalf("Error unmarshalling response body: %v", err)
+			}
+
+			expected := testCase.expectedResponse.(models.CastingResponse)
+			assert.Equal(t, expected.ID, respStruct.ID, "Actor ID mismatch")
+			assert.Equal(t, expected.CharacterName, respStruct.CharacterName, "CharacterName should be updated")
+			assert.Equal(t, expected.BillingOrder, respStruct.BillingOrder, "BillingOrder should be updated")
+
+			// The cast is read back sorted by billing order, so the updated actor moves to the end
+			movieResp, err := MovieModel.GetMovieByIdWithActors(db, movieResponses[0].ID)
+			if err != nil {
+				t.Fatalf("Error getting movie by id: %v", err)
+			}
+
+			if assert.NotEmpty(t, movieResp.Actors, "Cast should not be empty") {
+				assert.Equal(t, expected.ID, movieResp.Actors[len(movieResp.Actors)-1].ID, "Cast should be sorted by billing order")
+			}
+		}
+
 		if testCase.testType == "success-movies-actors" {
 			movieResp, err := MovieModel.GetMovieByIdWithActors(db, testCase.expectedResponse.(models.MovieResponseWithActors).ID)
 			if err != nil {
@@ -467,7 +621,7 @@ func Test_MoviesRoutes(t *testing.T) {
 
 			var actorWasInsertedInMovie bool
 			for _, actor := range movieResp.Actors {
-				if actor.ID.String() == testCase.data["actors"].([]string)[0] {
+				if actor.ID.String() == testCase.data["actors"].([]map[string]interface{})[0]["actorId"] {
 					actorWasInsertedInMovie = true
 				}
 			}
@@ -487,7 +641,7 @@ func Test_MoviesRoutes(t *testing.T) {
 
 			var actorWasNotDeletedFromMovie bool
 			for _, actor := range movieResp.Actors {
-				if actor.ID.String() == testCase.data["actors"].([]string)[0] {
+				if actor.ID.String() == testCase.data["actors"].([]map[string]interface{})[0]["actorId"] {
 					actorWasNotDeletedFromMovie = true
 				}
 			}
@@ -496,3 +650,193 @@ func Test_MoviesRoutes(t *testing.T) {
 		}
 	}
 }
+
+func Test_MovieRatingStats(t *testing.T) {
+	db := initializers.NewDatabaseConn()
+	defer db.Close()
+
+	movie, err := MovieModel.InsertMovieInDB(db, models.MovieBody{
+		Title:       "Rating Stats Movie",
+		Director:    "Rating Stats Director",
+		ReleaseDate: "2001-01-01",
+		CreatorId:   adminId,
+		Actors:      []models.CastingBody{{ActorId: actorResponses[0].ID.String()}},
+	})
+	if err != nil {
+		t.Fatalf("Error inserting movie for rating stats: %v", err)
+	}
+
+	getMovie := func(t *testing.T) models.MovieResponseWithActors {
+		statusCode, responseBody := sendSessionRequest(t, "GET", fmt.Sprintf("/movies/%v", movie.ID), "", nil)
+		if statusCode != 200 {
+			t.Fatalf("Unexpected status code %v getting movie: %s", statusCode, responseBody)
+		}
+
+		var movieResp models.MovieResponseWithActors
+		if err := json.Unmarshal(responseBody, &movieResp); err != nil {
+			t.Fatalf("Error unmarshalling response body: %v", err)
+		}
+
+		return movieResp
+	}
+
+	var reviews []models.CommentResponse
+	for i, grade := range []float64{5, 3.4, 1} {
+		review, err := CommentModel.InsertCommentInDB(db, userResponses[i].ID, models.CommentBody{Comment: "Rating stats review", Grade: grade, MovieId: movie.ID.String()})
+		if err != nil {
+			t.Fatalf("Error inserting review: %v", err)
+		}
+		reviews = append(reviews, review)
+	}
+
+	if _, err := CommentModel.InsertCommentInDB(db, userResponses[3].ID, models.CommentBody{Comment: "Ungraded", MovieId: movie.ID.String()}); err != nil {
+		t.Fatalf("Error inserting ungraded comment: %v", err)
+	}
+
+	movieResp := getMovie(t)
+	assert.Equal(t, 3.1, movieResp.AverageGrade, "Ungraded comments should not count in the average")
+	assert.Equal(t, 3, movieResp.RatingCount, "Ungraded comments should not count as ratings")
+	assert.Equal(t, models.GradeHistogram{"1": 1, "2": 0, "3": 1, "4": 0, "5": 1}, movieResp.GradeHistogram, "Grade histogram mismatch")
+
+	// Soft deleted reviews leave the stats
+	adminToken := loginForTest(t, "admin@admin.com", "Testando@Teste**").Token
+	statusCode, _ := sendSessionRequest(t, "DELETE", fmt.Sprintf("/comments/%v", reviews[0].ID), adminToken, nil)
+	assert.Equal(t, 204, statusCode, "status code of review deletion")
+
+	movieResp = getMovie(t)
+	assert.Equal(t, 2.2, movieResp.AverageGrade, "Deleted reviews should not count in the average")
+	assert.Equal(t, 2, movieResp.RatingCount, "Deleted reviews should not count as ratings")
+	assert.Equal(t, models.GradeHistogram{"1": 1, "2": 0, "3": 1, "4": 0, "5": 0}, movieResp.GradeHistogram, "Grade histogram mismatch after soft delete")
+
+	// Rows removed from the table also refresh the stats
+	if _, err := db.Exec("DELETE FROM comments WHERE id = $1;", reviews[2].ID); err != nil {
+		t.Fatalf("Error deleting review row: %v", err)
+	}
+
+	movieResp = getMovie(t)
+	assert.Equal(t, 3.4, movieResp.AverageGrade, "Average grade mismatch after row deletion")
+	assert.Equal(t, 1, movieResp.RatingCount, "Rating count mismatch after row deletion")
+
+	// Stats that drifted are fixed by the recompute route
+	if _, err := db.Exec("UPDATE movies SET average_grade = 0, rating_count = 99 WHERE id = $1;", movie.ID); err != nil {
+		t.Fatalf("Error corrupting movie stats: %v", err)
+	}
+
+	statusCode, responseBody := sendSessionRequest(t, "POST", "/movies/stats/recompute", adminToken, nil)
+	assert.Equal(t, 200, statusCode, "status code of stats recompute")
+
+	var recomputeResp controllers.RecomputeStatsResponse
+	if err := json.Unmarshal(responseBody, &recomputeResp); err != nil {
+		t.Fatalf("Error unmarshalling response body: %v", err)
+	}
+	assert.GreaterOrEqual(t, recomputeResp.MoviesRecomputed, len(movieResponses)+1, "Every movie should be recomputed")
+
+	movieResp = getMovie(t)
+	assert.Equal(t, 3.4, movieResp.AverageGrade, "Average grade should be recomputed")
+	assert.Equal(t, 1, movieResp.RatingCount, "Rating count should be recomputed")
+}
+
+func Test_TopAndTrendingMovies(t *testing.T) {
+	db := initializers.NewDatabaseConn()
+	defer db.Close()
+
+	insertMovie := func(t *testing.T, title string) models.MovieResponseWithActors {
+		movie, err := MovieModel.InsertMovieInDB(db, models.MovieBody{
+			Title:       title,
+			Director:    "Ranking Director",
+			ReleaseDate: "2005-05-05",
+			CreatorId:   adminId,
+			Actors:      []models.CastingBody{{ActorId: actorResponses[0].ID.String()}},
+		})
+		if err != nil {
+			t.Fatalf("Error inserting movie %v: %v", title, err)
+		}
+
+		return movie
+	}
+
+	review := func(t *testing.T, userId uuid.UUID, movie models.MovieResponseWithActors, grade float64) models.CommentResponse {
+		comment, err := CommentModel.InsertCommentInDB(db, userId, models.CommentBody{Comment: "Ranking review", Grade: grade, MovieId: movie.ID.String()})
+		if err != nil {
+			t.Fatalf("Error inserting review on %v: %v", movie.Title, err)
+		}
+
+		return comment
+	}
+
+	listTitles := func(t *testing.T, route string) ([]string, []models.MovieResponse) {
+		statusCode, responseBody := sendSessionRequest(t, "GET", route, "", nil)
+		if statusCode != 200 {
+			t.Fatalf("Unexpected status code %v on %v: %s", statusCode, route, responseBody)
+		}
+
+		var page models.Page[models.MovieResponse]
+		if err := json.Unmarshal(responseBody, &page); err != nil {
+			t.Fatalf("Error unmarshalling response body: %v", err)
+		}
+
+		var titles []string
+		for _, movie := range page.Data {
+			titles = append(titles, movie.Title)
+		}
+
+		return titles, page.Data
+	}
+
+	indexOf := func(titles []string, title string) int {
+		for i, current := range titles {
+			if current == title {
+				return i
+			}
+		}
+
+		return -1
+	}
+
+	// One perfect review against many good ones
+	single := insertMovie(t, "Single Perfect Review Movie")
+	oldReview := review(t, userResponses[0].ID, single, 5)
+
+	many := insertMovie(t, "Many Good Reviews Movie")
+	review(t, uuid.MustParse(adminId), many, 4.8)
+	for _, user := range userResponses {
+		review(t, user.ID, many, 4.8)
+	}
+
+	// With the default prior mean of 3 and 10 min votes: (1 * 5 + 10 * 3) / 11 and (5 * 4.8 + 10 * 3) / 15
+	titles, movies := listTitles(t, "/movies/top?limit=100")
+	singleIndex, manyIndex := indexOf(titles, single.Title), indexOf(titles, many.Title)
+	if assert.NotEqual(t, -1, singleIndex, "Reviewed movie should be in the top") && assert.NotEqual(t, -1, manyIndex, "Reviewed movie should be in the top") {
+		assert.Less(t, manyIndex, singleIndex, "Many good reviews should outrank a single perfect one")
+		assert.Equal(t, 3.182, movies[singleIndex].WeightedRating, "Weighted rating of the single review movie mismatch")
+		assert.Equal(t, 3.6, movies[manyIndex].WeightedRating, "Weighted rating of the many reviews movie mismatch")
+		assert.Equal(t, 5.0, movies[singleIndex].AverageGrade, "The plain average of the single review movie is still higher")
+	}
+
+	for _, movie := range movies {
+		assert.NotZero(t, movie.RatingCount, "Movies without reviews should not be in the top")
+	}
+
+	// The same movies, but in the sort sent
+	titles, _ = listTitles(t, "/movies/top?limit=100&sort=title,asc")
+	assert.Less(t, indexOf(titles, many.Title), indexOf(titles, single.Title), "Top movies should follow the sort param")
+
+	// Reviews out of the window don't make a movie trend
+	if _, err := db.Exec("UPDATE comments SET created_at = NOW() - INTERVAL '30 days' WHERE id = $1;", oldReview.ID); err != nil {
+		t.Fatalf("Error backdating review: %v", err)
+	}
+
+	titles, _ = listTitles(t, "/movies/trending?limit=100")
+	assert.NotEqual(t, -1, indexOf(titles, many.Title), "Movie reviewed this week should be trending")
+	assert.Equal(t, -1, indexOf(titles, single.Title), "Movie reviewed a month ago should not be trending in the default window")
+
+	titles, _ = listTitles(t, "/movies/trending?limit=100&window=60")
+	singleIndex, manyIndex = indexOf(titles, single.Title), indexOf(titles, many.Title)
+	if assert.NotEqual(t, -1, singleIndex, "Movie reviewed a month ago should be trending in a 60 days window") {
+		assert.Less(t, manyIndex, singleIndex, "Movies with more reviews per day should trend first")
+	}
+
+	statusCode, responseBody := sendSessionRequest(t, "GET", "/movies/trending?window=0", "", nil)
+	assert.Equal(t, 400, statusCode, "status code of invalid window")
+	assertProblem(t, apierrors.InvalidQueryParam("window", apierrors.DaysBetween, "1", "365"), responseBody, "response of invalid window")
+}
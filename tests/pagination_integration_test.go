@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/VinOfSteel/cinemagrader/apierrors"
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func getActorsPage(t *testing.T, route string) models.Page[models.ActorResponse] {
+	statusCode, responseBody := sendSessionRequest(t, "GET", route, "", nil)
+	if statusCode != 200 {
+		t.Fatalf("Unexpected status code %v getting %v: %s", statusCode, route, responseBody)
+	}
+
+	var page models.Page[models.ActorResponse]
+	if err := json.Unmarshal(responseBody, &page); err != nil {
+		t.Fatalf("Error unmarshalling response body: %v", err)
+	}
+
+	return page
+}
+
+func Test_CursorPagination(t *testing.T) {
+	// Offset pages and cursor pages have to agree on the same order
+	offsetPage := getActorsPage(t, "/actors?sort=name,asc&limit=100")
+	assert.Equal(t, offsetPage.Pagination.Total, len(offsetPage.Data), "Total should count every actor")
+	assert.Empty(t, offsetPage.Pagination.Next, "A page with every actor should not have a next cursor")
+	assert.Empty(t, offsetPage.Pagination.Prev, "The first page should not have a prev cursor")
+
+	expectedIds := idsOf(offsetPage.Data)
+
+	// Walking forward two by two
+	var forwardIds []uuid.UUID
+	page := getActorsPage(t, "/actors?sort=name,asc&limit=2")
+	for {
+		assert.LessOrEqual(t, len(page.Data), 2, "Page should respect the limit")
+		assert.Equal(t, offsetPage.Pagination.Total, page.Pagination.Total, "Total should not depend on the page")
+		forwardIds = append(forwardIds, idsOf(page.Data)...)
+
+		if page.Pagination.Next == "" {
+			break
+		}
+		page = getActorsPage(t, "/actors?sort=name,asc&limit=2&cursor="+url.QueryEscape(page.Pagination.Next))
+	}
+	assert.Equal(t, expectedIds, forwardIds, "Following next cursors should go through every actor in order")
+
+	// Walking backwards from the last page
+	var backwardIds []uuid.UUID
+	for {
+		backwardIds = append(idsOf(page.Data), backwardIds...)
+
+		if page.Pagination.Prev == "" {
+			break
+		}
+		page = getActorsPage(t, "/actors?sort=name,asc&limit=2&cursor="+url.QueryEscape(page.Pagination.Prev))
+	}
+	assert.Equal(t, expectedIds, backwardIds, "Following prev cursors should go back through every actor in order")
+
+	// Offset is still accepted and creates cursors relative to the page it returned
+	page = getActorsPage(t, "/actors?sort=name,asc&limit=2&offset=1")
+	assert.Equal(t, expectedIds[1:3], idsOf(page.Data), "Offset page mismatch")
+	prevPage := getActorsPage(t, "/actors?sort=name,asc&limit=2&cursor="+url.QueryEscape(page.Pagination.Prev))
+	assert.Equal(t, expectedIds[:1], idsOf(prevPage.Data), "Prev page of an offset page mismatch")
+
+	// Error cases
+	statusCode, responseBody := sendSessionRequest(t, "GET", "/actors?cursor=definitelynotacursor", "", nil)
+	assert.Equal(t, 400, statusCode, "status code of invalid cursor")
+	assertProblem(t, apierrors.InvalidCursor, responseBody, "response of invalid cursor")
+
+	firstPage := getActorsPage(t, "/actors?sort=name,asc&limit=2")
+	statusCode, responseBody = sendSessionRequest(t, "GET", "/actors?sort=surname,desc&limit=2&cursor="+url.QueryEscape(firstPage.Pagination.Next), "", nil)
+	assert.Equal(t, 400, statusCode, "status code of cursor with another sort")
+	assertProblem(t, apierrors.CursorSortMismatch, responseBody, "response of cursor with another sort")
+}
+
+func idsOf(actors []models.ActorResponse) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(actors))
+	for _, actor := range actors {
+		ids = append(ids, actor.ID)
+	}
+
+	return ids
+}
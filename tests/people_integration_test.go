@@ -0,0 +1,139 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/VinOfSteel/cinemagrader/apierrors"
+	"github.com/VinOfSteel/cinemagrader/initializers"
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func getFilmography(t *testing.T, personId uuid.UUID) models.PersonResponseWithFilmography {
+	statusCode, responseBody := sendSessionRequest(t, "GET", fmt.Sprintf("/people/%v/filmography", personId), "", nil)
+	if statusCode != 200 {
+		t.Fatalf("Unexpected status code %v getting filmography: %s", statusCode, responseBody)
+	}
+
+	var person models.PersonResponseWithFilmography
+	if err := json.Unmarshal(responseBody, &person); err != nil {
+		t.Fatalf("Error unmarshalling response body: %v", err)
+	}
+
+	return person
+}
+
+func creditRoles(person models.PersonResponseWithFilmography, movieId uuid.UUID) []string {
+	var roles []string
+	for _, credit := range person.Filmography {
+		if credit.Movie.ID == movieId {
+			roles = append(roles, credit.Role)
+		}
+	}
+
+	return roles
+}
+
+func Test_PeopleRoutes(t *testing.T) {
+	db := initializers.NewDatabaseConn()
+	defer db.Close()
+	adminToken := loginForTest(t, "admin@admin.com", "Testando@Teste**").Token
+
+	// Movies created without a crew are linked to a person named after their director
+	statusCode, responseBody := sendSessionRequest(t, "GET", "/people?role=director&name="+url.QueryEscape("inserted director 1"), "", nil)
+	assert.Equal(t, 200, statusCode, "status code of people list")
+
+	var directors models.Page[models.PersonResponse]
+	if err := json.Unmarshal(responseBody, &directors); err != nil {
+		t.Fatalf("Error unmarshalling response body: %v", err)
+	}
+	if !assert.Len(t, directors.Data, 1, "Director of the inserted movie should be a person") {
+		return
+	}
+	assert.Equal(t, "Inserted Director 1", directors.Data[0].Name, "Director name mismatch")
+	assert.Equal(t, "", directors.Data[0].Birthday, "Director created from a movie should not have a birthday")
+
+	director := getFilmography(t, directors.Data[0].ID)
+	assert.Equal(t, []string{models.CrewRoleDirector}, creditRoles(director, movieResponses[0].ID), "Director filmography mismatch")
+
+	// Creating a composer and crediting them in a movie
+	statusCode, responseBody = sendSessionRequest(t, "POST", "/people", adminToken, map[string]interface{}{
+		"name":      "Hans Zimmer",
+		"birthday":  "1957-09-12",
+		"creatorId": adminId,
+	})
+	assert.Equal(t, 201, statusCode, "status code of person creation")
+
+	var composer models.PersonResponse
+	if err := json.Unmarshal(responseBody, &composer); err != nil {
+		t.Fatalf("Error unmarshalling response body: %v", err)
+	}
+	assert.Equal(t, "Hans Zimmer", composer.Name, "Name mismatch")
+	assert.Equal(t, "1957-09-12", composer.Birthday, "Birthday mismatch")
+	assert.Equal(t, adminId, composer.CreatorId, "CreatorId mismatch")
+
+	crewBody := map[string]interface{}{
+		"crew": []map[string]interface{}{{"personId": composer.ID.String(), "role": models.CrewRoleComposer}},
+	}
+	crewRoute := fmt.Sprintf("/movies/%v/crew", movieResponses[0].ID)
+
+	statusCode, _ = sendSessionRequest(t, "POST", crewRoute, adminToken, crewBody)
+	assert.Equal(t, 204, statusCode, "status code of crew creation")
+
+	statusCode, responseBody = sendSessionRequest(t, "POST", crewRoute, adminToken, crewBody)
+	assert.Equal(t, 400, statusCode, "status code of repeated crew credit")
+	assertProblem(t, apierrors.CrewAlreadyInMovie, responseBody, "response of repeated crew credit")
+
+	statusCode, _ = sendSessionRequest(t, "POST", crewRoute, adminToken, map[string]interface{}{
+		"crew": []map[string]interface{}{{"personId": composer.ID.String(), "role": "catering"}},
+	})
+	assert.Equal(t, 400, statusCode, "status code of crew credit with an invalid role")
+
+	// Linking an actor to the person adds their acting credits to the filmography
+	statusCode, responseBody = sendSessionRequest(t, "PATCH", fmt.Sprintf("/actors/%v", actorResponses[1].ID), adminToken, map[string]interface{}{
+		"personId": composer.ID.String(),
+	})
+	assert.Equal(t, 200, statusCode, "status code of actor link")
+
+	var linkedActor models.ActorResponse
+	if err := json.Unmarshal(responseBody, &linkedActor); err != nil {
+		t.Fatalf("Error unmarshalling response body: %v", err)
+	}
+	assert.Equal(t, uuid.NullUUID{UUID: composer.ID, Valid: true}, linkedActor.PersonId, "Actor should be linked to the person")
+
+	filmography := getFilmography(t, composer.ID)
+	assert.ElementsMatch(t, []string{models.CreditRoleActor, models.CrewRoleComposer}, creditRoles(filmography, movieResponses[0].ID), "Filmography should have crew and acting credits")
+
+	statusCode, _ = sendSessionRequest(t, "DELETE", crewRoute, adminToken, crewBody)
+	assert.Equal(t, 204, statusCode, "status code of crew deletion")
+
+	filmography = getFilmography(t, composer.ID)
+	assert.Equal(t, []string{models.CreditRoleActor}, creditRoles(filmography, movieResponses[0].ID), "Crew credit should be removed from the filmography")
+
+	// Deleting the person unlinks the actor
+	statusCode, _ = sendSessionRequest(t, "DELETE", fmt.Sprintf("/people/%v", composer.ID), adminToken, nil)
+	assert.Equal(t, 204, statusCode, "status code of person deletion")
+
+	actor, err := ActorModel.GetActorById(db, actorResponses[1].ID)
+	if err != nil {
+		t.Fatalf("Error getting actor by id: %v", err)
+	}
+	assert.False(t, actor.PersonId.Valid, "Actor should not be linked to a deleted person")
+
+	// Error cases
+	statusCode, responseBody = sendSessionRequest(t, "GET", fmt.Sprintf("/people/%v", uuid.New()), "", nil)
+	assert.Equal(t, 404, statusCode, "status code of person that does not exist")
+	assertProblem(t, apierrors.PersonNotFound, responseBody, "response of person that does not exist")
+
+	statusCode, responseBody = sendSessionRequest(t, "GET", "/people/testestetsts/filmography", "", nil)
+	assert.Equal(t, 400, statusCode, "status code of invalid uuid")
+	assertProblem(t, apierrors.InvalidUUID, responseBody, "response of invalid uuid")
+
+	statusCode, responseBody = sendSessionRequest(t, "GET", "/people?role=catering", "", nil)
+	assert.Equal(t, 400, statusCode, "status code of invalid role filter")
+	assertProblem(t, apierrors.InvalidQueryParam("role", apierrors.OneOf, "director, writer, producer, composer, cinematographer"), responseBody, "response of invalid role filter")
+}
@@ -0,0 +1,133 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/VinOfSteel/cinemagrader/apierrors"
+	"github.com/VinOfSteel/cinemagrader/controllers"
+	"github.com/VinOfSteel/cinemagrader/initializers"
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func tokenClaims(t *testing.T, token string) jwt.MapClaims {
+	sessionController := controllers.Session{}
+	claims, err := sessionController.VerifyToken(token)
+	if err != nil {
+		t.Fatalf("Error verifying token: %v", err)
+	}
+
+	return claims
+}
+
+func Test_RolesAndPermissions(t *testing.T) {
+	db := initializers.NewDatabaseConn()
+	defer db.Close()
+
+	users := map[string]models.UserResponse{}
+	for _, user := range InsertMockedUsersInDB(db, []models.UserBody{
+		{Name: "Roles", Surname: "Moderator", Email: "rolesmoderator@teste.com", Password: "testando123@Teste", Birthday: "1990-01-01"},
+		{Name: "Roles", Surname: "Curator", Email: "rolescurator@teste.com", Password: "testando123@Teste", Birthday: "1990-01-01"},
+		{Name: "Roles", Surname: "Author", Email: "rolesauthor@teste.com", Password: "testando123@Teste", Birthday: "1990-01-01"},
+	}) {
+		users[user.Surname] = user
+	}
+	moderator, curator, author := users["Moderator"], users["Curator"], users["Author"]
+
+	if err := RoleModel.GrantRoleToUser(db, uuid.NullUUID{}, moderator.ID, models.RoleModerator); err != nil {
+		t.Fatalf("Error granting role: %v", err)
+	}
+	if err := RoleModel.GrantRoleToUser(db, uuid.NullUUID{}, curator.ID, models.RoleCurator); err != nil {
+		t.Fatalf("Error granting role: %v", err)
+	}
+
+	// Tokens carry the roles and the permissions they grant
+	moderatorLogin := loginForTest(t, moderator.Email, "testando123@Teste")
+	claims := tokenClaims(t, moderatorLogin.Token)
+	assert.Equal(t, []interface{}{models.RoleModerator}, claims["roles"], "Roles claim mismatch")
+	assert.Equal(t, []interface{}{models.PermissionModerateComments}, claims["permissions"], "Permissions claim mismatch")
+
+	adminClaims := tokenClaims(t, loginForTest(t, "admin@admin.com", "Testando@Teste**").Token)
+	for _, permission := range []string{models.PermissionManageUsers, models.PermissionEditCatalog, models.PermissionModerateComments} {
+		assert.True(t, controllers.HasPermission(adminClaims, permission), "Admin should have permission %v", permission)
+	}
+
+	curatorToken := loginForTest(t, curator.Email, "testando123@Teste").Token
+	assert.False(t, controllers.HasPermission(tokenClaims(t, curatorToken), models.PermissionModerateComments), "Curator should not moderate comments")
+
+	movie, err := MovieModel.InsertMovieInDB(db, models.MovieBody{
+		Title:       "Roles Movie",
+		Director:    "Roles Director",
+		ReleaseDate: "2012-12-12",
+		CreatorId:   adminId,
+		Actors:      []models.CastingBody{{ActorId: actorResponses[0].ID.String()}},
+	})
+	if err != nil {
+		t.Fatalf("Error inserting movie: %v", err)
+	}
+
+	comment, err := CommentModel.InsertCommentInDB(db, author.ID, models.CommentBody{Comment: "Rude words", MovieId: movie.ID.String()})
+	if err != nil {
+		t.Fatalf("Error inserting comment: %v", err)
+	}
+	moderateRoute := fmt.Sprintf("/moderation/comments/%v", comment.ID)
+
+	// Curators edit the catalog but can't moderate
+	statusCode, responseBody := sendSessionRequest(t, "POST", moderateRoute, curatorToken, map[string]interface{}{"action": models.ModerationHide})
+	assert.Equal(t, 401, statusCode, "status code of moderation by a curator")
+	assertProblem(t, apierrors.PermissionDenied, responseBody, "response of moderation by a curator")
+
+	// Moderators hide comments but can't ban their authors
+	statusCode, responseBody = sendSessionRequest(t, "POST", moderateRoute, moderatorLogin.Token, map[string]interface{}{"action": models.ModerationBan})
+	assert.Equal(t, 401, statusCode, "status code of ban by a moderator")
+	assertProblem(t, apierrors.BanPermissionDenied, responseBody, "response of ban by a moderator")
+
+	statusCode, responseBody = sendSessionRequest(t, "POST", moderateRoute, moderatorLogin.Token, map[string]interface{}{"action": models.ModerationHide})
+	assert.Equal(t, 200, statusCode, "status code of hiding by a moderator: %s", responseBody)
+
+	statusCode, _ = sendSessionRequest(t, "GET", "/admin/audit-log", moderatorLogin.Token, nil)
+	assert.Equal(t, 401, statusCode, "status code of audit log for a moderator")
+
+	// Roles granted after the login apply to the token already issued and show up on the next refresh
+	if err := RoleModel.GrantRoleToUser(db, uuid.NullUUID{}, moderator.ID, models.RoleAdmin); err != nil {
+		t.Fatalf("Error granting role: %v", err)
+	}
+
+	statusCode, responseBody = sendSessionRequest(t, "GET", "/admin/audit-log", moderatorLogin.Token, nil)
+	assert.Equal(t, 200, statusCode, "status code of audit log for a promoted moderator: %s", responseBody)
+
+	statusCode, responseBody = sendSessionRequest(t, "POST", "/refresh", "", map[string]interface{}{"refreshToken": moderatorLogin.RefreshToken})
+	assert.Equal(t, 200, statusCode, "status code of refresh: %s", responseBody)
+
+	var refreshed LoginResponse
+	if err := json.Unmarshal(responseBody, &refreshed); err != nil {
+		t.Fatalf("Error unmarshalling response body: %v", err)
+	}
+	assert.True(t, controllers.HasPermission(tokenClaims(t, refreshed.Token), models.PermissionManageUsers), "Refreshed token should carry the new permissions")
+
+	user, err := UserModel.GetUserById(db, moderator.ID)
+	if err != nil {
+		t.Fatalf("Error getting user by id: %v", err)
+	}
+	assert.True(t, user.IsAdm, "Admin role should set is_adm")
+
+	userRoles, err := RoleModel.GetUserRoles(db, moderator.ID)
+	if err != nil {
+		t.Fatalf("Error getting user roles: %v", err)
+	}
+	assert.ElementsMatch(t, []string{models.RoleAdmin, models.RoleModerator}, userRoles.Roles, "Roles mismatch")
+
+	if err := RoleModel.RevokeRoleFromUser(db, uuid.NullUUID{}, moderator.ID, models.RoleAdmin); err != nil {
+		t.Fatalf("Error revoking role: %v", err)
+	}
+
+	user, err = UserModel.GetUserById(db, moderator.ID)
+	if err != nil {
+		t.Fatalf("Error getting user by id: %v", err)
+	}
+	assert.False(t, user.IsAdm, "Revoking the admin role should clear is_adm")
+}
@@ -0,0 +1,146 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/VinOfSteel/cinemagrader/apierrors"
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SearchRoutes(t *testing.T) {
+	testCases := []struct {
+		description      string
+		route            string
+		expectedCode     int
+		expectedResponse interface{}
+		testType         string
+	}{
+		{
+			description:  "GET - Searching a movie by director - Success Case",
+			route:        "/search?q=" + url.QueryEscape("inserted director 2"),
+			expectedCode: 200,
+			expectedResponse: models.SearchResult{
+				Type:  models.SearchTypeMovie,
+				ID:    movieResponses[1].ID,
+				Title: movieResponses[1].Title,
+			},
+			testType: "success",
+		},
+		{
+			description:  "GET - Searching with accents and partial words - Success Case",
+			route:        "/search?q=" + url.QueryEscape("Insértéd Dirêc 2"),
+			expectedCode: 200,
+			expectedResponse: models.SearchResult{
+				Type:  models.SearchTypeMovie,
+				ID:    movieResponses[1].ID,
+				Title: movieResponses[1].Title,
+			},
+			testType: "success",
+		},
+		{
+			description:  "GET - Searching an actor by name, filtering by type - Success Case",
+			route:        fmt.Sprintf("/search?type=actor&q=%v", url.QueryEscape(actorResponses[0].Name+" "+actorResponses[0].Surname)),
+			expectedCode: 200,
+			expectedResponse: models.SearchResult{
+				Type:  models.SearchTypeActor,
+				ID:    actorResponses[0].ID,
+				Title: actorResponses[0].Name + " " + actorResponses[0].Surname,
+			},
+			testType: "success",
+		},
+		{
+			description:  "GET - Searching something that does not exist - Success Case",
+			route:        "/search?q=zzzzzzzzzzzz",
+			expectedCode: 200,
+			testType:     "empty",
+		},
+		{
+			description:      "GET - Searching without words - Error Case",
+			route:            "/search?q=" + url.QueryEscape("!! &"),
+			expectedCode:     400,
+			expectedResponse: apierrors.InvalidSearchQuery,
+			testType:         "global-error",
+		},
+		{
+			description:      "GET - Searching with an invalid type - Error Case",
+			route:            "/search?q=movie&type=banana",
+			expectedCode:     400,
+			expectedResponse: apierrors.InvalidSearchType,
+			testType:         "global-error",
+		},
+		{
+			description:      "GET - Passing a limit that is not a number - Error Case",
+			route:            "/search?q=movie&limit=aushaushaush",
+			expectedCode:     400,
+			expectedResponse: apierrors.InvalidLimit,
+			testType:         "global-error",
+		},
+		{
+			description:      "GET - Passing a negative limit - Error Case",
+			route:            "/search?q=movie&limit=-1",
+			expectedCode:     400,
+			expectedResponse: apierrors.NegativePagination,
+			testType:         "global-error",
+		},
+		{
+			description:      "GET - Passing a negative offset - Error Case",
+			route:            "/search?q=movie&offset=-5",
+			expectedCode:     400,
+			expectedResponse: apierrors.NegativePagination,
+			testType:         "global-error",
+		},
+	}
+
+	for _, testCase := range testCases {
+		req := httptest.NewRequest("GET", testCase.route, nil)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := App.Test(req, -1)
+		if err != nil {
+			t.Fatalf("Error testing app requisition: %v", err)
+		}
+
+		// Converting body to a byte slice
+		responseBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("Error reading response body: %v", err)
+		}
+
+		// Verifying status code
+		assert.Equal(t, testCase.expectedCode, resp.StatusCode, "status code")
+
+		if testCase.testType == "success" || testCase.testType == "empty" {
+			var respPage models.Page[models.SearchResult]
+			if err := json.Unmarshal(responseBody, &respPage); err != nil {
+				t.Fatalf("Error unmarshalling response body: %v", err)
+			}
+			respSlice := respPage.Data
+
+			assert.GreaterOrEqual(t, respPage.Pagination.Total, len(respSlice), "Total should count every result")
+
+			if testCase.testType == "empty" {
+				assert.Empty(t, respSlice, "Search should not return results")
+				continue
+			}
+
+			if assert.NotEmpty(t, respSlice, "Search should return results") {
+				expected := testCase.expectedResponse.(models.SearchResult)
+
+				assert.Equal(t, expected.Type, respSlice[0].Type, "Type mismatch on best ranked result")
+				assert.Equal(t, expected.ID, respSlice[0].ID, "ID mismatch on best ranked result")
+				assert.Equal(t, expected.Title, respSlice[0].Title, "Title mismatch on best ranked result")
+				assert.Greater(t, respSlice[0].Rank, 0.0, "Rank should be positive")
+			}
+		}
+
+		if testCase.testType == "global-error" {
+			assertProblem(t, testCase.expectedResponse.(*apierrors.Error), responseBody)
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/VinOfSteel/cinemagrader/apierrors"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -20,8 +21,57 @@ type LoginBody struct {
 }
 
 type LoginResponse struct {
-	UserID uuid.UUID `json:"userId"`
-	Token  string    `json:"token"`
+	UserID       uuid.UUID `json:"userId"`
+	Token        string    `json:"token"`
+	RefreshToken string    `json:"refreshToken"`
+}
+
+// sendSessionRequest sends a JSON request to the test app with an optional bearer token
+func sendSessionRequest(t *testing.T, method, route, token string, data map[string]interface{}) (int, []byte) {
+	var body io.Reader
+	if data != nil {
+		jsonData, err := json.Marshal(data)
+		if err != nil {
+			t.Fatalf("Error marshalling JSON data: %v", err)
+		}
+		body = bytes.NewBuffer(jsonData)
+	}
+
+	req := httptest.NewRequest(method, route, body)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := App.Test(req, -1)
+	if err != nil {
+		t.Fatalf("Error testing app requisition: %v", err)
+	}
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Error reading response body: %v", err)
+	}
+
+	return resp.StatusCode, responseBody
+}
+
+func loginForTest(t *testing.T, email, password string) LoginResponse {
+	statusCode, responseBody := sendSessionRequest(t, "POST", "/login", "", map[string]interface{}{
+		"email":    email,
+		"password": password,
+	})
+
+	if statusCode != 200 {
+		t.Fatalf("Error logging in with %s in tests, got status %v: %s", email, statusCode, string(responseBody))
+	}
+
+	var loginResponse LoginResponse
+	if err := json.Unmarshal(responseBody, &loginResponse); err != nil {
+		t.Fatalf("Error unmarshalling response body: %v", err)
+	}
+
+	return loginResponse
 }
 
 func Test_SessionsRoutes(t *testing.T) {
@@ -55,11 +105,9 @@ func Test_SessionsRoutes(t *testing.T) {
 				"email":    "teste1@teste1.com",
 				"password": "EuGostode123@@",
 			},
-			expectedCode: 400,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "Invalid email/password",
-			},
-			testType: "global-error",
+			expectedCode:     400,
+			expectedResponse: apierrors.InvalidCredentials,
+			testType:         "global-error",
 		},
 		{
 			description: "POST - Login with non-existant email in DB - Error Case",
@@ -69,11 +117,9 @@ func Test_SessionsRoutes(t *testing.T) {
 				"email":    "batatinha@tsdasde1.com",
 				"password": "EuGostode123@@",
 			},
-			expectedCode: 400,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "Invalid email/password",
-			},
-			testType: "global-error",
+			expectedCode:     400,
+			expectedResponse: apierrors.InvalidCredentials,
+			testType:         "global-error",
 		},
 	}
 
@@ -137,7 +183,62 @@ func Test_SessionsRoutes(t *testing.T) {
 		}
 
 		if testCase.testType == "global-error" {
-			assert.Equal(t, testCase.expectedResponse.(GlobalErrorHandlerResp).Message, string(responseBody))
+			assertProblem(t, testCase.expectedResponse.(*apierrors.Error), responseBody)
 		}
 	}
 }
+
+func Test_SessionsRefreshAndLogout(t *testing.T) {
+	firstLogin := loginForTest(t, "admin@admin.com", "Testando@Teste**")
+	assert.NotEmpty(t, firstLogin.RefreshToken, "Login route must return a refresh token")
+
+	// Refreshing rotates the refresh token
+	statusCode, responseBody := sendSessionRequest(t, "POST", "/refresh", "", map[string]interface{}{
+		"refreshToken": firstLogin.RefreshToken,
+	})
+	assert.Equal(t, 200, statusCode, "status code when refreshing")
+
+	var refreshed LoginResponse
+	if err := json.Unmarshal(responseBody, &refreshed); err != nil {
+		t.Fatalf("Error unmarshalling response body: %v", err)
+	}
+	assert.Equal(t, firstLogin.UserID, refreshed.UserID, "Refreshed token must belong to the same user")
+	assert.NotEmpty(t, refreshed.Token, "Refresh route must return a new access token")
+	assert.NotEqual(t, firstLogin.RefreshToken, refreshed.RefreshToken, "Refresh token must be rotated")
+
+	// An already used refresh token can't be exchanged again
+	statusCode, responseBody = sendSessionRequest(t, "POST", "/refresh", "", map[string]interface{}{
+		"refreshToken": firstLogin.RefreshToken,
+	})
+	assert.Equal(t, 401, statusCode, "status code when reusing a refresh token")
+	assertProblem(t, apierrors.InvalidRefreshToken, responseBody)
+
+	// Logging out revokes the session of the access token
+	statusCode, _ = sendSessionRequest(t, "POST", "/logout", refreshed.Token, nil)
+	assert.Equal(t, 204, statusCode, "status code when logging out")
+
+	statusCode, responseBody = sendSessionRequest(t, "POST", "/logout", refreshed.Token, nil)
+	assert.Equal(t, 401, statusCode, "status code when using a token of a revoked session")
+	assertProblem(t, apierrors.SessionRevoked, responseBody)
+
+	statusCode, _ = sendSessionRequest(t, "POST", "/refresh", "", map[string]interface{}{
+		"refreshToken": refreshed.RefreshToken,
+	})
+	assert.Equal(t, 401, statusCode, "status code when refreshing a revoked session")
+
+	// Logging out of every session revokes the other ones as well
+	secondLogin := loginForTest(t, "admin@admin.com", "Testando@Teste**")
+	thirdLogin := loginForTest(t, "admin@admin.com", "Testando@Teste**")
+
+	statusCode, _ = sendSessionRequest(t, "POST", "/logout-all", secondLogin.Token, nil)
+	assert.Equal(t, 204, statusCode, "status code when logging out of all sessions")
+
+	statusCode, responseBody = sendSessionRequest(t, "POST", "/logout", thirdLogin.Token, nil)
+	assert.Equal(t, 401, statusCode, "status code when using a token revoked by logout-all")
+	assertProblem(t, apierrors.SessionRevoked, responseBody)
+
+	// Requests without a token are refused
+	statusCode, responseBody = sendSessionRequest(t, "POST", "/logout", "", nil)
+	assert.Equal(t, 401, statusCode, "status code when logging out without a token")
+	assertProblem(t, apierrors.MissingAuthHeader, responseBody)
+}
@@ -0,0 +1,185 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/VinOfSteel/cinemagrader/apierrors"
+	"github.com/VinOfSteel/cinemagrader/initializers"
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func commentTexts(comments []models.CommentResponse) map[uuid.UUID]string {
+	texts := map[uuid.UUID]string{}
+	for _, comment := range comments {
+		texts[comment.ID] = comment.Comment
+	}
+
+	return texts
+}
+
+func activityTexts(activity []models.ActivityResponse) map[uuid.UUID]string {
+	texts := map[uuid.UUID]string{}
+	for _, item := range activity {
+		texts[item.CommentId.UUID] = item.Comment
+	}
+
+	return texts
+}
+
+func Test_SpoilerRedaction(t *testing.T) {
+	db := initializers.NewDatabaseConn()
+	defer db.Close()
+
+	users := map[string]models.UserResponse{}
+	for _, user := range InsertMockedUsersInDB(db, []models.UserBody{
+		{Name: "Spoiler", Surname: "Writer", Email: "spoilerwriter@teste.com", Password: "testando123@Teste", Birthday: "1990-01-01"},
+		{Name: "Spoiler", Surname: "Reader", Email: "spoilerreader@teste.com", Password: "testando123@Teste", Birthday: "1990-01-01"},
+	}) {
+		users[user.Surname] = user
+	}
+	writer, reader := users["Writer"], users["Reader"]
+	writerToken := loginForTest(t, writer.Email, "testando123@Teste").Token
+	readerToken := loginForTest(t, reader.Email, "testando123@Teste").Token
+	adminToken := loginForTest(t, "admin@admin.com", "Testando@Teste**").Token
+
+	movie, err := MovieModel.InsertMovieInDB(db, models.MovieBody{
+		Title:       "Spoiler Movie",
+		Director:    "Spoiler Director",
+		ReleaseDate: "2012-12-12",
+		CreatorId:   adminId,
+		Actors:      []models.CastingBody{{ActorId: actorResponses[0].ID.String()}},
+	})
+	if err != nil {
+		t.Fatalf("Error inserting movie: %v", err)
+	}
+
+	inline, err := CommentModel.InsertCommentInDB(db, writer.ID, models.CommentBody{Comment: "Loved it, ||the butler did it||", Grade: 5, MovieId: movie.ID.String()})
+	if err != nil {
+		t.Fatalf("Error inserting comment: %v", err)
+	}
+	flagged, err := CommentModel.InsertCommentInDB(db, writer.ID, models.CommentBody{Comment: "The whole third act", ContainsSpoilers: true, MovieId: movie.ID.String()})
+	if err != nil {
+		t.Fatalf("Error inserting comment: %v", err)
+	}
+	clean, err := CommentModel.InsertCommentInDB(db, writer.ID, models.CommentBody{Comment: "Nice soundtrack", MovieId: movie.ID.String()})
+	if err != nil {
+		t.Fatalf("Error inserting comment: %v", err)
+	}
+	assert.True(t, flagged.ContainsSpoilers, "Comment should be flagged as a spoiler")
+
+	commentsRoute := fmt.Sprintf("/movies/%v/comments", movie.ID)
+	redacted := map[uuid.UUID]string{
+		inline.ID:  "Loved it, [spoiler]",
+		flagged.ID: models.SpoilerPlaceholder,
+		clean.ID:   "Nice soundtrack",
+	}
+	revealed := map[uuid.UUID]string{
+		inline.ID:  "Loved it, ||the butler did it||",
+		flagged.ID: "The whole third act",
+		clean.ID:   "Nice soundtrack",
+	}
+
+	// Anonymous callers and users that didn't review the movie get the spoilers redacted
+	comments := getMovieComments(t, commentsRoute, "")
+	assert.Equal(t, redacted, commentTexts(comments), "Anonymous caller should get redacted comments")
+	for _, comment := range comments {
+		assert.Equal(t, comment.ID != clean.ID, comment.SpoilersRedacted, "Redacted mark mismatch of comment %v", comment.ID)
+	}
+
+	assert.Equal(t, redacted, commentTexts(getMovieComments(t, commentsRoute, readerToken)), "User without review should get redacted comments")
+
+	// Asking for the spoilers, writing the comments or having reviewed the movie reveals them
+	assert.Equal(t, revealed, commentTexts(getMovieComments(t, commentsRoute+"?reveal_spoilers=true", "")), "Spoilers should be revealed on request")
+	assert.Equal(t, revealed, commentTexts(getMovieComments(t, commentsRoute, writerToken)), "Author should see their own comments")
+
+	// A single comment and the feed follow the same rule
+	getComment := func(t *testing.T, route string, token string) models.CommentResponse {
+		statusCode, responseBody := sendSessionRequest(t, "GET", route, token, nil)
+		if statusCode != 200 {
+			t.Fatalf("Unexpected status code %v getting comment: %s", statusCode, responseBody)
+		}
+
+		var comment models.CommentResponse
+		if err := json.Unmarshal(responseBody, &comment); err != nil {
+			t.Fatalf("Error unmarshalling response body: %v", err)
+		}
+
+		return comment
+	}
+	getFeed := func(t *testing.T, route string) []models.ActivityResponse {
+		statusCode, responseBody := sendSessionRequest(t, "GET", route, readerToken, nil)
+		if statusCode != 200 {
+			t.Fatalf("Unexpected status code %v getting feed: %s", statusCode, responseBody)
+		}
+
+		var feed models.Page[models.ActivityResponse]
+		if err := json.Unmarshal(responseBody, &feed); err != nil {
+			t.Fatalf("Error unmarshalling response body: %v", err)
+		}
+
+		return feed.Data
+	}
+
+	inlineRoute := fmt.Sprintf("/comments/%v", inline.ID)
+	redactedComment := getComment(t, inlineRoute, readerToken)
+	assert.Equal(t, redacted[inline.ID], redactedComment.Comment, "User without review should get the comment redacted")
+	assert.True(t, redactedComment.SpoilersRedacted, "Redacted comment should be marked as redacted")
+	assert.Equal(t, redacted[inline.ID], getComment(t, inlineRoute, "").Comment, "Anonymous caller should get the comment redacted")
+	assert.Equal(t, revealed[inline.ID], getComment(t, inlineRoute+"?reveal_spoilers=true", "").Comment, "Spoilers of the comment should be revealed on request")
+	assert.Equal(t, revealed[inline.ID], getComment(t, inlineRoute, writerToken).Comment, "Author should see their own comment")
+
+	statusCode, _ := sendSessionRequest(t, "POST", fmt.Sprintf("/users/%v/follow", writer.ID), readerToken, nil)
+	assert.Equal(t, 204, statusCode, "status code of follow")
+
+	feed := getFeed(t, "/feed")
+	assert.Equal(t, redacted, activityTexts(feed), "User without review should get the feed redacted")
+	for _, item := range feed {
+		assert.Equal(t, item.CommentId.UUID != clean.ID, item.SpoilersRedacted, "Redacted mark mismatch of activity %v", item.ID)
+	}
+	assert.Equal(t, revealed, activityTexts(getFeed(t, "/feed?reveal_spoilers=true")), "Spoilers of the feed should be revealed on request")
+
+	statusCode, responseBody := sendSessionRequest(t, "PUT", fmt.Sprintf("/movies/%v/review", movie.ID), readerToken, map[string]interface{}{"comment": "Saw it", "grade": 4})
+	assert.Equal(t, 201, statusCode, "status code of review creation: %s", responseBody)
+
+	var review models.CommentResponse
+	if err := json.Unmarshal(responseBody, &review); err != nil {
+		t.Fatalf("Error unmarshalling response body: %v", err)
+	}
+
+	readerComments := commentTexts(getMovieComments(t, commentsRoute, readerToken))
+	delete(readerComments, review.ID)
+	assert.Equal(t, revealed, readerComments, "User that reviewed the movie should see the spoilers")
+	assert.Equal(t, revealed[inline.ID], getComment(t, inlineRoute, readerToken).Comment, "User that reviewed the movie should see the spoilers of the comment")
+	assert.Equal(t, revealed, activityTexts(getFeed(t, "/feed")), "User that reviewed the movie should see the spoilers of the feed")
+
+	// Admins flag existing comments as spoilers
+	spoilerRoute := fmt.Sprintf("/comments/%v/spoiler", clean.ID)
+	statusCode, _ = sendSessionRequest(t, "POST", spoilerRoute, adminToken, nil)
+	assert.Equal(t, 200, statusCode, "status code of spoiler flag")
+
+	assert.Equal(t, models.SpoilerPlaceholder, commentTexts(getMovieComments(t, commentsRoute, ""))[clean.ID], "Flagged comment should be redacted")
+
+	statusCode, _ = sendSessionRequest(t, "DELETE", spoilerRoute, adminToken, nil)
+	assert.Equal(t, 200, statusCode, "status code of spoiler unflag")
+
+	assert.Equal(t, "Nice soundtrack", commentTexts(getMovieComments(t, commentsRoute, ""))[clean.ID], "Unflagged comment should not be redacted")
+
+	// Error cases
+	statusCode, responseBody = sendSessionRequest(t, "GET", commentsRoute+"?reveal_spoilers=maybe", "", nil)
+	assert.Equal(t, 400, statusCode, "status code of invalid reveal_spoilers param")
+	assertProblem(t, apierrors.InvalidQueryParam("reveal_spoilers", apierrors.ValidBool), responseBody, "response of invalid reveal_spoilers param")
+
+	statusCode, _ = sendSessionRequest(t, "GET", commentsRoute, "invalidtoken", nil)
+	assert.Equal(t, 401, statusCode, "status code of invalid token")
+
+	statusCode, _ = sendSessionRequest(t, "POST", spoilerRoute, readerToken, nil)
+	assert.Equal(t, 401, statusCode, "status code of spoiler flag by a user that is not a moderator")
+
+	statusCode, responseBody = sendSessionRequest(t, "POST", fmt.Sprintf("/comments/%v/spoiler", uuid.New()), adminToken, nil)
+	assert.Equal(t, 404, statusCode, "status code of flagging a comment that does not exist")
+	assertProblem(t, apierrors.CommentNotFound, responseBody, "response of flagging a comment that does not exist")
+}
@@ -21,10 +21,8 @@ var UserModel models.UserModel
 var ActorModel models.ActorModel
 var MovieModel models.MovieModel
 var CommentModel models.CommentModel
-
-type GlobalErrorHandlerResp struct {
-	Message string `json:"message"`
-}
+var GenreModel models.GenreModel
+var RoleModel models.RoleModel
 
 func Setup() (string, error) {
 	// Initializing env variables
@@ -172,12 +170,13 @@ func InsertMockedActorsInDB(db *sql.DB, actors []models.ActorBody) []models.Acto
 	return output
 }
 
-func getActorsOfAMovie(db *sql.DB, movieID uuid.UUID) ([]models.ActorResponse, error) {
+func getActorsOfAMovie(db *sql.DB, movieID uuid.UUID) ([]models.CastingResponse, error) {
 	query := `SELECT 
-        a.id, a.name, a.surname, a.birthday, a.created_at, a.updated_at, a.deleted_at, a.creator_id 
+        a.id, a.name, a.surname, a.birthday, a.created_at, a.updated_at, a.deleted_at, a.creator_id, ma.character_name, ma.billing_order 
         FROM actors a
         	JOIN movies_actors ma ON a.id = ma.actor_id
-        		WHERE ma.movie_id = $1;`
+        		WHERE ma.movie_id = $1
+        			ORDER BY ma.billing_order ASC;`
 
 	rows, err := db.Query(query, movieID)
 	if err != nil {
@@ -185,10 +184,10 @@ func getActorsOfAMovie(db *sql.DB, movieID uuid.UUID) ([]models.ActorResponse, e
 	}
 	defer rows.Close()
 
-	var actors []models.ActorResponse
+	var actors []models.CastingResponse
 	for rows.Next() {
-		var actor models.ActorResponse
-		if err := rows.Scan(&actor.ID, &actor.Name, &actor.Surname, &actor.Birthday, &actor.CreatedAt, &actor.UpdatedAt, &actor.DeletedAt, &actor.CreatorId); err != nil {
+		var actor models.CastingResponse
+		if err := rows.Scan(&actor.ID, &actor.Name, &actor.Surname, &actor.Birthday, &actor.CreatedAt, &actor.UpdatedAt, &actor.DeletedAt, &actor.CreatorId, &actor.CharacterName, &actor.BillingOrder); err != nil {
 			return nil, err
 		}
 		actors = append(actors, actor)
@@ -239,20 +238,36 @@ func InsertMockedMoviesInDB(db *sql.DB, movies []models.MovieBody) []models.Movi
 	return responses
 }
 
-func InsertMockedCommentsInDB(db *sql.DB, comments []models.CommentBody, userId string) []models.CommentResponse {
+func InsertMockedGenresInDB(db *sql.DB, genres []models.GenreBody) []models.GenreResponse {
+	// Inserted one by one so the responses keep the order of the bodies
+	var output []models.GenreResponse
+	for _, genre := range genres {
+		genreResponse, err := GenreModel.InsertGenreInDB(db, genre)
+		if err != nil {
+			log.Fatalf("Error inserting mocked genre with name %v in Db: %v", genre.Name, err)
+		}
+		output = append(output, genreResponse)
+	}
+
+	return output
+}
+
+// Each comment is inserted by the user with the same index in userIds, since users can only review a movie once
+func InsertMockedCommentsInDB(db *sql.DB, comments []models.CommentBody, userIds []string) []models.CommentResponse {
 	var wg sync.WaitGroup
 	var respChan = make(chan models.CommentResponse, len(comments))
-	parsedId, err := uuid.Parse(userId)
-	if err != nil {
-		log.Fatalf("Error parsing userId %v to uuid in InsertMockedCommentsInDB: %v", userId, err)
-	}
 
-	for _, comment := range comments {
+	for i, comment := range comments {
+		parsedId, err := uuid.Parse(userIds[i])
+		if err != nil {
+			log.Fatalf("Error parsing userId %v to uuid in InsertMockedCommentsInDB: %v", userIds[i], err)
+		}
+
 		wg.Add(1)
 		go func(c models.CommentBody, id uuid.UUID) {
 			defer wg.Done()
 
-			commentResponse, err := CommentModel.InsertCommentInDB(db, parsedId, c)
+			commentResponse, err := CommentModel.InsertCommentInDB(db, id, c)
 			if err != nil {
 				log.Fatalf("Error inserting mocked comment with in Db: %v", err)
 			}
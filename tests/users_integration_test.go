@@ -13,10 +13,13 @@ import (
 	"testing"
 	"time"
 
-	"github.com/VinOfSteel/cinemagrader/controllers"
+	"github.com/VinOfSteel/cinemagrader/apierrors"
 	"github.com/VinOfSteel/cinemagrader/initializers"
 	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/VinOfSteel/cinemagrader/moderation"
+	"github.com/VinOfSteel/cinemagrader/server"
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
@@ -25,6 +28,7 @@ var userResponses []models.UserResponse
 var actorResponses []models.ActorResponse
 var movieResponses []models.MovieResponseWithActors
 var commentResponses []models.CommentResponse
+var genreResponses []models.GenreResponse
 var adminId string
 
 func TestMain(m *testing.M) {
@@ -36,9 +40,9 @@ func TestMain(m *testing.M) {
 
 	os.Setenv("PGDATABASE", TestDb)
 
-	validate := initializers.NewValidator()
 	db := initializers.NewDatabaseConn()
 	defer db.Close()
+	validate := initializers.NewValidator(db)
 
 	// God, forgive me for what I'm about to do.
 	// Inserting mocked users in DB for test
@@ -130,35 +134,35 @@ func TestMain(m *testing.M) {
 			Director:    "Inserted Director 1",
 			ReleaseDate: "1999-01-01",
 			CreatorId:   adminId,
-			Actors:      []string{actorResponses[1].ID.String(), actorResponses[2].ID.String()},
+			Actors:      []models.CastingBody{{ActorId: actorResponses[1].ID.String()}, {ActorId: actorResponses[2].ID.String()}},
 		},
 		{
 			Title:       "Inserted Movie 2",
 			Director:    "Inserted Director 2",
 			ReleaseDate: "1999-01-01",
 			CreatorId:   adminId,
-			Actors:      []string{actorResponses[0].ID.String()},
+			Actors:      []models.CastingBody{{ActorId: actorResponses[0].ID.String()}},
 		},
 		{
 			Title:       "Inserted Movie 3",
 			Director:    "Inserted Director 3",
 			ReleaseDate: "1999-01-01",
 			CreatorId:   adminId,
-			Actors:      []string{actorResponses[2].ID.String(), actorResponses[3].ID.String()},
+			Actors:      []models.CastingBody{{ActorId: actorResponses[2].ID.String()}, {ActorId: actorResponses[3].ID.String()}},
 		},
 		{
 			Title:       "Inserted Movie 4",
 			Director:    "Inserted Director 4",
 			ReleaseDate: "1999-01-01",
 			CreatorId:   adminId,
-			Actors:      []string{actorResponses[2].ID.String(), actorResponses[3].ID.String()},
+			Actors:      []models.CastingBody{{ActorId: actorResponses[2].ID.String()}, {ActorId: actorResponses[3].ID.String()}},
 		},
 		{
 			Title:       "Inserted Movie 5",
 			Director:    "Inserted Director 5",
 			ReleaseDate: "1999-01-01",
 			CreatorId:   adminId,
-			Actors:      []string{actorResponses[2].ID.String(), actorResponses[3].ID.String()},
+			Actors:      []models.CastingBody{{ActorId: actorResponses[2].ID.String()}, {ActorId: actorResponses[3].ID.String()}},
 		},
 	}
 	movieResponses = InsertMockedMoviesInDB(db, moviesToBeInsertedInDB)
@@ -190,70 +194,36 @@ func TestMain(m *testing.M) {
 			MovieId: movieResponses[0].ID.String(),
 		},
 	}
-	commentResponses = InsertMockedCommentsInDB(db, commentsToBeInsertedInDB, adminId)
+	commentsAuthors := []string{adminId, userResponses[0].ID.String(), userResponses[1].ID.String(), userResponses[2].ID.String(), userResponses[3].ID.String()}
+	commentResponses = InsertMockedCommentsInDB(db, commentsToBeInsertedInDB, commentsAuthors)
 
-	App = fiber.New()
-
-	userController := controllers.User{
-		DB:       db,
-		Validate: validate,
-	}
-
-	sessionController := controllers.Session{
-		DB:       db,
-		Validate: validate,
-	}
-
-	actorController := controllers.Actor{
-		DB:       db,
-		Validate: validate,
-	}
-
-	movieController := controllers.Movie{
-		DB:       db,
-		Validate: validate,
+	genresToBeInsertedInDB := []models.GenreBody{
+		{
+			Name:      "Inserted Genre 1",
+			CreatorId: adminId,
+		},
+		{
+			Name:      "Inserted Genre 2",
+			CreatorId: adminId,
+		},
+		{
+			Name:      "Inserted Genre 3",
+			CreatorId: adminId,
+		},
 	}
+	genreResponses = InsertMockedGenresInDB(db, genresToBeInsertedInDB)
 
-	commentController := controllers.Comment{
-		DB:       db,
-		Validate: validate,
+	// Comments with these words are held for moderation in the tests
+	blocklist, err := moderation.NewBlocklist([]string{"forbiddenword", "/sp[o0]iler/"})
+	if err != nil {
+		log.Fatalf("Error creating moderation blocklist: %v", err)
 	}
 
-	// Routes - Session
-	App.Post("/login", sessionController.HandleLogin)
-
-	// Routes - User
-	App.Post("/users", userController.CreateUser)
-	App.Get("/users", userController.ListAllUsersInDB)
-	App.Get("/users/:uuid", userController.GetUser)
-	App.Get("/users/:uuid/comments", userController.GetUserComments)
-	App.Delete("/users/:uuid", userController.DeleteUser)
-	App.Patch("/users/:uuid", userController.UpdateUser)
-
-	// Routes - Actor
-	App.Post("/actors", actorController.CreateActor)
-	App.Get("/actors", actorController.ListAllActorsInDB)
-	App.Get("/actors/:uuid", actorController.GetActor)
-	App.Get("/actors/:uuid/movies", actorController.GetActorMovies)
-	App.Delete("/actors/:uuid", actorController.DeleteActor)
-	App.Patch("/actors/:uuid", actorController.UpdateActor)
-
-	// Routes - Movie
-	App.Post("/movies", movieController.CreateMovie)
-	App.Post("/movies/:uuid/actors", movieController.CreateActorsRelationshipsWithMovie)
-	App.Get("/movies", movieController.ListAllMoviesInDB)
-	App.Get("/movies/:uuid", movieController.GetMovie)
-	App.Get("/movies/:uuid/comments", movieController.GetMovieComments)
-	App.Delete("/movies/:uuid", movieController.DeleteMovie)
-	App.Delete("/movies/:uuid/actors", movieController.DeleteActorsRelationshipsWithMovie)
-	App.Patch("/movies/:uuid", movieController.UpdateMovie)
-
-	// Routes - Comments
-	App.Post("/comments/:uuid", commentController.CreateComment)
-	App.Get("/comments", commentController.ListAllCommentsInDb)
-	App.Get("/comments/:uuid", commentController.GetComment)
-	App.Delete("/comments/:uuid", commentController.DeleteComment)
-	App.Patch("/comments/:uuid", commentController.UpdateComment)
+	// The app is the one of the server, with its error handler and the routes with their guards, so tests need the token of a
+	// user allowed in them and get errors as problems
+	App = fiber.New(server.Config())
+	App.Use(requestid.New())
+	server.RegisterRoutes(App, db, validate, blocklist)
 
 	// Run tests
 	exitCode := m.Run()
@@ -268,10 +238,14 @@ func TestMain(m *testing.M) {
 
 // No need to test for validation errors because the validation function is already unit tested elsewhere
 func Test_UsersRoutes(t *testing.T) {
+	// Every user route but the creation is guarded, and admins can act on any user
+	adminToken := loginForTest(t, "admin@admin.com", "Testando@Teste**").Token
+
 	testCases := []struct {
 		description      string
 		route            string
 		method           string
+		token            string
 		data             map[string]interface{}
 		expectedCode     int
 		expectedResponse interface{}
@@ -311,17 +285,16 @@ func Test_UsersRoutes(t *testing.T) {
 				"password": "testando123@Teste",
 				"birthday": "1999-10-10",
 			},
-			expectedCode: 400,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "User with this email already exists",
-			},
-			testType: "global-error",
+			expectedCode:     400,
+			expectedResponse: apierrors.UserEmailTaken,
+			testType:         "global-error",
 		},
 		// Get requests
 		{
 			description:  "GET - All users with basic query params - Success Case", // We don't do gigantic offsets and limits to not need to mock 10 things
 			route:        "/users?offset=1&limit=3&sort=name,asc",
 			method:       "GET",
+			token:        adminToken,
 			expectedCode: 200,
 			expectedResponse: []models.UserResponse{
 				{
@@ -350,82 +323,80 @@ func Test_UsersRoutes(t *testing.T) {
 			testType:     "success",
 		},
 		{
-			description:  "GET - Passing an offset that is not a number - Error Case",
-			route:        "/users?offset=2.254",
-			method:       "GET",
-			expectedCode: 400,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "Offset needs to be a valid integer",
-			},
-			responseType: "slice",
-			testType:     "global-error",
+			description:      "GET - Passing an offset that is not a number - Error Case",
+			route:            "/users?offset=2.254",
+			method:           "GET",
+			token:            adminToken,
+			expectedCode:     400,
+			expectedResponse: apierrors.InvalidOffset,
+			responseType:     "slice",
+			testType:         "global-error",
 		},
 		{
-			description:  "GET - Passing a limit that is not a number - Error Case",
-			route:        "/users?limit=aushaushaush",
-			method:       "GET",
-			expectedCode: 400,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "Limit needs to be a valid integer",
-			},
-			responseType: "slice",
-			testType:     "global-error",
+			description:      "GET - Passing a limit that is not a number - Error Case",
+			route:            "/users?limit=aushaushaush",
+			method:           "GET",
+			token:            adminToken,
+			expectedCode:     400,
+			expectedResponse: apierrors.InvalidLimit,
+			responseType:     "slice",
+			testType:         "global-error",
 		}, // Since sort casts every non-valid value to a default valid one, it does not need to be tested, as any error case will fall into the updated_at DESC clause.
 		{
 			description:      "GET BY ID - Passing an uuid that exists in DB - Success Case",
 			route:            fmt.Sprintf("/users/%v", userResponses[1].ID),
 			method:           "GET",
+			token:            adminToken,
 			expectedCode:     200,
 			expectedResponse: userResponses[1],
 			responseType:     "struct",
 			testType:         "success",
 		},
 		{
-			description:  "GET BY ID - Passing an uuid that does not exist in DB - Error Case",
-			route:        fmt.Sprintf("/users/%v", uuid.New()),
-			method:       "GET",
-			expectedCode: 404,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "User id not found in database",
-			},
-			responseType: "struct",
-			testType:     "global-error",
+			description:      "GET BY ID - Passing an uuid that does not exist in DB - Error Case",
+			route:            fmt.Sprintf("/users/%v", uuid.New()),
+			method:           "GET",
+			token:            adminToken,
+			expectedCode:     404,
+			expectedResponse: apierrors.UserNotFound,
+			responseType:     "struct",
+			testType:         "global-error",
 		},
 		{
-			description:  "GET BY ID - Passing an invalid uuid - Error Case",
-			route:        "/users/as9du9u192ejs",
-			method:       "GET",
-			expectedCode: 400,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "Invalid uuid parameter",
-			},
-			responseType: "struct",
-			testType:     "global-error",
+			description:      "GET BY ID - Passing an invalid uuid - Error Case",
+			route:            "/users/as9du9u192ejs",
+			method:           "GET",
+			token:            adminToken,
+			expectedCode:     400,
+			expectedResponse: apierrors.InvalidUUID,
+			responseType:     "struct",
+			testType:         "global-error",
 		},
 		// Delete requests
 		{
 			description:      "DELETE BY ID - Passing an uuid that exists in DB - Success Case",
 			route:            fmt.Sprintf("/users/%v", userResponses[2].ID),
 			method:           "DELETE",
+			token:            adminToken,
 			expectedCode:     204,
 			expectedResponse: userResponses[2],
 			testType:         "delete",
 		},
 		{
-			description:  "DELETE BY ID - Passing an uuid that does not exist in DB - Error Case",
-			route:        fmt.Sprintf("/users/%v", "aushauhsuahsaushuha"),
-			method:       "DELETE",
-			expectedCode: 400,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "Invalid uuid parameter",
-			},
-			testType: "global-error",
+			description:      "DELETE BY ID - Passing an uuid that does not exist in DB - Error Case",
+			route:            fmt.Sprintf("/users/%v", "aushauhsuahsaushuha"),
+			method:           "DELETE",
+			token:            adminToken,
+			expectedCode:     400,
+			expectedResponse: apierrors.InvalidUUID,
+			testType:         "global-error",
 		},
 		// Update requests
 		{
 			description: "UPDATE - Update user info (all keys) - Success Case",
 			route:       fmt.Sprintf("/users/%v", userResponses[3].ID),
 			method:      "PATCH",
+			token:       adminToken,
 			data: map[string]interface{}{
 				"name":     "New name",
 				"surname":  "New surname",
@@ -445,15 +416,14 @@ func Test_UsersRoutes(t *testing.T) {
 			testType: "update",
 		},
 		{
-			description:  "UPDATE - Passing an invalid uuid - Error Case",
-			route:        "/users/as9du9u192ejs",
-			method:       "PATCH",
-			expectedCode: 400,
-			expectedResponse: GlobalErrorHandlerResp{
-				Message: "Invalid uuid parameter",
-			},
-			responseType: "struct",
-			testType:     "global-error",
+			description:      "UPDATE - Passing an invalid uuid - Error Case",
+			route:            "/users/as9du9u192ejs",
+			method:           "PATCH",
+			token:            adminToken,
+			expectedCode:     400,
+			expectedResponse: apierrors.InvalidUUID,
+			responseType:     "struct",
+			testType:         "global-error",
 		},
 	}
 
@@ -478,6 +448,9 @@ func Test_UsersRoutes(t *testing.T) {
 		}
 
 		req.Header.Set("Content-Type", "application/json")
+		if testCase.token != "" {
+			req.Header.Set("Authorization", "Bearer "+testCase.token)
+		}
 
 		resp, err := App.Test(req, -1)
 		if err != nil {
@@ -499,9 +472,12 @@ func Test_UsersRoutes(t *testing.T) {
 			var respSlice []models.UserResponse
 
 			if testCase.responseType == "slice" {
-				if err := json.Unmarshal(responseBody, &respSlice); err != nil {
+				// List routes answer with the pagination envelope
+				var respPage models.Page[models.UserResponse]
+				if err := json.Unmarshal(responseBody, &respPage); err != nil {
 					t.Fatalf("Error unmarshalling response body: %v", err)
 				}
+				respSlice = respPage.Data
 			} else {
 				if err := json.Unmarshal(responseBody, &respStruct); err != nil {
 					t.Fatalf("Error unmarshalling response body: %v", err)
@@ -570,7 +546,7 @@ func Test_UsersRoutes(t *testing.T) {
 		}
 
 		if testCase.testType == "global-error" {
-			assert.Equal(t, testCase.expectedResponse.(GlobalErrorHandlerResp).Message, string(responseBody))
+			assertProblem(t, testCase.expectedResponse.(*apierrors.Error), responseBody)
 		}
 
 		if testCase.testType == "delete" {
@@ -0,0 +1,232 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/VinOfSteel/cinemagrader/apierrors"
+	"github.com/VinOfSteel/cinemagrader/initializers"
+	"github.com/VinOfSteel/cinemagrader/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WatchlistAndDiaryRoutes(t *testing.T) {
+	db := initializers.NewDatabaseConn()
+	defer db.Close()
+
+	viewer := InsertMockedUsersInDB(db, []models.UserBody{{
+		Name:     "Diary",
+		Surname:  "Keeper",
+		Email:    "diary@teste.com",
+		Password: "testando123@Teste",
+		Birthday: "1995-05-05",
+	}})[0]
+	viewerToken := loginForTest(t, viewer.Email, "testando123@Teste").Token
+	adminToken := loginForTest(t, "admin@admin.com", "Testando@Teste**").Token
+
+	insertMovie := func(t *testing.T, title string) models.MovieResponseWithActors {
+		movie, err := MovieModel.InsertMovieInDB(db, models.MovieBody{
+			Title:       title,
+			Director:    "Diary Director",
+			ReleaseDate: "2010-10-10",
+			CreatorId:   adminId,
+			Actors:      []models.CastingBody{{ActorId: actorResponses[0].ID.String()}},
+		})
+		if err != nil {
+			t.Fatalf("Error inserting movie %v: %v", title, err)
+		}
+
+		return movie
+	}
+
+	first := insertMovie(t, "A Diary Movie")
+	second := insertMovie(t, "B Diary Movie")
+
+	watchlistRoute := fmt.Sprintf("/users/%v/watchlist", viewer.ID)
+	diaryRoute := fmt.Sprintf("/users/%v/diary", viewer.ID)
+
+	// Watchlist
+	for _, movie := range []models.MovieResponseWithActors{second, first} {
+		statusCode, responseBody := sendSessionRequest(t, "POST", watchlistRoute, viewerToken, map[string]interface{}{"movieId": movie.ID.String(), "note": "Recommended"})
+		assert.Equal(t, 201, statusCode, "status code of watchlist insertion: %s", responseBody)
+	}
+
+	statusCode, responseBody := sendSessionRequest(t, "POST", watchlistRoute, viewerToken, map[string]interface{}{"movieId": first.ID.String()})
+	assert.Equal(t, 400, statusCode, "status code of repeated watchlist movie")
+	assertProblem(t, apierrors.MovieAlreadyWatchlist, responseBody, "response of repeated watchlist movie")
+
+	getWatchlistTitles := func(t *testing.T) []string {
+		statusCode, responseBody := sendSessionRequest(t, "GET", watchlistRoute+"?sort=title,asc", viewerToken, nil)
+		if statusCode != 200 {
+			t.Fatalf("Unexpected status code %v getting watchlist: %s", statusCode, responseBody)
+		}
+
+		var watchlist models.Page[models.WatchlistItemResponse]
+		if err := json.Unmarshal(responseBody, &watchlist); err != nil {
+			t.Fatalf("Error unmarshalling response body: %v", err)
+		}
+
+		titles := []string{}
+		for _, item := range watchlist.Data {
+			titles = append(titles, item.Movie.Title)
+		}
+
+		return titles
+	}
+	assert.Equal(t, []string{first.Title, second.Title}, getWatchlistTitles(t), "Watchlist mismatch")
+
+	// Logging a movie in the diary takes it out of the watchlist
+	review, err := CommentModel.InsertCommentInDB(db, viewer.ID, models.CommentBody{Comment: "Diary review", Grade: 4, MovieId: first.ID.String()})
+	if err != nil {
+		t.Fatalf("Error inserting review: %v", err)
+	}
+
+	statusCode, responseBody = sendSessionRequest(t, "POST", diaryRoute, viewerToken, map[string]interface{}{
+		"movieId":   first.ID.String(),
+		"watchedOn": "2023-03-10",
+		"reviewId":  review.ID.String(),
+	})
+	assert.Equal(t, 201, statusCode, "status code of diary entry creation")
+
+	var entry models.DiaryEntryResponse
+	if err := json.Unmarshal(responseBody, &entry); err != nil {
+		t.Fatalf("Error unmarshalling response body: %v", err)
+	}
+	assert.Equal(t, "2023-03-10", entry.WatchedOn, "WatchedOn mismatch")
+	assert.Equal(t, uuid.NullUUID{UUID: review.ID, Valid: true}, entry.ReviewId, "Review should be linked to the entry")
+	assert.Equal(t, first.ID, entry.Movie.ID, "Movie mismatch")
+	assert.Equal(t, []string{second.Title}, getWatchlistTitles(t), "Watched movie should leave the watchlist")
+
+	statusCode, responseBody = sendSessionRequest(t, "POST", diaryRoute, viewerToken, map[string]interface{}{
+		"movieId":   second.ID.String(),
+		"watchedOn": "2023-03-20",
+		"reviewId":  review.ID.String(),
+	})
+	assert.Equal(t, 400, statusCode, "status code of diary entry linked to the review of another movie")
+	assertProblem(t, apierrors.InvalidDiaryReview, responseBody, "response of diary entry linked to the review of another movie")
+
+	var rewatchEntry models.DiaryEntryResponse
+	for _, body := range []map[string]interface{}{
+		{"movieId": second.ID.String(), "watchedOn": "2023-03-20"},
+		{"movieId": second.ID.String(), "watchedOn": "2023-07-01", "rewatch": true},
+	} {
+		statusCode, responseBody = sendSessionRequest(t, "POST", diaryRoute, viewerToken, body)
+		assert.Equal(t, 201, statusCode, "status code of diary entry creation")
+
+		if err := json.Unmarshal(responseBody, &rewatchEntry); err != nil {
+			t.Fatalf("Error unmarshalling response body: %v", err)
+		}
+	}
+	assert.True(t, rewatchEntry.Rewatch, "Entry should be a rewatch")
+
+	// Yearly stats
+	getStats := func(t *testing.T, year int) models.DiaryStatsResponse {
+		statusCode, responseBody := sendSessionRequest(t, "GET", fmt.Sprintf("%v/stats?year=%v", diaryRoute, year), viewerToken, nil)
+		if statusCode != 200 {
+			t.Fatalf("Unexpected status code %v getting diary stats: %s", statusCode, responseBody)
+		}
+
+		var stats models.DiaryStatsResponse
+		if err := json.Unmarshal(responseBody, &stats); err != nil {
+			t.Fatalf("Error unmarshalling response body: %v", err)
+		}
+
+		return stats
+	}
+
+	assert.Equal(t, models.DiaryStatsResponse{
+		Year:          2023,
+		FilmsWatched:  3,
+		UniqueFilms:   2,
+		Rewatches:     1,
+		AverageGrade:  4,
+		FilmsPerMonth: [12]int{0, 0, 2, 0, 0, 0, 1, 0, 0, 0, 0, 0},
+	}, getStats(t, 2023), "Diary stats mismatch")
+	assert.Equal(t, models.DiaryStatsResponse{Year: 2022}, getStats(t, 2022), "Year without entries should be empty")
+
+	// Editing and deleting entries
+	entryRoute := fmt.Sprintf("%v/%v", diaryRoute, rewatchEntry.ID)
+
+	statusCode, responseBody = sendSessionRequest(t, "PATCH", entryRoute, viewerToken, map[string]interface{}{"rewatch": false, "notes": "Not a rewatch after all"})
+	assert.Equal(t, 200, statusCode, "status code of diary entry update")
+
+	var updatedEntry models.DiaryEntryResponse
+	if err := json.Unmarshal(responseBody, &updatedEntry); err != nil {
+		t.Fatalf("Error unmarshalling response body: %v", err)
+	}
+	assert.False(t, updatedEntry.Rewatch, "Rewatch should be updated")
+	assert.Equal(t, "Not a rewatch after all", updatedEntry.Notes, "Notes should be updated")
+
+	statusCode, _ = sendSessionRequest(t, "DELETE", entryRoute, viewerToken, nil)
+	assert.Equal(t, 204, statusCode, "status code of diary entry deletion")
+
+	statusCode, responseBody = sendSessionRequest(t, "DELETE", entryRoute, viewerToken, nil)
+	assert.Equal(t, 404, statusCode, "status code of deleted diary entry")
+	assertProblem(t, apierrors.DiaryEntryNotFound, responseBody, "response of deleted diary entry")
+
+	statusCode, responseBody = sendSessionRequest(t, "PATCH", fmt.Sprintf("/users/%v/diary/%v", adminId, entry.ID), adminToken, map[string]interface{}{"notes": "Not mine"})
+	assert.Equal(t, 404, statusCode, "status code of diary entry of another user")
+	assertProblem(t, apierrors.DiaryEntryNotFound, responseBody, "response of diary entry of another user")
+
+	statusCode, responseBody = sendSessionRequest(t, "GET", diaryRoute+"?year=2023", viewerToken, nil)
+	assert.Equal(t, 200, statusCode, "status code of diary list")
+
+	var diary models.Page[models.DiaryEntryResponse]
+	if err := json.Unmarshal(responseBody, &diary); err != nil {
+		t.Fatalf("Error unmarshalling response body: %v", err)
+	}
+	assert.Equal(t, 2, diary.Pagination.Total, "Deleted entries shouldn't be counted")
+	if assert.Len(t, diary.Data, 2, "Deleted entries should leave the diary") {
+		assert.Equal(t, "2023-03-20", diary.Data[0].WatchedOn, "Diary should be sorted by most recently watched")
+		assert.Equal(t, "2023-03-10", diary.Data[1].WatchedOn, "Diary should be sorted by most recently watched")
+	}
+
+	// Following the cursor of a page of one entry
+	statusCode, responseBody = sendSessionRequest(t, "GET", diaryRoute+"?year=2023&limit=1", viewerToken, nil)
+	assert.Equal(t, 200, statusCode, "status code of first diary page")
+
+	var firstPage models.Page[models.DiaryEntryResponse]
+	if err := json.Unmarshal(responseBody, &firstPage); err != nil {
+		t.Fatalf("Error unmarshalling response body: %v", err)
+	}
+	if assert.Len(t, firstPage.Data, 1, "First page should have the limit of entries") && assert.NotEmpty(t, firstPage.Pagination.Next, "First page should have a next cursor") {
+		statusCode, responseBody = sendSessionRequest(t, "GET", diaryRoute+"?year=2023&limit=1&cursor="+firstPage.Pagination.Next, viewerToken, nil)
+		assert.Equal(t, 200, statusCode, "status code of second diary page")
+
+		var secondPage models.Page[models.DiaryEntryResponse]
+		if err := json.Unmarshal(responseBody, &secondPage); err != nil {
+			t.Fatalf("Error unmarshalling response body: %v", err)
+		}
+		if assert.Len(t, secondPage.Data, 1, "Second page should have the last entry") {
+			assert.Equal(t, "2023-03-10", secondPage.Data[0].WatchedOn, "Second page should continue after the first")
+		}
+	}
+
+	// Removing from the watchlist
+	assert.Equal(t, []string{}, getWatchlistTitles(t), "Every watched movie should leave the watchlist")
+
+	statusCode, _ = sendSessionRequest(t, "POST", watchlistRoute, viewerToken, map[string]interface{}{"movieId": first.ID.String()})
+	assert.Equal(t, 201, statusCode, "status code of watched movie added back to the watchlist")
+
+	statusCode, _ = sendSessionRequest(t, "DELETE", fmt.Sprintf("%v/%v", watchlistRoute, first.ID), viewerToken, nil)
+	assert.Equal(t, 204, statusCode, "status code of watchlist removal")
+
+	statusCode, responseBody = sendSessionRequest(t, "DELETE", fmt.Sprintf("%v/%v", watchlistRoute, first.ID), viewerToken, nil)
+	assert.Equal(t, 404, statusCode, "status code of movie not in the watchlist")
+	assertProblem(t, apierrors.MovieNotInWatchlist, responseBody, "response of movie not in the watchlist")
+
+	// Error cases
+	statusCode, responseBody = sendSessionRequest(t, "GET", diaryRoute+"/stats?year=last", viewerToken, nil)
+	assert.Equal(t, 400, statusCode, "status code of invalid year")
+	assertProblem(t, apierrors.InvalidQueryParam("year", apierrors.WholeNumberBetween, "1", "9999"), responseBody, "response of invalid year")
+
+	statusCode, responseBody = sendSessionRequest(t, "GET", fmt.Sprintf("/users/%v/watchlist", adminId), viewerToken, nil)
+	assert.Equal(t, 401, statusCode, "status code of watchlist of another user")
+	assertProblem(t, apierrors.NotSelf, responseBody, "response of watchlist of another user")
+
+	statusCode, responseBody = sendSessionRequest(t, "GET", fmt.Sprintf("/users/%v/watchlist", uuid.New()), adminToken, nil)
+	assert.Equal(t, 404, statusCode, "status code of watchlist of a user that does not exist")
+	assertProblem(t, apierrors.UserNotFound, responseBody, "response of watchlist of a user that does not exist")
+}
@@ -0,0 +1,63 @@
+package validation
+
+import "github.com/VinOfSteel/cinemagrader/i18n"
+
+// Messages of each validation tag, built-in or registered in initializers.NewValidator. {0} is the field and {1} the
+// param of the tag, like the max length of max or the accepted values of oneof.
+var englishMessages = map[string]string{
+	"required":        "The {0} field is required.",
+	"password":        "The password field needs to have at least 8 characters in length, at least one symbol, one lowercased letter, one uppercased letter and one number.",
+	"email":           "The email field needs to be a valid email.",
+	"max":             "The {0} field can't be longer than {1} characters.",
+	"min":             "The {0} field needs to be at least {1}, counting its items if it is an array.",
+	"unique":          "The {0} field can't have repeated items.",
+	"datetime":        "The {0} field needs to follow the YYYY-MM-DD format.",
+	"isadminuuid":     "The creatorId field needs to be a valid uuid of a user allowed to edit the catalog.",
+	"validactorslice": "The actors field needs to be a valid array that contains uuids of existing actors.",
+	"validgenreslice": "The genres field needs to be a valid array that contains uuids of existing genres.",
+	"ispersonuuid":    "The {0} field needs to be a valid uuid of an existing person.",
+	"oneof":           "The {0} field needs to be one of: {1}.",
+	"isvaliduuid":     "The {0} field needs to be a valid uuid.",
+	"isvalidgrade":    "The {0} field needs to a float between 1.0 and 5.0, with only one decimal field.",
+}
+
+var brazilianPortugueseMessages = map[string]string{
+	"required":        "O campo {0} é obrigatório.",
+	"password":        "O campo password precisa ter pelo menos 8 caracteres, com pelo menos um símbolo, uma letra minúscula, uma letra maiúscula e um número.",
+	"email":           "O campo email precisa ser um email válido.",
+	"max":             "O campo {0} não pode ter mais de {1} caracteres.",
+	"min":             "O campo {0} precisa ser no mínimo {1}, contando seus itens se for um array.",
+	"unique":          "O campo {0} não pode ter itens repetidos.",
+	"datetime":        "O campo {0} precisa seguir o formato AAAA-MM-DD.",
+	"isadminuuid":     "O campo creatorId precisa ser um uuid válido de um usuário que pode editar o catálogo.",
+	"validactorslice": "O campo actors precisa ser um array válido com uuids de atores existentes.",
+	"validgenreslice": "O campo genres precisa ser um array válido com uuids de gêneros existentes.",
+	"ispersonuuid":    "O campo {0} precisa ser um uuid válido de uma pessoa existente.",
+	"oneof":           "O campo {0} precisa ser um de: {1}.",
+	"isvaliduuid":     "O campo {0} precisa ser um uuid válido.",
+	"isvalidgrade":    "O campo {0} precisa ser um número entre 1.0 e 5.0, com apenas uma casa decimal.",
+}
+
+// messageKey keeps the tags apart from the other keys of the i18n catalogues
+func messageKey(tag string) string {
+	return "validation." + tag
+}
+
+func keyed(messages map[string]string) map[string]string {
+	keyedMessages := make(map[string]string, len(messages))
+	for tag, message := range messages {
+		keyedMessages[messageKey(tag)] = message
+	}
+
+	return keyedMessages
+}
+
+func init() {
+	if err := i18n.Add(i18n.Translator(i18n.English), keyed(englishMessages)); err != nil {
+		panic(err)
+	}
+
+	if err := i18n.Add(i18n.Translator(i18n.BrazilianPortuguese), keyed(brazilianPortugueseMessages)); err != nil {
+		panic(err)
+	}
+}
@@ -1,13 +1,15 @@
 package validation
 
 import (
-	"fmt"
 	"log"
+	"strings"
 	"unicode"
 	"unicode/utf8"
 
+	"github.com/VinOfSteel/cinemagrader/apierrors"
+	"github.com/VinOfSteel/cinemagrader/i18n"
+	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
-	"github.com/gofiber/fiber/v2"
 )
 
 type ErrorResponse struct {
@@ -43,65 +45,61 @@ func firstAndLastToLower(s string) string {
 	return string(firstLower) + s[size:len(s)-lastSize] + string(lastLower)
 }
 
-func structValidation(validate *validator.Validate, data interface{}) []ErrorResponse {
+// invalidFields validates the data, returning the fields that failed
+func invalidFields(validate *validator.Validate, data interface{}) validator.ValidationErrors {
+	if errors := validate.Struct(data); errors != nil {
+		return errors.(validator.ValidationErrors)
+	}
+
+	return nil
+}
+
+// fieldErrors describes the failed fields with the messages of their tags in the language of the translator
+func fieldErrors(trans ut.Translator, fields validator.ValidationErrors) []ErrorResponse {
 	var validationErrors []ErrorResponse
 
-	errors := validate.Struct(data)
-	if errors != nil {
-		for _, err := range errors.(validator.ValidationErrors) {
-			var elem ErrorResponse
-
-			elem.FailedField = firstAndLastToLower(err.Field())
-			elem.Tag = err.Tag()
-			elem.Error = true
-
-			switch err.Tag() {
-			case "required":
-				elem.ErrorMessage = fmt.Sprintf("The %s field is required.", firstAndLastToLower(err.Field()))
-			case "password":
-				elem.ErrorMessage = "The password field needs to have at least 8 characters in length, at least one symbol, one lowercased letter, one uppercased letter and one number."
-			case "email":
-				elem.ErrorMessage = "The email field needs to be a valid email."
-			case "datetime":
-				elem.ErrorMessage = fmt.Sprintf("The %s field needs to follow the YYYY-MM-DD format.", firstAndLastToLower(err.Field()))
-			case "isadminuuid":
-				elem.ErrorMessage = "The creatorId field needs to be a valid uuid that belongs to an admin user."
-			case "validactorslice":
-				elem.ErrorMessage = "The actors field needs to be a valid array that contains uuids of existing actors."
-			case "isvaliduuid":
-				elem.ErrorMessage = fmt.Sprintf("The %s field needs to be a valid uuid.", firstAndLastToLower(err.Field()))
-			case "isvalidgrade":
-				elem.ErrorMessage = fmt.Sprintf("The %s field needs to a float between 1.0 and 5.0, with only one decimal field.", firstAndLastToLower(err.Field()))
-
-			}
-
-			validationErrors = append(validationErrors, elem)
+	for _, err := range fields {
+		var elem ErrorResponse
+
+		elem.FailedField = firstAndLastToLower(err.Field())
+		elem.Tag = err.Tag()
+		elem.Error = true
+
+		param := err.Param()
+		if err.Tag() == "oneof" {
+			param = strings.Join(strings.Fields(param), ", ")
 		}
+
+		// Tags without a message are left with an empty one
+		elem.ErrorMessage = i18n.T(trans, messageKey(err.Tag()), elem.FailedField, param)
+
+		validationErrors = append(validationErrors, elem)
 	}
 
 	return validationErrors
 }
 
-func ValidateData(c *fiber.Ctx, validate *validator.Validate, data interface{}) bool {
-	if errors := structValidation(validate, data); len(errors) > 0 && errors[0].Error {
-		log.Println("Errors while validating data in the ValidateData function...", errors)
+func structValidation(validate *validator.Validate, data interface{}) []ErrorResponse {
+	return fieldErrors(i18n.Translator(i18n.English), invalidFields(validate, data))
+}
+
+// ValidateData returns an apierrors.ValidationFailed with the message of each invalid field in the details, in the language
+// the error is localized to, or nil when the data is valid
+func ValidateData(validate *validator.Validate, data interface{}) error {
+	fields := invalidFields(validate, data)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	log.Println("Errors while validating data in the ValidateData function...", fieldErrors(i18n.Translator(i18n.English), fields))
+
+	return apierrors.ValidationFailed.WithLocalizedDetails(func(trans ut.Translator) map[string]string {
 		errMap := make(map[string]string)
 
-		for _, err := range errors {
+		for _, err := range fieldErrors(trans, fields) {
 			errMap[err.FailedField] = err.ErrorMessage
 		}
 
-		c.Status(fiber.ErrBadRequest.Code).JSON(struct {
-			Message string            `json:"message"`
-			Errors  map[string]string `json:"errors"`
-		}{
-			Message: "Validation failed",
-			Errors:  errMap,
-		},
-		)
-
-		return false
-	}
-
-	return true
+		return errMap
+	})
 }
@@ -3,8 +3,12 @@ package validation
 import (
 	"log"
 	"os"
+	"reflect"
+	"regexp"
+	"strings"
 	"testing"
 
+	"github.com/VinOfSteel/cinemagrader/i18n"
 	"github.com/VinOfSteel/cinemagrader/initializers"
 	"github.com/VinOfSteel/cinemagrader/models"
 	"github.com/VinOfSteel/cinemagrader/tests"
@@ -31,13 +35,13 @@ func TestMain(m *testing.M) {
 
 	os.Setenv("PGDATABASE", testDb)
 
-	// Validator setup
-	validate = initializers.NewValidator()
-
 	// Creating a new admin user to use on the validation tests
 	db := initializers.NewDatabaseConn()
 	defer db.Close()
 
+	// Validator setup
+	validate = initializers.NewValidator(db)
+
 	var adminUser = models.UserBody{
 		Name:     "The",
 		Surname:  "Admin",
@@ -182,7 +186,7 @@ func Test_structValidation(t *testing.T) {
 					Error:        true,
 					FailedField:  "creatorId",
 					Tag:          "isadminuuid",
-					ErrorMessage: "The creatorId field needs to be a valid uuid that belongs to an admin user.",
+					ErrorMessage: "The creatorId field needs to be a valid uuid of a user allowed to edit the catalog.",
 				},
 				{
 					Error:        true,
@@ -213,3 +217,55 @@ func Test_structValidation(t *testing.T) {
 		})
 	}
 }
+
+func Test_Messages(t *testing.T) {
+	placeholderRegex := regexp.MustCompile(`\{\d+\}`)
+
+	for tag, message := range englishMessages {
+		translated, found := brazilianPortugueseMessages[tag]
+		if assert.True(t, found, "Message of %s should be translated", tag) {
+			assert.ElementsMatch(t, placeholderRegex.FindAllString(message, -1), placeholderRegex.FindAllString(translated, -1), "Message of %s should have the same placeholders", tag)
+		}
+	}
+
+	fields := invalidFields(validate, struct {
+		Name string `json:"name" validate:"required"`
+	}{})
+	got := fieldErrors(i18n.Translator(i18n.BrazilianPortuguese), fields)
+	if assert.Len(t, got, 1, "Empty name should fail") {
+		assert.Equal(t, "O campo name é obrigatório.", got[0].ErrorMessage, "Message should be translated")
+	}
+}
+
+func Test_MessagesOfBodyTags(t *testing.T) {
+	// Tags that only change how the others apply, which never fail by themselves
+	modifiers := map[string]bool{"omitempty": true, "dive": true}
+
+	bodies := []interface{}{
+		models.UserBody{}, models.UserEditBody{}, models.BanBody{}, models.RoleBody{},
+		models.ActorBody{}, models.ActorEditBody{}, models.PersonBody{}, models.PersonEditBody{},
+		models.MovieBody{}, models.MovieEditBody{}, models.CastingBody{}, models.CastingEditBody{},
+		models.MovieActorsBody{}, models.CrewBody{}, models.MovieCrewBody{}, models.MovieGenresBody{},
+		models.GenreBody{}, models.GenreEditBody{}, models.CommentBody{}, models.CommentEditBody{},
+		models.ReviewBody{}, models.ReportBody{}, models.ModerationActionBody{}, models.WatchlistBody{},
+		models.DiaryBody{}, models.DiaryEditBody{}, models.ListBody{}, models.ListEditBody{},
+		models.ListItemBody{}, models.ListOrderBody{},
+	}
+
+	for _, body := range bodies {
+		bodyType := reflect.TypeOf(body)
+		for i := 0; i < bodyType.NumField(); i++ {
+			field := bodyType.Field(i)
+			for _, tag := range strings.Split(field.Tag.Get("validate"), ",") {
+				tag, param, _ := strings.Cut(tag, "=")
+				if tag == "" || modifiers[tag] {
+					continue
+				}
+
+				for _, trans := range i18n.Translators() {
+					assert.NotEmpty(t, i18n.T(trans, messageKey(tag), field.Name, param), "Tag %s of %s.%s should have a message in %s", tag, bodyType.Name(), field.Name, trans.Locale())
+				}
+			}
+		}
+	}
+}